@@ -1,25 +1,92 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"github.com/sirupsen/logrus"
 	"log"
 	"merchant_bank_payment_go_api/internal/config"
 	"merchant_bank_payment_go_api/internal/utils"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
-	cfg, err := config.LoadConfig()
+	configPath := flag.String("config", "", "path to a YAML configuration file; environment variables override its settings")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig(*configPath)
 	if err != nil {
 		log.Fatalf("Error loading config: %v", err)
 	}
 
 	utils.InitJwtConfig(cfg.SecretKey, cfg.ExpireInMinutes)
 
-	logger := config.NewLogger()
+	logger, closeLog, err := config.NewLogger(cfg)
+	if err != nil {
+		log.Fatalf("Error creating logger: %v", err)
+	}
+
+	shutdownTracing, err := config.NewTracerProvider(cfg)
+	if err != nil {
+		log.Fatalf("Error creating tracer provider: %v", err)
+	}
+
+	router, hooks := config.Bootstrap(logger, cfg)
+	hooks.Add("tracing", shutdownTracing)
+	hooks.Add("log file", func(context.Context) error {
+		return closeLog()
+	})
 
-	router := config.Bootstrap(logger)
+	os.Exit(serve(cfg, logger, router, hooks))
+}
+
+// serve runs the server until it fails or a signal arrives, then drains
+// in-flight requests and runs the shutdown hooks. It returns the exit code.
+func serve(cfg *config.Config, logger *logrus.Logger, handler http.Handler, hooks *config.ShutdownHooks) int {
+	// After the first signal the default handling is restored, so a second
+	// Ctrl+C kills the process without waiting for the drain.
+	signals, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	server := config.NewServer(cfg, handler)
+	serverErrors := make(chan error, 1)
+	go func() {
+		logger.Infof("Listening on %s", server.Addr)
+		serverErrors <- server.ListenAndServe()
+	}()
+
+	exitCode := 0
+	var serverErr error
+	select {
+	case serverErr = <-serverErrors:
+	case <-signals.Done():
+		stopSignals()
+		logger.Infof("Received shutdown signal, draining in-flight requests for up to %s", cfg.ShutdownTimeout)
+
+		drainCtx, cancelDrain := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer cancelDrain()
+		if err := server.Shutdown(drainCtx); err != nil {
+			logger.Errorf("Cutting off requests still running after %s: %v", cfg.ShutdownTimeout, err)
+			_ = server.Close()
+			exitCode = 1
+		}
+		serverErr = <-serverErrors
+	}
+	if !errors.Is(serverErr, http.ErrServerClosed) {
+		logger.Errorf("Server failed: %v", serverErr)
+		exitCode = 1
+	}
+	logger.Info("Server stopped, running shutdown hooks")
 
-	port := cfg.Port
-	if err := router.Run(":" + port); err != nil {
-		log.Fatal("Failed to start the server: ", err)
+	hookCtx, cancelHooks := context.WithTimeout(context.Background(), cfg.ShutdownHookTimeout)
+	defer cancelHooks()
+	if err := hooks.Run(hookCtx); err != nil {
+		log.Printf("Shutdown hooks failed: %v", err)
+		exitCode = 1
 	}
+	return exitCode
 }
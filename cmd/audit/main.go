@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"log"
+	repositoryImpl "merchant_bank_payment_go_api/internal/repository/impl"
+	usecaseImpl "merchant_bank_payment_go_api/internal/usecase/impl"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	dataDir := flag.String("data", "internal/repository/data", "directory holding the JSON data files")
+	key := flag.String("key", os.Getenv("AUDIT_HMAC_KEY"), "audit HMAC key, defaults to $AUDIT_HMAC_KEY")
+	archiveDir := flag.String("archive-dir", "", "directory holding the history archives, defaults to <data>/archive/history")
+	checkpoint := flag.Bool("checkpoint", false, "sign a checkpoint at the current head after a successful verification")
+	flag.Parse()
+
+	logger := logrus.New()
+	logger.SetOutput(os.Stderr)
+	logger.SetLevel(logrus.WarnLevel)
+
+	if *archiveDir == "" {
+		*archiveDir = filepath.Join(*dataDir, "archive", "history")
+	}
+
+	historyArchiveRepository := repositoryImpl.NewHistoryArchiveRepositoryImpl(logger, *archiveDir)
+	historyRepository := repositoryImpl.NewHistoryRepositoryImpl(logger, filepath.Join(*dataDir, "History.json"), []byte(*key), historyArchiveRepository)
+	auditCheckpointRepository := repositoryImpl.NewAuditCheckpointRepositoryImpl(logger, filepath.Join(*dataDir, "AuditCheckpoints.json"))
+	auditUseCase := usecaseImpl.NewAuditUseCaseImpl(logger, historyRepository, historyArchiveRepository, auditCheckpointRepository,
+		[]byte(*key))
+
+	ctx := context.Background()
+	result, err := auditUseCase.VerifyChain(ctx)
+	if err != nil {
+		log.Fatalf("Error verifying audit log: %v", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		log.Fatalf("Error writing result: %v", err)
+	}
+
+	if !result.Valid {
+		fmt.Fprintf(os.Stderr, "audit log broken at sequence %d: %s\n", result.BrokenLink.Sequence, result.BrokenLink.Reason)
+		os.Exit(1)
+	}
+
+	if *checkpoint {
+		created, err := auditUseCase.CreateCheckpoint(ctx)
+		if err != nil {
+			log.Fatalf("Error creating checkpoint: %v", err)
+		}
+		if created != nil {
+			fmt.Fprintf(os.Stderr, "checkpoint %s signed at sequence %d\n", created.Id, created.Sequence)
+		}
+	}
+}
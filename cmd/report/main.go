@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"io"
+	"log"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/internal/repository"
+	repositoryImpl "merchant_bank_payment_go_api/internal/repository/impl"
+	usecaseImpl "merchant_bank_payment_go_api/internal/usecase/impl"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	merchantId := flag.String("merchant", "", "merchant id to report on (required)")
+	from := flag.String("from", "", "start of the range, YYYY-MM-DD or RFC 3339 (required)")
+	to := flag.String("to", "", "end of the range, YYYY-MM-DD (inclusive) or RFC 3339 (required)")
+	format := flag.String("format", "csv", "output format: csv or json")
+	out := flag.String("out", "", "output file, defaults to stdout")
+	dataDir := flag.String("data", "internal/repository/data", "directory holding the JSON data files")
+	flag.Parse()
+
+	if *merchantId == "" || *from == "" || *to == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(os.Stderr)
+	logger.SetLevel(logrus.WarnLevel)
+
+	merchantRepository := repositoryImpl.NewMerchantRepositoryImpl(logger, filepath.Join(*dataDir, "Merchant.json"))
+	paymentTransactionRepository := repositoryImpl.NewPaymentTransactionImpl(logger, filepath.Join(*dataDir, "PaymentTransactions.json"))
+
+	reportUseCase := usecaseImpl.NewReportUseCaseImpl(paymentTransactionRepository, merchantLookup{merchantRepository})
+
+	var writer io.Writer = os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			log.Fatalf("Error creating output file: %v", err)
+		}
+		defer file.Close()
+		writer = file
+	}
+
+	err := reportUseCase.ExportReconciliation(context.Background(), model.ReconciliationReportRequest{
+		MerchantId: *merchantId,
+		From:       *from,
+		To:         *to,
+		Format:     *format,
+	}, writer)
+	if err != nil {
+		log.Fatalf("Error exporting reconciliation report: %v", err)
+	}
+}
+
+// merchantLookup reads merchants without recording the lookup. History.json
+// is only written by the server, which holds the audit chain key and
+// serialises appends to the chain.
+type merchantLookup struct {
+	repository repository.MerchantRepository
+}
+
+func (m merchantLookup) FindById(ctx context.Context, id string) (entity.Merchant, error) {
+	merchantId, err := uuid.Parse(id)
+	if err != nil {
+		return entity.Merchant{}, fmt.Errorf("invalid merchant id %q: %w", id, err)
+	}
+	return m.repository.FindById(ctx, merchantId)
+}
@@ -0,0 +1,38 @@
+package buildinfo
+
+import (
+	"runtime/debug"
+	"time"
+)
+
+// Version, Commit and BuildTime are set when building a release, for example
+//
+//	go build -ldflags "-X merchant_bank_payment_go_api/internal/buildinfo.Version=v1.4.0 \
+//	  -X merchant_bank_payment_go_api/internal/buildinfo.Commit=$(git rev-parse HEAD) \
+//	  -X merchant_bank_payment_go_api/internal/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/app
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+// StartedAt is when the process started.
+var StartedAt = time.Now()
+
+// ResolvedCommit returns Commit, falling back to the VCS revision the Go
+// toolchain stamps into binaries built from a git checkout.
+func ResolvedCommit() string {
+	if Commit != "" {
+		return Commit
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			return setting.Value
+		}
+	}
+	return "unknown"
+}
@@ -1,33 +1,145 @@
 package config
 
 import (
+	"context"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 	"merchant_bank_payment_go_api/internal/delivery/http/controller"
 	"merchant_bank_payment_go_api/internal/delivery/http/route"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/metrics"
+	"merchant_bank_payment_go_api/internal/repository"
 	repositoryImpl "merchant_bank_payment_go_api/internal/repository/impl"
 	usecaseImpl "merchant_bank_payment_go_api/internal/usecase/impl"
+	"merchant_bank_payment_go_api/internal/utils"
+	"net/http"
 )
 
-func Bootstrap(logger *logrus.Logger) *gin.Engine {
-	historyRepository := repositoryImpl.NewHistoryRepositoryImpl(logger, "internal/repository/data/History.json")
-	customerRepository := repositoryImpl.NewCustomerRepositoryImpl(logger, "internal/repository/data/Customer.json")
-	merchantRepository := repositoryImpl.NewMerchantRepositoryImpl(logger, "internal/repository/data/Merchant.json")
-	authRepository := repositoryImpl.NewAuthRepository(logger, "internal/repository/data/BlacklistToken.json")
-	paymentTransactionRepository := repositoryImpl.NewPaymentTransactionImpl(logger, "internal/repository/data/PaymentTransactions.json")
+// Bootstrap wires the application and starts its background workers. The
+// returned hooks stop the workers and flush buffered history entries; run them
+// after the server has drained its requests.
+func Bootstrap(logger *logrus.Logger, cfg *Config) (*gin.Engine, *ShutdownHooks) {
+	historyArchiveRepository := repositoryImpl.NewHistoryArchiveRepositoryImpl(logger, cfg.HistoryArchiveDir)
+	historyFileRepository := repositoryImpl.NewHistoryRepositoryImpl(logger, cfg.DataFiles.History, cfg.AuditKey, historyArchiveRepository)
+	historyRepository := repositoryImpl.NewBufferedHistoryRepositoryImpl(logger, historyFileRepository, cfg.HistoryQueueSize,
+		cfg.HistoryBatchSize, cfg.HistoryFlushInterval, cfg.HistoryOverflowPolicy)
+	customerRepository := repositoryImpl.NewCustomerRepositoryImpl(logger, cfg.DataFiles.Customers)
+	merchantRepository := repositoryImpl.NewMerchantRepositoryImpl(logger, cfg.DataFiles.Merchants)
+	authRepository := repositoryImpl.NewAuthRepository(logger, cfg.DataFiles.BlacklistedTokens)
+	paymentTransactionRepository := repositoryImpl.NewPaymentTransactionImpl(logger, cfg.DataFiles.PaymentTransactions)
+	exchangeRateRepository := repositoryImpl.NewExchangeRateRepositoryImpl(logger, cfg.DataFiles.ExchangeRates)
+	webhookDeliveryRepository := repositoryImpl.NewWebhookDeliveryRepositoryImpl(logger, cfg.DataFiles.WebhookDeliveries)
+	outboxRepository := repositoryImpl.NewOutboxRepositoryImpl(logger, cfg.DataFiles.Outbox)
+	settlementRepository := repositoryImpl.NewSettlementRepositoryImpl(logger, cfg.DataFiles.Settlements)
+	auditCheckpointRepository := repositoryImpl.NewAuditCheckpointRepositoryImpl(logger, cfg.DataFiles.AuditCheckpoints)
 
-	historyUsecase := usecaseImpl.NewHistoryUseCaseImpl(logger, historyRepository)
-	customerUseCase := usecaseImpl.NewCustomerUseCaseImpl(historyUsecase, customerRepository)
+	if err := outboxRepository.Recover(context.Background()); err != nil {
+		logger.Fatalf("Failed to recover interrupted outbox transaction: %v", err)
+	}
+
+	stopHistoryWriter := historyRepository.Start()
+	metrics.SetGaugeFunc("history_queue_depth", "History entries waiting for the background writer.", func() float64 {
+		return float64(historyRepository.Stats().Queued)
+	})
+
+	historyUsecase := usecaseImpl.NewHistoryUseCaseImpl(logger, historyRepository, historyArchiveRepository)
+	eventBus := usecaseImpl.NewEventBusImpl(logger, outboxRepository)
+	customerUseCase := usecaseImpl.NewCustomerUseCaseImpl(historyUsecase, customerRepository, paymentTransactionRepository)
 	merchantUseCase := usecaseImpl.NewMerchantUseCaseImpl(historyUsecase, merchantRepository)
-	authUseCase := usecaseImpl.NewAuthUseCaseImpl(authRepository, customerUseCase, historyUsecase)
+	authUseCase := usecaseImpl.NewAuthUseCaseImpl(authRepository, customerUseCase, historyUsecase, eventBus)
+	exchangeRateUseCase := usecaseImpl.NewExchangeRateUseCaseImpl(historyUsecase, exchangeRateRepository, cfg.FxMaxRateAge,
+		cfg.FxSpreadBasisPoints, cfg.FxRounding)
+	webhookUseCase := usecaseImpl.NewWebhookUseCaseImpl(logger, webhookDeliveryRepository, merchantRepository, historyUsecase,
+		&http.Client{Timeout: cfg.WebhookTimeout}, cfg.WebhookMaxAttempts, cfg.WebhookRetryBaseDelay)
+	stopWebhookWorker := webhookUseCase.StartWorker(cfg.WebhookPollInterval)
+	eventBus.Subscribe("webhook", entity.EventPaymentCreated, webhookUseCase.HandlePaymentCreated)
+	eventBus.Subscribe("webhook", entity.EventSettlementCreated, webhookUseCase.HandleSettlementCreated)
+	stopEventWorker := eventBus.StartWorker(cfg.EventPollInterval)
+	reloader := NewConfigReloader(logger, cfg, historyUsecase)
+	stopConfigWatcher := reloader.StartWatcher(cfg.ConfigWatchInterval)
 	paymentTransactionUseCase := usecaseImpl.NewPaymentTransactionUseCaseImpl(paymentTransactionRepository, customerUseCase,
-		merchantUseCase, historyUsecase)
+		merchantUseCase, historyUsecase, exchangeRateUseCase, eventBus, reloader.PaymentLimits)
+	settlementUseCase := usecaseImpl.NewSettlementUseCaseImpl(settlementRepository, paymentTransactionRepository, historyUsecase,
+		eventBus, cfg.SettlementCutoffHour)
+	reportUseCase := usecaseImpl.NewReportUseCaseImpl(paymentTransactionRepository, merchantUseCase)
+	auditUseCase := usecaseImpl.NewAuditUseCaseImpl(logger, historyRepository, historyArchiveRepository,
+		auditCheckpointRepository, cfg.AuditKey)
+	historyArchiveUseCase := usecaseImpl.NewHistoryArchiveUseCaseImpl(logger, historyRepository, historyArchiveRepository,
+		historyUsecase, cfg.HistoryRetention)
+	healthUseCase := usecaseImpl.NewHealthUseCaseImpl(logger, map[string]repository.HealthCheckRepository{
+		"history":          historyRepository,
+		"history_archive":  historyArchiveRepository,
+		"customer":         customerRepository,
+		"merchant":         merchantRepository,
+		"token_blacklist":  authRepository,
+		"payment":          paymentTransactionRepository,
+		"exchange_rate":    exchangeRateRepository,
+		"webhook_delivery": webhookDeliveryRepository,
+		"outbox":           outboxRepository,
+		"settlement":       settlementRepository,
+		"audit_checkpoint": auditCheckpointRepository,
+	}, utils.JwtKeyLoaded)
+
+	stopCheckpointWorker := func() {}
+	if len(cfg.AuditKey) > 0 {
+		stopCheckpointWorker = auditUseCase.StartCheckpointWorker(cfg.AuditCheckpointPeriod)
+	} else {
+		logger.Warn("AUDIT_HMAC_KEY is not set: history records are hash-chained but not MACed, and no checkpoints are created")
+	}
+
+	stopArchiveWorker := func() {}
+	if cfg.HistoryRetention > 0 {
+		stopArchiveWorker = historyArchiveUseCase.StartArchiveWorker(cfg.HistoryArchivePeriod)
+	}
 
 	authController := controller.NewAuthenticationController(logger, authUseCase)
 	paymentController := controller.NewPaymentTransactionController(logger, paymentTransactionUseCase)
+	settlementController := controller.NewSettlementController(logger, settlementUseCase)
+	reportController := controller.NewReportController(logger, reportUseCase)
+	webhookController := controller.NewWebhookController(logger, webhookUseCase)
+	merchantController := controller.NewMerchantController(logger, merchantUseCase)
+	customerController := controller.NewCustomerController(logger, customerUseCase)
+	historyController := controller.NewHistoryController(logger, historyUsecase)
+	healthController := controller.NewHealthController(logger, healthUseCase)
+	exchangeRateController := controller.NewExchangeRateController(logger, exchangeRateUseCase)
+	historyArchiveController := controller.NewHistoryArchiveController(logger, historyArchiveUseCase)
 
-	router := gin.Default()
-	route.ConfigureRouter(router, authController, paymentController, authUseCase)
+	router := gin.New()
+	// Without trusted proxies the client address is the connection's peer, so
+	// clients can not pick their own address for logs and rate limits.
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		logger.Fatalf("Invalid trusted proxies: %v", err)
+	}
+	router.Use(gin.RecoveryWithWriter(logger.WriterLevel(logrus.ErrorLevel)))
+	route.ConfigureRouter(router, logger, authController, paymentController, settlementController, reportController, webhookController, merchantController, customerController, historyController, healthController,
+		exchangeRateController, historyArchiveController, authUseCase, customerUseCase, route.Timeouts{Default: cfg.RequestTimeout, Export: cfg.ExportTimeout}, reloader.MaintenanceMode,
+		reloader.RateLimits)
+
+	hooks := NewShutdownHooks()
+	hooks.Add("config watcher", stopHook(stopConfigWatcher))
+	hooks.Add("event dispatcher", func(ctx context.Context) error {
+		stopEventWorker()
+		// Deliver what the drained requests published since the last dispatch.
+		_, err := eventBus.DispatchPending(ctx)
+		return err
+	})
+	hooks.Add("webhook worker", stopHook(stopWebhookWorker))
+	hooks.Add("audit checkpoint worker", stopHook(stopCheckpointWorker))
+	hooks.Add("history archive worker", stopHook(stopArchiveWorker))
+	hooks.Add("history writer", stopHook(stopHistoryWriter))
+	if len(cfg.AuditKey) > 0 {
+		hooks.Add("audit checkpoint", func(ctx context.Context) error {
+			_, err := auditUseCase.CreateCheckpoint(ctx)
+			return err
+		})
+	}
+
+	return router, hooks
+}
 
-	return router
+func stopHook(stop func()) func(ctx context.Context) error {
+	return func(context.Context) error {
+		stop()
+		return nil
+	}
 }
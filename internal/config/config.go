@@ -1,42 +1,384 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"github.com/joho/godotenv"
-	"os"
+	"github.com/sirupsen/logrus"
+	"io/fs"
+	"merchant_bank_payment_go_api/internal/delivery/http/route"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/repository"
+	"merchant_bank_payment_go_api/internal/utils"
+	"net"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
-	SecretKey       []byte
-	ExpireInMinutes int
-	Port            string
+	// Path is the configuration file the settings were read from, if any.
+	Path                  string
+	StorageBackend        string
+	DataFiles             DataFiles
+	SecretKey             []byte
+	ExpireInMinutes       int
+	Port                  string
+	FxMaxRateAge          time.Duration
+	FxSpreadBasisPoints   int64
+	FxRounding            entity.RoundingMode
+	SettlementCutoffHour  int
+	WebhookMaxAttempts    int
+	WebhookRetryBaseDelay time.Duration
+	WebhookTimeout        time.Duration
+	WebhookPollInterval   time.Duration
+	EventPollInterval     time.Duration
+	HistoryQueueSize      int
+	HistoryBatchSize      int
+	HistoryFlushInterval  time.Duration
+	HistoryOverflowPolicy repository.HistoryOverflowPolicy
+	HistoryRetention      time.Duration
+	HistoryArchiveDir     string
+	HistoryArchivePeriod  time.Duration
+	AuditKey              []byte
+	AuditCheckpointPeriod time.Duration
+	RequestTimeout        time.Duration
+	ExportTimeout         time.Duration
+	ServerReadTimeout     time.Duration
+	ServerWriteTimeout    time.Duration
+	ServerIdleTimeout     time.Duration
+	ShutdownTimeout       time.Duration
+	ShutdownHookTimeout   time.Duration
+	LogRedactor           *utils.Redactor
+	LogLevel              logrus.Level
+	LogFormat             string
+	LogOutput             string
+	LogFile               string
+	LogMaxSize            int64
+	LogRotateEvery        time.Duration
+	LogMaxBackups         int
+	LogMaxAge             time.Duration
+	LogCompress           bool
+	TracingExporter       string
+	TracingFile           string
+	TracingOtlpEndpoint   string
+	TracingOtlpHeaders    map[string]string
+	TracingSampleRatio    float64
+	TracingServiceName    string
+	PaymentLimits         entity.PaymentLimits
+	MaintenanceMode       bool
+	ConfigWatchInterval   time.Duration
+	TrustedProxies        []string
+	RateLimits            route.RateLimits
+
+	settings settings
+}
+
+const (
+	LogFormatJson = "json"
+	LogFormatText = "text"
+
+	LogOutputStdout = "stdout"
+	LogOutputFile   = "file"
+	LogOutputBoth   = "both"
+
+	TracingExporterNone   = "none"
+	TracingExporterStdout = "stdout"
+	TracingExporterFile   = "file"
+	TracingExporterOtlp   = "otlp"
+
+	StorageBackendJson = "json"
+)
+
+// DataFiles are the JSON files the repositories keep their data in.
+type DataFiles struct {
+	Customers           string
+	Merchants           string
+	BlacklistedTokens   string
+	PaymentTransactions string
+	ExchangeRates       string
+	History             string
+	WebhookDeliveries   string
+	Outbox              string
+	Settlements         string
+	AuditCheckpoints    string
+}
+
+// LoadConfig reads the YAML file at path, when path is not empty, over the
+// built-in defaults, and then applies the environment, which wins over the
+// file. A .env file in the working directory is loaded into the environment
+// first if there is one. Every invalid setting is reported in the error.
+func LoadConfig(path string) (*Config, error) {
+	if err := godotenv.Load(".env"); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("error loading .env file: %w", err)
+	}
+	return loadConfig(path)
+}
+
+func loadConfig(path string) (*Config, error) {
+	s := defaultSettings()
+	var errs []error
+	if path != "" {
+		errs = append(errs, s.readFile(path)...)
+	}
+	errs = append(errs, s.applyEnv()...)
+
+	cfg, validationErrs := s.build()
+	errs = append(errs, validationErrs...)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
+	cfg.Path = path
+	return cfg, nil
 }
 
-func LoadConfig() (*Config, error) {
-	err := godotenv.Load(".env")
+// problems collects validation failures, naming both the file key and the
+// environment variable of the setting.
+type problems struct {
+	envNames map[string]string
+	errs     []error
+}
+
+func (p *problems) add(key, format string, args ...interface{}) {
+	p.errs = append(p.errs, fmt.Errorf("%s (%s) %s", key, p.envNames[key], fmt.Sprintf(format, args...)))
+}
+
+func (p *problems) positive(key string, value int64) {
+	if value <= 0 {
+		p.add(key, "must be greater than 0")
+	}
+}
+
+func (p *problems) nonNegative(key string, value int64) {
+	if value < 0 {
+		p.add(key, "must not be negative")
+	}
+}
+
+func (p *problems) oneOf(key, value string, allowed ...string) {
+	for _, candidate := range allowed {
+		if value == candidate {
+			return
+		}
+	}
+	p.add(key, "must be one of %s, got %q", strings.Join(allowed, ", "), value)
+}
+
+func (s settings) build() (*Config, []error) {
+	p := &problems{envNames: envNames()}
+
+	if s.Auth.SecretKey == "" {
+		p.add("auth.secret_key", "must be set")
+	}
+	p.positive("auth.expire_in_minutes", int64(s.Auth.ExpireInMinutes))
+
+	if s.Server.Port == "" {
+		p.add("server.port", "must be set")
+	} else if port, err := strconv.Atoi(s.Server.Port); err != nil || port <= 0 || port > 65535 {
+		p.add("server.port", "must be a port number, got %q", s.Server.Port)
+	}
+	p.positive("server.read_timeout_seconds", int64(s.Server.ReadTimeoutSeconds))
+	p.positive("server.idle_timeout_seconds", int64(s.Server.IdleTimeoutSeconds))
+	p.positive("server.shutdown_timeout_seconds", int64(s.Server.ShutdownTimeoutSeconds))
+	p.positive("server.shutdown_hook_timeout_seconds", int64(s.Server.ShutdownHookTimeoutSeconds))
+	p.positive("limits.request_timeout_seconds", int64(s.Limits.RequestTimeoutSeconds))
+	p.positive("limits.export_timeout_seconds", int64(s.Limits.ExportTimeoutSeconds))
+
+	// The write timeout covers the whole handler, so it must outlast the
+	// longest request deadline or exports would be cut off without a response.
+	// When it is not set it follows the export timeout.
+	writeTimeoutSeconds := s.Server.WriteTimeoutSeconds
+	if writeTimeoutSeconds == 0 {
+		writeTimeoutSeconds = s.Limits.ExportTimeoutSeconds + 10
+	}
+	if writeTimeoutSeconds <= s.Limits.RequestTimeoutSeconds || writeTimeoutSeconds <= s.Limits.ExportTimeoutSeconds {
+		p.add("server.write_timeout_seconds", "must be greater than limits.request_timeout_seconds and limits.export_timeout_seconds")
+	}
+
+	p.oneOf("storage.backend", s.Storage.Backend, StorageBackendJson)
+	if s.Storage.DataDir == "" {
+		p.add("storage.data_dir", "must be set")
+	}
+	dataFile := func(path, name string) string {
+		if path != "" {
+			return path
+		}
+		return filepath.Join(s.Storage.DataDir, name)
+	}
+	historyArchiveDir := s.Storage.HistoryArchiveDir
+	if historyArchiveDir == "" {
+		historyArchiveDir = filepath.Join(s.Storage.DataDir, "archive", "history")
+	}
+
+	logLevel, err := logrus.ParseLevel(s.Logging.Level)
+	if err != nil {
+		p.add("logging.level", "is invalid: %v", err)
+	}
+	logFormat := strings.ToLower(s.Logging.Format)
+	p.oneOf("logging.format", logFormat, LogFormatJson, LogFormatText)
+	logOutput := strings.ToLower(s.Logging.Output)
+	p.oneOf("logging.output", logOutput, LogOutputStdout, LogOutputFile, LogOutputBoth)
+	if s.Logging.File == "" && logOutput != LogOutputStdout {
+		p.add("logging.file", "must be set unless logging.output is stdout")
+	}
+	p.nonNegative("logging.max_size_mb", int64(s.Logging.MaxSizeMb))
+	p.nonNegative("logging.rotate_interval_hours", int64(s.Logging.RotateIntervalHours))
+	p.nonNegative("logging.max_backups", int64(s.Logging.MaxBackups))
+	p.nonNegative("logging.max_age_days", int64(s.Logging.MaxAgeDays))
+	logRedactor, err := utils.NewRedactor(s.Logging.RedactPatterns)
+	if err != nil {
+		p.add("logging.redact_patterns", "is invalid: %v", err)
+	}
+
+	tracingExporter := strings.ToLower(s.Tracing.Exporter)
+	p.oneOf("tracing.exporter", tracingExporter, TracingExporterNone, TracingExporterStdout, TracingExporterFile, TracingExporterOtlp)
+	if s.Tracing.SampleRatio < 0 || s.Tracing.SampleRatio > 1 {
+		p.add("tracing.sample_ratio", "must be between 0 and 1")
+	}
+	if tracingExporter == TracingExporterFile && s.Tracing.File == "" {
+		p.add("tracing.file", "must be set for the file exporter")
+	}
+	if tracingExporter == TracingExporterOtlp && s.Tracing.OtlpEndpoint == "" {
+		p.add("tracing.otlp_endpoint", "must be set for the otlp exporter")
+	}
+	tracingOtlpHeaders := s.Tracing.OtlpHeaders
+	if tracingOtlpHeaders == nil {
+		tracingOtlpHeaders = map[string]string{}
+	}
+
+	p.nonNegative("fx.max_rate_age_minutes", int64(s.Fx.MaxRateAgeMinutes))
+	if s.Fx.SpreadBasisPoints < 0 || s.Fx.SpreadBasisPoints >= 10000 {
+		p.add("fx.spread_basis_points", "must be between 0 and 9999")
+	}
+	fxRounding, err := entity.ParseRoundingMode(s.Fx.Rounding)
 	if err != nil {
-		return nil, fmt.Errorf("Error loading .env file: %v", err)
+		p.add("fx.rounding", "is invalid: %v", err)
 	}
 
-	secretKey := os.Getenv("SECRET_KEY")
-	if len(secretKey) == 0 {
-		return nil, fmt.Errorf("SECRET_KEY not set in environment variables")
+	if s.Settlement.CutoffHour < 0 || s.Settlement.CutoffHour > 23 {
+		p.add("settlement.cutoff_hour", "must be between 0 and 23")
 	}
 
-	expireInMinutes, err := strconv.Atoi(os.Getenv("EXPIRE_IN_MINUTES"))
-	if err != nil || expireInMinutes <= 0 {
-		return nil, fmt.Errorf("EXPIRE_IN_MINUTES is not set or invalid")
+	p.positive("webhook.max_attempts", int64(s.Webhook.MaxAttempts))
+	p.positive("webhook.retry_base_seconds", int64(s.Webhook.RetryBaseSeconds))
+	p.positive("webhook.timeout_seconds", int64(s.Webhook.TimeoutSeconds))
+	p.positive("webhook.poll_interval_seconds", int64(s.Webhook.PollIntervalSeconds))
+	p.positive("events.poll_interval_seconds", int64(s.Events.PollIntervalSeconds))
+
+	p.positive("history.queue_size", int64(s.History.QueueSize))
+	p.positive("history.batch_size", int64(s.History.BatchSize))
+	p.positive("history.flush_interval_ms", int64(s.History.FlushIntervalMs))
+	historyOverflowPolicy := repository.HistoryOverflowPolicy(strings.ToUpper(s.History.OverflowPolicy))
+	p.oneOf("history.overflow_policy", string(historyOverflowPolicy), string(repository.HistoryOverflowBlock), string(repository.HistoryOverflowDrop))
+	p.nonNegative("history.retention_days", int64(s.History.RetentionDays))
+	p.positive("history.archive_interval_minutes", int64(s.History.ArchiveIntervalMinutes))
+
+	p.positive("audit.checkpoint_interval_minutes", int64(s.Audit.CheckpointIntervalMinutes))
+
+	paymentMaxAmounts := make(map[string]int64, len(s.Payments.MaxAmounts))
+	for code, maxAmount := range s.Payments.MaxAmounts {
+		currency, err := entity.FindCurrency(code)
+		if err != nil {
+			p.add("payments.max_amounts", "is invalid: %v", err)
+			continue
+		}
+		if maxAmount <= 0 {
+			p.add("payments.max_amounts", "must be greater than 0 for %s", currency.Code)
+		}
+		paymentMaxAmounts[currency.Code] = maxAmount
+	}
+
+	p.nonNegative("reload.watch_interval_seconds", int64(s.Reload.WatchIntervalSeconds))
+
+	rateLimit := func(key, value string) utils.RateLimitPolicy {
+		policy, err := utils.ParseRateLimitPolicy(value)
+		if err != nil {
+			p.add(key, "is invalid: %v", err)
+		}
+		return policy
+	}
+	rateLimits := route.RateLimits{
+		LoginPerIp:         rateLimit("rate_limits.login_per_ip", s.RateLimits.LoginPerIp),
+		ApiPerUser:         rateLimit("rate_limits.api_per_user", s.RateLimits.ApiPerUser),
+		PaymentPerUser:     rateLimit("rate_limits.payment_per_user", s.RateLimits.PaymentPerUser),
+		PaymentPerMerchant: rateLimit("rate_limits.payment_per_merchant", s.RateLimits.PaymentPerMerchant),
+	}
+	for _, proxy := range s.Server.TrustedProxies {
+		_, _, cidrErr := net.ParseCIDR(proxy)
+		if net.ParseIP(proxy) == nil && cidrErr != nil {
+			p.add("server.trusted_proxies", "must hold IP addresses or CIDR ranges, got %q", proxy)
+		}
 	}
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "4000"
+	if len(p.errs) > 0 {
+		return nil, p.errs
 	}
 
+	files := s.Storage.Files
 	return &Config{
-		SecretKey:       []byte(secretKey),
-		ExpireInMinutes: expireInMinutes,
-		Port:            port,
+		StorageBackend: s.Storage.Backend,
+		DataFiles: DataFiles{
+			Customers:           dataFile(files.Customers, "Customer.json"),
+			Merchants:           dataFile(files.Merchants, "Merchant.json"),
+			BlacklistedTokens:   dataFile(files.BlacklistedTokens, "BlacklistToken.json"),
+			PaymentTransactions: dataFile(files.PaymentTransactions, "PaymentTransactions.json"),
+			ExchangeRates:       dataFile(files.ExchangeRates, "ExchangeRates.json"),
+			History:             dataFile(files.History, "History.json"),
+			WebhookDeliveries:   dataFile(files.WebhookDeliveries, "WebhookDeliveries.json"),
+			Outbox:              dataFile(files.Outbox, "Outbox.json"),
+			Settlements:         dataFile(files.Settlements, "Settlement.json"),
+			AuditCheckpoints:    dataFile(files.AuditCheckpoints, "AuditCheckpoints.json"),
+		},
+		SecretKey:             []byte(s.Auth.SecretKey),
+		ExpireInMinutes:       s.Auth.ExpireInMinutes,
+		Port:                  s.Server.Port,
+		FxMaxRateAge:          time.Duration(s.Fx.MaxRateAgeMinutes) * time.Minute,
+		FxSpreadBasisPoints:   s.Fx.SpreadBasisPoints,
+		FxRounding:            fxRounding,
+		SettlementCutoffHour:  s.Settlement.CutoffHour,
+		WebhookMaxAttempts:    s.Webhook.MaxAttempts,
+		WebhookRetryBaseDelay: time.Duration(s.Webhook.RetryBaseSeconds) * time.Second,
+		WebhookTimeout:        time.Duration(s.Webhook.TimeoutSeconds) * time.Second,
+		WebhookPollInterval:   time.Duration(s.Webhook.PollIntervalSeconds) * time.Second,
+		EventPollInterval:     time.Duration(s.Events.PollIntervalSeconds) * time.Second,
+		HistoryQueueSize:      s.History.QueueSize,
+		HistoryBatchSize:      s.History.BatchSize,
+		HistoryFlushInterval:  time.Duration(s.History.FlushIntervalMs) * time.Millisecond,
+		HistoryOverflowPolicy: historyOverflowPolicy,
+		HistoryRetention:      time.Duration(s.History.RetentionDays) * 24 * time.Hour,
+		HistoryArchiveDir:     historyArchiveDir,
+		HistoryArchivePeriod:  time.Duration(s.History.ArchiveIntervalMinutes) * time.Minute,
+		AuditKey:              []byte(s.Audit.HmacKey),
+		AuditCheckpointPeriod: time.Duration(s.Audit.CheckpointIntervalMinutes) * time.Minute,
+		RequestTimeout:        time.Duration(s.Limits.RequestTimeoutSeconds) * time.Second,
+		ExportTimeout:         time.Duration(s.Limits.ExportTimeoutSeconds) * time.Second,
+		ServerReadTimeout:     time.Duration(s.Server.ReadTimeoutSeconds) * time.Second,
+		ServerWriteTimeout:    time.Duration(writeTimeoutSeconds) * time.Second,
+		ServerIdleTimeout:     time.Duration(s.Server.IdleTimeoutSeconds) * time.Second,
+		ShutdownTimeout:       time.Duration(s.Server.ShutdownTimeoutSeconds) * time.Second,
+		ShutdownHookTimeout:   time.Duration(s.Server.ShutdownHookTimeoutSeconds) * time.Second,
+		LogRedactor:           logRedactor,
+		LogLevel:              logLevel,
+		LogFormat:             logFormat,
+		LogOutput:             logOutput,
+		LogFile:               s.Logging.File,
+		LogMaxSize:            int64(s.Logging.MaxSizeMb) * 1024 * 1024,
+		LogRotateEvery:        time.Duration(s.Logging.RotateIntervalHours) * time.Hour,
+		LogMaxBackups:         s.Logging.MaxBackups,
+		LogMaxAge:             time.Duration(s.Logging.MaxAgeDays) * 24 * time.Hour,
+		LogCompress:           s.Logging.Compress,
+		TracingExporter:       tracingExporter,
+		TracingFile:           s.Tracing.File,
+		TracingOtlpEndpoint:   s.Tracing.OtlpEndpoint,
+		TracingOtlpHeaders:    tracingOtlpHeaders,
+		TracingSampleRatio:    s.Tracing.SampleRatio,
+		TracingServiceName:    s.Tracing.ServiceName,
+		PaymentLimits:         entity.PaymentLimits{MaxAmounts: paymentMaxAmounts},
+		MaintenanceMode:       s.Maintenance.Enabled,
+		ConfigWatchInterval:   time.Duration(s.Reload.WatchIntervalSeconds) * time.Second,
+		TrustedProxies:        s.Server.TrustedProxies,
+		RateLimits:            rateLimits,
+		settings:              s,
 	}, nil
 }
@@ -1,23 +1,62 @@
 package config
 
 import (
+	"fmt"
 	"github.com/sirupsen/logrus"
+	"io"
+	"merchant_bank_payment_go_api/internal/tracing"
+	"merchant_bank_payment_go_api/internal/utils"
 	"os"
 )
 
-func NewLogger() *logrus.Logger {
+// NewLogger builds the application logger from the LOG_* settings. The
+// returned close function flushes and closes the log file, if any.
+func NewLogger(cfg *Config) (*logrus.Logger, func() error, error) {
 	log := logrus.New()
 
-	log.SetLevel(logrus.InfoLevel)
+	log.SetLevel(cfg.LogLevel)
 
-	log.SetFormatter(&logrus.JSONFormatter{})
-
-	logFile, err := os.OpenFile("app_history.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
-	if err != nil {
-		log.Fatal("Failed to open log file for development, logging to stderr instead:", err)
+	if cfg.LogFormat == LogFormatText {
+		log.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
 	} else {
-		log.SetOutput(logFile)
+		log.SetFormatter(&logrus.JSONFormatter{})
+	}
+
+	// Hooks run in the order they are added; redaction goes first so the
+	// tracing hook never copies an unredacted message into a span.
+	if cfg.LogRedactor != nil {
+		log.AddHook(utils.RedactionHook{Redactor: cfg.LogRedactor})
+	}
+	log.AddHook(utils.RequestIdHook{})
+	log.AddHook(tracing.LogHook{})
+
+	closeLog := func() error { return nil }
+	switch cfg.LogOutput {
+	case LogOutputStdout:
+		log.SetOutput(os.Stdout)
+	case LogOutputFile, LogOutputBoth:
+		file := &utils.RotatingFile{
+			Filename:    cfg.LogFile,
+			MaxSize:     cfg.LogMaxSize,
+			RotateEvery: cfg.LogRotateEvery,
+			MaxBackups:  cfg.LogMaxBackups,
+			MaxAge:      cfg.LogMaxAge,
+			Compress:    cfg.LogCompress,
+		}
+		// Open the file now so a bad path fails at startup, not on the first line.
+		if _, err := file.Write(nil); err != nil {
+			return nil, nil, err
+		}
+		closeLog = file.Close
+
+		if cfg.LogOutput == LogOutputBoth {
+			log.SetOutput(io.MultiWriter(os.Stdout, file))
+		} else {
+			log.SetOutput(file)
+		}
+	default:
+		return nil, nil, fmt.Errorf("unknown log output %q", cfg.LogOutput)
 	}
 
-	return log
+	return log, closeLog, nil
 }
@@ -0,0 +1,233 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"merchant_bank_payment_go_api/internal/delivery/http/route"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/usecase"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// reloadableKeys are the settings a reload applies. Everything else is read
+// once at startup, and changing it needs a restart.
+var reloadableKeys = map[string]bool{
+	"logging.level":                    true,
+	"payments.max_amounts":             true,
+	"maintenance.enabled":              true,
+	"rate_limits.login_per_ip":         true,
+	"rate_limits.api_per_user":         true,
+	"rate_limits.payment_per_user":     true,
+	"rate_limits.payment_per_merchant": true,
+}
+
+// ConfigReloader holds the configuration the server currently runs with and
+// swaps in the reloadable settings when the configuration is read again.
+type ConfigReloader struct {
+	Log            *logrus.Logger
+	HistoryUseCase usecase.HistoryUseCase
+	current        atomic.Pointer[Config]
+	mu             sync.Mutex
+}
+
+func NewConfigReloader(log *logrus.Logger, cfg *Config, historyUseCase usecase.HistoryUseCase) *ConfigReloader {
+	reloader := &ConfigReloader{
+		Log:            log,
+		HistoryUseCase: historyUseCase,
+	}
+	reloader.current.Store(cfg)
+	return reloader
+}
+
+func (r *ConfigReloader) Config() *Config {
+	return r.current.Load()
+}
+
+func (r *ConfigReloader) PaymentLimits() entity.PaymentLimits {
+	return r.Config().PaymentLimits
+}
+
+func (r *ConfigReloader) MaintenanceMode() bool {
+	return r.Config().MaintenanceMode
+}
+
+func (r *ConfigReloader) RateLimits() route.RateLimits {
+	return r.Config().RateLimits
+}
+
+// Reload reads the configuration file and the environment again and validates
+// the result as a whole. When it is valid the reloadable settings are applied;
+// otherwise the current configuration stays in place. Either way the reload is
+// recorded in history.
+func (r *ConfigReloader) Reload(ctx context.Context, trigger string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current := r.Config()
+	next, err := loadConfig(current.Path)
+	if err != nil {
+		return r.logReload(ctx, entity.AuditErrorInvalidConfig,
+			fmt.Sprintf("Configuration reload on %s rejected, keeping the current configuration", trigger), err)
+	}
+
+	var applied, ignored []string
+	merged := current.settings
+	mergedValues := settingValues(&merged)
+	nextValues := settingValues(&next.settings)
+	for _, key := range changedSettings(current.settings, next.settings) {
+		if !reloadableKeys[key] {
+			ignored = append(ignored, key)
+			continue
+		}
+		mergedValues[key].Set(nextValues[key])
+		applied = append(applied, key)
+	}
+
+	cfg, errs := merged.build()
+	if len(errs) > 0 {
+		err = fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+		return r.logReload(ctx, entity.AuditErrorInvalidConfig,
+			fmt.Sprintf("Configuration reload on %s rejected, keeping the current configuration", trigger), err)
+	}
+	cfg.Path = current.Path
+
+	r.Log.SetLevel(cfg.LogLevel)
+	r.current.Store(cfg)
+
+	if len(ignored) > 0 {
+		r.Log.WithContext(ctx).Warnf("Changed settings need a restart to take effect: %s", strings.Join(ignored, ", "))
+	}
+	message := fmt.Sprintf("Configuration reloaded on %s, no reloadable setting changed", trigger)
+	if len(applied) > 0 {
+		message = fmt.Sprintf("Configuration reloaded on %s, applied %s", trigger, strings.Join(applied, ", "))
+	}
+	if len(ignored) > 0 {
+		message += fmt.Sprintf("; restart required for %s", strings.Join(ignored, ", "))
+	}
+	return r.logReload(ctx, "", message, nil)
+}
+
+// logReload records the reload in history. A rejected reload is logged with
+// its error, which is kept in the history message as well.
+func (r *ConfigReloader) logReload(ctx context.Context, errorCode entity.AuditErrorCode, message string, err error) error {
+	details := entity.AuditDetails{
+		ActorType:   entity.AuditActorSystem,
+		SubjectType: entity.AuditSubjectConfig,
+		SubjectId:   r.Config().Path,
+		Outcome:     entity.AuditOutcomeSuccess,
+		Message:     message,
+	}
+	if err != nil {
+		r.Log.WithContext(ctx).Errorf("%s: %v", message, err)
+		details.Outcome = entity.AuditOutcomeFailure
+		details.ErrorCode = errorCode
+		details.Message = fmt.Sprintf("%s: %v", message, err)
+	} else {
+		r.Log.WithContext(ctx).Info(message)
+	}
+
+	if historyErr := r.HistoryUseCase.AddHistory(ctx, "", entity.AuditActionConfigReload, details); historyErr != nil {
+		r.Log.WithContext(ctx).Errorf("Failed to add history for %s: %v", entity.AuditActionConfigReload, historyErr)
+		if err == nil {
+			return historyErr
+		}
+	}
+	return err
+}
+
+// StartWatcher reloads the configuration on SIGHUP and, when it was read from
+// a file and interval is positive, whenever the file's modification time or
+// size changes. The returned function stops the watcher and waits for a
+// running reload.
+func (r *ConfigReloader) StartWatcher(interval time.Duration) func() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	path := r.Config().Path
+	var ticks <-chan time.Time
+	var ticker *time.Ticker
+	if path != "" && interval > 0 {
+		ticker = time.NewTicker(interval)
+		ticks = ticker.C
+	}
+	lastStamp := statFile(path)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-done:
+				return
+			case <-signals:
+				_ = r.Reload(context.Background(), "SIGHUP")
+			case <-ticks:
+				stamp := statFile(path)
+				if stamp == lastStamp {
+					continue
+				}
+				lastStamp = stamp
+				_ = r.Reload(context.Background(), "file change")
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		if ticker != nil {
+			ticker.Stop()
+		}
+		close(done)
+		<-stopped
+	}
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// statFile returns the zero stamp when the file cannot be read, so removing
+// the file also counts as a change.
+func statFile(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}
+}
+
+func settingValues(s *settings) map[string]reflect.Value {
+	values := map[string]reflect.Value{}
+	walkSettings(reflect.ValueOf(s).Elem(), "", func(key string, _ reflect.StructField, value reflect.Value) {
+		values[key] = value
+	})
+	return values
+}
+
+// changedSettings lists the keys whose values differ, in file order. An empty
+// list or map equals a missing one.
+func changedSettings(current, next settings) []string {
+	nextValues := settingValues(&next)
+	var changed []string
+	walkSettings(reflect.ValueOf(&current).Elem(), "", func(key string, _ reflect.StructField, value reflect.Value) {
+		other := nextValues[key]
+		if (value.Kind() == reflect.Map || value.Kind() == reflect.Slice) && value.Len() == 0 && other.Len() == 0 {
+			return
+		}
+		if !reflect.DeepEqual(value.Interface(), other.Interface()) {
+			changed = append(changed, key)
+		}
+	})
+	return changed
+}
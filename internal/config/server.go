@@ -0,0 +1,75 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+func NewServer(cfg *Config, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ServerReadTimeout,
+		ReadTimeout:       cfg.ServerReadTimeout,
+		WriteTimeout:      cfg.ServerWriteTimeout,
+		IdleTimeout:       cfg.ServerIdleTimeout,
+	}
+}
+
+// ShutdownHooks holds the steps that run once the server has stopped taking
+// requests, such as stopping workers and flushing queued history. Hooks run
+// one at a time in the order they were added, so a later hook can rely on
+// the work of an earlier one having finished.
+type ShutdownHooks struct {
+	mu    sync.Mutex
+	hooks []shutdownHook
+}
+
+type shutdownHook struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+func NewShutdownHooks() *ShutdownHooks {
+	return &ShutdownHooks{}
+}
+
+func (s *ShutdownHooks) Add(name string, run func(ctx context.Context) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hooks = append(s.hooks, shutdownHook{name: name, run: run})
+}
+
+// Run keeps going after a hook fails and returns all failures joined. Once ctx
+// is done it stops waiting for the running hook and skips the rest, so a stuck
+// hook cannot keep the process from exiting.
+func (s *ShutdownHooks) Run(ctx context.Context) error {
+	s.mu.Lock()
+	hooks := append([]shutdownHook{}, s.hooks...)
+	s.mu.Unlock()
+
+	var errs []error
+	for i, hook := range hooks {
+		result := make(chan error, 1)
+		go func() {
+			result <- hook.run(ctx)
+		}()
+
+		select {
+		case err := <-result:
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", hook.name, err))
+			}
+		case <-ctx.Done():
+			errs = append(errs, fmt.Errorf("%s: %w", hook.name, ctx.Err()))
+			for _, skipped := range hooks[i+1:] {
+				errs = append(errs, fmt.Errorf("%s: skipped", skipped.name))
+			}
+			return errors.Join(errs...)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,356 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// settings mirrors the configuration file. The yaml tag is the key in the file
+// and the env tag names the environment variable that overrides it. Slices
+// and maps are read from the environment as lists split on the sep tag
+// (default ","), maps as key=value pairs.
+type settings struct {
+	Server      serverSettings      `yaml:"server"`
+	Auth        authSettings        `yaml:"auth"`
+	Storage     storageSettings     `yaml:"storage"`
+	Logging     loggingSettings     `yaml:"logging"`
+	Tracing     tracingSettings     `yaml:"tracing"`
+	Limits      limitsSettings      `yaml:"limits"`
+	Fx          fxSettings          `yaml:"fx"`
+	Settlement  settlementSettings  `yaml:"settlement"`
+	Webhook     webhookSettings     `yaml:"webhook"`
+	Events      eventsSettings      `yaml:"events"`
+	History     historySettings     `yaml:"history"`
+	Audit       auditSettings       `yaml:"audit"`
+	Payments    paymentsSettings    `yaml:"payments"`
+	Maintenance maintenanceSettings `yaml:"maintenance"`
+	Reload      reloadSettings      `yaml:"reload"`
+	RateLimits  rateLimitSettings   `yaml:"rate_limits"`
+}
+
+type serverSettings struct {
+	Port                       string   `yaml:"port" env:"PORT"`
+	ReadTimeoutSeconds         int      `yaml:"read_timeout_seconds" env:"SERVER_READ_TIMEOUT_SECONDS"`
+	WriteTimeoutSeconds        int      `yaml:"write_timeout_seconds" env:"SERVER_WRITE_TIMEOUT_SECONDS"`
+	IdleTimeoutSeconds         int      `yaml:"idle_timeout_seconds" env:"SERVER_IDLE_TIMEOUT_SECONDS"`
+	ShutdownTimeoutSeconds     int      `yaml:"shutdown_timeout_seconds" env:"SHUTDOWN_TIMEOUT_SECONDS"`
+	ShutdownHookTimeoutSeconds int      `yaml:"shutdown_hook_timeout_seconds" env:"SHUTDOWN_HOOK_TIMEOUT_SECONDS"`
+	TrustedProxies             []string `yaml:"trusted_proxies" env:"TRUSTED_PROXIES"`
+}
+
+type authSettings struct {
+	SecretKey       string `yaml:"secret_key" env:"SECRET_KEY"`
+	ExpireInMinutes int    `yaml:"expire_in_minutes" env:"EXPIRE_IN_MINUTES"`
+}
+
+type storageSettings struct {
+	Backend           string               `yaml:"backend" env:"STORAGE_BACKEND"`
+	DataDir           string               `yaml:"data_dir" env:"DATA_DIR"`
+	HistoryArchiveDir string               `yaml:"history_archive_dir" env:"HISTORY_ARCHIVE_DIR"`
+	Files             storageFilesSettings `yaml:"files"`
+}
+
+// storageFilesSettings holds the data file paths. An empty path means the
+// default file name inside the data directory.
+type storageFilesSettings struct {
+	Customers           string `yaml:"customers" env:"STORAGE_CUSTOMERS_FILE"`
+	Merchants           string `yaml:"merchants" env:"STORAGE_MERCHANTS_FILE"`
+	BlacklistedTokens   string `yaml:"blacklisted_tokens" env:"STORAGE_BLACKLISTED_TOKENS_FILE"`
+	PaymentTransactions string `yaml:"payment_transactions" env:"STORAGE_PAYMENT_TRANSACTIONS_FILE"`
+	ExchangeRates       string `yaml:"exchange_rates" env:"STORAGE_EXCHANGE_RATES_FILE"`
+	History             string `yaml:"history" env:"STORAGE_HISTORY_FILE"`
+	WebhookDeliveries   string `yaml:"webhook_deliveries" env:"STORAGE_WEBHOOK_DELIVERIES_FILE"`
+	Outbox              string `yaml:"outbox" env:"STORAGE_OUTBOX_FILE"`
+	Settlements         string `yaml:"settlements" env:"STORAGE_SETTLEMENTS_FILE"`
+	AuditCheckpoints    string `yaml:"audit_checkpoints" env:"STORAGE_AUDIT_CHECKPOINTS_FILE"`
+}
+
+type loggingSettings struct {
+	Level               string   `yaml:"level" env:"LOG_LEVEL"`
+	Format              string   `yaml:"format" env:"LOG_FORMAT"`
+	Output              string   `yaml:"output" env:"LOG_OUTPUT"`
+	File                string   `yaml:"file" env:"LOG_FILE"`
+	MaxSizeMb           int      `yaml:"max_size_mb" env:"LOG_MAX_SIZE_MB"`
+	RotateIntervalHours int      `yaml:"rotate_interval_hours" env:"LOG_ROTATE_INTERVAL_HOURS"`
+	MaxBackups          int      `yaml:"max_backups" env:"LOG_MAX_BACKUPS"`
+	MaxAgeDays          int      `yaml:"max_age_days" env:"LOG_MAX_AGE_DAYS"`
+	Compress            bool     `yaml:"compress" env:"LOG_COMPRESS"`
+	RedactPatterns      []string `yaml:"redact_patterns" env:"LOG_REDACT_PATTERNS" sep:";"`
+}
+
+type tracingSettings struct {
+	Exporter     string            `yaml:"exporter" env:"TRACING_EXPORTER"`
+	File         string            `yaml:"file" env:"TRACING_FILE"`
+	SampleRatio  float64           `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO"`
+	ServiceName  string            `yaml:"service_name" env:"OTEL_SERVICE_NAME"`
+	OtlpEndpoint string            `yaml:"otlp_endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	OtlpHeaders  map[string]string `yaml:"otlp_headers" env:"OTEL_EXPORTER_OTLP_HEADERS"`
+}
+
+type limitsSettings struct {
+	RequestTimeoutSeconds int `yaml:"request_timeout_seconds" env:"REQUEST_TIMEOUT_SECONDS"`
+	ExportTimeoutSeconds  int `yaml:"export_timeout_seconds" env:"EXPORT_TIMEOUT_SECONDS"`
+}
+
+type fxSettings struct {
+	MaxRateAgeMinutes int    `yaml:"max_rate_age_minutes" env:"FX_MAX_RATE_AGE_MINUTES"`
+	SpreadBasisPoints int64  `yaml:"spread_basis_points" env:"FX_SPREAD_BASIS_POINTS"`
+	Rounding          string `yaml:"rounding" env:"FX_ROUNDING"`
+}
+
+type settlementSettings struct {
+	CutoffHour int `yaml:"cutoff_hour" env:"SETTLEMENT_CUTOFF_HOUR"`
+}
+
+type webhookSettings struct {
+	MaxAttempts         int `yaml:"max_attempts" env:"WEBHOOK_MAX_ATTEMPTS"`
+	RetryBaseSeconds    int `yaml:"retry_base_seconds" env:"WEBHOOK_RETRY_BASE_SECONDS"`
+	TimeoutSeconds      int `yaml:"timeout_seconds" env:"WEBHOOK_TIMEOUT_SECONDS"`
+	PollIntervalSeconds int `yaml:"poll_interval_seconds" env:"WEBHOOK_POLL_INTERVAL_SECONDS"`
+}
+
+type eventsSettings struct {
+	PollIntervalSeconds int `yaml:"poll_interval_seconds" env:"EVENT_POLL_INTERVAL_SECONDS"`
+}
+
+type historySettings struct {
+	QueueSize              int    `yaml:"queue_size" env:"HISTORY_QUEUE_SIZE"`
+	BatchSize              int    `yaml:"batch_size" env:"HISTORY_BATCH_SIZE"`
+	FlushIntervalMs        int    `yaml:"flush_interval_ms" env:"HISTORY_FLUSH_INTERVAL_MS"`
+	OverflowPolicy         string `yaml:"overflow_policy" env:"HISTORY_OVERFLOW_POLICY"`
+	RetentionDays          int    `yaml:"retention_days" env:"HISTORY_RETENTION_DAYS"`
+	ArchiveIntervalMinutes int    `yaml:"archive_interval_minutes" env:"HISTORY_ARCHIVE_INTERVAL_MINUTES"`
+}
+
+type auditSettings struct {
+	HmacKey                   string `yaml:"hmac_key" env:"AUDIT_HMAC_KEY"`
+	CheckpointIntervalMinutes int    `yaml:"checkpoint_interval_minutes" env:"AUDIT_CHECKPOINT_INTERVAL_MINUTES"`
+}
+
+type paymentsSettings struct {
+	MaxAmounts map[string]int64 `yaml:"max_amounts" env:"PAYMENT_MAX_AMOUNTS"`
+}
+
+type maintenanceSettings struct {
+	Enabled bool `yaml:"enabled" env:"MAINTENANCE_MODE"`
+}
+
+// rateLimitSettings are policies written as requests/window, such as "10/1m",
+// or "off".
+type rateLimitSettings struct {
+	LoginPerIp         string `yaml:"login_per_ip" env:"RATE_LIMIT_LOGIN_PER_IP"`
+	ApiPerUser         string `yaml:"api_per_user" env:"RATE_LIMIT_API_PER_USER"`
+	PaymentPerUser     string `yaml:"payment_per_user" env:"RATE_LIMIT_PAYMENT_PER_USER"`
+	PaymentPerMerchant string `yaml:"payment_per_merchant" env:"RATE_LIMIT_PAYMENT_PER_MERCHANT"`
+}
+
+type reloadSettings struct {
+	WatchIntervalSeconds int `yaml:"watch_interval_seconds" env:"CONFIG_WATCH_INTERVAL_SECONDS"`
+}
+
+func defaultSettings() settings {
+	return settings{
+		Server: serverSettings{
+			Port:                       "4000",
+			ReadTimeoutSeconds:         15,
+			IdleTimeoutSeconds:         60,
+			ShutdownTimeoutSeconds:     30,
+			ShutdownHookTimeoutSeconds: 10,
+		},
+		Auth: authSettings{
+			ExpireInMinutes: 10,
+		},
+		Storage: storageSettings{
+			Backend: StorageBackendJson,
+			DataDir: "internal/repository/data",
+		},
+		Logging: loggingSettings{
+			Level:               "info",
+			Format:              LogFormatJson,
+			Output:              LogOutputFile,
+			File:                "app_history.log",
+			MaxSizeMb:           100,
+			RotateIntervalHours: 24,
+			MaxBackups:          7,
+			MaxAgeDays:          30,
+			Compress:            true,
+		},
+		Tracing: tracingSettings{
+			Exporter:     TracingExporterNone,
+			File:         "traces.json",
+			SampleRatio:  1,
+			ServiceName:  "merchant-bank-payment-api",
+			OtlpEndpoint: "http://localhost:4318",
+		},
+		Limits: limitsSettings{
+			RequestTimeoutSeconds: 10,
+			ExportTimeoutSeconds:  120,
+		},
+		Fx: fxSettings{
+			MaxRateAgeMinutes: 24 * 60,
+			Rounding:          "HALF_EVEN",
+		},
+		Webhook: webhookSettings{
+			MaxAttempts:         8,
+			RetryBaseSeconds:    30,
+			TimeoutSeconds:      10,
+			PollIntervalSeconds: 5,
+		},
+		Events: eventsSettings{
+			PollIntervalSeconds: 5,
+		},
+		History: historySettings{
+			QueueSize:              1000,
+			BatchSize:              100,
+			FlushIntervalMs:        1000,
+			OverflowPolicy:         "BLOCK",
+			RetentionDays:          90,
+			ArchiveIntervalMinutes: 60,
+		},
+		Audit: auditSettings{
+			CheckpointIntervalMinutes: 60,
+		},
+		Reload: reloadSettings{
+			WatchIntervalSeconds: 5,
+		},
+		RateLimits: rateLimitSettings{
+			LoginPerIp:         "10/1m",
+			ApiPerUser:         "120/1m",
+			PaymentPerUser:     "30/1m",
+			PaymentPerMerchant: "600/1m",
+		},
+	}
+}
+
+// readFile applies the YAML file at path over s. Unknown keys and values of
+// the wrong type are all reported, not just the first one.
+func (s *settings) readFile(path string) []error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return []error{fmt.Errorf("error reading config file: %w", err)}
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	err = decoder.Decode(s)
+	if err == nil || errors.Is(err, io.EOF) {
+		return nil
+	}
+
+	var typeErr *yaml.TypeError
+	if !errors.As(err, &typeErr) {
+		return []error{fmt.Errorf("error parsing config file %s: %w", path, err)}
+	}
+	errs := make([]error, 0, len(typeErr.Errors))
+	for _, message := range typeErr.Errors {
+		errs = append(errs, fmt.Errorf("config file %s: %s", path, message))
+	}
+	return errs
+}
+
+// applyEnv overrides every setting whose environment variable is set and not
+// empty.
+func (s *settings) applyEnv() []error {
+	var errs []error
+	walkSettings(reflect.ValueOf(s).Elem(), "", func(key string, field reflect.StructField, value reflect.Value) {
+		env := field.Tag.Get("env")
+		raw, ok := os.LookupEnv(env)
+		if !ok || strings.TrimSpace(raw) == "" {
+			return
+		}
+		if err := setFromEnv(value, strings.TrimSpace(raw), field.Tag.Get("sep")); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", env, err))
+		}
+	})
+	return errs
+}
+
+// envNames maps every file key, such as "server.port", to its environment
+// variable.
+func envNames() map[string]string {
+	names := map[string]string{}
+	walkSettings(reflect.ValueOf(&settings{}).Elem(), "", func(key string, field reflect.StructField, _ reflect.Value) {
+		names[key] = field.Tag.Get("env")
+	})
+	return names
+}
+
+func walkSettings(value reflect.Value, prefix string, visit func(key string, field reflect.StructField, value reflect.Value)) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		key := field.Tag.Get("yaml")
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		if field.Tag.Get("env") == "" && field.Type.Kind() == reflect.Struct {
+			walkSettings(value.Field(i), key, visit)
+			continue
+		}
+		visit(key, field, value.Field(i))
+	}
+}
+
+func setFromEnv(value reflect.Value, raw, sep string) error {
+	if sep == "" {
+		sep = ","
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Int, reflect.Int64:
+		parsed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a whole number", raw)
+		}
+		value.SetInt(parsed)
+	case reflect.Float64:
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", raw)
+		}
+		value.SetFloat(parsed)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not true or false", raw)
+		}
+		value.SetBool(parsed)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(raw, sep) {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		value.Set(reflect.ValueOf(items))
+	case reflect.Map:
+		pairs := reflect.MakeMap(value.Type())
+		for _, pair := range strings.Split(raw, sep) {
+			if strings.TrimSpace(pair) == "" {
+				continue
+			}
+			key, item, found := strings.Cut(pair, "=")
+			if !found || strings.TrimSpace(key) == "" {
+				return fmt.Errorf("%q is not a key=value pair", pair)
+			}
+			parsed := reflect.New(value.Type().Elem()).Elem()
+			if err := setFromEnv(parsed, strings.TrimSpace(item), sep); err != nil {
+				return fmt.Errorf("%s: %w", strings.TrimSpace(key), err)
+			}
+			pairs.SetMapIndex(reflect.ValueOf(strings.TrimSpace(key)), parsed)
+		}
+		value.Set(pairs)
+	default:
+		return fmt.Errorf("unsupported setting type %s", value.Type())
+	}
+	return nil
+}
@@ -22,11 +22,11 @@ func NewAuthenticationController(logger *logrus.Logger, authUseCase usecase.Auth
 
 func (ac *AuthenticationController) Login(c *gin.Context) {
 	var loginRequest model.LoginRequest
-	ac.Log.Debug("Attempting login for user")
+	ac.Log.WithContext(c.Request.Context()).Debug("Attempting login for user")
 
 	err := c.ShouldBind(&loginRequest)
 	if err != nil {
-		ac.Log.Errorf("Invalid login request: %v", err)
+		ac.Log.WithContext(c.Request.Context()).Errorf("Invalid login request: %v", err)
 		c.JSON(http.StatusBadRequest, model.CommonResponse[interface{}]{
 			HttpStatus: http.StatusBadRequest,
 			Message:    "Invalid body request",
@@ -35,9 +35,9 @@ func (ac *AuthenticationController) Login(c *gin.Context) {
 		return
 	}
 
-	token, err := ac.AuthUseCase.Login(loginRequest)
+	token, err := ac.AuthUseCase.Login(c.Request.Context(), loginRequest)
 	if err != nil {
-		ac.Log.Errorf("Login failed for user %s: %v", loginRequest.Username, err)
+		ac.Log.WithContext(c.Request.Context()).Errorf("Login failed for user %s: %v", loginRequest.Username, err)
 		c.JSON(http.StatusUnauthorized, model.CommonResponse[interface{}]{
 			HttpStatus: http.StatusUnauthorized,
 			Message:    err.Error(),
@@ -46,7 +46,7 @@ func (ac *AuthenticationController) Login(c *gin.Context) {
 		return
 	}
 
-	ac.Log.Infof("Successful login for user: %s", loginRequest.Username)
+	ac.Log.WithContext(c.Request.Context()).Infof("Successful login for user: %s", loginRequest.Username)
 	c.JSON(http.StatusOK, model.CommonResponse[model.LoginResponse]{
 		HttpStatus: http.StatusOK,
 		Message:    "Successfully logged in",
@@ -55,11 +55,11 @@ func (ac *AuthenticationController) Login(c *gin.Context) {
 }
 
 func (ac *AuthenticationController) Logout(c *gin.Context) {
-	ac.Log.Debug("Attempting lgoout for user")
+	ac.Log.WithContext(c.Request.Context()).Debug("Attempting lgoout for user")
 
 	token, exists := c.Get("token")
 	if !exists {
-		ac.Log.Warn("Token not found in context")
+		ac.Log.WithContext(c.Request.Context()).Warn("Token not found in context")
 		c.JSON(http.StatusUnauthorized, model.CommonResponse[interface{}]{
 			HttpStatus: http.StatusUnauthorized,
 			Message:    "Token not found",
@@ -69,9 +69,9 @@ func (ac *AuthenticationController) Logout(c *gin.Context) {
 	}
 
 	tokenString, _ := token.(string)
-	err := ac.AuthUseCase.Logout(tokenString)
+	err := ac.AuthUseCase.Logout(c.Request.Context(), tokenString)
 	if err != nil {
-		ac.Log.Warn("Error during logout")
+		ac.Log.WithContext(c.Request.Context()).Warn("Error during logout")
 		c.JSON(http.StatusUnauthorized, model.CommonResponse[interface{}]{
 			HttpStatus: http.StatusUnauthorized,
 			Message:    err.Error(),
@@ -80,7 +80,8 @@ func (ac *AuthenticationController) Logout(c *gin.Context) {
 		return
 	}
 
-	ac.Log.Infof("Successfully logged out and blacklisted token for user: %s", tokenString)
+	userId, _ := c.Get("user_id")
+	ac.Log.WithContext(c.Request.Context()).Infof("Successfully logged out and blacklisted token for user: %v", userId)
 	c.JSON(http.StatusOK, model.CommonResponse[interface{}]{
 		HttpStatus: http.StatusOK,
 		Message:    "Successfully logged out",
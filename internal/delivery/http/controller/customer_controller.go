@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/internal/usecase"
+	"net/http"
+)
+
+type CustomerController struct {
+	Log             *logrus.Logger
+	CustomerUseCase usecase.CustomerUseCase
+}
+
+func NewCustomerController(log *logrus.Logger, customerUseCase usecase.CustomerUseCase) *CustomerController {
+	return &CustomerController{
+		Log:             log,
+		CustomerUseCase: customerUseCase,
+	}
+}
+
+func (cc *CustomerController) GetCustomers(c *gin.Context) {
+	var customerRequest model.CustomerQueryRequest
+	cc.Log.WithContext(c.Request.Context()).Debug("Attempting to get customers")
+
+	if err := c.ShouldBindQuery(&customerRequest); err != nil {
+		cc.Log.WithContext(c.Request.Context()).Warnf("Invalid customer query: %v", err)
+		c.JSON(http.StatusBadRequest, model.CommonResponse[interface{}]{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "Invalid query parameters",
+			Data:       nil,
+		})
+		return
+	}
+
+	page, err := cc.CustomerUseCase.GetCustomers(c.Request.Context(), customerRequest)
+	if err != nil {
+		cc.Log.WithContext(c.Request.Context()).Errorf("Error getting customers: %v", err)
+		c.JSON(http.StatusBadRequest, model.CommonResponse[interface{}]{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+			Data:       nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.CommonResponse[model.CustomerPage]{
+		HttpStatus: http.StatusOK,
+		Message:    "Successfully got customers",
+		Data:       page,
+	})
+}
+
+func (cc *CustomerController) GetCustomerDetail(c *gin.Context) {
+	cc.Log.WithContext(c.Request.Context()).Debug("Attempting to get customer detail")
+
+	userId, _ := c.Get("user_id")
+	adminId, _ := userId.(string)
+	detail, err := cc.CustomerUseCase.GetCustomerDetail(c.Request.Context(), adminId, c.Param("id"))
+	if err != nil {
+		cc.Log.WithContext(c.Request.Context()).Warnf("Error getting customer detail: %v", err)
+		c.JSON(http.StatusNotFound, model.CommonResponse[interface{}]{
+			HttpStatus: http.StatusNotFound,
+			Message:    "Customer not found",
+			Data:       nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.CommonResponse[model.CustomerDetailResponse]{
+		HttpStatus: http.StatusOK,
+		Message:    "Successfully got customer",
+		Data:       detail,
+	})
+}
+
+func (cc *CustomerController) DisableCustomer(c *gin.Context) {
+	cc.Log.WithContext(c.Request.Context()).Debug("Attempting to disable customer")
+
+	userId, _ := c.Get("user_id")
+	adminId, _ := userId.(string)
+	customer, err := cc.CustomerUseCase.DisableCustomer(c.Request.Context(), adminId, c.Param("id"))
+	if err != nil {
+		cc.respondError(c, "Error disabling customer", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.CommonResponse[model.CustomerResponse]{
+		HttpStatus: http.StatusOK,
+		Message:    "Successfully disabled customer",
+		Data:       customer,
+	})
+}
+
+func (cc *CustomerController) EnableCustomer(c *gin.Context) {
+	cc.Log.WithContext(c.Request.Context()).Debug("Attempting to enable customer")
+
+	userId, _ := c.Get("user_id")
+	adminId, _ := userId.(string)
+	customer, err := cc.CustomerUseCase.EnableCustomer(c.Request.Context(), adminId, c.Param("id"))
+	if err != nil {
+		cc.respondError(c, "Error enabling customer", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.CommonResponse[model.CustomerResponse]{
+		HttpStatus: http.StatusOK,
+		Message:    "Successfully enabled customer",
+		Data:       customer,
+	})
+}
+
+func (cc *CustomerController) ForceLogout(c *gin.Context) {
+	cc.Log.WithContext(c.Request.Context()).Debug("Attempting to force logout customer")
+
+	userId, _ := c.Get("user_id")
+	adminId, _ := userId.(string)
+	customer, err := cc.CustomerUseCase.ForceLogout(c.Request.Context(), adminId, c.Param("id"))
+	if err != nil {
+		cc.respondError(c, "Error forcing logout", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.CommonResponse[model.CustomerResponse]{
+		HttpStatus: http.StatusOK,
+		Message:    "Successfully logged out customer",
+		Data:       customer,
+	})
+}
+
+func (cc *CustomerController) respondError(c *gin.Context, message string, err error) {
+	cc.Log.WithContext(c.Request.Context()).Warnf("%s: %v", message, err)
+	c.JSON(http.StatusBadRequest, model.CommonResponse[interface{}]{
+		HttpStatus: http.StatusBadRequest,
+		Message:    err.Error(),
+		Data:       nil,
+	})
+}
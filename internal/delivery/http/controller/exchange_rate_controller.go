@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/internal/usecase"
+	"net/http"
+)
+
+type ExchangeRateController struct {
+	Log                 *logrus.Logger
+	ExchangeRateUseCase usecase.ExchangeRateUseCase
+}
+
+func NewExchangeRateController(log *logrus.Logger, exchangeRateUseCase usecase.ExchangeRateUseCase) *ExchangeRateController {
+	return &ExchangeRateController{
+		Log:                 log,
+		ExchangeRateUseCase: exchangeRateUseCase,
+	}
+}
+
+func (e *ExchangeRateController) GetRates(c *gin.Context) {
+	e.Log.WithContext(c.Request.Context()).Debug("Attempting to get exchange rates")
+
+	rates, err := e.ExchangeRateUseCase.GetRates(c.Request.Context())
+	if err != nil {
+		e.Log.WithContext(c.Request.Context()).Errorf("Error getting exchange rates: %v", err)
+		c.JSON(http.StatusInternalServerError, model.CommonResponse[interface{}]{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    "Failed to get exchange rates",
+			Data:       nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.CommonResponse[[]model.ExchangeRateResponse]{
+		HttpStatus: http.StatusOK,
+		Message:    "Successfully got exchange rates",
+		Data:       rates,
+	})
+}
+
+func (e *ExchangeRateController) ReplaceRates(c *gin.Context) {
+	var exchangeRateRequest model.ExchangeRateRequest
+	e.Log.WithContext(c.Request.Context()).Debug("Attempting to replace exchange rates")
+
+	if err := c.ShouldBindJSON(&exchangeRateRequest); err != nil {
+		e.Log.WithContext(c.Request.Context()).Warnf("Invalid exchange rate body request: %v", err)
+		c.JSON(http.StatusBadRequest, model.CommonResponse[interface{}]{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "Invalid body request",
+			Data:       nil,
+		})
+		return
+	}
+
+	userId, _ := c.Get("user_id")
+	adminId, _ := userId.(string)
+	rates, err := e.ExchangeRateUseCase.ReplaceRates(c.Request.Context(), adminId, exchangeRateRequest)
+	if err != nil {
+		e.Log.WithContext(c.Request.Context()).Warnf("Error replacing exchange rates: %v", err)
+		c.JSON(http.StatusBadRequest, model.CommonResponse[interface{}]{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+			Data:       nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.CommonResponse[[]model.ExchangeRateResponse]{
+		HttpStatus: http.StatusOK,
+		Message:    "Successfully replaced exchange rates",
+		Data:       rates,
+	})
+}
@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/internal/usecase"
+	"merchant_bank_payment_go_api/internal/usecase/impl"
+	"net/http"
+)
+
+type HealthController struct {
+	Log           *logrus.Logger
+	HealthUseCase usecase.HealthUseCase
+}
+
+func NewHealthController(log *logrus.Logger, healthUseCase usecase.HealthUseCase) *HealthController {
+	return &HealthController{
+		Log:           log,
+		HealthUseCase: healthUseCase,
+	}
+}
+
+// Healthz only tells that the process is serving requests; it checks nothing
+// else, so a slow data file never gets the process restarted.
+func (h *HealthController) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, model.CommonResponse[interface{}]{
+		HttpStatus: http.StatusOK,
+		Message:    "Service is alive",
+		Data:       nil,
+	})
+}
+
+func (h *HealthController) Readyz(c *gin.Context) {
+	readiness := h.HealthUseCase.CheckReadiness(c.Request.Context())
+	if readiness.Status != impl.HealthStatusUp {
+		c.JSON(http.StatusServiceUnavailable, model.CommonResponse[model.ReadinessResponse]{
+			HttpStatus: http.StatusServiceUnavailable,
+			Message:    "Service is not ready",
+			Data:       readiness,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.CommonResponse[model.ReadinessResponse]{
+		HttpStatus: http.StatusOK,
+		Message:    "Service is ready",
+		Data:       readiness,
+	})
+}
+
+func (h *HealthController) Version(c *gin.Context) {
+	c.JSON(http.StatusOK, model.CommonResponse[model.VersionResponse]{
+		HttpStatus: http.StatusOK,
+		Message:    "Successfully got version",
+		Data:       h.HealthUseCase.GetVersion(),
+	})
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/internal/usecase"
+	"net/http"
+	"time"
+)
+
+type HistoryArchiveController struct {
+	Log                   *logrus.Logger
+	HistoryArchiveUseCase usecase.HistoryArchiveUseCase
+}
+
+func NewHistoryArchiveController(log *logrus.Logger, historyArchiveUseCase usecase.HistoryArchiveUseCase) *HistoryArchiveController {
+	return &HistoryArchiveController{
+		Log:                   log,
+		HistoryArchiveUseCase: historyArchiveUseCase,
+	}
+}
+
+// ArchiveHistory runs the archival inside the server, which is the only
+// process writing History.json.
+func (h *HistoryArchiveController) ArchiveHistory(c *gin.Context) {
+	var archiveRequest model.HistoryArchiveRequest
+	h.Log.WithContext(c.Request.Context()).Debug("Attempting to archive history")
+
+	if err := c.ShouldBindQuery(&archiveRequest); err != nil {
+		h.Log.WithContext(c.Request.Context()).Warnf("Invalid history archive query: %v", err)
+		c.JSON(http.StatusBadRequest, model.CommonResponse[interface{}]{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "Invalid query parameters",
+			Data:       nil,
+		})
+		return
+	}
+
+	userId, _ := c.Get("user_id")
+	adminId, _ := userId.(string)
+	olderThan := time.Duration(archiveRequest.OlderThanDays) * 24 * time.Hour
+	archive, err := h.HistoryArchiveUseCase.ArchiveNow(c.Request.Context(), adminId, olderThan)
+	if err != nil {
+		h.Log.WithContext(c.Request.Context()).Errorf("Error archiving history: %v", err)
+		c.JSON(http.StatusBadRequest, model.CommonResponse[interface{}]{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+			Data:       nil,
+		})
+		return
+	}
+
+	if archive == nil {
+		c.JSON(http.StatusOK, model.CommonResponse[interface{}]{
+			HttpStatus: http.StatusOK,
+			Message:    "No history to archive",
+			Data:       nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.CommonResponse[model.HistoryArchiveResponse]{
+		HttpStatus: http.StatusOK,
+		Message:    "Successfully archived history",
+		Data: model.HistoryArchiveResponse{
+			Id:            archive.Id.String(),
+			Filename:      archive.Filename,
+			From:          archive.From,
+			To:            archive.To,
+			Records:       archive.Records,
+			FirstSequence: archive.FirstSequence,
+			LastSequence:  archive.LastSequence,
+			CreatedAt:     archive.CreatedAt,
+		},
+	})
+}
@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/internal/usecase"
+	"net/http"
+	"strings"
+)
+
+type HistoryController struct {
+	Log            *logrus.Logger
+	HistoryUseCase usecase.HistoryUseCase
+}
+
+func NewHistoryController(log *logrus.Logger, historyUseCase usecase.HistoryUseCase) *HistoryController {
+	return &HistoryController{
+		Log:            log,
+		HistoryUseCase: historyUseCase,
+	}
+}
+
+func (h *HistoryController) GetMyHistory(c *gin.Context) {
+	var historyRequest model.HistoryQueryRequest
+	h.Log.WithContext(c.Request.Context()).Debug("Attempting to get customer history")
+
+	if err := c.ShouldBindQuery(&historyRequest); err != nil {
+		h.respondInvalidQuery(c, err)
+		return
+	}
+
+	userId, _ := c.Get("user_id")
+	customerId, _ := userId.(string)
+	page, err := h.HistoryUseCase.GetCustomerHistory(c.Request.Context(), customerId, historyRequest)
+	if err != nil {
+		h.Log.WithContext(c.Request.Context()).Errorf("Error getting customer history: %v", err)
+		c.JSON(http.StatusBadRequest, model.CommonResponse[interface{}]{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+			Data:       nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.CommonResponse[model.HistoryPage]{
+		HttpStatus: http.StatusOK,
+		Message:    "Successfully retrieved history",
+		Data:       page,
+	})
+}
+
+func (h *HistoryController) SearchHistory(c *gin.Context) {
+	var historyRequest model.HistoryQueryRequest
+	h.Log.WithContext(c.Request.Context()).Debug("Attempting to search history")
+
+	if err := c.ShouldBindQuery(&historyRequest); err != nil {
+		h.respondInvalidQuery(c, err)
+		return
+	}
+
+	userId, _ := c.Get("user_id")
+	adminId, _ := userId.(string)
+
+	if strings.EqualFold(historyRequest.Format, "csv") {
+		h.exportHistory(c, adminId, historyRequest)
+		return
+	}
+
+	page, err := h.HistoryUseCase.SearchHistory(c.Request.Context(), adminId, historyRequest)
+	if err != nil {
+		h.Log.WithContext(c.Request.Context()).Errorf("Error searching history: %v", err)
+		c.JSON(http.StatusBadRequest, model.CommonResponse[interface{}]{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+			Data:       nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.CommonResponse[model.HistoryPage]{
+		HttpStatus: http.StatusOK,
+		Message:    "Successfully retrieved history",
+		Data:       page,
+	})
+}
+
+func (h *HistoryController) exportHistory(c *gin.Context, adminId string, historyRequest model.HistoryQueryRequest) {
+	writer := &reportResponseWriter{
+		writer:      c.Writer,
+		contentType: "text/csv; charset=utf-8",
+		filename:    "history.csv",
+	}
+
+	err := h.HistoryUseCase.ExportHistory(c.Request.Context(), adminId, historyRequest, writer)
+	if err != nil {
+		if writer.started {
+			h.Log.WithContext(c.Request.Context()).Errorf("History export aborted after streaming started: %v", err)
+			c.Abort()
+			return
+		}
+
+		h.Log.WithContext(c.Request.Context()).Warnf("Error exporting history: %v", err)
+		c.JSON(http.StatusBadRequest, model.CommonResponse[interface{}]{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+			Data:       nil,
+		})
+		return
+	}
+
+	h.Log.WithContext(c.Request.Context()).Info("Successfully exported history")
+}
+
+func (h *HistoryController) respondInvalidQuery(c *gin.Context, err error) {
+	h.Log.WithContext(c.Request.Context()).Errorf("Invalid history query: %v", err)
+	c.JSON(http.StatusBadRequest, model.CommonResponse[interface{}]{
+		HttpStatus: http.StatusBadRequest,
+		Message:    "Invalid query parameters",
+		Data:       nil,
+	})
+}
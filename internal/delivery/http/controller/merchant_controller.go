@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/internal/usecase"
+	"net/http"
+)
+
+type MerchantController struct {
+	Log             *logrus.Logger
+	MerchantUseCase usecase.MerchantUseCase
+}
+
+func NewMerchantController(log *logrus.Logger, merchantUseCase usecase.MerchantUseCase) *MerchantController {
+	return &MerchantController{
+		Log:             log,
+		MerchantUseCase: merchantUseCase,
+	}
+}
+
+func (m *MerchantController) GetMerchants(c *gin.Context) {
+	var merchantRequest model.MerchantQueryRequest
+	m.Log.WithContext(c.Request.Context()).Debug("Attempting to get merchants")
+
+	if err := c.ShouldBindQuery(&merchantRequest); err != nil {
+		m.Log.WithContext(c.Request.Context()).Warnf("Invalid merchant query: %v", err)
+		c.JSON(http.StatusBadRequest, model.CommonResponse[interface{}]{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "Invalid query parameters",
+			Data:       nil,
+		})
+		return
+	}
+
+	page, err := m.MerchantUseCase.GetMerchants(c.Request.Context(), merchantRequest)
+	if err != nil {
+		m.Log.WithContext(c.Request.Context()).Errorf("Error getting merchants: %v", err)
+		c.JSON(http.StatusBadRequest, model.CommonResponse[interface{}]{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+			Data:       nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.CommonResponse[model.MerchantPage]{
+		HttpStatus: http.StatusOK,
+		Message:    "Successfully got merchants",
+		Data:       page,
+	})
+}
+
+func (m *MerchantController) CreateMerchant(c *gin.Context) {
+	var merchantRequest model.MerchantRequest
+	m.Log.WithContext(c.Request.Context()).Debug("Attempting to create merchant")
+
+	if err := c.ShouldBindJSON(&merchantRequest); err != nil {
+		m.respondInvalidBody(c, err)
+		return
+	}
+
+	userId, _ := c.Get("user_id")
+	adminId, _ := userId.(string)
+	merchant, err := m.MerchantUseCase.CreateMerchant(c.Request.Context(), adminId, merchantRequest)
+	if err != nil {
+		m.respondError(c, "Error creating merchant", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.CommonResponse[model.MerchantResponse]{
+		HttpStatus: http.StatusCreated,
+		Message:    "Successfully created merchant",
+		Data:       merchant,
+	})
+}
+
+func (m *MerchantController) UpdateMerchant(c *gin.Context) {
+	var merchantRequest model.MerchantRequest
+	m.Log.WithContext(c.Request.Context()).Debug("Attempting to update merchant")
+
+	if err := c.ShouldBindJSON(&merchantRequest); err != nil {
+		m.respondInvalidBody(c, err)
+		return
+	}
+
+	userId, _ := c.Get("user_id")
+	adminId, _ := userId.(string)
+	merchant, err := m.MerchantUseCase.UpdateMerchant(c.Request.Context(), adminId, c.Param("id"), merchantRequest)
+	if err != nil {
+		m.respondError(c, "Error updating merchant", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.CommonResponse[model.MerchantResponse]{
+		HttpStatus: http.StatusOK,
+		Message:    "Successfully updated merchant",
+		Data:       merchant,
+	})
+}
+
+func (m *MerchantController) SuspendMerchant(c *gin.Context) {
+	m.Log.WithContext(c.Request.Context()).Debug("Attempting to suspend merchant")
+
+	userId, _ := c.Get("user_id")
+	adminId, _ := userId.(string)
+	merchant, err := m.MerchantUseCase.SuspendMerchant(c.Request.Context(), adminId, c.Param("id"))
+	if err != nil {
+		m.respondError(c, "Error suspending merchant", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.CommonResponse[model.MerchantResponse]{
+		HttpStatus: http.StatusOK,
+		Message:    "Successfully suspended merchant",
+		Data:       merchant,
+	})
+}
+
+func (m *MerchantController) ReactivateMerchant(c *gin.Context) {
+	m.Log.WithContext(c.Request.Context()).Debug("Attempting to reactivate merchant")
+
+	userId, _ := c.Get("user_id")
+	adminId, _ := userId.(string)
+	merchant, err := m.MerchantUseCase.ReactivateMerchant(c.Request.Context(), adminId, c.Param("id"))
+	if err != nil {
+		m.respondError(c, "Error reactivating merchant", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.CommonResponse[model.MerchantResponse]{
+		HttpStatus: http.StatusOK,
+		Message:    "Successfully reactivated merchant",
+		Data:       merchant,
+	})
+}
+
+func (m *MerchantController) respondInvalidBody(c *gin.Context, err error) {
+	m.Log.WithContext(c.Request.Context()).Warnf("Invalid merchant body request: %v", err)
+	c.JSON(http.StatusBadRequest, model.CommonResponse[interface{}]{
+		HttpStatus: http.StatusBadRequest,
+		Message:    "Invalid body request",
+		Data:       nil,
+	})
+}
+
+func (m *MerchantController) respondError(c *gin.Context, message string, err error) {
+	m.Log.WithContext(c.Request.Context()).Warnf("%s: %v", message, err)
+	c.JSON(http.StatusBadRequest, model.CommonResponse[interface{}]{
+		HttpStatus: http.StatusBadRequest,
+		Message:    err.Error(),
+		Data:       nil,
+	})
+}
@@ -22,11 +22,11 @@ func NewPaymentTransactionController(log *logrus.Logger, paymentUseCase usecase.
 
 func (p *PaymentTransactionController) AddPayment(c *gin.Context) {
 	var paymentRequest model.PaymentRequest
-	p.Log.Debug("Attempting to add payment request")
+	p.Log.WithContext(c.Request.Context()).Debug("Attempting to add payment request")
 
 	err := c.ShouldBind(&paymentRequest)
 	if err != nil {
-		p.Log.Errorf("Invalid payment body request: %v", err)
+		p.Log.WithContext(c.Request.Context()).Errorf("Invalid payment body request: %v", err)
 		c.JSON(http.StatusBadRequest, model.CommonResponse[interface{}]{
 			HttpStatus: http.StatusBadRequest,
 			Message:    "Invalid body request",
@@ -37,7 +37,7 @@ func (p *PaymentTransactionController) AddPayment(c *gin.Context) {
 
 	userId, exists := c.Get("user_id")
 	if !exists {
-		p.Log.Warn("User ID not found in context")
+		p.Log.WithContext(c.Request.Context()).Warn("User ID not found in context")
 		c.JSON(http.StatusUnauthorized, model.CommonResponse[interface{}]{
 			HttpStatus: http.StatusUnauthorized,
 			Message:    "User ID not found",
@@ -46,9 +46,9 @@ func (p *PaymentTransactionController) AddPayment(c *gin.Context) {
 		return
 	}
 
-	err = p.PaymentUseCase.AddPayment(userId.(string), paymentRequest)
+	err = p.PaymentUseCase.AddPayment(c.Request.Context(), userId.(string), paymentRequest)
 	if err != nil {
-		p.Log.Warnf("Error adding payment: %v", err)
+		p.Log.WithContext(c.Request.Context()).Warnf("Error adding payment: %v", err)
 		c.JSON(http.StatusBadRequest, model.CommonResponse[interface{}]{
 			HttpStatus: http.StatusBadRequest,
 			Message:    err.Error(),
@@ -57,10 +57,76 @@ func (p *PaymentTransactionController) AddPayment(c *gin.Context) {
 		return
 	}
 
-	p.Log.Infof("Successfully added payment")
+	p.Log.WithContext(c.Request.Context()).Infof("Successfully added payment")
 	c.JSON(http.StatusOK, model.CommonResponse[interface{}]{
 		HttpStatus: http.StatusOK,
 		Message:    "Successfully added payment",
 		Data:       nil,
 	})
 }
+
+func (p *PaymentTransactionController) GetPayments(c *gin.Context) {
+	p.Log.WithContext(c.Request.Context()).Debug("Attempting to get payments")
+
+	userId, exists := c.Get("user_id")
+	if !exists {
+		p.Log.WithContext(c.Request.Context()).Warn("User ID not found in context")
+		c.JSON(http.StatusUnauthorized, model.CommonResponse[interface{}]{
+			HttpStatus: http.StatusUnauthorized,
+			Message:    "User ID not found",
+			Data:       nil,
+		})
+		return
+	}
+
+	payments, err := p.PaymentUseCase.GetPayments(c.Request.Context(), userId.(string))
+	if err != nil {
+		p.Log.WithContext(c.Request.Context()).Errorf("Error getting payments: %v", err)
+		c.JSON(http.StatusInternalServerError, model.CommonResponse[interface{}]{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    "Failed to get payments",
+			Data:       nil,
+		})
+		return
+	}
+
+	p.Log.WithContext(c.Request.Context()).Infof("Successfully got %d payments", len(payments))
+	c.JSON(http.StatusOK, model.CommonResponse[[]model.PaymentResponse]{
+		HttpStatus: http.StatusOK,
+		Message:    "Successfully got payments",
+		Data:       payments,
+	})
+}
+
+func (p *PaymentTransactionController) GetPaymentById(c *gin.Context) {
+	p.Log.WithContext(c.Request.Context()).Debug("Attempting to get payment by id")
+
+	userId, exists := c.Get("user_id")
+	if !exists {
+		p.Log.WithContext(c.Request.Context()).Warn("User ID not found in context")
+		c.JSON(http.StatusUnauthorized, model.CommonResponse[interface{}]{
+			HttpStatus: http.StatusUnauthorized,
+			Message:    "User ID not found",
+			Data:       nil,
+		})
+		return
+	}
+
+	payment, err := p.PaymentUseCase.GetPaymentById(c.Request.Context(), userId.(string), c.Param("id"))
+	if err != nil {
+		p.Log.WithContext(c.Request.Context()).Warnf("Error getting payment: %v", err)
+		c.JSON(http.StatusNotFound, model.CommonResponse[interface{}]{
+			HttpStatus: http.StatusNotFound,
+			Message:    "Payment not found",
+			Data:       nil,
+		})
+		return
+	}
+
+	p.Log.WithContext(c.Request.Context()).Infof("Successfully got payment %s", payment.Id)
+	c.JSON(http.StatusOK, model.CommonResponse[model.PaymentResponse]{
+		HttpStatus: http.StatusOK,
+		Message:    "Successfully got payment",
+		Data:       payment,
+	})
+}
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/internal/usecase"
+	"merchant_bank_payment_go_api/internal/usecase/impl"
+	"net/http"
+	"strings"
+)
+
+type ReportController struct {
+	Log           *logrus.Logger
+	ReportUseCase usecase.ReportUseCase
+}
+
+func NewReportController(log *logrus.Logger, reportUseCase usecase.ReportUseCase) *ReportController {
+	return &ReportController{
+		Log:           log,
+		ReportUseCase: reportUseCase,
+	}
+}
+
+// reportResponseWriter defers the download headers until the report writes its
+// first byte, so validation errors can still be answered with a JSON body.
+type reportResponseWriter struct {
+	writer      gin.ResponseWriter
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (r *reportResponseWriter) Write(data []byte) (int, error) {
+	if !r.started {
+		r.started = true
+		r.writer.Header().Set("Content-Type", r.contentType)
+		r.writer.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", r.filename))
+		r.writer.WriteHeader(http.StatusOK)
+	}
+	return r.writer.Write(data)
+}
+
+func (r *ReportController) ExportReconciliation(c *gin.Context) {
+	var reportRequest model.ReconciliationReportRequest
+	r.Log.WithContext(c.Request.Context()).Debug("Attempting to export reconciliation report")
+
+	if err := c.ShouldBindQuery(&reportRequest); err != nil {
+		r.Log.WithContext(c.Request.Context()).Errorf("Invalid reconciliation report query: %v", err)
+		c.JSON(http.StatusBadRequest, model.CommonResponse[interface{}]{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "Invalid query parameters",
+			Data:       nil,
+		})
+		return
+	}
+
+	format := strings.ToLower(reportRequest.Format)
+	contentType := "text/csv; charset=utf-8"
+	if format == impl.ReportFormatJson {
+		contentType = "application/json; charset=utf-8"
+	} else if format == "" {
+		format = impl.ReportFormatCsv
+	}
+
+	writer := &reportResponseWriter{
+		writer:      c.Writer,
+		contentType: contentType,
+		filename: fmt.Sprintf("reconciliation_%s_%s_%s.%s", reportRequest.MerchantId, reportRequest.From,
+			reportRequest.To, format),
+	}
+
+	err := r.ReportUseCase.ExportReconciliation(c.Request.Context(), reportRequest, writer)
+	if err != nil {
+		if writer.started {
+			r.Log.WithContext(c.Request.Context()).Errorf("Reconciliation report aborted after streaming started: %v", err)
+			c.Abort()
+			return
+		}
+
+		r.Log.WithContext(c.Request.Context()).Warnf("Error exporting reconciliation report: %v", err)
+		c.JSON(http.StatusBadRequest, model.CommonResponse[interface{}]{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+			Data:       nil,
+		})
+		return
+	}
+
+	r.Log.WithContext(c.Request.Context()).Infof("Successfully exported reconciliation report for merchant %s", reportRequest.MerchantId)
+}
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/internal/usecase"
+	"net/http"
+	"time"
+)
+
+type SettlementController struct {
+	Log               *logrus.Logger
+	SettlementUseCase usecase.SettlementUseCase
+}
+
+func NewSettlementController(log *logrus.Logger, settlementUseCase usecase.SettlementUseCase) *SettlementController {
+	return &SettlementController{
+		Log:               log,
+		SettlementUseCase: settlementUseCase,
+	}
+}
+
+func (s *SettlementController) RunSettlement(c *gin.Context) {
+	var settlementRequest model.SettlementRequest
+	s.Log.WithContext(c.Request.Context()).Debug("Attempting to run settlement")
+
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&settlementRequest); err != nil {
+			s.Log.WithContext(c.Request.Context()).Errorf("Invalid settlement body request: %v", err)
+			c.JSON(http.StatusBadRequest, model.CommonResponse[interface{}]{
+				HttpStatus: http.StatusBadRequest,
+				Message:    "Invalid body request",
+				Data:       nil,
+			})
+			return
+		}
+	}
+
+	cutoff := time.Now()
+	if settlementRequest.Cutoff != nil {
+		cutoff = *settlementRequest.Cutoff
+	}
+
+	userId, _ := c.Get("user_id")
+	adminId, _ := userId.(string)
+	settlements, err := s.SettlementUseCase.RunSettlement(c.Request.Context(), adminId, cutoff)
+	if err != nil {
+		s.Log.WithContext(c.Request.Context()).Errorf("Error running settlement: %v", err)
+		c.JSON(http.StatusInternalServerError, model.CommonResponse[interface{}]{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+			Data:       nil,
+		})
+		return
+	}
+
+	s.Log.WithContext(c.Request.Context()).Infof("Successfully created %d settlement batches", len(settlements))
+	c.JSON(http.StatusOK, model.CommonResponse[[]model.SettlementResponse]{
+		HttpStatus: http.StatusOK,
+		Message:    "Successfully ran settlement",
+		Data:       settlements,
+	})
+}
+
+func (s *SettlementController) GetSettlements(c *gin.Context) {
+	s.Log.WithContext(c.Request.Context()).Debug("Attempting to get settlements")
+
+	settlements, err := s.SettlementUseCase.GetSettlements(c.Request.Context(), c.Query("merchantId"))
+	if err != nil {
+		s.Log.WithContext(c.Request.Context()).Errorf("Error getting settlements: %v", err)
+		c.JSON(http.StatusBadRequest, model.CommonResponse[interface{}]{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+			Data:       nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.CommonResponse[[]model.SettlementResponse]{
+		HttpStatus: http.StatusOK,
+		Message:    "Successfully got settlements",
+		Data:       settlements,
+	})
+}
+
+func (s *SettlementController) GetSettlementById(c *gin.Context) {
+	s.Log.WithContext(c.Request.Context()).Debug("Attempting to get settlement by id")
+
+	settlement, err := s.SettlementUseCase.GetSettlementById(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		s.Log.WithContext(c.Request.Context()).Warnf("Error getting settlement: %v", err)
+		c.JSON(http.StatusNotFound, model.CommonResponse[interface{}]{
+			HttpStatus: http.StatusNotFound,
+			Message:    "Settlement not found",
+			Data:       nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.CommonResponse[model.SettlementResponse]{
+		HttpStatus: http.StatusOK,
+		Message:    "Successfully got settlement",
+		Data:       settlement,
+	})
+}
@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/internal/usecase"
+	"net/http"
+)
+
+type WebhookController struct {
+	Log            *logrus.Logger
+	WebhookUseCase usecase.WebhookUseCase
+}
+
+func NewWebhookController(log *logrus.Logger, webhookUseCase usecase.WebhookUseCase) *WebhookController {
+	return &WebhookController{
+		Log:            log,
+		WebhookUseCase: webhookUseCase,
+	}
+}
+
+func (w *WebhookController) GetDeliveries(c *gin.Context) {
+	w.Log.WithContext(c.Request.Context()).Debug("Attempting to get webhook deliveries")
+
+	deliveries, err := w.WebhookUseCase.GetDeliveries(c.Request.Context(), c.Query("status"), c.Query("merchantId"))
+	if err != nil {
+		w.Log.WithContext(c.Request.Context()).Errorf("Error getting webhook deliveries: %v", err)
+		c.JSON(http.StatusBadRequest, model.CommonResponse[interface{}]{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+			Data:       nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.CommonResponse[[]model.WebhookDeliveryResponse]{
+		HttpStatus: http.StatusOK,
+		Message:    "Successfully got webhook deliveries",
+		Data:       deliveries,
+	})
+}
+
+func (w *WebhookController) GetDeliveryById(c *gin.Context) {
+	w.Log.WithContext(c.Request.Context()).Debug("Attempting to get webhook delivery by id")
+
+	delivery, err := w.WebhookUseCase.GetDeliveryById(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		w.Log.WithContext(c.Request.Context()).Warnf("Error getting webhook delivery: %v", err)
+		c.JSON(http.StatusNotFound, model.CommonResponse[interface{}]{
+			HttpStatus: http.StatusNotFound,
+			Message:    "Webhook delivery not found",
+			Data:       nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.CommonResponse[model.WebhookDeliveryResponse]{
+		HttpStatus: http.StatusOK,
+		Message:    "Successfully got webhook delivery",
+		Data:       delivery,
+	})
+}
+
+func (w *WebhookController) Redeliver(c *gin.Context) {
+	w.Log.WithContext(c.Request.Context()).Debug("Attempting to redeliver webhook")
+
+	userId, _ := c.Get("user_id")
+	adminId, _ := userId.(string)
+	delivery, err := w.WebhookUseCase.Redeliver(c.Request.Context(), adminId, c.Param("id"))
+	if err != nil {
+		w.Log.WithContext(c.Request.Context()).Warnf("Error redelivering webhook: %v", err)
+		if errors.Is(err, usecase.ErrWebhookDeliveryInFlight) {
+			c.JSON(http.StatusConflict, model.CommonResponse[interface{}]{
+				HttpStatus: http.StatusConflict,
+				Message:    err.Error(),
+				Data:       nil,
+			})
+			return
+		}
+		c.JSON(http.StatusNotFound, model.CommonResponse[interface{}]{
+			HttpStatus: http.StatusNotFound,
+			Message:    "Webhook delivery not found",
+			Data:       nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.CommonResponse[model.WebhookDeliveryResponse]{
+		HttpStatus: http.StatusOK,
+		Message:    "Successfully redelivered webhook",
+		Data:       delivery,
+	})
+}
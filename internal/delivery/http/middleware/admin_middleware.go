@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/internal/usecase"
+	"net/http"
+)
+
+func AdminMiddleware(log *logrus.Logger, customerUseCase usecase.CustomerUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userId, exists := c.Get("user_id")
+		if !exists {
+			log.WithContext(c.Request.Context()).Warn("User ID not found in context for admin route")
+			c.JSON(http.StatusUnauthorized, model.CommonResponse[interface{}]{
+				HttpStatus: http.StatusUnauthorized,
+				Message:    "User ID not found",
+				Data:       nil,
+			})
+			c.Abort()
+			return
+		}
+
+		customer, err := customerUseCase.FindById(c.Request.Context(), userId.(string))
+		if err != nil || !customer.IsAdmin() {
+			log.WithContext(c.Request.Context()).Warnf("User %s is not allowed to access %s", userId, c.Request.URL.Path)
+			c.JSON(http.StatusForbidden, model.CommonResponse[interface{}]{
+				HttpStatus: http.StatusForbidden,
+				Message:    "Admin access required",
+				Data:       nil,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -10,13 +10,13 @@ import (
 	"strings"
 )
 
-func AuthenticationMiddleware(authUseCase usecase.AuthUseCase) gin.HandlerFunc {
+func AuthenticationMiddleware(log *logrus.Logger, authUseCase usecase.AuthUseCase) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		logrus.Infof("Starting Authorization header validation for %s", c.Request.URL.Path)
+		log.WithContext(c.Request.Context()).Infof("Starting Authorization header validation for %s", c.Request.URL.Path)
 
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			logrus.Warn("Missing Authorization header")
+			log.WithContext(c.Request.Context()).Warn("Missing Authorization header")
 			c.JSON(http.StatusUnauthorized, model.CommonResponse[interface{}]{
 				HttpStatus: http.StatusUnauthorized,
 				Message:    "Authorization Header is required",
@@ -28,7 +28,7 @@ func AuthenticationMiddleware(authUseCase usecase.AuthUseCase) gin.HandlerFunc {
 
 		tokenParts := strings.Split(authHeader, " ")
 		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-			logrus.Warn("Invalid Authorization header format")
+			log.WithContext(c.Request.Context()).Warn("Invalid Authorization header format")
 			c.JSON(http.StatusUnauthorized, model.CommonResponse[interface{}]{
 				HttpStatus: http.StatusUnauthorized,
 				Message:    "Invalid Authorization Header format, must be 'Bearer <token>'",
@@ -39,14 +39,14 @@ func AuthenticationMiddleware(authUseCase usecase.AuthUseCase) gin.HandlerFunc {
 		}
 
 		tokenString := tokenParts[1]
-		logrus.Debugf("Verifying token for request: %s", c.Request.URL.Path)
+		log.WithContext(c.Request.Context()).Debugf("Verifying token for request: %s", c.Request.URL.Path)
 
 		valid, err := auth.VerifyAccessToken(tokenString)
 		if err != nil || !valid {
 			if err != nil {
-				logrus.Errorf("Error verifying token: %v", err)
+				log.WithContext(c.Request.Context()).Errorf("Error verifying token: %v", err)
 			} else {
-				logrus.Warn("Expired or invalid token detected")
+				log.WithContext(c.Request.Context()).Warn("Expired or invalid token detected")
 			}
 
 			c.JSON(http.StatusUnauthorized, model.CommonResponse[interface{}]{
@@ -58,9 +58,9 @@ func AuthenticationMiddleware(authUseCase usecase.AuthUseCase) gin.HandlerFunc {
 			return
 		}
 
-		isBlacklisted, err := authUseCase.IsTokenBlacklisted(tokenString)
+		isBlacklisted, err := authUseCase.IsTokenBlacklisted(c.Request.Context(), tokenString)
 		if err != nil {
-			logrus.Errorf("Error checking blacklist status: %v", err)
+			log.WithContext(c.Request.Context()).Errorf("Error checking blacklist status: %v", err)
 			c.JSON(http.StatusInternalServerError, model.CommonResponse[interface{}]{
 				HttpStatus: http.StatusInternalServerError,
 				Message:    "Internal server error",
@@ -71,7 +71,7 @@ func AuthenticationMiddleware(authUseCase usecase.AuthUseCase) gin.HandlerFunc {
 		}
 
 		if isBlacklisted {
-			logrus.Warnf("Token %s is already blacklisted", tokenString)
+			log.WithContext(c.Request.Context()).Warn("Rejected request with a blacklisted token")
 			c.JSON(http.StatusForbidden, model.CommonResponse[interface{}]{
 				HttpStatus: http.StatusForbidden,
 				Message:    "Token is already blacklisted",
@@ -83,7 +83,7 @@ func AuthenticationMiddleware(authUseCase usecase.AuthUseCase) gin.HandlerFunc {
 
 		userId, err := auth.ExtractIDFromToken(tokenString)
 		if err != nil {
-			logrus.Errorf("Error extracting ID from token: %v", err)
+			log.WithContext(c.Request.Context()).Errorf("Error extracting ID from token: %v", err)
 			c.JSON(http.StatusUnauthorized, model.CommonResponse[interface{}]{
 				HttpStatus: http.StatusUnauthorized,
 				Message:    "Invalid token data",
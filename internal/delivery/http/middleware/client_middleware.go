@@ -0,0 +1,15 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"merchant_bank_payment_go_api/internal/utils"
+)
+
+// ClientMiddleware stores the client address, as resolved through the trusted
+// proxies, and the User-Agent in the request context for the audit history.
+func ClientMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(utils.WithClient(c.Request.Context(), c.ClientIP(), c.Request.UserAgent()))
+		c.Next()
+	}
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"merchant_bank_payment_go_api/internal/model"
+	"net/http"
+)
+
+// MaintenanceMiddleware turns requests away with 503 while maintenance mode is
+// on. The mode is read for every request, so it can be toggled by reloading
+// the configuration.
+func MaintenanceMiddleware(log *logrus.Logger, enabled func() bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !enabled() {
+			c.Next()
+			return
+		}
+
+		log.WithContext(c.Request.Context()).Infof("Rejecting request to %s during maintenance", c.Request.URL.Path)
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, model.CommonResponse[interface{}]{
+			HttpStatus: http.StatusServiceUnavailable,
+			Message:    "Service is under maintenance, please try again later",
+			Data:       nil,
+		})
+	}
+}
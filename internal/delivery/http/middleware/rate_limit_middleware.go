@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"io"
+	"math"
+	"merchant_bank_payment_go_api/internal/metrics"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/internal/utils"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimitKey returns what a rate limit counts the request against. An empty
+// key leaves the request out of that limit.
+type RateLimitKey func(c *gin.Context) string
+
+func RateLimitByIp(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// RateLimitByUser needs the user id set by AuthenticationMiddleware.
+func RateLimitByUser(c *gin.Context) string {
+	userId, exists := c.Get("user_id")
+	if !exists {
+		return ""
+	}
+	return fmt.Sprintf("user:%v", userId)
+}
+
+// maxPeekedBodyBytes bounds how much of a request body RateLimitByMerchant
+// reads to find the merchant.
+const maxPeekedBodyBytes = 64 * 1024
+
+// RateLimitByMerchant reads the merchantId of a JSON body and puts the body
+// back for the handler.
+func RateLimitByMerchant(c *gin.Context) string {
+	if c.Request.Body == nil {
+		return ""
+	}
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxPeekedBodyBytes))
+	c.Request.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), c.Request.Body), c.Request.Body}
+	if err != nil {
+		return ""
+	}
+
+	var request struct {
+		MerchantId string `json:"merchantId"`
+	}
+	if json.Unmarshal(body, &request) != nil || request.MerchantId == "" {
+		return ""
+	}
+	return "merchant:" + request.MerchantId
+}
+
+// RateLimitMiddleware counts requests against the named policy, keyed by key,
+// and responds 429 once the bucket is empty. The policy is read for every
+// request, so a reloaded policy applies at once. RateLimit-Limit,
+// RateLimit-Remaining and RateLimit-Reset describe the most restrictive limit
+// the request passed through.
+func RateLimitMiddleware(log *logrus.Logger, limiter *utils.RateLimiter, name string, key RateLimitKey,
+	policy func() utils.RateLimitPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		current := policy()
+		if !current.Enabled() {
+			c.Next()
+			return
+		}
+		requestKey := key(c)
+		if requestKey == "" {
+			c.Next()
+			return
+		}
+
+		status := limiter.Allow(name+":"+requestKey, current)
+		setRateLimitHeaders(c, status)
+		if status.Allowed {
+			c.Next()
+			return
+		}
+
+		retryAfter := ceilSeconds(status.RetryAfter)
+		metrics.RateLimitedRequests.WithLabelValues(name).Inc()
+		log.WithContext(c.Request.Context()).Warnf("Rate limit %s (%s) exceeded by %s on %s", name, current, requestKey, c.Request.URL.Path)
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, model.CommonResponse[interface{}]{
+			HttpStatus: http.StatusTooManyRequests,
+			Message:    fmt.Sprintf("Too many requests, retry after %d seconds", retryAfter),
+			Data:       nil,
+		})
+	}
+}
+
+func setRateLimitHeaders(c *gin.Context, status utils.RateLimitStatus) {
+	if previous := c.Writer.Header().Get("RateLimit-Remaining"); previous != "" {
+		if remaining, err := strconv.Atoi(previous); err == nil && remaining <= status.Remaining && status.Allowed {
+			return
+		}
+	}
+	c.Header("RateLimit-Limit", strconv.Itoa(status.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(status.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(status.Reset)))
+}
+
+func ceilSeconds(duration time.Duration) int {
+	return int(math.Ceil(duration.Seconds()))
+}
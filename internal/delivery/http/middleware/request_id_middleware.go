@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"merchant_bank_payment_go_api/internal/utils"
+	"regexp"
+)
+
+var validRequestId = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestIdMiddleware keeps the caller's X-Request-ID when it is a plausible
+// id and generates one otherwise. The id is stored in the request context and
+// echoed in the response.
+func RequestIdMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestId := c.GetHeader(utils.RequestIdHeader)
+		if !validRequestId.MatchString(requestId) {
+			requestId = uuid.New().String()
+		}
+
+		c.Set("request_id", requestId)
+		c.Request = c.Request.WithContext(utils.WithRequestId(c.Request.Context(), requestId))
+		c.Header(utils.RequestIdHeader, requestId)
+		c.Next()
+	}
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"merchant_bank_payment_go_api/internal/model"
+	"net/http"
+	"time"
+)
+
+// TimeoutMiddleware gives the request context a deadline. Handlers pass that
+// context down to the use cases, so the work stops once it expires and the
+// client receives 504 instead of whatever error the cancelled call produced.
+func TimeoutMiddleware(log *logrus.Logger, timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		original := c.Writer
+		writer := &timeoutWriter{ResponseWriter: original, ctx: ctx}
+		c.Request = c.Request.WithContext(ctx)
+		c.Writer = writer
+		c.Next()
+		c.Writer = original
+
+		if writer.Written() || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return
+		}
+
+		log.WithContext(ctx).Warnf("Request to %s timed out after %s", c.FullPath(), timeout)
+		c.AbortWithStatusJSON(http.StatusGatewayTimeout, model.CommonResponse[interface{}]{
+			HttpStatus: http.StatusGatewayTimeout,
+			Message:    "Request timed out",
+			Data:       nil,
+		})
+	}
+}
+
+// timeoutWriter discards a response started after the deadline passed, so the
+// middleware can replace it with the timeout response. A response that was
+// already streaming before the deadline is left alone.
+type timeoutWriter struct {
+	gin.ResponseWriter
+	ctx context.Context
+}
+
+func (w *timeoutWriter) expired() bool {
+	return !w.ResponseWriter.Written() && w.ctx.Err() != nil
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	if w.expired() {
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *timeoutWriter) WriteHeaderNow() {
+	if w.expired() {
+		return
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	if w.expired() {
+		return len(data), nil
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	if w.expired() {
+		return len(s), nil
+	}
+	return w.ResponseWriter.WriteString(s)
+}
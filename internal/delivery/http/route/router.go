@@ -2,21 +2,99 @@ package route
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 	"merchant_bank_payment_go_api/internal/delivery/http/controller"
 	"merchant_bank_payment_go_api/internal/delivery/http/middleware"
+	"merchant_bank_payment_go_api/internal/metrics"
+	"merchant_bank_payment_go_api/internal/usecase"
 	"merchant_bank_payment_go_api/internal/usecase/impl"
+	"merchant_bank_payment_go_api/internal/utils"
+	"time"
 )
 
-func ConfigureRouter(router *gin.Engine, authController *controller.AuthenticationController, paymentController *controller.PaymentTransactionController, authUseCase *impl.AuthUseCaseImpl) {
-	authMiddleware := middleware.AuthenticationMiddleware(authUseCase)
-	publicRoute := router.Group("/api/auth")
+// Timeouts bounds how long a request may run. Export applies to the streaming
+// CSV endpoints, which legitimately take longer than the rest of the API.
+type Timeouts struct {
+	Default time.Duration
+	Export  time.Duration
+}
+
+// RateLimits are the policies for the rate limited routes. They are read for
+// every request, so reloaded policies apply at once.
+type RateLimits struct {
+	LoginPerIp         utils.RateLimitPolicy
+	ApiPerUser         utils.RateLimitPolicy
+	PaymentPerUser     utils.RateLimitPolicy
+	PaymentPerMerchant utils.RateLimitPolicy
+}
+
+func ConfigureRouter(router *gin.Engine, log *logrus.Logger, authController *controller.AuthenticationController, paymentController *controller.PaymentTransactionController,
+	settlementController *controller.SettlementController, reportController *controller.ReportController,
+	webhookController *controller.WebhookController, merchantController *controller.MerchantController,
+	customerController *controller.CustomerController, historyController *controller.HistoryController, healthController *controller.HealthController,
+	exchangeRateController *controller.ExchangeRateController, historyArchiveController *controller.HistoryArchiveController,
+	authUseCase *impl.AuthUseCaseImpl, customerUseCase usecase.CustomerUseCase, timeouts Timeouts, maintenanceMode func() bool,
+	rateLimits func() RateLimits) {
+	router.Use(middleware.RequestIdMiddleware(), middleware.ClientMiddleware(), middleware.TracingMiddleware(), middleware.AccessLogMiddleware(log), middleware.MetricsMiddleware())
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	authMiddleware := middleware.AuthenticationMiddleware(log, authUseCase)
+	adminMiddleware := middleware.AdminMiddleware(log, customerUseCase)
+	defaultTimeout := middleware.TimeoutMiddleware(log, timeouts.Default)
+	exportTimeout := middleware.TimeoutMiddleware(log, timeouts.Export)
+	// Admin, health and metrics endpoints stay available during maintenance.
+	maintenance := middleware.MaintenanceMiddleware(log, maintenanceMode)
+
+	limiter := utils.NewRateLimiter()
+	loginRateLimit := middleware.RateLimitMiddleware(log, limiter, "login_per_ip", middleware.RateLimitByIp,
+		func() utils.RateLimitPolicy { return rateLimits().LoginPerIp })
+	apiRateLimit := middleware.RateLimitMiddleware(log, limiter, "api_per_user", middleware.RateLimitByUser,
+		func() utils.RateLimitPolicy { return rateLimits().ApiPerUser })
+	paymentRateLimit := middleware.RateLimitMiddleware(log, limiter, "payment_per_user", middleware.RateLimitByUser,
+		func() utils.RateLimitPolicy { return rateLimits().PaymentPerUser })
+	merchantRateLimit := middleware.RateLimitMiddleware(log, limiter, "payment_per_merchant", middleware.RateLimitByMerchant,
+		func() utils.RateLimitPolicy { return rateLimits().PaymentPerMerchant })
+
+	router.GET("/healthz", healthController.Healthz)
+	router.GET("/readyz", defaultTimeout, healthController.Readyz)
+	router.GET("/version", healthController.Version)
+
+	publicRoute := router.Group("/api/auth", maintenance, defaultTimeout)
 	{
-		publicRoute.POST("/login", authController.Login)
+		publicRoute.POST("/login", loginRateLimit, authController.Login)
 	}
 
-	protectedRoute := router.Group("/api", authMiddleware)
+	protectedRoute := router.Group("/api", maintenance, defaultTimeout, authMiddleware, apiRateLimit)
 	{
 		protectedRoute.POST("/auth/logout", authController.Logout)
-		protectedRoute.POST("/payment", paymentController.AddPayment)
+		protectedRoute.POST("/payment", paymentRateLimit, merchantRateLimit, paymentController.AddPayment)
+		protectedRoute.GET("/payment", paymentController.GetPayments)
+		protectedRoute.GET("/payment/:id", paymentController.GetPaymentById)
+		protectedRoute.GET("/history", historyController.GetMyHistory)
+	}
+
+	adminRoute := router.Group("/api/admin", authMiddleware, apiRateLimit, adminMiddleware)
+	{
+		adminRoute.POST("/settlements", defaultTimeout, settlementController.RunSettlement)
+		adminRoute.GET("/settlements", defaultTimeout, settlementController.GetSettlements)
+		adminRoute.GET("/settlements/:id", defaultTimeout, settlementController.GetSettlementById)
+		adminRoute.GET("/reports/reconciliation", exportTimeout, reportController.ExportReconciliation)
+		adminRoute.GET("/webhooks", defaultTimeout, webhookController.GetDeliveries)
+		adminRoute.GET("/webhooks/:id", defaultTimeout, webhookController.GetDeliveryById)
+		adminRoute.POST("/webhooks/:id/redeliver", defaultTimeout, webhookController.Redeliver)
+		adminRoute.GET("/merchants", defaultTimeout, merchantController.GetMerchants)
+		adminRoute.POST("/merchants", defaultTimeout, merchantController.CreateMerchant)
+		adminRoute.PUT("/merchants/:id", defaultTimeout, merchantController.UpdateMerchant)
+		adminRoute.POST("/merchants/:id/suspend", defaultTimeout, merchantController.SuspendMerchant)
+		adminRoute.POST("/merchants/:id/reactivate", defaultTimeout, merchantController.ReactivateMerchant)
+		adminRoute.GET("/customers", defaultTimeout, customerController.GetCustomers)
+		adminRoute.GET("/customers/:id", defaultTimeout, customerController.GetCustomerDetail)
+		adminRoute.POST("/customers/:id/disable", defaultTimeout, customerController.DisableCustomer)
+		adminRoute.POST("/customers/:id/enable", defaultTimeout, customerController.EnableCustomer)
+		adminRoute.POST("/customers/:id/logout", defaultTimeout, customerController.ForceLogout)
+		adminRoute.GET("/history", exportTimeout, historyController.SearchHistory)
+		adminRoute.POST("/history/archive", exportTimeout, historyArchiveController.ArchiveHistory)
+		adminRoute.GET("/exchange-rates", defaultTimeout, exchangeRateController.GetRates)
+		adminRoute.PUT("/exchange-rates", defaultTimeout, exchangeRateController.ReplaceRates)
 	}
 }
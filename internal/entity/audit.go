@@ -0,0 +1,184 @@
+package entity
+
+import "strings"
+
+type AuditAction string
+
+const (
+	AuditActionLogin               AuditAction = "AUTH_LOGIN"
+	AuditActionLogout              AuditAction = "AUTH_LOGOUT"
+	AuditActionCustomerLookup      AuditAction = "CUSTOMER_LOOKUP"
+	AuditActionCustomerDisable     AuditAction = "CUSTOMER_DISABLE"
+	AuditActionCustomerEnable      AuditAction = "CUSTOMER_ENABLE"
+	AuditActionCustomerForceLogout AuditAction = "CUSTOMER_FORCE_LOGOUT"
+	AuditActionMerchantLookup      AuditAction = "MERCHANT_LOOKUP"
+	AuditActionMerchantCreate      AuditAction = "MERCHANT_CREATE"
+	AuditActionMerchantUpdate      AuditAction = "MERCHANT_UPDATE"
+	AuditActionMerchantSuspend     AuditAction = "MERCHANT_SUSPEND"
+	AuditActionMerchantReactivate  AuditAction = "MERCHANT_REACTIVATE"
+	AuditActionPaymentCreate       AuditAction = "PAYMENT_CREATE"
+	AuditActionPaymentView         AuditAction = "PAYMENT_VIEW"
+	AuditActionSettlementRun       AuditAction = "SETTLEMENT_RUN"
+	AuditActionWebhookRedeliver    AuditAction = "WEBHOOK_REDELIVER"
+	AuditActionHistoryView         AuditAction = "HISTORY_VIEW"
+	AuditActionHistoryArchive      AuditAction = "HISTORY_ARCHIVE"
+	AuditActionConfigReload        AuditAction = "CONFIG_RELOAD"
+	AuditActionExchangeRateUpdate  AuditAction = "EXCHANGE_RATE_UPDATE"
+	// AuditActionUnknown is only used for legacy records whose action could not be mapped.
+	AuditActionUnknown AuditAction = "UNKNOWN"
+)
+
+var AuditActions = []AuditAction{
+	AuditActionLogin,
+	AuditActionLogout,
+	AuditActionCustomerLookup,
+	AuditActionCustomerDisable,
+	AuditActionCustomerEnable,
+	AuditActionCustomerForceLogout,
+	AuditActionMerchantLookup,
+	AuditActionMerchantCreate,
+	AuditActionMerchantUpdate,
+	AuditActionMerchantSuspend,
+	AuditActionMerchantReactivate,
+	AuditActionPaymentCreate,
+	AuditActionPaymentView,
+	AuditActionSettlementRun,
+	AuditActionWebhookRedeliver,
+	AuditActionHistoryView,
+	AuditActionHistoryArchive,
+	AuditActionConfigReload,
+	AuditActionExchangeRateUpdate,
+}
+
+func (a AuditAction) IsValid() bool {
+	for _, action := range AuditActions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+type AuditActorType string
+
+const (
+	AuditActorCustomer  AuditActorType = "CUSTOMER"
+	AuditActorAdmin     AuditActorType = "ADMIN"
+	AuditActorAnonymous AuditActorType = "ANONYMOUS"
+	AuditActorSystem    AuditActorType = "SYSTEM"
+)
+
+type AuditSubjectType string
+
+const (
+	AuditSubjectCustomer        AuditSubjectType = "CUSTOMER"
+	AuditSubjectMerchant        AuditSubjectType = "MERCHANT"
+	AuditSubjectPayment         AuditSubjectType = "PAYMENT"
+	AuditSubjectSettlement      AuditSubjectType = "SETTLEMENT"
+	AuditSubjectWebhookDelivery AuditSubjectType = "WEBHOOK_DELIVERY"
+	AuditSubjectSession         AuditSubjectType = "SESSION"
+	AuditSubjectHistory         AuditSubjectType = "HISTORY"
+	AuditSubjectConfig          AuditSubjectType = "CONFIG"
+	AuditSubjectExchangeRate    AuditSubjectType = "EXCHANGE_RATE"
+)
+
+type AuditOutcome string
+
+const (
+	AuditOutcomeSuccess AuditOutcome = "SUCCESS"
+	AuditOutcomeFailure AuditOutcome = "FAILURE"
+)
+
+type AuditErrorCode string
+
+const (
+	AuditErrorInvalidId          AuditErrorCode = "INVALID_ID"
+	AuditErrorNotFound           AuditErrorCode = "NOT_FOUND"
+	AuditErrorInvalidCredentials AuditErrorCode = "INVALID_CREDENTIALS"
+	AuditErrorInvalidToken       AuditErrorCode = "INVALID_TOKEN"
+	AuditErrorInvalidRequest     AuditErrorCode = "INVALID_REQUEST"
+	AuditErrorConversionFailed   AuditErrorCode = "CONVERSION_FAILED"
+	AuditErrorLimitExceeded      AuditErrorCode = "LIMIT_EXCEEDED"
+	AuditErrorMerchantSuspended  AuditErrorCode = "MERCHANT_SUSPENDED"
+	AuditErrorAccountDisabled    AuditErrorCode = "ACCOUNT_DISABLED"
+	AuditErrorInvalidConfig      AuditErrorCode = "INVALID_CONFIG"
+	AuditErrorStorageFailed      AuditErrorCode = "STORAGE_FAILED"
+	AuditErrorInternal           AuditErrorCode = "INTERNAL"
+)
+
+// AuditDetails is the structured payload of a history entry. The actor id is
+// History.CustomerId; Ip, UserAgent and RequestId describe the HTTP request
+// that caused the entry, when there was one.
+type AuditDetails struct {
+	ActorType   AuditActorType   `json:"actor_type"`
+	SubjectType AuditSubjectType `json:"subject_type,omitempty"`
+	SubjectId   string           `json:"subject_id,omitempty"`
+	Outcome     AuditOutcome     `json:"outcome"`
+	ErrorCode   AuditErrorCode   `json:"error_code,omitempty"`
+	Message     string           `json:"message,omitempty"`
+	Ip          string           `json:"ip,omitempty"`
+	UserAgent   string           `json:"user_agent,omitempty"`
+	RequestId   string           `json:"request_id,omitempty"`
+	Legacy      bool             `json:"legacy,omitempty"`
+}
+
+var legacyAuditActions = map[string]struct {
+	action      AuditAction
+	subjectType AuditSubjectType
+	failed      bool
+}{
+	"LOGIN":                             {AuditActionLogin, AuditSubjectSession, false},
+	"LOGOUT":                            {AuditActionLogout, AuditSubjectSession, false},
+	"PAYMENT":                           {AuditActionPaymentCreate, AuditSubjectPayment, false},
+	"VIEW_PAYMENT":                      {AuditActionPaymentView, AuditSubjectPayment, false},
+	"SETTLEMENT":                        {AuditActionSettlementRun, AuditSubjectSettlement, false},
+	"WEBHOOK_REDELIVER":                 {AuditActionWebhookRedeliver, AuditSubjectWebhookDelivery, false},
+	"Successfully found customer by id": {AuditActionCustomerLookup, AuditSubjectCustomer, false},
+	"Successfully found customer by username": {AuditActionCustomerLookup, AuditSubjectCustomer, false},
+	"Failed to find customer by id":           {AuditActionCustomerLookup, AuditSubjectCustomer, true},
+	"Failed to find customer by username":     {AuditActionCustomerLookup, AuditSubjectCustomer, true},
+	"Failed to parse uuid":                    {AuditActionCustomerLookup, AuditSubjectCustomer, true},
+	"Successfully found merchant by id":       {AuditActionMerchantLookup, AuditSubjectMerchant, false},
+	"Failed to find merchant by id":           {AuditActionMerchantLookup, AuditSubjectMerchant, true},
+	"Failed to parse UUID":                    {AuditActionMerchantLookup, AuditSubjectMerchant, true},
+}
+
+var legacyFailureMarkers = []string{"fail", "invalid", "error", "not found", "belongs to another"}
+
+// ConvertLegacyAudit maps a free-text action and details string written before
+// the action catalogue existed to a catalogue action and structured details.
+// The original text is kept as the message.
+func ConvertLegacyAudit(action, customerId, details string) (AuditAction, AuditDetails) {
+	converted := AuditDetails{
+		ActorType: AuditActorCustomer,
+		Outcome:   AuditOutcomeSuccess,
+		Message:   details,
+		Legacy:    true,
+	}
+	if customerId == "-" || customerId == "" {
+		converted.ActorType = AuditActorAnonymous
+	}
+
+	known, ok := legacyAuditActions[action]
+	if !ok {
+		converted.Message = strings.TrimSpace(action + ": " + details)
+		known.action = AuditActionUnknown
+	}
+	converted.SubjectType = known.subjectType
+	if known.subjectType == AuditSubjectCustomer || known.subjectType == AuditSubjectMerchant {
+		converted.SubjectId = customerId
+	}
+
+	text := strings.ToLower(action + " " + details)
+	failed := known.failed
+	for _, marker := range legacyFailureMarkers {
+		if strings.Contains(text, marker) {
+			failed = true
+		}
+	}
+	if failed {
+		converted.Outcome = AuditOutcomeFailure
+	}
+
+	return known.action, converted
+}
@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+// AuditCheckpoint pins the history chain at Sequence to Hash. Signature is an
+// HMAC of the other fields with the audit key, so a rewritten history can not
+// be made to match an earlier checkpoint without the key.
+type AuditCheckpoint struct {
+	Id        uuid.UUID `json:"id"`
+	Sequence  int64     `json:"sequence"`
+	Hash      string    `json:"hash"`
+	CreatedAt time.Time `json:"created_at"`
+	Signature string    `json:"signature"`
+}
+
+func NewAuditCheckpoint(last History, key []byte, now time.Time) AuditCheckpoint {
+	checkpoint := AuditCheckpoint{
+		Id:        uuid.New(),
+		Sequence:  last.Sequence,
+		Hash:      last.Hash,
+		CreatedAt: now,
+	}
+	checkpoint.Signature = AuditMac(key, checkpoint.signedValue())
+	return checkpoint
+}
+
+func (c AuditCheckpoint) ValidSignature(key []byte) bool {
+	return ValidAuditMac(key, c.signedValue(), c.Signature)
+}
+
+func (c AuditCheckpoint) signedValue() string {
+	return fmt.Sprintf("%s.%d.%s.%s", c.Id, c.Sequence, c.Hash, c.CreatedAt.UTC().Format(time.RFC3339Nano))
+}
@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+)
+
+const DefaultCurrency = "IDR"
+
+type Currency struct {
+	Code       string `json:"code"`
+	Numeric    string `json:"numeric"`
+	MinorUnits int    `json:"minor_units"`
+}
+
+// IDR is listed with 2 minor units in ISO 4217, but sen are no longer in
+// circulation and every stored payment is in whole rupiah, so we settle it with 0.
+var supportedCurrencies = map[string]Currency{
+	"IDR": {Code: "IDR", Numeric: "360", MinorUnits: 0},
+	"JPY": {Code: "JPY", Numeric: "392", MinorUnits: 0},
+	"KWD": {Code: "KWD", Numeric: "414", MinorUnits: 3},
+	"SGD": {Code: "SGD", Numeric: "702", MinorUnits: 2},
+	"MYR": {Code: "MYR", Numeric: "458", MinorUnits: 2},
+	"USD": {Code: "USD", Numeric: "840", MinorUnits: 2},
+	"EUR": {Code: "EUR", Numeric: "978", MinorUnits: 2},
+}
+
+func FindCurrency(code string) (Currency, error) {
+	currency, ok := supportedCurrencies[strings.ToUpper(strings.TrimSpace(code))]
+	if !ok {
+		return Currency{}, fmt.Errorf("unsupported currency %q", code)
+	}
+	return currency, nil
+}
+
+func IsSupportedCurrency(code string) bool {
+	_, err := FindCurrency(code)
+	return err == nil
+}
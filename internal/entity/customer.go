@@ -5,10 +5,43 @@ import (
 	"time"
 )
 
+const (
+	RoleCustomer = "CUSTOMER"
+	RoleAdmin    = "ADMIN"
+)
+
+type CustomerStatus string
+
+const (
+	CustomerStatusActive   CustomerStatus = "ACTIVE"
+	CustomerStatusDisabled CustomerStatus = "DISABLED"
+)
+
+// Customer.Status is empty for customers created before statuses existed,
+// which are active. Access tokens issued before SessionsRevokedAt are no
+// longer accepted.
 type Customer struct {
-	Id        uuid.UUID `json:"id"`
-	Username  string    `json:"username"`
-	Password  string    `json:"password"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Id                uuid.UUID      `json:"id"`
+	Username          string         `json:"username"`
+	Password          string         `json:"password"`
+	Role              string         `json:"role,omitempty"`
+	Status            CustomerStatus `json:"status,omitempty"`
+	SessionsRevokedAt *time.Time     `json:"sessions_revoked_at,omitempty"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+}
+
+func (c Customer) IsAdmin() bool {
+	return c.Role == RoleAdmin
+}
+
+func (c Customer) IsDisabled() bool {
+	return c.Status == CustomerStatusDisabled
+}
+
+// IsSessionRevoked reports whether a token issued at issuedAt is no longer
+// accepted. Tokens carry whole seconds, so a token issued in the same second
+// as the revocation counts as revoked.
+func (c Customer) IsSessionRevoked(issuedAt time.Time) bool {
+	return c.IsDisabled() || (c.SessionsRevokedAt != nil && issuedAt.Before(*c.SessionsRevokedAt))
 }
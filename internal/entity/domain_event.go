@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"time"
+)
+
+type DomainEventType string
+
+const (
+	EventPaymentCreated    DomainEventType = "PaymentCreated"
+	EventSettlementCreated DomainEventType = "SettlementCreated"
+	EventLoginSucceeded    DomainEventType = "LoginSucceeded"
+	EventTokenRevoked      DomainEventType = "TokenRevoked"
+)
+
+type DomainEventStatus string
+
+const (
+	DomainEventStatusPending    DomainEventStatus = "PENDING"
+	DomainEventStatusDispatched DomainEventStatus = "DISPATCHED"
+)
+
+type DomainEvent struct {
+	Id           uuid.UUID         `json:"id"`
+	Type         DomainEventType   `json:"type"`
+	AggregateId  string            `json:"aggregate_id"`
+	Payload      json.RawMessage   `json:"payload"`
+	Status       DomainEventStatus `json:"status"`
+	Attempts     int               `json:"attempts"`
+	DeliveredTo  []string          `json:"delivered_to,omitempty"`
+	LastError    string            `json:"last_error,omitempty"`
+	OccurredAt   time.Time         `json:"occurred_at"`
+	DispatchedAt *time.Time        `json:"dispatched_at,omitempty"`
+}
+
+func NewDomainEvent(eventType DomainEventType, aggregateId string, payload interface{}) (DomainEvent, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return DomainEvent{}, err
+	}
+
+	return DomainEvent{
+		Id:          uuid.New(),
+		Type:        eventType,
+		AggregateId: aggregateId,
+		Payload:     encoded,
+		Status:      DomainEventStatusPending,
+		OccurredAt:  time.Now(),
+	}, nil
+}
+
+func (d DomainEvent) IsDeliveredTo(subscriber string) bool {
+	for _, delivered := range d.DeliveredTo {
+		if delivered == subscriber {
+			return true
+		}
+	}
+	return false
+}
+
+type TokenRevokedPayload struct {
+	CustomerId string `json:"customer_id"`
+}
+
+type LoginSucceededPayload struct {
+	CustomerId string `json:"customer_id"`
+}
@@ -0,0 +1,74 @@
+package entity
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+type ExchangeRate struct {
+	Base      string    `json:"base"`
+	Quote     string    `json:"quote"`
+	Rate      float64   `json:"rate"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type CurrencyConversion struct {
+	OriginalAmount    Money     `json:"original_amount"`
+	MidRate           float64   `json:"mid_rate"`
+	AppliedRate       float64   `json:"applied_rate"`
+	SpreadBasisPoints int64     `json:"spread_basis_points"`
+	RateTimestamp     time.Time `json:"rate_timestamp"`
+}
+
+type RoundingMode string
+
+const (
+	RoundHalfUp   RoundingMode = "HALF_UP"
+	RoundHalfEven RoundingMode = "HALF_EVEN"
+	RoundDown     RoundingMode = "DOWN"
+	RoundUp       RoundingMode = "UP"
+)
+
+func ParseRoundingMode(mode string) (RoundingMode, error) {
+	switch RoundingMode(strings.ToUpper(strings.TrimSpace(mode))) {
+	case "", RoundHalfEven:
+		return RoundHalfEven, nil
+	case RoundHalfUp:
+		return RoundHalfUp, nil
+	case RoundDown:
+		return RoundDown, nil
+	case RoundUp:
+		return RoundUp, nil
+	}
+	return "", fmt.Errorf("unknown rounding mode %q", mode)
+}
+
+// Round rounds a non-negative amount to a whole number of minor units.
+func (r RoundingMode) Round(amount *big.Rat) int64 {
+	quotient, remainder := new(big.Int).QuoRem(amount.Num(), amount.Denom(), new(big.Int))
+	if remainder.Sign() == 0 {
+		return quotient.Int64()
+	}
+
+	doubled := new(big.Int).Mul(remainder, big.NewInt(2))
+	half := doubled.CmpAbs(amount.Denom())
+
+	roundUp := false
+	switch r {
+	case RoundUp:
+		roundUp = true
+	case RoundDown:
+		roundUp = false
+	case RoundHalfUp:
+		roundUp = half >= 0
+	default:
+		roundUp = half > 0 || (half == 0 && quotient.Bit(0) == 1)
+	}
+
+	if roundUp {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+	return quotient.Int64()
+}
@@ -0,0 +1,131 @@
+package entity
+
+import (
+	"fmt"
+	"math/big"
+)
+
+type FeeType string
+
+const (
+	FeeTypePercentage FeeType = "PERCENTAGE"
+	FeeTypeFixed      FeeType = "FIXED"
+	FeeTypeTiered     FeeType = "TIERED"
+)
+
+// FeePlan amounts are in minor units of the merchant's settlement currency.
+// MinFee and MaxFee apply to every plan type; a zero MaxFee means uncapped.
+type FeePlan struct {
+	Type                  FeeType   `json:"type"`
+	PercentageBasisPoints int64     `json:"percentage_basis_points,omitempty"`
+	FixedAmount           int64     `json:"fixed_amount,omitempty"`
+	Tiers                 []FeeTier `json:"tiers,omitempty"`
+	MinFee                int64     `json:"min_fee,omitempty"`
+	MaxFee                int64     `json:"max_fee,omitempty"`
+}
+
+// FeeTier applies to payments up to and including UpTo; a zero UpTo matches any amount.
+type FeeTier struct {
+	UpTo                  int64 `json:"up_to,omitempty"`
+	PercentageBasisPoints int64 `json:"percentage_basis_points,omitempty"`
+	FixedAmount           int64 `json:"fixed_amount,omitempty"`
+}
+
+func (f FeePlan) Calculate(amount int64) (int64, error) {
+	var fee int64
+	switch f.Type {
+	case "":
+		return 0, nil
+	case FeeTypePercentage:
+		fee = percentageOf(amount, f.PercentageBasisPoints) + f.FixedAmount
+	case FeeTypeFixed:
+		fee = f.FixedAmount
+	case FeeTypeTiered:
+		tier, err := f.findTier(amount)
+		if err != nil {
+			return 0, err
+		}
+		fee = percentageOf(amount, tier.PercentageBasisPoints) + tier.FixedAmount
+	default:
+		return 0, fmt.Errorf("unknown fee plan type %q", f.Type)
+	}
+
+	if fee < f.MinFee {
+		fee = f.MinFee
+	}
+	if f.MaxFee > 0 && fee > f.MaxFee {
+		fee = f.MaxFee
+	}
+	if fee > amount {
+		return 0, fmt.Errorf("fee %d exceeds payment amount %d", fee, amount)
+	}
+	return fee, nil
+}
+
+// Validate rejects plans Calculate could not apply: unknown types, negative
+// amounts, percentages over 100% and tiers that are out of order or leave
+// amounts uncovered.
+func (f FeePlan) Validate() error {
+	switch f.Type {
+	case "", FeeTypePercentage, FeeTypeFixed:
+		if len(f.Tiers) > 0 {
+			return fmt.Errorf("tiers are only allowed on %s fee plans", FeeTypeTiered)
+		}
+	case FeeTypeTiered:
+		if len(f.Tiers) == 0 {
+			return fmt.Errorf("%s fee plan needs at least one tier", FeeTypeTiered)
+		}
+	default:
+		return fmt.Errorf("unknown fee plan type %q", f.Type)
+	}
+
+	if err := validateFee(f.PercentageBasisPoints, f.FixedAmount); err != nil {
+		return err
+	}
+	if f.MinFee < 0 || f.MaxFee < 0 {
+		return fmt.Errorf("min and max fee must not be negative")
+	}
+	if f.MaxFee > 0 && f.MaxFee < f.MinFee {
+		return fmt.Errorf("max fee %d is below min fee %d", f.MaxFee, f.MinFee)
+	}
+
+	for i, tier := range f.Tiers {
+		if err := validateFee(tier.PercentageBasisPoints, tier.FixedAmount); err != nil {
+			return fmt.Errorf("tier %d: %w", i+1, err)
+		}
+		last := i == len(f.Tiers)-1
+		switch {
+		case tier.UpTo < 0:
+			return fmt.Errorf("tier %d: up to must not be negative", i+1)
+		case tier.UpTo == 0 && !last:
+			return fmt.Errorf("tier %d: only the last tier may cover any amount", i+1)
+		case i > 0 && tier.UpTo != 0 && tier.UpTo <= f.Tiers[i-1].UpTo:
+			return fmt.Errorf("tier %d: up to must be above the previous tier", i+1)
+		}
+	}
+	return nil
+}
+
+func validateFee(basisPoints, fixedAmount int64) error {
+	if basisPoints < 0 || basisPoints > 10000 {
+		return fmt.Errorf("percentage basis points must be between 0 and 10000")
+	}
+	if fixedAmount < 0 {
+		return fmt.Errorf("fixed amount must not be negative")
+	}
+	return nil
+}
+
+func (f FeePlan) findTier(amount int64) (FeeTier, error) {
+	for _, tier := range f.Tiers {
+		if tier.UpTo == 0 || amount <= tier.UpTo {
+			return tier, nil
+		}
+	}
+	return FeeTier{}, fmt.Errorf("no fee tier covers amount %d", amount)
+}
+
+func percentageOf(amount, basisPoints int64) int64 {
+	fee := new(big.Int).Mul(big.NewInt(amount), big.NewInt(basisPoints))
+	return RoundHalfUp.Round(new(big.Rat).SetFrac(fee, big.NewInt(10000)))
+}
@@ -1,14 +1,116 @@
 package entity
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"github.com/google/uuid"
 	"time"
 )
 
+// History records are hash-chained: Hash covers the record and PrevHash, so
+// editing, removing or reordering a record breaks every later link. Mac is an
+// HMAC of Hash with the server's audit key, when one is configured.
 type History struct {
-	Id         uuid.UUID `json:"id"`
-	Action     string    `json:"action"`
-	CustomerId string    `json:"customer_id"`
-	Timestamp  time.Time `json:"timestamp"`
-	Details    string    `json:"details"`
+	Sequence   int64        `json:"sequence,omitempty"`
+	Id         uuid.UUID    `json:"id"`
+	Action     AuditAction  `json:"action"`
+	CustomerId string       `json:"customer_id"`
+	Timestamp  time.Time    `json:"timestamp"`
+	Details    AuditDetails `json:"details"`
+	PrevHash   string       `json:"prev_hash,omitempty"`
+	Hash       string       `json:"hash,omitempty"`
+	Mac        string       `json:"mac,omitempty"`
+}
+
+// Seal links the record to prev, the last record already stored, and sets its
+// sequence, hash and, with a non-empty key, its MAC. A nil or unchained prev
+// starts a new chain.
+func (h *History) Seal(prev *History, key []byte) {
+	h.Sequence = 1
+	h.PrevHash = ""
+	if prev != nil && prev.Hash != "" {
+		h.Sequence = prev.Sequence + 1
+		h.PrevHash = prev.Hash
+	}
+
+	h.Hash = h.ComputeHash()
+	h.Mac = ""
+	if len(key) > 0 {
+		h.Mac = AuditMac(key, h.Hash)
+	}
+}
+
+func (h History) IsChained() bool {
+	return h.Hash != ""
+}
+
+func (h History) ComputeHash() string {
+	canonical, _ := json.Marshal(struct {
+		Sequence   int64        `json:"sequence"`
+		Id         uuid.UUID    `json:"id"`
+		Action     AuditAction  `json:"action"`
+		CustomerId string       `json:"customer_id"`
+		Timestamp  string       `json:"timestamp"`
+		Details    AuditDetails `json:"details"`
+		PrevHash   string       `json:"prev_hash"`
+	}{h.Sequence, h.Id, h.Action, h.CustomerId, h.Timestamp.UTC().Format(time.RFC3339Nano), h.Details, h.PrevHash})
+
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:])
+}
+
+// AuditMac is the hex HMAC-SHA256 of value keyed with the audit key.
+func AuditMac(key []byte, value string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func ValidAuditMac(key []byte, value, expected string) bool {
+	return hmac.Equal([]byte(AuditMac(key, value)), []byte(expected))
+}
+
+// UnmarshalJSON also accepts records written before AuditDetails existed,
+// whose details field is a plain string, and converts them with ConvertLegacyAudit.
+func (h *History) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Sequence   int64           `json:"sequence"`
+		Id         uuid.UUID       `json:"id"`
+		Action     string          `json:"action"`
+		CustomerId string          `json:"customer_id"`
+		Timestamp  time.Time       `json:"timestamp"`
+		Details    json.RawMessage `json:"details"`
+		PrevHash   string          `json:"prev_hash"`
+		Hash       string          `json:"hash"`
+		Mac        string          `json:"mac"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	h.Sequence = raw.Sequence
+	h.Id = raw.Id
+	h.CustomerId = raw.CustomerId
+	h.Timestamp = raw.Timestamp
+	h.PrevHash = raw.PrevHash
+	h.Hash = raw.Hash
+	h.Mac = raw.Mac
+	h.Action = AuditAction(raw.Action)
+	h.Details = AuditDetails{}
+
+	var legacyDetails string
+	if len(raw.Details) > 0 && raw.Details[0] == '"' {
+		if err := json.Unmarshal(raw.Details, &legacyDetails); err != nil {
+			return err
+		}
+		h.Action, h.Details = ConvertLegacyAudit(raw.Action, raw.CustomerId, legacyDetails)
+		return nil
+	}
+
+	if len(raw.Details) > 0 && string(raw.Details) != "null" {
+		return json.Unmarshal(raw.Details, &h.Details)
+	}
+	return nil
 }
@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// HistoryArchive is an index entry for one gzip-compressed archive file
+// holding a contiguous run of history records moved out of History.json.
+// From and To are the timestamps of the oldest and newest archived record.
+type HistoryArchive struct {
+	Id            uuid.UUID `json:"id"`
+	Filename      string    `json:"filename"`
+	From          time.Time `json:"from"`
+	To            time.Time `json:"to"`
+	Records       int       `json:"records"`
+	FirstSequence int64     `json:"first_sequence,omitempty"`
+	LastSequence  int64     `json:"last_sequence,omitempty"`
+	LastHash      string    `json:"last_hash,omitempty"`
+	Checksum      string    `json:"checksum"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Overlaps reports whether the archive may hold records in [from, to). A zero
+// bound is open.
+func (a HistoryArchive) Overlaps(from, to time.Time) bool {
+	return (from.IsZero() || !a.To.Before(from)) && (to.IsZero() || a.From.Before(to))
+}
@@ -2,12 +2,51 @@ package entity
 
 import (
 	"github.com/google/uuid"
+	"strings"
 	"time"
 )
 
+type MerchantStatus string
+
+const (
+	MerchantStatusActive    MerchantStatus = "ACTIVE"
+	MerchantStatusSuspended MerchantStatus = "SUSPENDED"
+)
+
+// Merchant.Status is empty for merchants created before statuses existed,
+// which are active.
 type Merchant struct {
-	Id        uuid.UUID `json:"id"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Id                 uuid.UUID      `json:"id"`
+	Name               string         `json:"name"`
+	AcceptedCurrencies []string       `json:"accepted_currencies,omitempty"`
+	FeePlan            FeePlan        `json:"fee_plan"`
+	WebhookUrl         string         `json:"webhook_url,omitempty"`
+	WebhookSecret      string         `json:"webhook_secret,omitempty"`
+	Status             MerchantStatus `json:"status,omitempty"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+}
+
+func (m Merchant) SettlementCurrency() string {
+	if len(m.AcceptedCurrencies) == 0 {
+		return DefaultCurrency
+	}
+	return strings.ToUpper(m.AcceptedCurrencies[0])
+}
+
+func (m Merchant) AcceptsCurrency(code string) bool {
+	if len(m.AcceptedCurrencies) == 0 {
+		return strings.EqualFold(code, DefaultCurrency)
+	}
+
+	for _, accepted := range m.AcceptedCurrencies {
+		if strings.EqualFold(accepted, code) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m Merchant) IsSuspended() bool {
+	return m.Status == MerchantStatusSuspended
 }
@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Money holds an amount in the minor units of its ISO 4217 currency,
+// e.g. 1500 JPY is 1500 and 1.250 KWD is 1250.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func NewMoney(amount int64, currencyCode string) (Money, error) {
+	if strings.TrimSpace(currencyCode) == "" {
+		currencyCode = DefaultCurrency
+	}
+
+	currency, err := FindCurrency(currencyCode)
+	if err != nil {
+		return Money{}, err
+	}
+
+	if amount <= 0 {
+		return Money{}, fmt.Errorf("amount must be greater than zero")
+	}
+
+	return Money{Amount: amount, Currency: currency.Code}, nil
+}
+
+func (m Money) CurrencyCode() string {
+	if m.Currency == "" {
+		return DefaultCurrency
+	}
+	return m.Currency
+}
+
+func (m Money) MajorUnits() float64 {
+	currency, err := FindCurrency(m.CurrencyCode())
+	if err != nil {
+		return float64(m.Amount)
+	}
+	return float64(m.Amount) / math.Pow10(currency.MinorUnits)
+}
+
+func (m Money) String() string {
+	currency, err := FindCurrency(m.CurrencyCode())
+	if err != nil || currency.MinorUnits == 0 {
+		return fmt.Sprintf("%d %s", m.Amount, m.CurrencyCode())
+	}
+
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	scale := int64(math.Pow10(currency.MinorUnits))
+	return fmt.Sprintf("%s%d.%0*d %s", sign, amount/scale, currency.MinorUnits, amount%scale, currency.Code)
+}
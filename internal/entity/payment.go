@@ -1,14 +1,54 @@
 package entity
 
 import (
+	"fmt"
 	"github.com/google/uuid"
 	"time"
 )
 
+type PaymentStatus string
+
+const (
+	PaymentStatusCaptured PaymentStatus = "CAPTURED"
+	PaymentStatusRefunded PaymentStatus = "REFUNDED"
+)
+
 type Payment struct {
 	Id         uuid.UUID `json:"id"`
 	CustomerId uuid.UUID `json:"customer_id"`
 	MerchantId uuid.UUID `json:"merchant_id"`
-	Amount     int64     `json:"amount"`
-	Timestamp  time.Time `json:"timestamp"`
+	Money
+	FeeAmount    int64               `json:"fee_amount"`
+	NetAmount    int64               `json:"net_amount"`
+	Conversion   *CurrencyConversion `json:"conversion,omitempty"`
+	Status       PaymentStatus       `json:"status,omitempty"`
+	SettlementId *uuid.UUID          `json:"settlement_id,omitempty"`
+	Timestamp    time.Time           `json:"timestamp"`
+}
+
+// CurrentStatus treats records written before statuses existed as captured.
+func (p Payment) CurrentStatus() PaymentStatus {
+	if p.Status == "" {
+		return PaymentStatusCaptured
+	}
+	return p.Status
+}
+
+func (p Payment) IsSettleable() bool {
+	return p.CurrentStatus() == PaymentStatusCaptured && p.SettlementId == nil
+}
+
+// PaymentLimits caps the amount of a single payment, in minor units of the
+// currency it is paid in. Currencies without a limit are not capped.
+type PaymentLimits struct {
+	MaxAmounts map[string]int64
+}
+
+func (l PaymentLimits) Check(money Money) error {
+	maxAmount, ok := l.MaxAmounts[money.CurrencyCode()]
+	if ok && money.Amount > maxAmount {
+		return fmt.Errorf("amount %s exceeds the limit of %s per payment", money,
+			Money{Amount: maxAmount, Currency: money.CurrencyCode()})
+	}
+	return nil
 }
@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type SettlementStatus string
+
+const (
+	SettlementStatusPendingPayout SettlementStatus = "PENDING_PAYOUT"
+	SettlementStatusPaid          SettlementStatus = "PAID"
+)
+
+type Settlement struct {
+	Id           uuid.UUID        `json:"id"`
+	MerchantId   uuid.UUID        `json:"merchant_id"`
+	Currency     string           `json:"currency"`
+	WindowStart  time.Time        `json:"window_start"`
+	WindowEnd    time.Time        `json:"window_end"`
+	PaymentIds   []uuid.UUID      `json:"payment_ids"`
+	PaymentCount int              `json:"payment_count"`
+	GrossAmount  int64            `json:"gross_amount"`
+	FeeAmount    int64            `json:"fee_amount"`
+	NetAmount    int64            `json:"net_amount"`
+	Status       SettlementStatus `json:"status"`
+	CreatedAt    time.Time        `json:"created_at"`
+}
@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"time"
+)
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending    WebhookDeliveryStatus = "PENDING"
+	WebhookDeliveryStatusDelivered  WebhookDeliveryStatus = "DELIVERED"
+	WebhookDeliveryStatusDeadLetter WebhookDeliveryStatus = "DEAD_LETTER"
+)
+
+const (
+	WebhookEventPaymentCaptured   = "payment.captured"
+	WebhookEventSettlementCreated = "settlement.created"
+)
+
+type WebhookDelivery struct {
+	Id               uuid.UUID             `json:"id"`
+	MerchantId       uuid.UUID             `json:"merchant_id"`
+	EventType        string                `json:"event_type"`
+	Url              string                `json:"url"`
+	Payload          json.RawMessage       `json:"payload"`
+	Status           WebhookDeliveryStatus `json:"status"`
+	Attempts         int                   `json:"attempts"`
+	NextAttemptAt    time.Time             `json:"next_attempt_at"`
+	LastError        string                `json:"last_error,omitempty"`
+	LastResponseCode int                   `json:"last_response_code,omitempty"`
+	DeliveredAt      *time.Time            `json:"delivered_at,omitempty"`
+	CreatedAt        time.Time             `json:"created_at"`
+	UpdatedAt        time.Time             `json:"updated_at"`
+}
+
+func (w WebhookDelivery) IsDue(now time.Time) bool {
+	return w.Status == WebhookDeliveryStatusPending && !w.NextAttemptAt.After(now)
+}
@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Default is the registry served on /metrics. It also carries the Go runtime
+// and process collectors.
+var Default = prometheus.NewRegistry()
+
+// DurationBuckets are latency buckets in seconds, from 1ms to 10s.
+var DurationBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+var factory = promauto.With(Default)
+
+var (
+	HttpRequests = factory.NewCounterVec(prometheus.CounterOpts{Name: "http_requests_total",
+		Help: "HTTP requests by method, route and status."}, []string{"method", "route", "status"})
+	HttpRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{Name: "http_request_duration_seconds",
+		Help: "HTTP request latency by method, route and status.", Buckets: DurationBuckets}, []string{"method", "route", "status"})
+	LoginAttempts = factory.NewCounterVec(prometheus.CounterOpts{Name: "auth_login_attempts_total",
+		Help: "Login attempts by result."}, []string{"result"})
+	BlacklistedTokens = factory.NewGauge(prometheus.GaugeOpts{Name: "auth_blacklisted_tokens",
+		Help: "Tokens in the logout blacklist."})
+	Payments = factory.NewCounterVec(prometheus.CounterOpts{Name: "payments_total",
+		Help: "Payments created by merchant and currency."}, []string{"merchant_id", "currency"})
+	PaymentAmount = factory.NewCounterVec(prometheus.CounterOpts{Name: "payments_amount_minor_units_total",
+		Help: "Sum of created payment amounts in minor units, by merchant and currency."}, []string{"merchant_id", "currency"})
+	RateLimitedRequests = factory.NewCounterVec(prometheus.CounterOpts{Name: "http_rate_limited_requests_total",
+		Help: "Requests rejected with 429 by rate limit policy."}, []string{"policy"})
+	RepositoryOperationDuration = factory.NewHistogramVec(prometheus.HistogramOpts{Name: "repository_operation_duration_seconds",
+		Help: "Latency of data file operations by operation and file.", Buckets: DurationBuckets}, []string{"operation", "file"})
+)
+
+const (
+	LoginSuccess = "success"
+	LoginFailure = "failure"
+)
+
+func init() {
+	Default.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+}
+
+// Handler serves Default in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Default, promhttp.HandlerOpts{})
+}
+
+var gaugeFuncs = struct {
+	sync.Mutex
+	registered map[string]prometheus.Collector
+}{registered: map[string]prometheus.Collector{}}
+
+// SetGaugeFunc reports the value of fn at scrape time, for values another
+// component already tracks. Setting it again replaces the previous fn.
+func SetGaugeFunc(name, help string, fn func() float64) {
+	gaugeFuncs.Lock()
+	defer gaugeFuncs.Unlock()
+
+	if previous, ok := gaugeFuncs.registered[name]; ok {
+		Default.Unregister(previous)
+	}
+	gauge := prometheus.NewGaugeFunc(prometheus.GaugeOpts{Name: name, Help: help}, fn)
+	Default.MustRegister(gauge)
+	gaugeFuncs.registered[name] = gauge
+}
+
+// ObserveRepositoryOperation records the time since start for a read, write,
+// stream or commit on filename. Only the base name is used as the label.
+func ObserveRepositoryOperation(operation, filename string, start time.Time) {
+	RepositoryOperationDuration.WithLabelValues(operation, filepath.Base(filename)).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,19 @@
+package model
+
+type AuditBrokenLink struct {
+	Sequence int64  `json:"sequence"`
+	Id       string `json:"id,omitempty"`
+	Reason   string `json:"reason"`
+}
+
+type AuditVerificationResult struct {
+	Valid               bool             `json:"valid"`
+	Records             int              `json:"records"`
+	Archives            int              `json:"archives"`
+	LegacyRecords       int              `json:"legacyRecords"`
+	LastSequence        int64            `json:"lastSequence"`
+	LastHash            string           `json:"lastHash,omitempty"`
+	MacChecked          bool             `json:"macChecked"`
+	CheckpointsVerified int              `json:"checkpointsVerified"`
+	BrokenLink          *AuditBrokenLink `json:"brokenLink,omitempty"`
+}
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type LoginRequest struct {
 	Username string `json:"username" binding:"required"`
 	Password string `json:"password" binding:"required"`
@@ -8,3 +10,35 @@ type LoginRequest struct {
 type LoginResponse struct {
 	AccessToken string `json:"accessToken"`
 }
+
+type CustomerQueryRequest struct {
+	// Search matches part of the username or the whole customer id.
+	Search   string `form:"search"`
+	Status   string `form:"status"`
+	Page     int    `form:"page"`
+	PageSize int    `form:"pageSize"`
+}
+
+type CustomerResponse struct {
+	Id                string     `json:"id"`
+	Username          string     `json:"username"`
+	Role              string     `json:"role"`
+	Status            string     `json:"status"`
+	SessionsRevokedAt *time.Time `json:"sessionsRevokedAt,omitempty"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	UpdatedAt         time.Time  `json:"updatedAt"`
+}
+
+type CustomerPage struct {
+	Items      []CustomerResponse `json:"items"`
+	Page       int                `json:"page"`
+	PageSize   int                `json:"pageSize"`
+	TotalItems int                `json:"totalItems"`
+	TotalPages int                `json:"totalPages"`
+}
+
+type CustomerDetailResponse struct {
+	Customer       CustomerResponse  `json:"customer"`
+	RecentPayments []PaymentResponse `json:"recentPayments"`
+	RecentHistory  []HistoryResponse `json:"recentHistory"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// ExchangeRateRequest replaces the whole rate table. Every rate is stamped with
+// the time it is published, so the table never carries hand-set timestamps.
+type ExchangeRateRequest struct {
+	Rates []ExchangeRateEntry `json:"rates" binding:"required,min=1,dive"`
+}
+
+type ExchangeRateEntry struct {
+	Base  string  `json:"base" binding:"required"`
+	Quote string  `json:"quote" binding:"required"`
+	Rate  float64 `json:"rate" binding:"required,gt=0"`
+}
+
+type ExchangeRateResponse struct {
+	Base      string    `json:"base"`
+	Quote     string    `json:"quote"`
+	Rate      float64   `json:"rate"`
+	Timestamp time.Time `json:"timestamp"`
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+type ReadinessCheckResponse struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type ReadinessResponse struct {
+	Status string                   `json:"status"`
+	Checks []ReadinessCheckResponse `json:"checks"`
+}
+
+type VersionResponse struct {
+	Version       string    `json:"version"`
+	Commit        string    `json:"commit"`
+	BuildTime     string    `json:"buildTime,omitempty"`
+	GoVersion     string    `json:"goVersion"`
+	StartedAt     time.Time `json:"startedAt"`
+	UptimeSeconds int64     `json:"uptimeSeconds"`
+}
@@ -0,0 +1,57 @@
+package model
+
+import "time"
+
+type HistoryQueryRequest struct {
+	CustomerId string `form:"customerId"`
+	Action     string `form:"action"`
+	Outcome    string `form:"outcome"`
+	From       string `form:"from"`
+	To         string `form:"to"`
+	Page       int    `form:"page"`
+	PageSize   int    `form:"pageSize"`
+	Format     string `form:"format"`
+	// IncludeArchived also searches archived history in the requested range.
+	IncludeArchived bool `form:"includeArchived"`
+}
+
+type HistoryResponse struct {
+	Id          string    `json:"id"`
+	Sequence    int64     `json:"sequence,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+	Action      string    `json:"action"`
+	CustomerId  string    `json:"customerId"`
+	ActorType   string    `json:"actorType"`
+	SubjectType string    `json:"subjectType,omitempty"`
+	SubjectId   string    `json:"subjectId,omitempty"`
+	Outcome     string    `json:"outcome"`
+	ErrorCode   string    `json:"errorCode,omitempty"`
+	Message     string    `json:"message,omitempty"`
+	Ip          string    `json:"ip,omitempty"`
+	UserAgent   string    `json:"userAgent,omitempty"`
+	RequestId   string    `json:"requestId,omitempty"`
+}
+
+type HistoryPage struct {
+	Items      []HistoryResponse `json:"items"`
+	Page       int               `json:"page"`
+	PageSize   int               `json:"pageSize"`
+	TotalItems int               `json:"totalItems"`
+	TotalPages int               `json:"totalPages"`
+}
+
+type HistoryArchiveRequest struct {
+	// OlderThanDays overrides the configured retention for this run.
+	OlderThanDays int `form:"olderThanDays" binding:"gte=0"`
+}
+
+type HistoryArchiveResponse struct {
+	Id            string    `json:"id"`
+	Filename      string    `json:"filename"`
+	From          time.Time `json:"from"`
+	To            time.Time `json:"to"`
+	Records       int       `json:"records"`
+	FirstSequence int64     `json:"firstSequence,omitempty"`
+	LastSequence  int64     `json:"lastSequence,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
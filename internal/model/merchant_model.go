@@ -0,0 +1,55 @@
+package model
+
+import "time"
+
+// MerchantRequest replaces a merchant's settings. The webhook secret is never
+// returned, so an empty WebhookSecret keeps the current one on update.
+type MerchantRequest struct {
+	Name               string   `json:"name" binding:"required"`
+	AcceptedCurrencies []string `json:"acceptedCurrencies"`
+	FeePlan            FeePlan  `json:"feePlan"`
+	WebhookUrl         string   `json:"webhookUrl"`
+	WebhookSecret      string   `json:"webhookSecret"`
+}
+
+type FeePlan struct {
+	Type                  string    `json:"type,omitempty"`
+	PercentageBasisPoints int64     `json:"percentageBasisPoints,omitempty"`
+	FixedAmount           int64     `json:"fixedAmount,omitempty"`
+	Tiers                 []FeeTier `json:"tiers,omitempty"`
+	MinFee                int64     `json:"minFee,omitempty"`
+	MaxFee                int64     `json:"maxFee,omitempty"`
+}
+
+type FeeTier struct {
+	UpTo                  int64 `json:"upTo,omitempty"`
+	PercentageBasisPoints int64 `json:"percentageBasisPoints,omitempty"`
+	FixedAmount           int64 `json:"fixedAmount,omitempty"`
+}
+
+type MerchantQueryRequest struct {
+	// Search matches part of the merchant name or the whole merchant id.
+	Search   string `form:"search"`
+	Status   string `form:"status"`
+	Page     int    `form:"page"`
+	PageSize int    `form:"pageSize"`
+}
+
+type MerchantResponse struct {
+	Id                 string    `json:"id"`
+	Name               string    `json:"name"`
+	AcceptedCurrencies []string  `json:"acceptedCurrencies"`
+	FeePlan            FeePlan   `json:"feePlan"`
+	WebhookUrl         string    `json:"webhookUrl,omitempty"`
+	Status             string    `json:"status"`
+	CreatedAt          time.Time `json:"createdAt"`
+	UpdatedAt          time.Time `json:"updatedAt"`
+}
+
+type MerchantPage struct {
+	Items      []MerchantResponse `json:"items"`
+	Page       int                `json:"page"`
+	PageSize   int                `json:"pageSize"`
+	TotalItems int                `json:"totalItems"`
+	TotalPages int                `json:"totalPages"`
+}
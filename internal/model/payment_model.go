@@ -1,6 +1,32 @@
 package model
 
+import "time"
+
 type PaymentRequest struct {
 	MerchantId string `json:"merchantId" binding:"required" validation:"min=1"`
 	Amount     int64  `json:"amount" binding:"required"`
+	Currency   string `json:"currency" binding:"omitempty,len=3"`
+}
+
+type PaymentResponse struct {
+	Id           string                     `json:"id"`
+	CustomerId   string                     `json:"customerId"`
+	MerchantId   string                     `json:"merchantId"`
+	Amount       int64                      `json:"amount"`
+	Currency     string                     `json:"currency"`
+	FeeAmount    int64                      `json:"feeAmount"`
+	NetAmount    int64                      `json:"netAmount"`
+	Conversion   *PaymentConversionResponse `json:"conversion,omitempty"`
+	Status       string                     `json:"status"`
+	SettlementId string                     `json:"settlementId,omitempty"`
+	Timestamp    time.Time                  `json:"timestamp"`
+}
+
+type PaymentConversionResponse struct {
+	OriginalAmount    int64     `json:"originalAmount"`
+	OriginalCurrency  string    `json:"originalCurrency"`
+	MidRate           float64   `json:"midRate"`
+	AppliedRate       float64   `json:"appliedRate"`
+	SpreadBasisPoints int64     `json:"spreadBasisPoints"`
+	RateTimestamp     time.Time `json:"rateTimestamp"`
 }
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+type ReconciliationReportRequest struct {
+	MerchantId string `form:"merchantId" binding:"required"`
+	From       string `form:"from" binding:"required"`
+	To         string `form:"to" binding:"required"`
+	Format     string `form:"format"`
+}
+
+type ReconciliationRow struct {
+	Type         string    `json:"type"`
+	PaymentId    string    `json:"paymentId"`
+	CustomerId   string    `json:"customerId"`
+	Timestamp    time.Time `json:"timestamp"`
+	Currency     string    `json:"currency"`
+	GrossAmount  int64     `json:"grossAmount"`
+	FeeAmount    int64     `json:"feeAmount"`
+	NetAmount    int64     `json:"netAmount"`
+	Status       string    `json:"status"`
+	SettlementId string    `json:"settlementId,omitempty"`
+}
+
+type ReconciliationTotal struct {
+	Currency     string `json:"currency"`
+	PaymentCount int    `json:"paymentCount"`
+	RefundCount  int    `json:"refundCount"`
+	GrossAmount  int64  `json:"grossAmount"`
+	FeeAmount    int64  `json:"feeAmount"`
+	NetAmount    int64  `json:"netAmount"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+type SettlementRequest struct {
+	Cutoff *time.Time `json:"cutoff"`
+}
+
+type SettlementResponse struct {
+	Id           string    `json:"id"`
+	MerchantId   string    `json:"merchantId"`
+	Currency     string    `json:"currency"`
+	WindowStart  time.Time `json:"windowStart"`
+	WindowEnd    time.Time `json:"windowEnd"`
+	PaymentIds   []string  `json:"paymentIds"`
+	PaymentCount int       `json:"paymentCount"`
+	GrossAmount  int64     `json:"grossAmount"`
+	FeeAmount    int64     `json:"feeAmount"`
+	NetAmount    int64     `json:"netAmount"`
+	Status       string    `json:"status"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type WebhookEvent struct {
+	Id        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"createdAt"`
+	Data      interface{} `json:"data"`
+}
+
+type WebhookDeliveryResponse struct {
+	Id               string          `json:"id"`
+	MerchantId       string          `json:"merchantId"`
+	EventType        string          `json:"eventType"`
+	Url              string          `json:"url"`
+	Payload          json.RawMessage `json:"payload"`
+	Status           string          `json:"status"`
+	Attempts         int             `json:"attempts"`
+	NextAttemptAt    time.Time       `json:"nextAttemptAt"`
+	LastError        string          `json:"lastError,omitempty"`
+	LastResponseCode int             `json:"lastResponseCode,omitempty"`
+	DeliveredAt      *time.Time      `json:"deliveredAt,omitempty"`
+	CreatedAt        time.Time       `json:"createdAt"`
+	UpdatedAt        time.Time       `json:"updatedAt"`
+}
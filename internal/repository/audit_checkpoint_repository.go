@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+	"merchant_bank_payment_go_api/internal/entity"
+)
+
+type AuditCheckpointRepository interface {
+	LoadCheckpoints(ctx context.Context) ([]entity.AuditCheckpoint, error)
+	AddCheckpoint(ctx context.Context, checkpoint entity.AuditCheckpoint) error
+}
@@ -1,8 +1,14 @@
 package repository
 
+import (
+	"context"
+	"merchant_bank_payment_go_api/internal/utils"
+)
+
 type AuthRepository interface {
-	LoadBlacklist() ([]string, error)
-	SaveBlacklist(blacklistedTokens []string) error
-	AddToBlacklist(token string) error
-	IsTokenBlacklisted(token string) (bool, error)
+	LoadBlacklist(ctx context.Context) ([]string, error)
+	SaveBlacklist(ctx context.Context, blacklistedTokens []string) error
+	AddToBlacklist(ctx context.Context, token string) error
+	StageAddToBlacklist(ctx context.Context, tx *utils.JsonFileTransaction, token string) error
+	IsTokenBlacklisted(ctx context.Context, token string) (bool, error)
 }
@@ -0,0 +1,24 @@
+package repository
+
+type HistoryOverflowPolicy string
+
+const (
+	// HistoryOverflowBlock makes AddHistory wait until the queue has room.
+	HistoryOverflowBlock HistoryOverflowPolicy = "BLOCK"
+	// HistoryOverflowDrop discards the entry and counts it as dropped.
+	HistoryOverflowDrop HistoryOverflowPolicy = "DROP"
+)
+
+type BufferedHistoryStats struct {
+	Queued  int64
+	Dropped int64
+	Written int64
+	Failed  int64
+}
+
+type BufferedHistoryRepository interface {
+	HistoryRepository
+	Start() func()
+	Flush() error
+	Stats() BufferedHistoryStats
+}
@@ -1,12 +1,14 @@
 package repository
 
 import (
+	"context"
 	"github.com/google/uuid"
 	"merchant_bank_payment_go_api/internal/entity"
 )
 
 type CustomerRepository interface {
-	LoadCustomers() ([]entity.Customer, error)
-	FindById(id uuid.UUID) (entity.Customer, error)
-	FindByUsername(username string) (entity.Customer, error)
+	LoadCustomers(ctx context.Context) ([]entity.Customer, error)
+	FindById(ctx context.Context, id uuid.UUID) (entity.Customer, error)
+	FindByUsername(ctx context.Context, username string) (entity.Customer, error)
+	Update(ctx context.Context, customer entity.Customer) error
 }
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+	"merchant_bank_payment_go_api/internal/entity"
+)
+
+type ExchangeRateRepository interface {
+	LoadRates(ctx context.Context) ([]entity.ExchangeRate, error)
+	FindRate(ctx context.Context, base, quote string) (entity.ExchangeRate, error)
+	SaveRates(ctx context.Context, rates []entity.ExchangeRate) error
+}
@@ -0,0 +1,9 @@
+package repository
+
+import "context"
+
+// HealthCheckRepository is implemented by repositories that can verify their
+// backing store is readable and writable.
+type HealthCheckRepository interface {
+	CheckHealth(ctx context.Context) error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"merchant_bank_payment_go_api/internal/entity"
+	"time"
+)
+
+type HistoryArchiveRepository interface {
+	LoadArchives(ctx context.Context) ([]entity.HistoryArchive, error)
+	WriteArchive(ctx context.Context, histories []entity.History, createdAt time.Time) (entity.HistoryArchive, error)
+	LoadArchivedHistories(ctx context.Context, archive entity.HistoryArchive) ([]entity.History, error)
+}
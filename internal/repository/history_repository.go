@@ -1,9 +1,17 @@
 package repository
 
-import "merchant_bank_payment_go_api/internal/entity"
+import (
+	"context"
+	"merchant_bank_payment_go_api/internal/entity"
+	"time"
+)
 
 type HistoryRepository interface {
-	LoadHistories() ([]entity.History, error)
-	SaveHistories(histories []entity.History) error
-	AddHistory(history entity.History) error
+	LoadHistories(ctx context.Context) ([]entity.History, error)
+	SaveHistories(ctx context.Context, histories []entity.History) error
+	AddHistory(ctx context.Context, history entity.History) error
+	AddHistories(ctx context.Context, histories []entity.History) error
+	// PruneHistories removes the leading records older than before and hands
+	// them to archive first. Nothing is removed when archive fails.
+	PruneHistories(ctx context.Context, before time.Time, archive func(histories []entity.History) error) (int, error)
 }
@@ -0,0 +1,76 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/tracing"
+	"merchant_bank_payment_go_api/internal/utils"
+	"sync"
+)
+
+type AuditCheckpointRepositoryImpl struct {
+	Log      *logrus.Logger
+	Filename string
+	mu       sync.Mutex
+}
+
+func NewAuditCheckpointRepositoryImpl(log *logrus.Logger, filename string) *AuditCheckpointRepositoryImpl {
+	return &AuditCheckpointRepositoryImpl{
+		Log:      log,
+		Filename: filename,
+	}
+}
+
+func (a *AuditCheckpointRepositoryImpl) LoadCheckpoints(ctx context.Context) ([]entity.AuditCheckpoint, error) {
+	ctx, span := tracing.Start(ctx, "AuditCheckpointRepository.LoadCheckpoints")
+	defer span.End()
+
+	a.Log.WithContext(ctx).Debugf("Loading audit checkpoints from file: %s", a.Filename)
+
+	file, err := utils.ReadJsonFile(ctx, a.Filename, a.Log)
+	if err != nil {
+		a.Log.WithContext(ctx).Errorf("Failed to read file %s: %v", a.Filename, err)
+		return nil, fmt.Errorf("failed to read audit checkpoints file: %w", err)
+	}
+
+	var checkpoints []entity.AuditCheckpoint
+	if err := json.Unmarshal(file, &checkpoints); err != nil {
+		a.Log.WithContext(ctx).Errorf("Failed to decode JSON from file %s: %v", a.Filename, err)
+		return nil, fmt.Errorf("failed to parse audit checkpoints: %w", err)
+	}
+
+	a.Log.WithContext(ctx).Infof("Successfully loaded %d audit checkpoints", len(checkpoints))
+	return checkpoints, nil
+}
+
+func (a *AuditCheckpointRepositoryImpl) AddCheckpoint(ctx context.Context, checkpoint entity.AuditCheckpoint) error {
+	ctx, span := tracing.Start(ctx, "AuditCheckpointRepository.AddCheckpoint")
+	defer span.End()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	checkpoints, err := a.LoadCheckpoints(ctx)
+	if err != nil {
+		return err
+	}
+
+	checkpoints = append(checkpoints, checkpoint)
+	if err := utils.WriteJsonFile(ctx, a.Filename, checkpoints, a.Log); err != nil {
+		a.Log.WithContext(ctx).Errorf("Error saving audit checkpoints to file %s: %v", a.Filename, err)
+		return fmt.Errorf("failed to save audit checkpoints: %w", err)
+	}
+
+	a.Log.WithContext(ctx).Infof("Added audit checkpoint at sequence %d", checkpoint.Sequence)
+	return nil
+}
+
+func (a *AuditCheckpointRepositoryImpl) CheckHealth(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "AuditCheckpointRepository.CheckHealth")
+	defer span.End()
+
+	return utils.CheckFileAccess(ctx, a.Filename)
+}
@@ -1,9 +1,12 @@
 package impl
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/sirupsen/logrus"
+	"merchant_bank_payment_go_api/internal/metrics"
+	"merchant_bank_payment_go_api/internal/tracing"
 	"merchant_bank_payment_go_api/internal/utils"
 )
 
@@ -19,70 +22,112 @@ func NewAuthRepository(log *logrus.Logger, filename string) *AuthRepositoryImpl
 	}
 }
 
-func (r *AuthRepositoryImpl) LoadBlacklist() ([]string, error) {
-	r.Log.Debugf("Loading blacklisted tokens from file: %s", r.Filename)
+func (r *AuthRepositoryImpl) LoadBlacklist(ctx context.Context) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "AuthRepository.LoadBlacklist")
+	defer span.End()
 
-	file, err := utils.ReadJsonFile(r.Filename, r.Log)
+	r.Log.WithContext(ctx).Debugf("Loading blacklisted tokens from file: %s", r.Filename)
+
+	file, err := utils.ReadJsonFile(ctx, r.Filename, r.Log)
 	if err != nil {
-		r.Log.Errorf("Failed to read file %s: %v", r.Filename, err)
+		r.Log.WithContext(ctx).Errorf("Failed to read file %s: %v", r.Filename, err)
 		return nil, err
 	}
 
 	var blacklistedTokens []string
 	if err := json.Unmarshal(file, &blacklistedTokens); err != nil {
-		r.Log.Errorf("Failed to decode JSON from file %s: %v", r.Filename, err)
+		r.Log.WithContext(ctx).Errorf("Failed to decode JSON from file %s: %v", r.Filename, err)
 		return nil, err
 	}
 
-	r.Log.Infof("Loaded %d blacklisted tokens", len(blacklistedTokens))
+	r.Log.WithContext(ctx).Infof("Loaded %d blacklisted tokens", len(blacklistedTokens))
+	metrics.BlacklistedTokens.Set(float64(len(blacklistedTokens)))
 	return blacklistedTokens, nil
 }
 
-func (r *AuthRepositoryImpl) SaveBlacklist(blacklistedTokens []string) error {
-	r.Log.Infof("Saving %d blacklisted tokens to file: %s", len(blacklistedTokens), r.Filename)
+func (r *AuthRepositoryImpl) SaveBlacklist(ctx context.Context, blacklistedTokens []string) error {
+	ctx, span := tracing.Start(ctx, "AuthRepository.SaveBlacklist")
+	defer span.End()
+
+	r.Log.WithContext(ctx).Infof("Saving %d blacklisted tokens to file: %s", len(blacklistedTokens), r.Filename)
 
-	if err := utils.WriteJsonFile(r.Filename, blacklistedTokens, r.Log); err != nil {
-		r.Log.Errorf("Failed to save blacklist to file %s: %v", r.Filename, err)
+	if err := utils.WriteJsonFile(ctx, r.Filename, blacklistedTokens, r.Log); err != nil {
+		r.Log.WithContext(ctx).Errorf("Failed to save blacklist to file %s: %v", r.Filename, err)
 		return fmt.Errorf("error saving blacklist: %w", err)
 	}
 
-	r.Log.Infof("Successfully saved blacklist to %s", r.Filename)
+	r.Log.WithContext(ctx).Infof("Successfully saved blacklist to %s", r.Filename)
+	metrics.BlacklistedTokens.Set(float64(len(blacklistedTokens)))
 	return nil
 }
 
-func (r *AuthRepositoryImpl) AddToBlacklist(token string) error {
-	blacklistedTokens, err := r.LoadBlacklist()
+func (r *AuthRepositoryImpl) AddToBlacklist(ctx context.Context, token string) error {
+	ctx, span := tracing.Start(ctx, "AuthRepository.AddToBlacklist")
+	defer span.End()
+
+	blacklistedTokens, err := r.LoadBlacklist(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to load blacklist: %w", err)
 	}
 
 	for _, blacklistedToken := range blacklistedTokens {
 		if blacklistedToken == token {
-			r.Log.Warnf("Token %s is already blacklisted", token)
-			return fmt.Errorf("token %s is already blacklisted", token)
+			r.Log.WithContext(ctx).Warn("Token is already blacklisted")
+			return fmt.Errorf("token is already blacklisted")
 		}
 	}
 
-	r.Log.Infof("Adding token %s to blacklist", token)
+	r.Log.WithContext(ctx).Info("Adding token to blacklist")
 
 	blacklistedTokens = append(blacklistedTokens, token)
 
-	return r.SaveBlacklist(blacklistedTokens)
+	return r.SaveBlacklist(ctx, blacklistedTokens)
+}
+
+func (r *AuthRepositoryImpl) StageAddToBlacklist(ctx context.Context, tx *utils.JsonFileTransaction, token string) error {
+	ctx, span := tracing.Start(ctx, "AuthRepository.StageAddToBlacklist")
+	defer span.End()
+
+	blacklistedTokens, err := r.LoadBlacklist(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load blacklist: %w", err)
+	}
+
+	for _, blacklistedToken := range blacklistedTokens {
+		if blacklistedToken == token {
+			r.Log.WithContext(ctx).Warn("Token is already blacklisted")
+			return fmt.Errorf("token is already blacklisted")
+		}
+	}
+
+	r.Log.WithContext(ctx).Info("Staging token for blacklist")
+	tx.Stage(r.Filename, append(blacklistedTokens, token))
+	return nil
 }
 
-func (r *AuthRepositoryImpl) IsTokenBlacklisted(token string) (bool, error) {
-	blacklistedTokens, err := r.LoadBlacklist()
+func (r *AuthRepositoryImpl) IsTokenBlacklisted(ctx context.Context, token string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "AuthRepository.IsTokenBlacklisted")
+	defer span.End()
+
+	blacklistedTokens, err := r.LoadBlacklist(ctx)
 	if err != nil {
 		return false, fmt.Errorf("failed to load blacklist: %w", err)
 	}
 
 	for _, blacklistedToken := range blacklistedTokens {
 		if blacklistedToken == token {
-			r.Log.Infof("Token %s is blacklisted", token)
+			r.Log.WithContext(ctx).Info("Token is blacklisted")
 			return true, nil
 		}
 	}
 
-	r.Log.Debugf("Token %s is not blacklisted", token)
+	r.Log.WithContext(ctx).Debug("Token is not blacklisted")
 	return false, nil
 }
+
+func (r *AuthRepositoryImpl) CheckHealth(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "AuthRepository.CheckHealth")
+	defer span.End()
+
+	return utils.CheckFileAccess(ctx, r.Filename)
+}
@@ -0,0 +1,235 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/repository"
+	"merchant_bank_payment_go_api/internal/tracing"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BufferedHistoryRepositoryImpl queues history entries in memory and appends
+// them to the wrapped repository in batches, so a request no longer rewrites
+// the history file once per entry. Until Start is called, and after it has been
+// stopped, entries are written through synchronously.
+type BufferedHistoryRepositoryImpl struct {
+	Log            *logrus.Logger
+	Repository     repository.HistoryRepository
+	BatchSize      int
+	FlushInterval  time.Duration
+	OverflowPolicy repository.HistoryOverflowPolicy
+	queue          chan entity.History
+	flushRequests  chan chan error
+	done           chan struct{}
+	mu             sync.RWMutex
+	started        bool
+	closed         bool
+	queued         atomic.Int64
+	dropped        atomic.Int64
+	written        atomic.Int64
+	failed         atomic.Int64
+}
+
+func NewBufferedHistoryRepositoryImpl(log *logrus.Logger, historyRepository repository.HistoryRepository, queueSize, batchSize int,
+	flushInterval time.Duration, overflowPolicy repository.HistoryOverflowPolicy) *BufferedHistoryRepositoryImpl {
+	return &BufferedHistoryRepositoryImpl{
+		Log:            log,
+		Repository:     historyRepository,
+		BatchSize:      batchSize,
+		FlushInterval:  flushInterval,
+		OverflowPolicy: overflowPolicy,
+		queue:          make(chan entity.History, queueSize),
+		flushRequests:  make(chan chan error),
+		done:           make(chan struct{}),
+	}
+}
+
+func (b *BufferedHistoryRepositoryImpl) LoadHistories(ctx context.Context) ([]entity.History, error) {
+	ctx, span := tracing.Start(ctx, "BufferedHistoryRepository.LoadHistories")
+	defer span.End()
+
+	if err := b.Flush(); err != nil {
+		b.Log.WithContext(ctx).Warnf("Loading histories without %d unwritten entries: %v", b.queued.Load(), err)
+	}
+	return b.Repository.LoadHistories(ctx)
+}
+
+func (b *BufferedHistoryRepositoryImpl) SaveHistories(ctx context.Context, histories []entity.History) error {
+	ctx, span := tracing.Start(ctx, "BufferedHistoryRepository.SaveHistories")
+	defer span.End()
+
+	if err := b.Flush(); err != nil {
+		return err
+	}
+	return b.Repository.SaveHistories(ctx, histories)
+}
+
+func (b *BufferedHistoryRepositoryImpl) PruneHistories(ctx context.Context, before time.Time, archive func(histories []entity.History) error) (int, error) {
+	ctx, span := tracing.Start(ctx, "BufferedHistoryRepository.PruneHistories")
+	defer span.End()
+
+	if err := b.Flush(); err != nil {
+		return 0, err
+	}
+	return b.Repository.PruneHistories(ctx, before, archive)
+}
+
+func (b *BufferedHistoryRepositoryImpl) AddHistory(ctx context.Context, history entity.History) error {
+	ctx, span := tracing.Start(ctx, "BufferedHistoryRepository.AddHistory")
+	defer span.End()
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if !b.started || b.closed {
+		return b.Repository.AddHistory(ctx, history)
+	}
+
+	b.queued.Add(1)
+	if b.OverflowPolicy == repository.HistoryOverflowDrop {
+		select {
+		case b.queue <- history:
+		default:
+			b.queued.Add(-1)
+			dropped := b.dropped.Add(1)
+			b.Log.WithContext(ctx).Warnf("History queue is full, dropped %s entry for %s (%d dropped so far)", history.Action, history.CustomerId, dropped)
+		}
+		return nil
+	}
+
+	b.queue <- history
+	return nil
+}
+
+func (b *BufferedHistoryRepositoryImpl) AddHistories(ctx context.Context, histories []entity.History) error {
+	ctx, span := tracing.Start(ctx, "BufferedHistoryRepository.AddHistories")
+	defer span.End()
+
+	for _, history := range histories {
+		if err := b.AddHistory(ctx, history); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start runs the background writer and returns a function that stops it after
+// writing every entry still in the queue.
+func (b *BufferedHistoryRepositoryImpl) Start() func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.started {
+		return func() {}
+	}
+	b.started = true
+	go b.run()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			b.closed = true
+			close(b.queue)
+			b.mu.Unlock()
+
+			<-b.done
+			stats := b.Stats()
+			b.Log.Infof("History writer stopped: written=%d failed=%d dropped=%d", stats.Written, stats.Failed, stats.Dropped)
+		})
+	}
+}
+
+// Flush writes every entry queued so far and waits for the write to finish.
+func (b *BufferedHistoryRepositoryImpl) Flush() error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if !b.started || b.closed {
+		return nil
+	}
+
+	reply := make(chan error, 1)
+	b.flushRequests <- reply
+	return <-reply
+}
+
+// CheckHealth fails while the queue is full, since new entries would block or
+// be dropped, and otherwise checks the wrapped repository.
+func (b *BufferedHistoryRepositoryImpl) CheckHealth(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "BufferedHistoryRepository.CheckHealth")
+	defer span.End()
+
+	if cap(b.queue) > 0 && len(b.queue) >= cap(b.queue) {
+		return fmt.Errorf("history queue is full (%d entries)", cap(b.queue))
+	}
+	if checker, ok := b.Repository.(repository.HealthCheckRepository); ok {
+		return checker.CheckHealth(ctx)
+	}
+	return nil
+}
+
+func (b *BufferedHistoryRepositoryImpl) Stats() repository.BufferedHistoryStats {
+	return repository.BufferedHistoryStats{
+		Queued:  b.queued.Load(),
+		Dropped: b.dropped.Load(),
+		Written: b.written.Load(),
+		Failed:  b.failed.Load(),
+	}
+}
+
+func (b *BufferedHistoryRepositoryImpl) run() {
+	defer close(b.done)
+
+	ticker := time.NewTicker(b.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]entity.History, 0, b.BatchSize)
+	for {
+		select {
+		case history, ok := <-b.queue:
+			if !ok {
+				b.write(batch)
+				return
+			}
+			batch = append(batch, history)
+			if len(batch) >= b.BatchSize {
+				b.write(batch)
+				batch = make([]entity.History, 0, b.BatchSize)
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				b.write(batch)
+				batch = make([]entity.History, 0, b.BatchSize)
+			}
+		case reply := <-b.flushRequests:
+			for pending := len(b.queue); pending > 0; pending-- {
+				batch = append(batch, <-b.queue)
+			}
+			reply <- b.write(batch)
+			batch = make([]entity.History, 0, b.BatchSize)
+		}
+	}
+}
+
+func (b *BufferedHistoryRepositoryImpl) write(batch []entity.History) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	err := b.Repository.AddHistories(context.Background(), batch)
+	b.queued.Add(-int64(len(batch)))
+	if err != nil {
+		b.failed.Add(int64(len(batch)))
+		b.Log.Errorf("Failed to write %d history entries: %v", len(batch), err)
+		return err
+	}
+
+	b.written.Add(int64(len(batch)))
+	b.Log.Debugf("Wrote %d history entries", len(batch))
+	return nil
+}
@@ -1,17 +1,21 @@
 package impl
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/tracing"
 	"merchant_bank_payment_go_api/internal/utils"
+	"sync"
 )
 
 type CustomerRepositoryImpl struct {
 	Log      *logrus.Logger
 	Filename string
+	mu       sync.Mutex
 }
 
 func NewCustomerRepositoryImpl(log *logrus.Logger, filename string) *CustomerRepositoryImpl {
@@ -21,64 +25,109 @@ func NewCustomerRepositoryImpl(log *logrus.Logger, filename string) *CustomerRep
 	}
 }
 
-func (r *CustomerRepositoryImpl) LoadCustomers() ([]entity.Customer, error) {
-	r.Log.Debugf("Loading customers from file: %s", r.Filename)
+func (r *CustomerRepositoryImpl) LoadCustomers(ctx context.Context) ([]entity.Customer, error) {
+	ctx, span := tracing.Start(ctx, "CustomerRepository.LoadCustomers")
+	defer span.End()
 
-	file, err := utils.ReadJsonFile(r.Filename, r.Log)
+	r.Log.WithContext(ctx).Debugf("Loading customers from file: %s", r.Filename)
+
+	file, err := utils.ReadJsonFile(ctx, r.Filename, r.Log)
 	if err != nil {
-		r.Log.Errorf("Error reading file %s: %v", r.Filename, err)
+		r.Log.WithContext(ctx).Errorf("Error reading file %s: %v", r.Filename, err)
 		return nil, err
 	}
 
 	var customers []entity.Customer
 	err = json.Unmarshal(file, &customers)
 	if err != nil {
-		r.Log.Errorf("Error decoding JSON from file %s: %v", r.Filename, err)
+		r.Log.WithContext(ctx).Errorf("Error decoding JSON from file %s: %v", r.Filename, err)
 		return nil, err
 	}
 
-	r.Log.Infof("Successfully loaded %d customers from %s", len(customers), r.Filename)
+	r.Log.WithContext(ctx).Infof("Successfully loaded %d customers from %s", len(customers), r.Filename)
 	return customers, nil
 }
 
-func (r *CustomerRepositoryImpl) FindById(id uuid.UUID) (entity.Customer, error) {
-	r.Log.Debugf("Finding customer by id: %s", id.String())
+func (r *CustomerRepositoryImpl) FindById(ctx context.Context, id uuid.UUID) (entity.Customer, error) {
+	ctx, span := tracing.Start(ctx, "CustomerRepository.FindById")
+	defer span.End()
+
+	r.Log.WithContext(ctx).Debugf("Finding customer by id: %s", id.String())
 
-	customers, err := r.LoadCustomers()
+	customers, err := r.LoadCustomers(ctx)
 	if err != nil {
-		r.Log.Errorf("Error loading customers from file %s: %v", r.Filename, err)
+		r.Log.WithContext(ctx).Errorf("Error loading customers from file %s: %v", r.Filename, err)
 		return entity.Customer{}, err
 	}
 
 	for _, customer := range customers {
 		if customer.Id == id {
-			r.Log.Infof("Found customer with id: %s", id.String())
+			r.Log.WithContext(ctx).Infof("Found customer with id: %s", id.String())
 			return customer, nil
 		}
 	}
 
 	err = fmt.Errorf("customer with id %s not found in %s", id, r.Filename)
-	r.Log.Errorf(err.Error())
+	r.Log.WithContext(ctx).Errorf(err.Error())
 	return entity.Customer{}, err
 }
 
-func (r *CustomerRepositoryImpl) FindByUsername(username string) (entity.Customer, error) {
-	r.Log.Debugf("Finding customer by username: %s", username)
+func (r *CustomerRepositoryImpl) FindByUsername(ctx context.Context, username string) (entity.Customer, error) {
+	ctx, span := tracing.Start(ctx, "CustomerRepository.FindByUsername")
+	defer span.End()
 
-	customers, err := r.LoadCustomers()
+	r.Log.WithContext(ctx).Debugf("Finding customer by username: %s", username)
+
+	customers, err := r.LoadCustomers(ctx)
 	if err != nil {
-		r.Log.Errorf("Error loading customers from file %s: %v", r.Filename, err)
+		r.Log.WithContext(ctx).Errorf("Error loading customers from file %s: %v", r.Filename, err)
 		return entity.Customer{}, err
 	}
 
 	for _, customer := range customers {
 		if customer.Username == username {
-			r.Log.Infof("Found customer with username: %s", username)
+			r.Log.WithContext(ctx).Infof("Found customer with username: %s", username)
 			return customer, nil
 		}
 	}
 
 	err = fmt.Errorf("customer with username %s not found in %s", username, r.Filename)
-	r.Log.Errorf(err.Error())
+	r.Log.WithContext(ctx).Errorf(err.Error())
 	return entity.Customer{}, err
 }
+
+func (r *CustomerRepositoryImpl) Update(ctx context.Context, customer entity.Customer) error {
+	ctx, span := tracing.Start(ctx, "CustomerRepository.Update")
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	customers, err := r.LoadCustomers(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range customers {
+		if customers[i].Id == customer.Id {
+			customers[i] = customer
+			if err := utils.WriteJsonFile(ctx, r.Filename, customers, r.Log); err != nil {
+				r.Log.WithContext(ctx).Errorf("Error saving customers to file %s: %v", r.Filename, err)
+				return fmt.Errorf("failed to save customers: %w", err)
+			}
+			r.Log.WithContext(ctx).Infof("Updated customer %s", customer.Id)
+			return nil
+		}
+	}
+
+	err = fmt.Errorf("customer with id %s not found in %s", customer.Id, r.Filename)
+	r.Log.WithContext(ctx).Errorf(err.Error())
+	return err
+}
+
+func (r *CustomerRepositoryImpl) CheckHealth(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "CustomerRepository.CheckHealth")
+	defer span.End()
+
+	return utils.CheckFileAccess(ctx, r.Filename)
+}
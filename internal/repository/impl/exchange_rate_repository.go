@@ -0,0 +1,113 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/tracing"
+	"merchant_bank_payment_go_api/internal/utils"
+	"strings"
+	"sync"
+)
+
+type ExchangeRateRepositoryImpl struct {
+	Log      *logrus.Logger
+	Filename string
+	// mu keeps readers from seeing a rate table that is half rewritten.
+	mu sync.RWMutex
+}
+
+func NewExchangeRateRepositoryImpl(log *logrus.Logger, filename string) *ExchangeRateRepositoryImpl {
+	return &ExchangeRateRepositoryImpl{
+		Log:      log,
+		Filename: filename,
+	}
+}
+
+func (e *ExchangeRateRepositoryImpl) LoadRates(ctx context.Context) ([]entity.ExchangeRate, error) {
+	ctx, span := tracing.Start(ctx, "ExchangeRateRepository.LoadRates")
+	defer span.End()
+
+	e.Log.WithContext(ctx).Debugf("Loading exchange rates from file: %s", e.Filename)
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	file, err := utils.ReadJsonFile(ctx, e.Filename, e.Log)
+	if err != nil {
+		e.Log.WithContext(ctx).Errorf("Error reading file %s: %v", e.Filename, err)
+		return nil, err
+	}
+
+	var rates []entity.ExchangeRate
+	err = json.Unmarshal(file, &rates)
+	if err != nil {
+		e.Log.WithContext(ctx).Errorf("Error decoding JSON from file %s: %v", e.Filename, err)
+		return nil, err
+	}
+
+	e.Log.WithContext(ctx).Infof("Successfully loaded %d exchange rates from %s", len(rates), e.Filename)
+	return rates, nil
+}
+
+func (e *ExchangeRateRepositoryImpl) FindRate(ctx context.Context, base, quote string) (entity.ExchangeRate, error) {
+	ctx, span := tracing.Start(ctx, "ExchangeRateRepository.FindRate")
+	defer span.End()
+
+	e.Log.WithContext(ctx).Debugf("Finding exchange rate for %s/%s", base, quote)
+
+	rates, err := e.LoadRates(ctx)
+	if err != nil {
+		e.Log.WithContext(ctx).Errorf("Error loading exchange rates from file %s: %v", e.Filename, err)
+		return entity.ExchangeRate{}, err
+	}
+
+	for _, rate := range rates {
+		if strings.EqualFold(rate.Base, base) && strings.EqualFold(rate.Quote, quote) {
+			e.Log.WithContext(ctx).Infof("Found exchange rate for %s/%s", base, quote)
+			return rate, nil
+		}
+	}
+
+	for _, rate := range rates {
+		if strings.EqualFold(rate.Base, quote) && strings.EqualFold(rate.Quote, base) && rate.Rate > 0 {
+			e.Log.WithContext(ctx).Infof("Found inverse exchange rate for %s/%s", base, quote)
+			return entity.ExchangeRate{
+				Base:      strings.ToUpper(base),
+				Quote:     strings.ToUpper(quote),
+				Rate:      1 / rate.Rate,
+				Timestamp: rate.Timestamp,
+			}, nil
+		}
+	}
+
+	err = fmt.Errorf("exchange rate %s/%s not found in %s", base, quote, e.Filename)
+	e.Log.WithContext(ctx).Errorf(err.Error())
+	return entity.ExchangeRate{}, err
+}
+
+// SaveRates replaces the whole rate table.
+func (e *ExchangeRateRepositoryImpl) SaveRates(ctx context.Context, rates []entity.ExchangeRate) error {
+	ctx, span := tracing.Start(ctx, "ExchangeRateRepository.SaveRates")
+	defer span.End()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := utils.WriteJsonFile(ctx, e.Filename, rates, e.Log); err != nil {
+		e.Log.WithContext(ctx).Errorf("Error saving exchange rates to file %s: %v", e.Filename, err)
+		return fmt.Errorf("failed to save exchange rates: %w", err)
+	}
+
+	e.Log.WithContext(ctx).Infof("Saved %d exchange rates to %s", len(rates), e.Filename)
+	return nil
+}
+
+func (e *ExchangeRateRepositoryImpl) CheckHealth(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "ExchangeRateRepository.CheckHealth")
+	defer span.End()
+
+	return utils.CheckFileAccess(ctx, e.Filename)
+}
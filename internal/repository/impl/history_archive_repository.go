@@ -0,0 +1,201 @@
+package impl
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"io"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/tracing"
+	"merchant_bank_payment_go_api/internal/utils"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const historyArchiveIndexFilename = "index.json"
+
+// HistoryArchiveRepositoryImpl keeps archived history as gzip-compressed JSON
+// files in Dir, listed in Dir/index.json in the order they were written.
+type HistoryArchiveRepositoryImpl struct {
+	Log *logrus.Logger
+	Dir string
+	mu  sync.Mutex
+}
+
+func NewHistoryArchiveRepositoryImpl(log *logrus.Logger, dir string) *HistoryArchiveRepositoryImpl {
+	return &HistoryArchiveRepositoryImpl{
+		Log: log,
+		Dir: dir,
+	}
+}
+
+func (h *HistoryArchiveRepositoryImpl) LoadArchives(ctx context.Context) ([]entity.HistoryArchive, error) {
+	ctx, span := tracing.Start(ctx, "HistoryArchiveRepository.LoadArchives")
+	defer span.End()
+
+	indexFilename := filepath.Join(h.Dir, historyArchiveIndexFilename)
+	h.Log.WithContext(ctx).Debugf("Loading history archive index from file: %s", indexFilename)
+
+	if _, err := os.Stat(indexFilename); os.IsNotExist(err) {
+		return []entity.HistoryArchive{}, nil
+	}
+
+	file, err := utils.ReadJsonFile(ctx, indexFilename, h.Log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history archive index: %w", err)
+	}
+
+	var archives []entity.HistoryArchive
+	if err := json.Unmarshal(file, &archives); err != nil {
+		h.Log.WithContext(ctx).Errorf("Failed to decode JSON from file %s: %v", indexFilename, err)
+		return nil, fmt.Errorf("failed to parse history archive index: %w", err)
+	}
+
+	h.Log.WithContext(ctx).Infof("Successfully loaded %d history archives", len(archives))
+	return archives, nil
+}
+
+func (h *HistoryArchiveRepositoryImpl) WriteArchive(ctx context.Context, histories []entity.History, createdAt time.Time) (entity.HistoryArchive, error) {
+	ctx, span := tracing.Start(ctx, "HistoryArchiveRepository.WriteArchive")
+	defer span.End()
+
+	if len(histories) == 0 {
+		return entity.HistoryArchive{}, fmt.Errorf("no histories to archive")
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := os.MkdirAll(h.Dir, 0o755); err != nil {
+		return entity.HistoryArchive{}, fmt.Errorf("failed to create history archive directory: %w", err)
+	}
+
+	first, last := histories[0], histories[len(histories)-1]
+	archive := entity.HistoryArchive{
+		Id:            uuid.New(),
+		From:          first.Timestamp,
+		To:            last.Timestamp,
+		Records:       len(histories),
+		FirstSequence: first.Sequence,
+		LastSequence:  last.Sequence,
+		LastHash:      last.Hash,
+		CreatedAt:     createdAt,
+	}
+	archive.Filename = fmt.Sprintf("history-%s-%s-%s.json.gz", first.Timestamp.UTC().Format("20060102"),
+		last.Timestamp.UTC().Format("20060102"), archive.Id.String()[:8])
+
+	checksum, err := h.writeGzipFile(ctx, filepath.Join(h.Dir, archive.Filename), histories)
+	if err != nil {
+		return entity.HistoryArchive{}, err
+	}
+	archive.Checksum = checksum
+
+	archives, err := h.LoadArchives(ctx)
+	if err == nil {
+		err = utils.WriteJsonFile(ctx, filepath.Join(h.Dir, historyArchiveIndexFilename), append(archives, archive), h.Log)
+	}
+	if err != nil {
+		if removeErr := os.Remove(filepath.Join(h.Dir, archive.Filename)); removeErr != nil {
+			h.Log.WithContext(ctx).Errorf("Failed to remove unindexed history archive %s: %v", archive.Filename, removeErr)
+		}
+		return entity.HistoryArchive{}, fmt.Errorf("failed to update history archive index: %w", err)
+	}
+
+	h.Log.WithContext(ctx).Infof("Archived %d histories to %s", archive.Records, archive.Filename)
+	return archive, nil
+}
+
+// LoadArchivedHistories reads an archive back, failing when the file no longer
+// matches the checksum recorded in the index.
+func (h *HistoryArchiveRepositoryImpl) LoadArchivedHistories(ctx context.Context, archive entity.HistoryArchive) ([]entity.History, error) {
+	ctx, span := tracing.Start(ctx, "HistoryArchiveRepository.LoadArchivedHistories")
+	defer span.End()
+
+	filename := filepath.Join(h.Dir, archive.Filename)
+	h.Log.WithContext(ctx).Debugf("Loading archived histories from file: %s", filename)
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		h.Log.WithContext(ctx).Errorf("Error reading file %s: %v", filename, err)
+		return nil, fmt.Errorf("failed to read history archive %s: %w", archive.Filename, err)
+	}
+
+	sum := sha256.Sum256(content)
+	if hex.EncodeToString(sum[:]) != archive.Checksum {
+		return nil, fmt.Errorf("history archive %s does not match its checksum", archive.Filename)
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress history archive %s: %w", archive.Filename, err)
+	}
+	defer func() {
+		if err := reader.Close(); err != nil {
+			h.Log.WithContext(ctx).Errorf("Error closing archive %s: %v", filename, err)
+		}
+	}()
+
+	var histories []entity.History
+	if err := json.NewDecoder(reader).Decode(&histories); err != nil {
+		h.Log.WithContext(ctx).Errorf("Error decoding JSON from file %s: %v", filename, err)
+		return nil, fmt.Errorf("failed to parse history archive %s: %w", archive.Filename, err)
+	}
+
+	h.Log.WithContext(ctx).Infof("Successfully loaded %d archived histories from %s", len(histories), archive.Filename)
+	return histories, nil
+}
+
+// writeGzipFile writes to a temporary file first so a crash never leaves a
+// truncated archive under the final name. It returns the SHA-256 of the file.
+func (h *HistoryArchiveRepositoryImpl) writeGzipFile(ctx context.Context, filename string, histories []entity.History) (string, error) {
+	tempFilename := filename + ".tmp"
+	file, err := os.Create(tempFilename)
+	if err != nil {
+		h.Log.WithContext(ctx).Errorf("Error creating file %s: %v", tempFilename, err)
+		return "", fmt.Errorf("error creating file %s: %w", tempFilename, err)
+	}
+
+	hasher := sha256.New()
+	writer := gzip.NewWriter(io.MultiWriter(file, hasher))
+	err = json.NewEncoder(writer).Encode(histories)
+	if closeErr := writer.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tempFilename, filename)
+	}
+	if err != nil {
+		h.Log.WithContext(ctx).Errorf("Error writing archive %s: %v", filename, err)
+		_ = os.Remove(tempFilename)
+		return "", fmt.Errorf("error writing archive %s: %w", filename, err)
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// CheckHealth creates Dir when it is missing, as the first archive run would.
+func (h *HistoryArchiveRepositoryImpl) CheckHealth(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "HistoryArchiveRepository.CheckHealth")
+	defer span.End()
+
+	if err := os.MkdirAll(h.Dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create history archive directory: %w", err)
+	}
+
+	indexFilename := filepath.Join(h.Dir, historyArchiveIndexFilename)
+	if _, err := os.Stat(indexFilename); err == nil {
+		return utils.CheckFileAccess(ctx, indexFilename)
+	}
+	return utils.CheckDirAccess(ctx, h.Dir)
+}
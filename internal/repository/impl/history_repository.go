@@ -1,69 +1,182 @@
 package impl
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/sirupsen/logrus"
 	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/repository"
+	"merchant_bank_payment_go_api/internal/tracing"
 	"merchant_bank_payment_go_api/internal/utils"
+	"sync"
+	"time"
 )
 
+// HistoryRepositoryImpl chains every appended record to the last stored one,
+// or to the last archived one once archival has emptied the file. ChainKey,
+// when set, is also used to MAC each record.
 type HistoryRepositoryImpl struct {
-	Log      *logrus.Logger
-	Filename string
+	Log               *logrus.Logger
+	Filename          string
+	ChainKey          []byte
+	ArchiveRepository repository.HistoryArchiveRepository
+	mu                sync.Mutex
 }
 
-func NewHistoryRepositoryImpl(log *logrus.Logger, filename string) *HistoryRepositoryImpl {
+func NewHistoryRepositoryImpl(log *logrus.Logger, filename string, chainKey []byte,
+	archiveRepository repository.HistoryArchiveRepository) *HistoryRepositoryImpl {
 	return &HistoryRepositoryImpl{
-		Log:      log,
-		Filename: filename,
+		Log:               log,
+		Filename:          filename,
+		ChainKey:          chainKey,
+		ArchiveRepository: archiveRepository,
 	}
 }
 
-func (h *HistoryRepositoryImpl) LoadHistories() ([]entity.History, error) {
-	h.Log.Debugf("Loading histories from file: %s", h.Filename)
+func (h *HistoryRepositoryImpl) LoadHistories(ctx context.Context) ([]entity.History, error) {
+	ctx, span := tracing.Start(ctx, "HistoryRepository.LoadHistories")
+	defer span.End()
 
-	file, err := utils.ReadJsonFile(h.Filename, h.Log)
+	h.Log.WithContext(ctx).Debugf("Loading histories from file: %s", h.Filename)
+
+	file, err := utils.ReadJsonFile(ctx, h.Filename, h.Log)
 	if err != nil {
-		h.Log.Errorf("Error reading file %s: %v", h.Filename, err)
+		h.Log.WithContext(ctx).Errorf("Error reading file %s: %v", h.Filename, err)
 		return nil, err
 	}
 
-	h.Log.Tracef("File content: %s", string(file))
+	h.Log.WithContext(ctx).Tracef("File content: %s", string(file))
 
 	var histories []entity.History
 	err = json.Unmarshal(file, &histories)
 	if err != nil {
-		h.Log.Errorf("Error decoding JSON from file %s: %v", h.Filename, err)
+		h.Log.WithContext(ctx).Errorf("Error decoding JSON from file %s: %v", h.Filename, err)
 		return nil, err
 	}
 
-	h.Log.Infof("Successfully loaded %d histories from %s", len(histories), h.Filename)
+	h.Log.WithContext(ctx).Infof("Successfully loaded %d histories from %s", len(histories), h.Filename)
 	return histories, nil
 }
 
-func (h *HistoryRepositoryImpl) SaveHistories(histories []entity.History) error {
-	h.Log.Infof("Saving %d histories to file: %s", len(histories), h.Filename)
+func (h *HistoryRepositoryImpl) SaveHistories(ctx context.Context, histories []entity.History) error {
+	ctx, span := tracing.Start(ctx, "HistoryRepository.SaveHistories")
+	defer span.End()
+
+	h.Log.WithContext(ctx).Infof("Saving %d histories to file: %s", len(histories), h.Filename)
 
-	err := utils.WriteJsonFile(h.Filename, histories, h.Log)
+	err := utils.WriteJsonFile(ctx, h.Filename, histories, h.Log)
 	if err != nil {
-		h.Log.Errorf("Error saving histories to file %s: %v", h.Filename, err)
+		h.Log.WithContext(ctx).Errorf("Error saving histories to file %s: %v", h.Filename, err)
 		return fmt.Errorf("error saving histories to file %s: %v", h.Filename, err)
 	}
 
-	h.Log.Infof("Successfully saved histories to %s", h.Filename)
+	h.Log.WithContext(ctx).Infof("Successfully saved histories to %s", h.Filename)
 	return nil
 }
 
-func (h *HistoryRepositoryImpl) AddHistory(history entity.History) error {
-	histories, err := h.LoadHistories()
+func (h *HistoryRepositoryImpl) AddHistory(ctx context.Context, history entity.History) error {
+	ctx, span := tracing.Start(ctx, "HistoryRepository.AddHistory")
+	defer span.End()
+
+	h.Log.WithContext(ctx).Infof("Adding history: Action=%s, CustomerId=%s, Outcome=%s, Message=%s", history.Action, history.CustomerId,
+		history.Details.Outcome, history.Details.Message)
+
+	return h.AddHistories(ctx, []entity.History{history})
+}
+
+func (h *HistoryRepositoryImpl) AddHistories(ctx context.Context, newHistories []entity.History) error {
+	ctx, span := tracing.Start(ctx, "HistoryRepository.AddHistories")
+	defer span.End()
+
+	if len(newHistories) == 0 {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	histories, err := h.LoadHistories(ctx)
 	if err != nil {
 		return err
 	}
 
-	h.Log.Infof("Adding history: Action=%s, CustomerId=%s, Details=%s", history.Action, history.CustomerId, history.Details)
+	prev, err := h.chainTail(ctx, histories)
+	if err != nil {
+		return err
+	}
+
+	h.Log.WithContext(ctx).Infof("Adding %d histories", len(newHistories))
+
+	for _, history := range newHistories {
+		history.Seal(prev, h.ChainKey)
+		histories = append(histories, history)
+		prev = &histories[len(histories)-1]
+	}
+
+	return h.SaveHistories(ctx, histories)
+}
+
+// chainTail returns the record the next one links to. When every record has
+// been archived that is the last archived record, known from the index.
+func (h *HistoryRepositoryImpl) chainTail(ctx context.Context, histories []entity.History) (*entity.History, error) {
+	if len(histories) > 0 {
+		return &histories[len(histories)-1], nil
+	}
+	if h.ArchiveRepository == nil {
+		return nil, nil
+	}
+
+	archives, err := h.ArchiveRepository.LoadArchives(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the last archived history: %w", err)
+	}
+	for i := len(archives) - 1; i >= 0; i-- {
+		if archives[i].LastHash != "" {
+			return &entity.History{Sequence: archives[i].LastSequence, Hash: archives[i].LastHash}, nil
+		}
+	}
+	return nil, nil
+}
+
+// PruneHistories only removes a leading run of records, so the records that
+// stay still form one chain whose first PrevHash is the last archived hash.
+func (h *HistoryRepositoryImpl) PruneHistories(ctx context.Context, before time.Time, archive func(histories []entity.History) error) (int, error) {
+	ctx, span := tracing.Start(ctx, "HistoryRepository.PruneHistories")
+	defer span.End()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	histories, err := h.LoadHistories(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for count < len(histories) && histories[count].Timestamp.Before(before) {
+		count++
+	}
+	if count == 0 {
+		return 0, nil
+	}
+
+	if err := archive(histories[:count]); err != nil {
+		return 0, err
+	}
+
+	// The archive is written, so removing the records must not be cancelled.
+	if err := h.SaveHistories(context.WithoutCancel(ctx), histories[count:]); err != nil {
+		return 0, err
+	}
+
+	h.Log.WithContext(ctx).Infof("Pruned %d histories older than %s", count, before.Format(time.RFC3339))
+	return count, nil
+}
 
-	histories = append(histories, history)
+func (h *HistoryRepositoryImpl) CheckHealth(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "HistoryRepository.CheckHealth")
+	defer span.End()
 
-	return h.SaveHistories(histories)
+	return utils.CheckFileAccess(ctx, h.Filename)
 }
@@ -1,17 +1,21 @@
 package impl
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/tracing"
 	"merchant_bank_payment_go_api/internal/utils"
+	"sync"
 )
 
 type MerchantRepositoryImpl struct {
 	Log      *logrus.Logger
 	Filename string
+	mu       sync.Mutex
 }
 
 func NewMerchantRepositoryImpl(log *logrus.Logger, filename string) *MerchantRepositoryImpl {
@@ -21,43 +25,121 @@ func NewMerchantRepositoryImpl(log *logrus.Logger, filename string) *MerchantRep
 	}
 }
 
-func (m *MerchantRepositoryImpl) LoadMerchants() ([]entity.Merchant, error) {
-	m.Log.Debugf("Loading merchants from file: %s", m.Filename)
+func (m *MerchantRepositoryImpl) LoadMerchants(ctx context.Context) ([]entity.Merchant, error) {
+	ctx, span := tracing.Start(ctx, "MerchantRepository.LoadMerchants")
+	defer span.End()
 
-	file, err := utils.ReadJsonFile(m.Filename, m.Log)
+	m.Log.WithContext(ctx).Debugf("Loading merchants from file: %s", m.Filename)
+
+	file, err := utils.ReadJsonFile(ctx, m.Filename, m.Log)
 	if err != nil {
-		m.Log.Errorf("Error reading file %s: %v", m.Filename, err)
+		m.Log.WithContext(ctx).Errorf("Error reading file %s: %v", m.Filename, err)
 		return nil, err
 	}
 
 	var merchants []entity.Merchant
 	err = json.Unmarshal(file, &merchants)
 	if err != nil {
-		m.Log.Errorf("Error decoding JSON from file %s: %v", m.Filename, err)
+		m.Log.WithContext(ctx).Errorf("Error decoding JSON from file %s: %v", m.Filename, err)
 		return nil, err
 	}
 
-	m.Log.Infof("Successfully loaded %d merchants from %s", len(merchants), m.Filename)
+	m.Log.WithContext(ctx).Infof("Successfully loaded %d merchants from %s", len(merchants), m.Filename)
 	return merchants, nil
 }
 
-func (m *MerchantRepositoryImpl) FindById(id uuid.UUID) (entity.Merchant, error) {
-	m.Log.Debugf("Finding merchant by id: %s", id.String())
+func (m *MerchantRepositoryImpl) FindById(ctx context.Context, id uuid.UUID) (entity.Merchant, error) {
+	ctx, span := tracing.Start(ctx, "MerchantRepository.FindById")
+	defer span.End()
+
+	m.Log.WithContext(ctx).Debugf("Finding merchant by id: %s", id.String())
 
-	merchants, err := m.LoadMerchants()
+	merchants, err := m.LoadMerchants(ctx)
 	if err != nil {
-		m.Log.Errorf("Error loading merchants from file %s: %v", m.Filename, err)
+		m.Log.WithContext(ctx).Errorf("Error loading merchants from file %s: %v", m.Filename, err)
 		return entity.Merchant{}, err
 	}
 
 	for _, merchant := range merchants {
 		if merchant.Id == id {
-			m.Log.Infof("Found merchant with id: %s", id.String())
+			m.Log.WithContext(ctx).Infof("Found merchant with id: %s", id.String())
 			return merchant, nil
 		}
 	}
 
 	err = fmt.Errorf("merchant with id %s not found in %s", id, m.Filename)
-	m.Log.Errorf(err.Error())
+	m.Log.WithContext(ctx).Errorf(err.Error())
 	return entity.Merchant{}, err
 }
+
+func (m *MerchantRepositoryImpl) Save(ctx context.Context, merchant entity.Merchant) error {
+	ctx, span := tracing.Start(ctx, "MerchantRepository.Save")
+	defer span.End()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	merchants, err := m.LoadMerchants(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, existing := range merchants {
+		if existing.Id == merchant.Id {
+			err = fmt.Errorf("merchant with id %s already exists in %s", merchant.Id, m.Filename)
+			m.Log.WithContext(ctx).Errorf(err.Error())
+			return err
+		}
+	}
+
+	merchants = append(merchants, merchant)
+	if err := m.saveMerchants(ctx, merchants); err != nil {
+		return err
+	}
+
+	m.Log.WithContext(ctx).Infof("Saved merchant %s", merchant.Id)
+	return nil
+}
+
+func (m *MerchantRepositoryImpl) Update(ctx context.Context, merchant entity.Merchant) error {
+	ctx, span := tracing.Start(ctx, "MerchantRepository.Update")
+	defer span.End()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	merchants, err := m.LoadMerchants(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range merchants {
+		if merchants[i].Id == merchant.Id {
+			merchants[i] = merchant
+			if err := m.saveMerchants(ctx, merchants); err != nil {
+				return err
+			}
+			m.Log.WithContext(ctx).Infof("Updated merchant %s", merchant.Id)
+			return nil
+		}
+	}
+
+	err = fmt.Errorf("merchant with id %s not found in %s", merchant.Id, m.Filename)
+	m.Log.WithContext(ctx).Errorf(err.Error())
+	return err
+}
+
+func (m *MerchantRepositoryImpl) saveMerchants(ctx context.Context, merchants []entity.Merchant) error {
+	if err := utils.WriteJsonFile(ctx, m.Filename, merchants, m.Log); err != nil {
+		m.Log.WithContext(ctx).Errorf("Error saving merchants to file %s: %v", m.Filename, err)
+		return fmt.Errorf("failed to save merchants: %w", err)
+	}
+	return nil
+}
+
+func (m *MerchantRepositoryImpl) CheckHealth(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "MerchantRepository.CheckHealth")
+	defer span.End()
+
+	return utils.CheckFileAccess(ctx, m.Filename)
+}
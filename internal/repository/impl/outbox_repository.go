@@ -0,0 +1,163 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/tracing"
+	"merchant_bank_payment_go_api/internal/utils"
+	"sync"
+)
+
+type OutboxRepositoryImpl struct {
+	Log      *logrus.Logger
+	Filename string
+	mu       sync.Mutex
+}
+
+func NewOutboxRepositoryImpl(log *logrus.Logger, filename string) *OutboxRepositoryImpl {
+	return &OutboxRepositoryImpl{
+		Log:      log,
+		Filename: filename,
+	}
+}
+
+func (o *OutboxRepositoryImpl) journalFilename() string {
+	return o.Filename + ".journal"
+}
+
+// CommitWithEvents calls stage with a new transaction and commits whatever it
+// staged together with the events it returns, so they are stored if and only
+// if the other staged files are. The outbox stays locked from before stage
+// runs until the commit is done, so files stage reads and rewrites can not
+// change in between.
+func (o *OutboxRepositoryImpl) CommitWithEvents(ctx context.Context, stage func(tx *utils.JsonFileTransaction) ([]entity.DomainEvent, error)) error {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.CommitWithEvents")
+	defer span.End()
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	tx := utils.NewJsonFileTransaction(o.Log, o.journalFilename())
+	events, err := stage(tx)
+	if err != nil {
+		return err
+	}
+
+	if len(events) > 0 {
+		stored, err := o.LoadEvents(ctx)
+		if err != nil {
+			return err
+		}
+		tx.Stage(o.Filename, append(stored, events...))
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		o.Log.WithContext(ctx).Errorf("Failed to commit %d outbox events: %v", len(events), err)
+		return fmt.Errorf("failed to commit outbox events: %w", err)
+	}
+
+	o.Log.WithContext(ctx).Debugf("Committed %d outbox events", len(events))
+	return nil
+}
+
+func (o *OutboxRepositoryImpl) LoadEvents(ctx context.Context) ([]entity.DomainEvent, error) {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.LoadEvents")
+	defer span.End()
+
+	o.Log.WithContext(ctx).Debugf("Loading outbox events from file: %s", o.Filename)
+
+	file, err := utils.ReadJsonFile(ctx, o.Filename, o.Log)
+	if err != nil {
+		o.Log.WithContext(ctx).Errorf("Failed to read file %s: %v", o.Filename, err)
+		return nil, fmt.Errorf("failed to read outbox file: %w", err)
+	}
+
+	var events []entity.DomainEvent
+	if err := json.Unmarshal(file, &events); err != nil {
+		o.Log.WithContext(ctx).Errorf("Failed to decode JSON from file %s: %v", o.Filename, err)
+		return nil, fmt.Errorf("failed to parse outbox events: %w", err)
+	}
+	return events, nil
+}
+
+func (o *OutboxRepositoryImpl) UpdateEvent(ctx context.Context, event entity.DomainEvent) error {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.UpdateEvent")
+	defer span.End()
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	events, err := o.LoadEvents(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range events {
+		if events[i].Id == event.Id {
+			events[i] = event
+			if err := utils.WriteJsonFile(ctx, o.Filename, events, o.Log); err != nil {
+				o.Log.WithContext(ctx).Errorf("Error saving outbox events to file %s: %v", o.Filename, err)
+				return fmt.Errorf("failed to save outbox events: %w", err)
+			}
+			return nil
+		}
+	}
+
+	err = fmt.Errorf("outbox event with id %s not found in %s", event.Id, o.Filename)
+	o.Log.WithContext(ctx).Errorf(err.Error())
+	return err
+}
+
+// PruneDispatched removes the events every subscriber has handled, so the
+// outbox only holds events still waiting for delivery.
+func (o *OutboxRepositoryImpl) PruneDispatched(ctx context.Context) (int, error) {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.PruneDispatched")
+	defer span.End()
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	events, err := o.LoadEvents(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	pending := make([]entity.DomainEvent, 0, len(events))
+	for _, event := range events {
+		if event.Status != entity.DomainEventStatusDispatched {
+			pending = append(pending, event)
+		}
+	}
+	pruned := len(events) - len(pending)
+	if pruned == 0 {
+		return 0, nil
+	}
+
+	if err := utils.WriteJsonFile(ctx, o.Filename, pending, o.Log); err != nil {
+		o.Log.WithContext(ctx).Errorf("Error saving outbox events to file %s: %v", o.Filename, err)
+		return 0, fmt.Errorf("failed to save outbox events: %w", err)
+	}
+
+	o.Log.WithContext(ctx).Debugf("Pruned %d dispatched outbox events", pruned)
+	return pruned, nil
+}
+
+func (o *OutboxRepositoryImpl) Recover(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.Recover")
+	defer span.End()
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return utils.RecoverJsonFileTransaction(o.journalFilename(), o.Log)
+}
+
+func (o *OutboxRepositoryImpl) CheckHealth(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.CheckHealth")
+	defer span.End()
+
+	return utils.CheckFileAccess(ctx, o.Filename)
+}
@@ -1,10 +1,13 @@
 package impl
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/tracing"
 	"merchant_bank_payment_go_api/internal/utils"
 )
 
@@ -20,51 +23,147 @@ func NewPaymentTransactionImpl(log *logrus.Logger, filename string) *PaymentTran
 	}
 }
 
-func (p *PaymentTransactionImpl) LoadPayments() ([]entity.Payment, error) {
-	p.Log.Debugf("Loading payment transactions from file: %s", p.Filename)
+func (p *PaymentTransactionImpl) LoadPayments(ctx context.Context) ([]entity.Payment, error) {
+	ctx, span := tracing.Start(ctx, "PaymentTransactionRepository.LoadPayments")
+	defer span.End()
 
-	file, err := utils.ReadJsonFile(p.Filename, p.Log)
+	p.Log.WithContext(ctx).Debugf("Loading payment transactions from file: %s", p.Filename)
+
+	file, err := utils.ReadJsonFile(ctx, p.Filename, p.Log)
 	if err != nil {
-		p.Log.Errorf("Failed to read file %s: %v", p.Filename, err)
+		p.Log.WithContext(ctx).Errorf("Failed to read file %s: %v", p.Filename, err)
 		return nil, fmt.Errorf("failed to read payment transactions file: %w", err)
 	}
 
 	var transactions []entity.Payment
 	if err := json.Unmarshal(file, &transactions); err != nil {
-		p.Log.Errorf("Failed to decode JSON from file %s: %v", p.Filename, err)
+		p.Log.WithContext(ctx).Errorf("Failed to decode JSON from file %s: %v", p.Filename, err)
 		return nil, fmt.Errorf("failed to parse payment transactions: %w", err)
 	}
 
-	p.Log.Infof("Successfully loaded %d payment transactions", len(transactions))
+	p.Log.WithContext(ctx).Infof("Successfully loaded %d payment transactions", len(transactions))
 	return transactions, nil
 }
 
-func (p *PaymentTransactionImpl) SavePayments(transactions []entity.Payment) error {
-	p.Log.Infof("Saving %d payment transactions to file: %s", len(transactions), p.Filename)
+func (p *PaymentTransactionImpl) SavePayments(ctx context.Context, transactions []entity.Payment) error {
+	ctx, span := tracing.Start(ctx, "PaymentTransactionRepository.SavePayments")
+	defer span.End()
+
+	p.Log.WithContext(ctx).Infof("Saving %d payment transactions to file: %s", len(transactions), p.Filename)
 
-	if err := utils.WriteJsonFile(p.Filename, transactions, p.Log); err != nil {
-		p.Log.Errorf("Error saving payment transactions to file %s: %v", p.Filename, err)
+	if err := utils.WriteJsonFile(ctx, p.Filename, transactions, p.Log); err != nil {
+		p.Log.WithContext(ctx).Errorf("Error saving payment transactions to file %s: %v", p.Filename, err)
 		return fmt.Errorf("failed to save payment transactions: %w", err)
 	}
 
-	p.Log.Infof("Successfully saved %d payment transactions", len(transactions))
+	p.Log.WithContext(ctx).Infof("Successfully saved %d payment transactions", len(transactions))
 	return nil
 }
 
-func (p *PaymentTransactionImpl) AddPayment(payment entity.Payment) error {
-	transactions, err := p.LoadPayments()
+func (p *PaymentTransactionImpl) AddPayment(ctx context.Context, payment entity.Payment) error {
+	ctx, span := tracing.Start(ctx, "PaymentTransactionRepository.AddPayment")
+	defer span.End()
+
+	transactions, err := p.LoadPayments(ctx)
 	if err != nil {
 		return err
 	}
 
-	p.Log.Infof("Adding new payment transaction with ID %s", payment.Id.String())
+	p.Log.WithContext(ctx).Infof("Adding new payment transaction with ID %s", payment.Id.String())
 	transactions = append(transactions, payment)
 
-	if err := p.SavePayments(transactions); err != nil {
-		p.Log.Errorf("Failed to save updated payment transactions: %v", err)
+	if err := p.SavePayments(ctx, transactions); err != nil {
+		p.Log.WithContext(ctx).Errorf("Failed to save updated payment transactions: %v", err)
 		return fmt.Errorf("error saving updated payment transactions: %w", err)
 	}
 
-	p.Log.Infof("Payment transaction %s successfully added", payment.Id.String())
+	p.Log.WithContext(ctx).Infof("Payment transaction %s successfully added", payment.Id.String())
+	return nil
+}
+
+func (p *PaymentTransactionImpl) StageAddPayment(ctx context.Context, tx *utils.JsonFileTransaction, payment entity.Payment) error {
+	ctx, span := tracing.Start(ctx, "PaymentTransactionRepository.StageAddPayment")
+	defer span.End()
+
+	transactions, err := p.LoadPayments(ctx)
+	if err != nil {
+		return err
+	}
+
+	p.Log.WithContext(ctx).Infof("Staging new payment transaction with ID %s", payment.Id.String())
+	tx.Stage(p.Filename, append(transactions, payment))
 	return nil
 }
+
+func (p *PaymentTransactionImpl) StageSavePayments(ctx context.Context, tx *utils.JsonFileTransaction, payments []entity.Payment) {
+	ctx, span := tracing.Start(ctx, "PaymentTransactionRepository.StageSavePayments")
+	defer span.End()
+
+	p.Log.WithContext(ctx).Infof("Staging %d payment transactions for file: %s", len(payments), p.Filename)
+	tx.Stage(p.Filename, payments)
+}
+
+func (p *PaymentTransactionImpl) FindById(ctx context.Context, id uuid.UUID) (entity.Payment, error) {
+	ctx, span := tracing.Start(ctx, "PaymentTransactionRepository.FindById")
+	defer span.End()
+
+	p.Log.WithContext(ctx).Debugf("Finding payment transaction by id: %s", id.String())
+
+	transactions, err := p.LoadPayments(ctx)
+	if err != nil {
+		return entity.Payment{}, err
+	}
+
+	for _, transaction := range transactions {
+		if transaction.Id == id {
+			p.Log.WithContext(ctx).Infof("Found payment transaction with id: %s", id.String())
+			return transaction, nil
+		}
+	}
+
+	err = fmt.Errorf("payment transaction with id %s not found in %s", id, p.Filename)
+	p.Log.WithContext(ctx).Errorf(err.Error())
+	return entity.Payment{}, err
+}
+
+func (p *PaymentTransactionImpl) FindByCustomerId(ctx context.Context, customerId uuid.UUID) ([]entity.Payment, error) {
+	ctx, span := tracing.Start(ctx, "PaymentTransactionRepository.FindByCustomerId")
+	defer span.End()
+
+	p.Log.WithContext(ctx).Debugf("Finding payment transactions by customer id: %s", customerId.String())
+
+	transactions, err := p.LoadPayments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	customerTransactions := make([]entity.Payment, 0)
+	for _, transaction := range transactions {
+		if transaction.CustomerId == customerId {
+			customerTransactions = append(customerTransactions, transaction)
+		}
+	}
+
+	p.Log.WithContext(ctx).Infof("Found %d payment transactions for customer %s", len(customerTransactions), customerId.String())
+	return customerTransactions, nil
+}
+
+func (p *PaymentTransactionImpl) StreamPayments(ctx context.Context, handler func(payment entity.Payment) error) error {
+	ctx, span := tracing.Start(ctx, "PaymentTransactionRepository.StreamPayments")
+	defer span.End()
+
+	p.Log.WithContext(ctx).Debugf("Streaming payment transactions from file: %s", p.Filename)
+
+	if err := utils.StreamJsonArray(ctx, p.Filename, p.Log, handler); err != nil {
+		p.Log.WithContext(ctx).Errorf("Failed to stream payment transactions from %s: %v", p.Filename, err)
+		return fmt.Errorf("failed to stream payment transactions: %w", err)
+	}
+	return nil
+}
+
+func (p *PaymentTransactionImpl) CheckHealth(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "PaymentTransactionRepository.CheckHealth")
+	defer span.End()
+
+	return utils.CheckFileAccess(ctx, p.Filename)
+}
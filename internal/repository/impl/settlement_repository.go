@@ -0,0 +1,99 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/tracing"
+	"merchant_bank_payment_go_api/internal/utils"
+)
+
+type SettlementRepositoryImpl struct {
+	Log      *logrus.Logger
+	Filename string
+}
+
+func NewSettlementRepositoryImpl(log *logrus.Logger, filename string) *SettlementRepositoryImpl {
+	return &SettlementRepositoryImpl{
+		Log:      log,
+		Filename: filename,
+	}
+}
+
+func (s *SettlementRepositoryImpl) LoadSettlements(ctx context.Context) ([]entity.Settlement, error) {
+	ctx, span := tracing.Start(ctx, "SettlementRepository.LoadSettlements")
+	defer span.End()
+
+	s.Log.WithContext(ctx).Debugf("Loading settlements from file: %s", s.Filename)
+
+	file, err := utils.ReadJsonFile(ctx, s.Filename, s.Log)
+	if err != nil {
+		s.Log.WithContext(ctx).Errorf("Failed to read file %s: %v", s.Filename, err)
+		return nil, fmt.Errorf("failed to read settlements file: %w", err)
+	}
+
+	var settlements []entity.Settlement
+	if err := json.Unmarshal(file, &settlements); err != nil {
+		s.Log.WithContext(ctx).Errorf("Failed to decode JSON from file %s: %v", s.Filename, err)
+		return nil, fmt.Errorf("failed to parse settlements: %w", err)
+	}
+
+	s.Log.WithContext(ctx).Infof("Successfully loaded %d settlements", len(settlements))
+	return settlements, nil
+}
+
+func (s *SettlementRepositoryImpl) SaveSettlements(ctx context.Context, settlements []entity.Settlement) error {
+	ctx, span := tracing.Start(ctx, "SettlementRepository.SaveSettlements")
+	defer span.End()
+
+	s.Log.WithContext(ctx).Infof("Saving %d settlements to file: %s", len(settlements), s.Filename)
+
+	if err := utils.WriteJsonFile(ctx, s.Filename, settlements, s.Log); err != nil {
+		s.Log.WithContext(ctx).Errorf("Error saving settlements to file %s: %v", s.Filename, err)
+		return fmt.Errorf("failed to save settlements: %w", err)
+	}
+
+	s.Log.WithContext(ctx).Infof("Successfully saved %d settlements", len(settlements))
+	return nil
+}
+
+func (s *SettlementRepositoryImpl) StageSaveSettlements(ctx context.Context, tx *utils.JsonFileTransaction, settlements []entity.Settlement) {
+	ctx, span := tracing.Start(ctx, "SettlementRepository.StageSaveSettlements")
+	defer span.End()
+
+	s.Log.WithContext(ctx).Infof("Staging %d settlements for file: %s", len(settlements), s.Filename)
+	tx.Stage(s.Filename, settlements)
+}
+
+func (s *SettlementRepositoryImpl) FindById(ctx context.Context, id uuid.UUID) (entity.Settlement, error) {
+	ctx, span := tracing.Start(ctx, "SettlementRepository.FindById")
+	defer span.End()
+
+	s.Log.WithContext(ctx).Debugf("Finding settlement by id: %s", id.String())
+
+	settlements, err := s.LoadSettlements(ctx)
+	if err != nil {
+		return entity.Settlement{}, err
+	}
+
+	for _, settlement := range settlements {
+		if settlement.Id == id {
+			s.Log.WithContext(ctx).Infof("Found settlement with id: %s", id.String())
+			return settlement, nil
+		}
+	}
+
+	err = fmt.Errorf("settlement with id %s not found in %s", id, s.Filename)
+	s.Log.WithContext(ctx).Errorf(err.Error())
+	return entity.Settlement{}, err
+}
+
+func (s *SettlementRepositoryImpl) CheckHealth(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "SettlementRepository.CheckHealth")
+	defer span.End()
+
+	return utils.CheckFileAccess(ctx, s.Filename)
+}
@@ -0,0 +1,142 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/tracing"
+	"merchant_bank_payment_go_api/internal/utils"
+	"sync"
+)
+
+type WebhookDeliveryRepositoryImpl struct {
+	Log      *logrus.Logger
+	Filename string
+	mu       sync.Mutex
+}
+
+func NewWebhookDeliveryRepositoryImpl(log *logrus.Logger, filename string) *WebhookDeliveryRepositoryImpl {
+	return &WebhookDeliveryRepositoryImpl{
+		Log:      log,
+		Filename: filename,
+	}
+}
+
+func (w *WebhookDeliveryRepositoryImpl) LoadDeliveries(ctx context.Context) ([]entity.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "WebhookDeliveryRepository.LoadDeliveries")
+	defer span.End()
+
+	w.Log.WithContext(ctx).Debugf("Loading webhook deliveries from file: %s", w.Filename)
+
+	file, err := utils.ReadJsonFile(ctx, w.Filename, w.Log)
+	if err != nil {
+		w.Log.WithContext(ctx).Errorf("Failed to read file %s: %v", w.Filename, err)
+		return nil, fmt.Errorf("failed to read webhook deliveries file: %w", err)
+	}
+
+	var deliveries []entity.WebhookDelivery
+	if err := json.Unmarshal(file, &deliveries); err != nil {
+		w.Log.WithContext(ctx).Errorf("Failed to decode JSON from file %s: %v", w.Filename, err)
+		return nil, fmt.Errorf("failed to parse webhook deliveries: %w", err)
+	}
+
+	w.Log.WithContext(ctx).Debugf("Successfully loaded %d webhook deliveries", len(deliveries))
+	return deliveries, nil
+}
+
+func (w *WebhookDeliveryRepositoryImpl) SaveDeliveries(ctx context.Context, deliveries []entity.WebhookDelivery) error {
+	ctx, span := tracing.Start(ctx, "WebhookDeliveryRepository.SaveDeliveries")
+	defer span.End()
+
+	w.Log.WithContext(ctx).Debugf("Saving %d webhook deliveries to file: %s", len(deliveries), w.Filename)
+
+	if err := utils.WriteJsonFile(ctx, w.Filename, deliveries, w.Log); err != nil {
+		w.Log.WithContext(ctx).Errorf("Error saving webhook deliveries to file %s: %v", w.Filename, err)
+		return fmt.Errorf("failed to save webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+func (w *WebhookDeliveryRepositoryImpl) AddDelivery(ctx context.Context, delivery entity.WebhookDelivery) error {
+	ctx, span := tracing.Start(ctx, "WebhookDeliveryRepository.AddDelivery")
+	defer span.End()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	deliveries, err := w.LoadDeliveries(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, existing := range deliveries {
+		if existing.Id == delivery.Id {
+			w.Log.WithContext(ctx).Infof("Webhook delivery %s is already queued", delivery.Id)
+			return nil
+		}
+	}
+
+	deliveries = append(deliveries, delivery)
+	if err := w.SaveDeliveries(ctx, deliveries); err != nil {
+		return err
+	}
+
+	w.Log.WithContext(ctx).Infof("Queued webhook delivery %s (%s) for merchant %s", delivery.Id, delivery.EventType, delivery.MerchantId)
+	return nil
+}
+
+func (w *WebhookDeliveryRepositoryImpl) UpdateDelivery(ctx context.Context, delivery entity.WebhookDelivery) error {
+	ctx, span := tracing.Start(ctx, "WebhookDeliveryRepository.UpdateDelivery")
+	defer span.End()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	deliveries, err := w.LoadDeliveries(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range deliveries {
+		if deliveries[i].Id == delivery.Id {
+			deliveries[i] = delivery
+			return w.SaveDeliveries(ctx, deliveries)
+		}
+	}
+
+	err = fmt.Errorf("webhook delivery with id %s not found in %s", delivery.Id, w.Filename)
+	w.Log.WithContext(ctx).Errorf(err.Error())
+	return err
+}
+
+func (w *WebhookDeliveryRepositoryImpl) FindById(ctx context.Context, id uuid.UUID) (entity.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "WebhookDeliveryRepository.FindById")
+	defer span.End()
+
+	w.Log.WithContext(ctx).Debugf("Finding webhook delivery by id: %s", id.String())
+
+	deliveries, err := w.LoadDeliveries(ctx)
+	if err != nil {
+		return entity.WebhookDelivery{}, err
+	}
+
+	for _, delivery := range deliveries {
+		if delivery.Id == id {
+			return delivery, nil
+		}
+	}
+
+	err = fmt.Errorf("webhook delivery with id %s not found in %s", id, w.Filename)
+	w.Log.WithContext(ctx).Errorf(err.Error())
+	return entity.WebhookDelivery{}, err
+}
+
+func (w *WebhookDeliveryRepositoryImpl) CheckHealth(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "WebhookDeliveryRepository.CheckHealth")
+	defer span.End()
+
+	return utils.CheckFileAccess(ctx, w.Filename)
+}
@@ -1,11 +1,14 @@
 package repository
 
 import (
+	"context"
 	"github.com/google/uuid"
 	"merchant_bank_payment_go_api/internal/entity"
 )
 
 type MerchantRepository interface {
-	LoadMerchants() ([]entity.Merchant, error)
-	FindById(id uuid.UUID) (entity.Merchant, error)
+	LoadMerchants(ctx context.Context) ([]entity.Merchant, error)
+	FindById(ctx context.Context, id uuid.UUID) (entity.Merchant, error)
+	Save(ctx context.Context, merchant entity.Merchant) error
+	Update(ctx context.Context, merchant entity.Merchant) error
 }
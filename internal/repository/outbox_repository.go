@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/utils"
+)
+
+type OutboxRepository interface {
+	CommitWithEvents(ctx context.Context, stage func(tx *utils.JsonFileTransaction) ([]entity.DomainEvent, error)) error
+	LoadEvents(ctx context.Context) ([]entity.DomainEvent, error)
+	UpdateEvent(ctx context.Context, event entity.DomainEvent) error
+	PruneDispatched(ctx context.Context) (int, error)
+	Recover(ctx context.Context) error
+}
@@ -1,11 +1,19 @@
 package repository
 
 import (
+	"context"
+	"github.com/google/uuid"
 	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/utils"
 )
 
 type PaymentTransactionRepository interface {
-	LoadPayments() ([]entity.Payment, error)
-	SavePayments([]entity.Payment) error
-	AddPayment(payment entity.Payment) error
+	LoadPayments(ctx context.Context) ([]entity.Payment, error)
+	SavePayments(ctx context.Context, payments []entity.Payment) error
+	AddPayment(ctx context.Context, payment entity.Payment) error
+	StageAddPayment(ctx context.Context, tx *utils.JsonFileTransaction, payment entity.Payment) error
+	StageSavePayments(ctx context.Context, tx *utils.JsonFileTransaction, payments []entity.Payment)
+	FindById(ctx context.Context, id uuid.UUID) (entity.Payment, error)
+	FindByCustomerId(ctx context.Context, customerId uuid.UUID) ([]entity.Payment, error)
+	StreamPayments(ctx context.Context, handler func(payment entity.Payment) error) error
 }
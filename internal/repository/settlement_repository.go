@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/utils"
+)
+
+type SettlementRepository interface {
+	LoadSettlements(ctx context.Context) ([]entity.Settlement, error)
+	SaveSettlements(ctx context.Context, settlements []entity.Settlement) error
+	StageSaveSettlements(ctx context.Context, tx *utils.JsonFileTransaction, settlements []entity.Settlement)
+	FindById(ctx context.Context, id uuid.UUID) (entity.Settlement, error)
+}
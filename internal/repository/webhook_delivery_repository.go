@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"merchant_bank_payment_go_api/internal/entity"
+)
+
+type WebhookDeliveryRepository interface {
+	LoadDeliveries(ctx context.Context) ([]entity.WebhookDelivery, error)
+	SaveDeliveries(ctx context.Context, deliveries []entity.WebhookDelivery) error
+	AddDelivery(ctx context.Context, delivery entity.WebhookDelivery) error
+	UpdateDelivery(ctx context.Context, delivery entity.WebhookDelivery) error
+	FindById(ctx context.Context, id uuid.UUID) (entity.WebhookDelivery, error)
+}
@@ -0,0 +1,14 @@
+package usecase
+
+import (
+	"context"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/model"
+	"time"
+)
+
+type AuditUseCase interface {
+	VerifyChain(ctx context.Context) (model.AuditVerificationResult, error)
+	CreateCheckpoint(ctx context.Context) (*entity.AuditCheckpoint, error)
+	StartCheckpointWorker(interval time.Duration) func()
+}
@@ -1,10 +1,13 @@
 package usecase
 
-import "merchant_bank_payment_go_api/internal/model"
+import (
+	"context"
+	"merchant_bank_payment_go_api/internal/model"
+)
 
 type AuthUseCase interface {
-	Login(request model.LoginRequest) (model.LoginResponse, error)
-	Logout(accessToken string) error
-	IsTokenBlacklisted(accessToken string) (bool, error)
-	AddToBlacklist(accessToken string) error
+	Login(ctx context.Context, request model.LoginRequest) (model.LoginResponse, error)
+	Logout(ctx context.Context, accessToken string) error
+	IsTokenBlacklisted(ctx context.Context, accessToken string) (bool, error)
+	AddToBlacklist(ctx context.Context, accessToken string) error
 }
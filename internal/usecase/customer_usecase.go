@@ -1,8 +1,19 @@
 package usecase
 
-import "merchant_bank_payment_go_api/internal/entity"
+import (
+	"context"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/model"
+	"time"
+)
 
 type CustomerUseCase interface {
-	FindById(id string) (entity.Customer, error)
-	FindByUsername(username string) (entity.Customer, error)
+	FindById(ctx context.Context, id string) (entity.Customer, error)
+	FindByUsername(ctx context.Context, username string) (entity.Customer, error)
+	IsSessionRevoked(ctx context.Context, customerId string, issuedAt time.Time) (bool, error)
+	GetCustomers(ctx context.Context, request model.CustomerQueryRequest) (model.CustomerPage, error)
+	GetCustomerDetail(ctx context.Context, adminId, id string) (model.CustomerDetailResponse, error)
+	DisableCustomer(ctx context.Context, adminId, id string) (model.CustomerResponse, error)
+	EnableCustomer(ctx context.Context, adminId, id string) (model.CustomerResponse, error)
+	ForceLogout(ctx context.Context, adminId, id string) (model.CustomerResponse, error)
 }
@@ -0,0 +1,18 @@
+package usecase
+
+import (
+	"context"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/utils"
+)
+
+type EventHandler func(ctx context.Context, event entity.DomainEvent) error
+
+type EventBus interface {
+	// Publish stores the events stage returns together with the files it stages.
+	// stage must read the files it rewrites itself, as they can change until
+	// it is called.
+	Publish(ctx context.Context, stage func(tx *utils.JsonFileTransaction) ([]entity.DomainEvent, error)) error
+	Subscribe(name string, eventType entity.DomainEventType, handler EventHandler)
+	DispatchPending(ctx context.Context) (int, error)
+}
@@ -0,0 +1,13 @@
+package usecase
+
+import (
+	"context"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/model"
+)
+
+type ExchangeRateUseCase interface {
+	Convert(ctx context.Context, amount entity.Money, targetCurrency string) (entity.Money, entity.CurrencyConversion, error)
+	GetRates(ctx context.Context) ([]model.ExchangeRateResponse, error)
+	ReplaceRates(ctx context.Context, adminId string, request model.ExchangeRateRequest) ([]model.ExchangeRateResponse, error)
+}
@@ -0,0 +1,11 @@
+package usecase
+
+import (
+	"context"
+	"merchant_bank_payment_go_api/internal/model"
+)
+
+type HealthUseCase interface {
+	CheckReadiness(ctx context.Context) model.ReadinessResponse
+	GetVersion() model.VersionResponse
+}
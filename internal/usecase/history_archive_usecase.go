@@ -0,0 +1,13 @@
+package usecase
+
+import (
+	"context"
+	"merchant_bank_payment_go_api/internal/entity"
+	"time"
+)
+
+type HistoryArchiveUseCase interface {
+	ArchiveHistories(ctx context.Context, now time.Time) (*entity.HistoryArchive, error)
+	ArchiveNow(ctx context.Context, adminId string, olderThan time.Duration) (*entity.HistoryArchive, error)
+	StartArchiveWorker(interval time.Duration) func()
+}
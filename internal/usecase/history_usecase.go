@@ -1,6 +1,16 @@
 package usecase
 
+import (
+	"context"
+	"io"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/model"
+)
+
 type HistoryUseCase interface {
-	AddHistory(customerId, action, details string) error
-	LogAndAddHistory(userId, action, message string, err error) error
+	AddHistory(ctx context.Context, actorId string, action entity.AuditAction, details entity.AuditDetails) error
+	LogAndAddHistory(ctx context.Context, actorId string, action entity.AuditAction, details entity.AuditDetails, err error) error
+	GetCustomerHistory(ctx context.Context, customerId string, request model.HistoryQueryRequest) (model.HistoryPage, error)
+	SearchHistory(ctx context.Context, adminId string, request model.HistoryQueryRequest) (model.HistoryPage, error)
+	ExportHistory(ctx context.Context, adminId string, request model.HistoryQueryRequest, writer io.Writer) error
 }
@@ -0,0 +1,195 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/internal/repository"
+	"merchant_bank_payment_go_api/internal/tracing"
+	"time"
+)
+
+type AuditUseCaseImpl struct {
+	Log                       *logrus.Logger
+	HistoryRepository         repository.HistoryRepository
+	HistoryArchiveRepository  repository.HistoryArchiveRepository
+	AuditCheckpointRepository repository.AuditCheckpointRepository
+	Key                       []byte
+}
+
+func NewAuditUseCaseImpl(log *logrus.Logger, historyRepository repository.HistoryRepository,
+	historyArchiveRepository repository.HistoryArchiveRepository, auditCheckpointRepository repository.AuditCheckpointRepository,
+	key []byte) *AuditUseCaseImpl {
+	return &AuditUseCaseImpl{
+		Log:                       log,
+		HistoryRepository:         historyRepository,
+		HistoryArchiveRepository:  historyArchiveRepository,
+		AuditCheckpointRepository: auditCheckpointRepository,
+		Key:                       key,
+	}
+}
+
+// VerifyChain walks the archived and current history from the start and
+// stops at the first record that does not link to its predecessor, does not
+// match its own hash or MAC, or contradicts a checkpoint. Legacy records are
+// only allowed before the chain.
+func (a *AuditUseCaseImpl) VerifyChain(ctx context.Context) (model.AuditVerificationResult, error) {
+	ctx, span := tracing.Start(ctx, "AuditUseCase.VerifyChain")
+	defer span.End()
+
+	archives, err := a.HistoryArchiveRepository.LoadArchives(ctx)
+	if err != nil {
+		return model.AuditVerificationResult{}, err
+	}
+
+	current, err := a.HistoryRepository.LoadHistories(ctx)
+	if err != nil {
+		return model.AuditVerificationResult{}, err
+	}
+
+	checkpoints, err := a.AuditCheckpointRepository.LoadCheckpoints(ctx)
+	if err != nil {
+		return model.AuditVerificationResult{}, err
+	}
+
+	result := model.AuditVerificationResult{MacChecked: len(a.Key) > 0}
+	broken := func(history entity.History, reason string) (model.AuditVerificationResult, error) {
+		result.BrokenLink = &model.AuditBrokenLink{Sequence: history.Sequence, Id: history.Id.String(), Reason: reason}
+		a.Log.WithContext(ctx).Warnf("Audit chain broken at sequence %d (%s): %s", history.Sequence, history.Id, reason)
+		return result, nil
+	}
+
+	var histories []entity.History
+	for _, archive := range archives {
+		archived, err := a.HistoryArchiveRepository.LoadArchivedHistories(ctx, archive)
+		if err != nil {
+			return broken(entity.History{Sequence: archive.FirstSequence}, err.Error())
+		}
+		if len(archived) == 0 || len(archived) != archive.Records || archived[len(archived)-1].Hash != archive.LastHash {
+			return broken(entity.History{Sequence: archive.FirstSequence},
+				fmt.Sprintf("archive %s does not match its index entry", archive.Filename))
+		}
+		histories = append(histories, archived...)
+		result.Archives++
+	}
+	histories = append(histories, current...)
+	result.Records = len(histories)
+
+	hashBySequence := make(map[int64]string, len(histories))
+	var prev *entity.History
+	for i, history := range histories {
+		if !history.IsChained() {
+			if prev != nil {
+				return broken(history, fmt.Sprintf("record after sequence %d is not chained", prev.Sequence))
+			}
+			result.LegacyRecords++
+			continue
+		}
+
+		if prev == nil && (history.Sequence != 1 || history.PrevHash != "") {
+			return broken(history, "chain does not start at sequence 1")
+		}
+		if prev != nil && history.Sequence != prev.Sequence+1 {
+			return broken(history, fmt.Sprintf("expected sequence %d", prev.Sequence+1))
+		}
+		if prev != nil && history.PrevHash != prev.Hash {
+			return broken(history, "previous hash does not match the preceding record")
+		}
+		if history.ComputeHash() != history.Hash {
+			return broken(history, "record content does not match its hash")
+		}
+		if len(a.Key) > 0 && !entity.ValidAuditMac(a.Key, history.Hash, history.Mac) {
+			return broken(history, "invalid record MAC")
+		}
+
+		hashBySequence[history.Sequence] = history.Hash
+		prev = &histories[i]
+		result.LastSequence = history.Sequence
+		result.LastHash = history.Hash
+	}
+
+	for _, checkpoint := range checkpoints {
+		pinned := entity.History{Sequence: checkpoint.Sequence}
+		if len(a.Key) > 0 && !checkpoint.ValidSignature(a.Key) {
+			return broken(pinned, fmt.Sprintf("checkpoint %s has an invalid signature", checkpoint.Id))
+		}
+
+		hash, found := hashBySequence[checkpoint.Sequence]
+		if !found {
+			return broken(pinned, fmt.Sprintf("checkpoint %s refers to a record that no longer exists", checkpoint.Id))
+		}
+		if hash != checkpoint.Hash {
+			return broken(pinned, fmt.Sprintf("record does not match checkpoint %s", checkpoint.Id))
+		}
+		result.CheckpointsVerified++
+	}
+
+	result.Valid = true
+	return result, nil
+}
+
+// CreateCheckpoint signs the current head of the chain. It returns nil when
+// the head is already covered by the latest checkpoint or nothing is chained yet.
+func (a *AuditUseCaseImpl) CreateCheckpoint(ctx context.Context) (*entity.AuditCheckpoint, error) {
+	ctx, span := tracing.Start(ctx, "AuditUseCase.CreateCheckpoint")
+	defer span.End()
+
+	if len(a.Key) == 0 {
+		return nil, fmt.Errorf("audit checkpoints require an audit key")
+	}
+
+	histories, err := a.HistoryRepository.LoadHistories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(histories) == 0 || !histories[len(histories)-1].IsChained() {
+		return nil, nil
+	}
+	head := histories[len(histories)-1]
+
+	checkpoints, err := a.AuditCheckpointRepository.LoadCheckpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(checkpoints) > 0 && checkpoints[len(checkpoints)-1].Sequence == head.Sequence {
+		return nil, nil
+	}
+
+	checkpoint := entity.NewAuditCheckpoint(head, a.Key, time.Now())
+	if err := a.AuditCheckpointRepository.AddCheckpoint(ctx, checkpoint); err != nil {
+		return nil, err
+	}
+
+	a.Log.WithContext(ctx).Infof("Created audit checkpoint at sequence %d", checkpoint.Sequence)
+	return &checkpoint, nil
+}
+
+func (a *AuditUseCaseImpl) StartCheckpointWorker(interval time.Duration) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if _, err := a.CreateCheckpoint(ctx); err != nil {
+					a.Log.WithContext(ctx).Errorf("Audit checkpoint failed: %v", err)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		ticker.Stop()
+		close(done)
+		<-stopped
+	}
+}
@@ -1,10 +1,14 @@
 package impl
 
 import (
+	"context"
 	"fmt"
 	"golang.org/x/crypto/bcrypt"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/metrics"
 	"merchant_bank_payment_go_api/internal/model"
 	"merchant_bank_payment_go_api/internal/repository"
+	"merchant_bank_payment_go_api/internal/tracing"
 	"merchant_bank_payment_go_api/internal/usecase"
 	"merchant_bank_payment_go_api/internal/utils"
 )
@@ -13,20 +17,34 @@ type AuthUseCaseImpl struct {
 	AuthRepository  repository.AuthRepository
 	CustomerUseCase usecase.CustomerUseCase
 	HistoryUseCase  usecase.HistoryUseCase
+	EventBus        usecase.EventBus
 }
 
-func NewAuthUseCaseImpl(authRepository repository.AuthRepository, customerUseCase usecase.CustomerUseCase, historyUseCase usecase.HistoryUseCase) *AuthUseCaseImpl {
+func NewAuthUseCaseImpl(authRepository repository.AuthRepository, customerUseCase usecase.CustomerUseCase, historyUseCase usecase.HistoryUseCase,
+	eventBus usecase.EventBus) *AuthUseCaseImpl {
 	return &AuthUseCaseImpl{
 		AuthRepository:  authRepository,
 		CustomerUseCase: customerUseCase,
 		HistoryUseCase:  historyUseCase,
+		EventBus:        eventBus,
 	}
 }
 
-func (c *AuthUseCaseImpl) Login(request model.LoginRequest) (model.LoginResponse, error) {
-	customer, err := c.CustomerUseCase.FindByUsername(request.Username)
+func (c *AuthUseCaseImpl) Login(ctx context.Context, request model.LoginRequest) (response model.LoginResponse, err error) {
+	ctx, span := tracing.Start(ctx, "AuthUseCase.Login")
+	defer span.End()
+
+	defer func() {
+		if err != nil {
+			metrics.LoginAttempts.WithLabelValues(metrics.LoginFailure).Inc()
+		} else {
+			metrics.LoginAttempts.WithLabelValues(metrics.LoginSuccess).Inc()
+		}
+	}()
+
+	customer, err := c.CustomerUseCase.FindByUsername(ctx, request.Username)
 	if err != nil {
-		errLogHistory := c.HistoryUseCase.LogAndAddHistory("-", "LOGIN", fmt.Sprintf("Login failed because customer with username %s not exists", request.Username), err)
+		errLogHistory := c.logHistory(ctx, "", entity.AuditActionLogin, entity.AuditErrorInvalidCredentials, fmt.Sprintf("Login failed because customer with username %s not exists", request.Username), err)
 		if errLogHistory != nil {
 			return model.LoginResponse{}, errLogHistory
 		}
@@ -35,23 +53,47 @@ func (c *AuthUseCaseImpl) Login(request model.LoginRequest) (model.LoginResponse
 
 	err = bcrypt.CompareHashAndPassword([]byte(customer.Password), []byte(request.Password))
 	if err != nil {
-		errLogHistory := c.HistoryUseCase.LogAndAddHistory(customer.Id.String(), "LOGIN", "Invalid credentials", err)
+		errLogHistory := c.logHistory(ctx, customer.Id.String(), entity.AuditActionLogin, entity.AuditErrorInvalidCredentials, "Invalid credentials", err)
 		if errLogHistory != nil {
 			return model.LoginResponse{}, errLogHistory
 		}
 		return model.LoginResponse{}, fmt.Errorf("invalid credentials")
 	}
 
+	if customer.IsDisabled() {
+		err = fmt.Errorf("account is disabled")
+		errLogHistory := c.logHistory(ctx, customer.Id.String(), entity.AuditActionLogin, entity.AuditErrorAccountDisabled, "Login rejected because the account is disabled", err)
+		if errLogHistory != nil {
+			return model.LoginResponse{}, errLogHistory
+		}
+		return model.LoginResponse{}, err
+	}
+
 	accessToken, err := utils.GenerateAccessToken(customer.Id.String())
 	if err != nil {
-		errLogHistory := c.HistoryUseCase.LogAndAddHistory(customer.Id.String(), "LOGIN", "Failed to generate access token", err)
+		errLogHistory := c.logHistory(ctx, customer.Id.String(), entity.AuditActionLogin, entity.AuditErrorInternal, "Failed to generate access token", err)
 		if errLogHistory != nil {
 			return model.LoginResponse{}, errLogHistory
 		}
 		return model.LoginResponse{}, err
 	}
 
-	errLogHistory := c.HistoryUseCase.LogAndAddHistory(customer.Id.String(), "LOGIN", "Login successful", nil)
+	event, err := entity.NewDomainEvent(entity.EventLoginSucceeded, customer.Id.String(),
+		entity.LoginSucceededPayload{CustomerId: customer.Id.String()})
+	if err == nil {
+		err = c.EventBus.Publish(ctx, func(tx *utils.JsonFileTransaction) ([]entity.DomainEvent, error) {
+			return []entity.DomainEvent{event}, nil
+		})
+	}
+	if err != nil {
+		errLogHistory := c.logHistory(ctx, customer.Id.String(), entity.AuditActionLogin, entity.AuditErrorStorageFailed, "Failed to record login event", err)
+		if errLogHistory != nil {
+			return model.LoginResponse{}, errLogHistory
+		}
+		return model.LoginResponse{}, err
+	}
+
+	errLogHistory := c.logHistory(ctx, customer.Id.String(), entity.AuditActionLogin, "", "Login successful", nil)
 	if errLogHistory != nil {
 		return model.LoginResponse{}, errLogHistory
 	}
@@ -59,36 +101,47 @@ func (c *AuthUseCaseImpl) Login(request model.LoginRequest) (model.LoginResponse
 	return model.LoginResponse{AccessToken: accessToken}, nil
 }
 
-func (c *AuthUseCaseImpl) Logout(accessToken string) error {
+func (c *AuthUseCaseImpl) Logout(ctx context.Context, accessToken string) error {
+	ctx, span := tracing.Start(ctx, "AuthUseCase.Logout")
+	defer span.End()
+
 	userId, err := utils.ExtractIDFromToken(accessToken)
 	if err != nil {
-		errLogHistory := c.HistoryUseCase.LogAndAddHistory("-", "LOGOUT", fmt.Sprintf("Logout failed: %v", err), err)
+		errLogHistory := c.logHistory(ctx, "", entity.AuditActionLogout, entity.AuditErrorInvalidToken, fmt.Sprintf("Logout failed: %v", err), err)
 		if errLogHistory != nil {
 			return errLogHistory
 		}
 		return err
 	}
 
-	errLogHistory := c.HistoryUseCase.LogAndAddHistory(userId, "LOGOUT", "Customer ID extracted successfully", nil)
+	errLogHistory := c.logHistory(ctx, userId, entity.AuditActionLogout, "", "Customer ID extracted successfully", nil)
 	if errLogHistory != nil {
 		return errLogHistory
 	}
 
-	err = c.AuthRepository.AddToBlacklist(accessToken)
+	event, err := entity.NewDomainEvent(entity.EventTokenRevoked, userId, entity.TokenRevokedPayload{CustomerId: userId})
+	if err == nil {
+		err = c.EventBus.Publish(ctx, func(tx *utils.JsonFileTransaction) ([]entity.DomainEvent, error) {
+			if err := c.AuthRepository.StageAddToBlacklist(ctx, tx, accessToken); err != nil {
+				return nil, err
+			}
+			return []entity.DomainEvent{event}, nil
+		})
+	}
 	if err != nil {
-		errLogHistory = c.HistoryUseCase.LogAndAddHistory(userId, "LOGOUT", fmt.Sprintf("Failed to blacklist token: %v", err), err)
+		errLogHistory = c.logHistory(ctx, userId, entity.AuditActionLogout, entity.AuditErrorStorageFailed, fmt.Sprintf("Failed to blacklist token: %v", err), err)
 		if errLogHistory != nil {
 			return errLogHistory
 		}
 		return err
 	}
 
-	errLogHistory = c.HistoryUseCase.LogAndAddHistory(userId, "LOGOUT", "Token blacklisted successfully", nil)
+	errLogHistory = c.logHistory(ctx, userId, entity.AuditActionLogout, "", "Token blacklisted successfully", nil)
 	if errLogHistory != nil {
 		return errLogHistory
 	}
 
-	errLogHistory = c.HistoryUseCase.LogAndAddHistory(userId, "LOGOUT", "Logout successful", nil)
+	errLogHistory = c.logHistory(ctx, userId, entity.AuditActionLogout, "", "Logout successful", nil)
 	if errLogHistory != nil {
 		return errLogHistory
 	}
@@ -96,10 +149,39 @@ func (c *AuthUseCaseImpl) Logout(accessToken string) error {
 	return nil
 }
 
-func (c *AuthUseCaseImpl) IsTokenBlacklisted(accessToken string) (bool, error) {
-	return c.AuthRepository.IsTokenBlacklisted(accessToken)
+// IsTokenBlacklisted also reports tokens issued before an admin revoked the
+// customer's sessions, and tokens of disabled customers.
+func (c *AuthUseCaseImpl) IsTokenBlacklisted(ctx context.Context, accessToken string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "AuthUseCase.IsTokenBlacklisted")
+	defer span.End()
+
+	blacklisted, err := c.AuthRepository.IsTokenBlacklisted(ctx, accessToken)
+	if err != nil || blacklisted {
+		return blacklisted, err
+	}
+
+	customerId, err := utils.ExtractIDFromToken(accessToken)
+	if err != nil {
+		return false, err
+	}
+	issuedAt, err := utils.ExtractIssuedAtFromToken(accessToken)
+	if err != nil {
+		return false, err
+	}
+	return c.CustomerUseCase.IsSessionRevoked(ctx, customerId, issuedAt)
+}
+
+func (c *AuthUseCaseImpl) AddToBlacklist(ctx context.Context, accessToken string) error {
+	ctx, span := tracing.Start(ctx, "AuthUseCase.AddToBlacklist")
+	defer span.End()
+
+	return c.AuthRepository.AddToBlacklist(ctx, accessToken)
 }
 
-func (c *AuthUseCaseImpl) AddToBlacklist(accessToken string) error {
-	return c.AuthRepository.AddToBlacklist(accessToken)
+func (c *AuthUseCaseImpl) logHistory(ctx context.Context, actorId string, action entity.AuditAction, errorCode entity.AuditErrorCode, message string, err error) error {
+	return c.HistoryUseCase.LogAndAddHistory(ctx, actorId, action, entity.AuditDetails{
+		SubjectType: entity.AuditSubjectSession,
+		ErrorCode:   errorCode,
+		Message:     message,
+	}, err)
 }
@@ -1,69 +1,341 @@
 package impl
 
 import (
+	"context"
+	"fmt"
 	"github.com/google/uuid"
 	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/model"
 	"merchant_bank_payment_go_api/internal/repository"
+	"merchant_bank_payment_go_api/internal/tracing"
 	"merchant_bank_payment_go_api/internal/usecase"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 )
 
 type CustomerUseCaseImpl struct {
-	HistoryUseCase     usecase.HistoryUseCase
-	CustomerRepository repository.CustomerRepository
+	HistoryUseCase               usecase.HistoryUseCase
+	CustomerRepository           repository.CustomerRepository
+	PaymentTransactionRepository repository.PaymentTransactionRepository
+	mu                           sync.Mutex
 }
 
-func NewCustomerUseCaseImpl(historyUseCase usecase.HistoryUseCase, customerRepository repository.CustomerRepository) *CustomerUseCaseImpl {
+func NewCustomerUseCaseImpl(historyUseCase usecase.HistoryUseCase, customerRepository repository.CustomerRepository,
+	paymentTransactionRepository repository.PaymentTransactionRepository) *CustomerUseCaseImpl {
 	return &CustomerUseCaseImpl{
-		HistoryUseCase:     historyUseCase,
-		CustomerRepository: customerRepository,
+		HistoryUseCase:               historyUseCase,
+		CustomerRepository:           customerRepository,
+		PaymentTransactionRepository: paymentTransactionRepository,
 	}
 }
 
-func (c *CustomerUseCaseImpl) FindById(id string) (entity.Customer, error) {
+func (c *CustomerUseCaseImpl) FindById(ctx context.Context, id string) (entity.Customer, error) {
+	ctx, span := tracing.Start(ctx, "CustomerUseCase.FindById")
+	defer span.End()
+
 	parsedUUID, err := uuid.Parse(id)
 	if err != nil {
-		logHistoryErr := c.handleLogHistory(id, "Failed to parse uuid", "Error parsing customer UUID", err)
+		logHistoryErr := c.handleLogHistory(ctx, id, id, entity.AuditErrorInvalidId, "Error parsing customer UUID", err)
 		if logHistoryErr != nil {
 			return entity.Customer{}, logHistoryErr
 		}
 		return entity.Customer{}, err
 	}
 
-	customer, err := c.CustomerRepository.FindById(parsedUUID)
+	customer, err := c.CustomerRepository.FindById(ctx, parsedUUID)
 	if err != nil {
-		logHistoryErr := c.handleLogHistory(id, "Failed to find customer by id", err.Error(), err)
+		logHistoryErr := c.handleLogHistory(ctx, id, id, entity.AuditErrorNotFound, err.Error(), err)
 		if logHistoryErr != nil {
 			return entity.Customer{}, logHistoryErr
 		}
 		return entity.Customer{}, err
 	}
 
-	logHistoryErr := c.handleLogHistory(id, "Successfully found customer by id", "Customer found successfully", nil)
+	logHistoryErr := c.handleLogHistory(ctx, id, id, "", "Customer found successfully", nil)
 	if logHistoryErr != nil {
 		return entity.Customer{}, logHistoryErr
 	}
 	return customer, nil
 }
 
-func (c *CustomerUseCaseImpl) FindByUsername(username string) (entity.Customer, error) {
-	customer, err := c.CustomerRepository.FindByUsername(username)
+func (c *CustomerUseCaseImpl) FindByUsername(ctx context.Context, username string) (entity.Customer, error) {
+	ctx, span := tracing.Start(ctx, "CustomerUseCase.FindByUsername")
+	defer span.End()
+
+	customer, err := c.CustomerRepository.FindByUsername(ctx, username)
 	if err != nil {
-		logHistoryErr := c.handleLogHistory(username, "Failed to find customer by username", err.Error(), err)
+		logHistoryErr := c.handleLogHistory(ctx, "", username, entity.AuditErrorNotFound, err.Error(), err)
 		if logHistoryErr != nil {
 			return entity.Customer{}, logHistoryErr
 		}
 		return entity.Customer{}, err
 	}
 
-	logHistoryErr := c.handleLogHistory(username, "Successfully found customer by username", "Customer found successfully", nil)
+	logHistoryErr := c.handleLogHistory(ctx, "", username, "", "Customer found successfully", nil)
 	if logHistoryErr != nil {
 		return entity.Customer{}, logHistoryErr
 	}
 	return customer, nil
 }
 
-func (c *CustomerUseCaseImpl) handleLogHistory(idOrUsername, action, message string, err error) error {
-	logHistoryErr := c.HistoryUseCase.LogAndAddHistory(idOrUsername, action, message, err)
+// IsSessionRevoked is checked on every authenticated request, so it reads the
+// customers without recording a lookup. Tokens of customers that no longer
+// exist are revoked as well.
+func (c *CustomerUseCaseImpl) IsSessionRevoked(ctx context.Context, customerId string, issuedAt time.Time) (bool, error) {
+	ctx, span := tracing.Start(ctx, "CustomerUseCase.IsSessionRevoked")
+	defer span.End()
+
+	customers, err := c.CustomerRepository.LoadCustomers(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	for _, customer := range customers {
+		if customer.Id.String() == customerId {
+			return customer.IsSessionRevoked(issuedAt), nil
+		}
+	}
+	return true, nil
+}
+
+const (
+	defaultCustomerPageSize = 20
+	maxCustomerPageSize     = 100
+	recentCustomerItems     = 10
+)
+
+// GetCustomers pages through the customers ordered by username.
+func (c *CustomerUseCaseImpl) GetCustomers(ctx context.Context, request model.CustomerQueryRequest) (model.CustomerPage, error) {
+	ctx, span := tracing.Start(ctx, "CustomerUseCase.GetCustomers")
+	defer span.End()
+
+	if request.Page < 0 || request.PageSize < 0 || request.PageSize > maxCustomerPageSize {
+		return model.CustomerPage{}, fmt.Errorf("page must be positive and pageSize between 1 and %d", maxCustomerPageSize)
+	}
+	if request.Page == 0 {
+		request.Page = 1
+	}
+	if request.PageSize == 0 {
+		request.PageSize = defaultCustomerPageSize
+	}
+	status := entity.CustomerStatus(strings.ToUpper(request.Status))
+	if status != "" && status != entity.CustomerStatusActive && status != entity.CustomerStatusDisabled {
+		return model.CustomerPage{}, fmt.Errorf("unknown customer status %q", request.Status)
+	}
+
+	customers, err := c.CustomerRepository.LoadCustomers(ctx)
+	if err != nil {
+		return model.CustomerPage{}, err
+	}
+
+	search := strings.ToLower(strings.TrimSpace(request.Search))
+	matching := make([]entity.Customer, 0, len(customers))
+	for _, customer := range customers {
+		if status != "" && customerStatus(customer) != status {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(customer.Username), search) && customer.Id.String() != search {
+			continue
+		}
+		matching = append(matching, customer)
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		return strings.ToLower(matching[i].Username) < strings.ToLower(matching[j].Username)
+	})
+
+	page := model.CustomerPage{
+		Items:      make([]model.CustomerResponse, 0, request.PageSize),
+		Page:       request.Page,
+		PageSize:   request.PageSize,
+		TotalItems: len(matching),
+		TotalPages: (len(matching) + request.PageSize - 1) / request.PageSize,
+	}
+
+	start := (request.Page - 1) * request.PageSize
+	for i := start; i < len(matching) && i < start+request.PageSize; i++ {
+		page.Items = append(page.Items, toCustomerResponse(matching[i]))
+	}
+	return page, nil
+}
+
+// GetCustomerDetail returns the customer with their latest payments and the
+// latest history entries they are the actor of, newest first.
+func (c *CustomerUseCaseImpl) GetCustomerDetail(ctx context.Context, adminId, id string) (model.CustomerDetailResponse, error) {
+	ctx, span := tracing.Start(ctx, "CustomerUseCase.GetCustomerDetail")
+	defer span.End()
+
+	parsedId, err := uuid.Parse(id)
+	if err != nil {
+		return model.CustomerDetailResponse{}, c.handleLogAdminHistory(ctx, adminId, entity.AuditActionCustomerLookup, id,
+			entity.AuditErrorInvalidId, "Error parsing customer UUID", fmt.Errorf("invalid customer id %s: %w", id, err))
+	}
+
+	customer, err := c.CustomerRepository.FindById(ctx, parsedId)
+	if err != nil {
+		return model.CustomerDetailResponse{}, c.handleLogAdminHistory(ctx, adminId, entity.AuditActionCustomerLookup, id,
+			entity.AuditErrorNotFound, err.Error(), err)
+	}
+
+	payments, err := c.PaymentTransactionRepository.FindByCustomerId(ctx, parsedId)
+	if err != nil {
+		return model.CustomerDetailResponse{}, c.handleLogAdminHistory(ctx, adminId, entity.AuditActionCustomerLookup, id,
+			entity.AuditErrorStorageFailed, fmt.Sprintf("Failed to load payments: %v", err), err)
+	}
+	sort.SliceStable(payments, func(i, j int) bool {
+		return payments[i].Timestamp.After(payments[j].Timestamp)
+	})
+
+	history, err := c.HistoryUseCase.GetCustomerHistory(ctx, id, model.HistoryQueryRequest{PageSize: recentCustomerItems})
+	if err != nil {
+		return model.CustomerDetailResponse{}, c.handleLogAdminHistory(ctx, adminId, entity.AuditActionCustomerLookup, id,
+			entity.AuditErrorStorageFailed, fmt.Sprintf("Failed to load history: %v", err), err)
+	}
+
+	detail := model.CustomerDetailResponse{
+		Customer:       toCustomerResponse(customer),
+		RecentPayments: make([]model.PaymentResponse, 0, recentCustomerItems),
+		RecentHistory:  history.Items,
+	}
+	for i := 0; i < len(payments) && i < recentCustomerItems; i++ {
+		detail.RecentPayments = append(detail.RecentPayments, toPaymentResponse(payments[i]))
+	}
+
+	errLog := c.handleLogAdminHistory(ctx, adminId, entity.AuditActionCustomerLookup, id, "", "Viewed customer details", nil)
+	if errLog != nil {
+		return model.CustomerDetailResponse{}, errLog
+	}
+	return detail, nil
+}
+
+// DisableCustomer also ends the customer's sessions, so enabling the account
+// again does not bring back old tokens.
+func (c *CustomerUseCaseImpl) DisableCustomer(ctx context.Context, adminId, id string) (model.CustomerResponse, error) {
+	ctx, span := tracing.Start(ctx, "CustomerUseCase.DisableCustomer")
+	defer span.End()
+
+	return c.changeCustomer(ctx, adminId, id, entity.AuditActionCustomerDisable, func(customer *entity.Customer, now time.Time) (string, error) {
+		if customer.Id.String() == adminId {
+			return "", fmt.Errorf("admins can not disable their own account")
+		}
+		if customer.IsDisabled() {
+			return "", fmt.Errorf("customer %s is already disabled", customer.Id)
+		}
+		customer.Status = entity.CustomerStatusDisabled
+		customer.SessionsRevokedAt = &now
+		return fmt.Sprintf("Customer %s disabled", customer.Username), nil
+	})
+}
+
+func (c *CustomerUseCaseImpl) EnableCustomer(ctx context.Context, adminId, id string) (model.CustomerResponse, error) {
+	ctx, span := tracing.Start(ctx, "CustomerUseCase.EnableCustomer")
+	defer span.End()
+
+	return c.changeCustomer(ctx, adminId, id, entity.AuditActionCustomerEnable, func(customer *entity.Customer, now time.Time) (string, error) {
+		if !customer.IsDisabled() {
+			return "", fmt.Errorf("customer %s is not disabled", customer.Id)
+		}
+		customer.Status = entity.CustomerStatusActive
+		return fmt.Sprintf("Customer %s enabled", customer.Username), nil
+	})
+}
+
+// ForceLogout revokes every access token issued to the customer so far.
+func (c *CustomerUseCaseImpl) ForceLogout(ctx context.Context, adminId, id string) (model.CustomerResponse, error) {
+	ctx, span := tracing.Start(ctx, "CustomerUseCase.ForceLogout")
+	defer span.End()
+
+	return c.changeCustomer(ctx, adminId, id, entity.AuditActionCustomerForceLogout, func(customer *entity.Customer, now time.Time) (string, error) {
+		customer.SessionsRevokedAt = &now
+		return fmt.Sprintf("Sessions of customer %s revoked", customer.Username), nil
+	})
+}
+
+// changeCustomer applies change to the stored customer and saves it with a new
+// UpdatedAt. change returns the history message, or an error when the change
+// is not allowed.
+func (c *CustomerUseCaseImpl) changeCustomer(ctx context.Context, adminId, id string, action entity.AuditAction,
+	change func(customer *entity.Customer, now time.Time) (string, error)) (model.CustomerResponse, error) {
+	parsedId, err := uuid.Parse(id)
+	if err != nil {
+		return model.CustomerResponse{}, c.handleLogAdminHistory(ctx, adminId, action, id, entity.AuditErrorInvalidId,
+			"Error parsing customer UUID", fmt.Errorf("invalid customer id %s: %w", id, err))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	customer, err := c.CustomerRepository.FindById(ctx, parsedId)
+	if err != nil {
+		return model.CustomerResponse{}, c.handleLogAdminHistory(ctx, adminId, action, id, entity.AuditErrorNotFound, err.Error(), err)
+	}
+
+	now := time.Now()
+	message, err := change(&customer, now)
+	if err != nil {
+		return model.CustomerResponse{}, c.handleLogAdminHistory(ctx, adminId, action, id, entity.AuditErrorInvalidRequest,
+			fmt.Sprintf("Customer change rejected: %v", err), err)
+	}
+	customer.UpdatedAt = now
+
+	if err := c.CustomerRepository.Update(ctx, customer); err != nil {
+		return model.CustomerResponse{}, c.handleLogAdminHistory(ctx, adminId, action, id, entity.AuditErrorStorageFailed,
+			fmt.Sprintf("Customer change failed: %v", err), err)
+	}
+
+	errLog := c.handleLogAdminHistory(ctx, adminId, action, id, "", message, nil)
+	if errLog != nil {
+		return model.CustomerResponse{}, errLog
+	}
+	return toCustomerResponse(customer), nil
+}
+
+func customerStatus(customer entity.Customer) entity.CustomerStatus {
+	if customer.IsDisabled() {
+		return entity.CustomerStatusDisabled
+	}
+	return entity.CustomerStatusActive
+}
+
+func toCustomerResponse(customer entity.Customer) model.CustomerResponse {
+	role := customer.Role
+	if role == "" {
+		role = entity.RoleCustomer
+	}
+	return model.CustomerResponse{
+		Id:                customer.Id.String(),
+		Username:          customer.Username,
+		Role:              role,
+		Status:            string(customerStatus(customer)),
+		SessionsRevokedAt: customer.SessionsRevokedAt,
+		CreatedAt:         customer.CreatedAt,
+		UpdatedAt:         customer.UpdatedAt,
+	}
+}
+
+func (c *CustomerUseCaseImpl) handleLogAdminHistory(ctx context.Context, adminId string, action entity.AuditAction, customerId string,
+	errorCode entity.AuditErrorCode, message string, err error) error {
+	errLog := c.HistoryUseCase.LogAndAddHistory(ctx, adminId, action, entity.AuditDetails{
+		ActorType:   entity.AuditActorAdmin,
+		SubjectType: entity.AuditSubjectCustomer,
+		SubjectId:   customerId,
+		ErrorCode:   errorCode,
+		Message:     message,
+	}, err)
+	if errLog != nil {
+		return errLog
+	}
+	return err
+}
+
+func (c *CustomerUseCaseImpl) handleLogHistory(ctx context.Context, actorId, idOrUsername string, errorCode entity.AuditErrorCode, message string, err error) error {
+	logHistoryErr := c.HistoryUseCase.LogAndAddHistory(ctx, actorId, entity.AuditActionCustomerLookup, entity.AuditDetails{
+		SubjectType: entity.AuditSubjectCustomer,
+		SubjectId:   idOrUsername,
+		ErrorCode:   errorCode,
+		Message:     message,
+	}, err)
 	if logHistoryErr != nil {
 		return logHistoryErr
 	}
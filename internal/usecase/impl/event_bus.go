@@ -0,0 +1,160 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/repository"
+	"merchant_bank_payment_go_api/internal/tracing"
+	"merchant_bank_payment_go_api/internal/usecase"
+	"merchant_bank_payment_go_api/internal/utils"
+	"sync"
+	"time"
+)
+
+type subscription struct {
+	name      string
+	eventType entity.DomainEventType
+	handler   usecase.EventHandler
+}
+
+// EventBusImpl stores events in the outbox together with the state change that
+// produced them and hands them to subscribers afterwards. A subscriber can see
+// the same event more than once, never zero times.
+type EventBusImpl struct {
+	Log              *logrus.Logger
+	OutboxRepository repository.OutboxRepository
+	subscriptions    []subscription
+	subscriptionsMu  sync.RWMutex
+	dispatchMu       sync.Mutex
+	notify           chan struct{}
+}
+
+func NewEventBusImpl(log *logrus.Logger, outboxRepository repository.OutboxRepository) *EventBusImpl {
+	return &EventBusImpl{
+		Log:              log,
+		OutboxRepository: outboxRepository,
+		notify:           make(chan struct{}, 1),
+	}
+}
+
+func (e *EventBusImpl) Publish(ctx context.Context, stage func(tx *utils.JsonFileTransaction) ([]entity.DomainEvent, error)) error {
+	ctx, span := tracing.Start(ctx, "EventBus.Publish")
+	defer span.End()
+
+	// Every event is stored with its state change, subscribed or not. The
+	// dispatcher marks events nobody handles as dispatched and prunes them.
+	if err := e.OutboxRepository.CommitWithEvents(ctx, stage); err != nil {
+		return err
+	}
+
+	select {
+	case e.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (e *EventBusImpl) Subscribe(name string, eventType entity.DomainEventType, handler usecase.EventHandler) {
+	e.subscriptionsMu.Lock()
+	defer e.subscriptionsMu.Unlock()
+
+	e.subscriptions = append(e.subscriptions, subscription{name: name, eventType: eventType, handler: handler})
+}
+
+// DispatchPending hands the pending events to their subscribers and then
+// removes the dispatched ones from the outbox.
+func (e *EventBusImpl) DispatchPending(ctx context.Context) (int, error) {
+	ctx, span := tracing.Start(ctx, "EventBus.DispatchPending")
+	defer span.End()
+
+	e.dispatchMu.Lock()
+	defer e.dispatchMu.Unlock()
+
+	events, err := e.OutboxRepository.LoadEvents(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	e.subscriptionsMu.RLock()
+	subscriptions := append([]subscription(nil), e.subscriptions...)
+	e.subscriptionsMu.RUnlock()
+
+	dispatched, prunable := 0, 0
+	for _, event := range events {
+		if event.Status != entity.DomainEventStatusPending {
+			prunable++
+			continue
+		}
+
+		var failures []string
+		for _, sub := range subscriptions {
+			if sub.eventType != event.Type || event.IsDeliveredTo(sub.name) {
+				continue
+			}
+
+			if err := sub.handler(ctx, event); err != nil {
+				e.Log.WithContext(ctx).Warnf("Subscriber %s failed to handle event %s (%s): %v", sub.name, event.Id, event.Type, err)
+				failures = append(failures, fmt.Sprintf("%s: %v", sub.name, err))
+				continue
+			}
+			event.DeliveredTo = append(event.DeliveredTo, sub.name)
+		}
+
+		event.Attempts++
+		if len(failures) == 0 {
+			now := time.Now()
+			event.Status = entity.DomainEventStatusDispatched
+			event.DispatchedAt = &now
+			event.LastError = ""
+			dispatched++
+		} else {
+			event.LastError = fmt.Sprint(failures)
+		}
+
+		if err := e.OutboxRepository.UpdateEvent(ctx, event); err != nil {
+			return dispatched, err
+		}
+	}
+
+	if dispatched+prunable > 0 {
+		if _, err := e.OutboxRepository.PruneDispatched(ctx); err != nil {
+			return dispatched, err
+		}
+	}
+	return dispatched, nil
+}
+
+// StartWorker dispatches right after every publish and at least once per
+// interval, until the returned stop function is called. Stopping waits for a
+// dispatch in progress to return.
+func (e *EventBusImpl) StartWorker(interval time.Duration) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			case <-e.notify:
+			}
+
+			if _, err := e.DispatchPending(ctx); err != nil {
+				e.Log.WithContext(ctx).Errorf("Event dispatcher failed: %v", err)
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		ticker.Stop()
+		close(done)
+		<-stopped
+	}
+}
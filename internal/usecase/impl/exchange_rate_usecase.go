@@ -0,0 +1,194 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/internal/repository"
+	"merchant_bank_payment_go_api/internal/tracing"
+	"merchant_bank_payment_go_api/internal/usecase"
+	"strconv"
+	"time"
+)
+
+const basisPointsScale = 10000
+
+type ExchangeRateUseCaseImpl struct {
+	HistoryUseCase         usecase.HistoryUseCase
+	ExchangeRateRepository repository.ExchangeRateRepository
+	MaxRateAge             time.Duration
+	SpreadBasisPoints      int64
+	Rounding               entity.RoundingMode
+}
+
+func NewExchangeRateUseCaseImpl(historyUseCase usecase.HistoryUseCase, exchangeRateRepository repository.ExchangeRateRepository,
+	maxRateAge time.Duration, spreadBasisPoints int64, rounding entity.RoundingMode) *ExchangeRateUseCaseImpl {
+	return &ExchangeRateUseCaseImpl{
+		HistoryUseCase:         historyUseCase,
+		ExchangeRateRepository: exchangeRateRepository,
+		MaxRateAge:             maxRateAge,
+		SpreadBasisPoints:      spreadBasisPoints,
+		Rounding:               rounding,
+	}
+}
+
+func (e *ExchangeRateUseCaseImpl) Convert(ctx context.Context, amount entity.Money, targetCurrency string) (entity.Money, entity.CurrencyConversion, error) {
+	ctx, span := tracing.Start(ctx, "ExchangeRateUseCase.Convert")
+	defer span.End()
+
+	source, err := entity.FindCurrency(amount.CurrencyCode())
+	if err != nil {
+		return entity.Money{}, entity.CurrencyConversion{}, err
+	}
+
+	target, err := entity.FindCurrency(targetCurrency)
+	if err != nil {
+		return entity.Money{}, entity.CurrencyConversion{}, err
+	}
+
+	if source.Code == target.Code {
+		return amount, entity.CurrencyConversion{OriginalAmount: amount, MidRate: 1, AppliedRate: 1}, nil
+	}
+
+	if e.SpreadBasisPoints < 0 || e.SpreadBasisPoints >= basisPointsScale {
+		return entity.Money{}, entity.CurrencyConversion{}, fmt.Errorf("invalid fx spread of %d basis points", e.SpreadBasisPoints)
+	}
+
+	rate, err := e.ExchangeRateRepository.FindRate(ctx, source.Code, target.Code)
+	if err != nil {
+		return entity.Money{}, entity.CurrencyConversion{}, err
+	}
+
+	if rate.Rate <= 0 {
+		return entity.Money{}, entity.CurrencyConversion{}, fmt.Errorf("invalid exchange rate %s/%s: %v", source.Code, target.Code, rate.Rate)
+	}
+
+	if e.MaxRateAge > 0 && time.Since(rate.Timestamp) > e.MaxRateAge {
+		return entity.Money{}, entity.CurrencyConversion{}, fmt.Errorf("exchange rate %s/%s from %s is stale",
+			source.Code, target.Code, rate.Timestamp.Format(time.RFC3339))
+	}
+
+	midRate, ok := new(big.Rat).SetString(strconv.FormatFloat(rate.Rate, 'f', -1, 64))
+	if !ok {
+		return entity.Money{}, entity.CurrencyConversion{}, fmt.Errorf("invalid exchange rate %s/%s: %v", source.Code, target.Code, rate.Rate)
+	}
+
+	appliedRate := new(big.Rat).Mul(midRate, big.NewRat(basisPointsScale-e.SpreadBasisPoints, basisPointsScale))
+
+	converted := new(big.Rat).Mul(new(big.Rat).SetInt64(amount.Amount), appliedRate)
+	converted.Mul(converted, new(big.Rat).SetFrac(pow10(target.MinorUnits), pow10(source.MinorUnits)))
+
+	convertedAmount := e.Rounding.Round(converted)
+	if convertedAmount <= 0 {
+		return entity.Money{}, entity.CurrencyConversion{}, fmt.Errorf("%s is too small to convert to %s", amount, target.Code)
+	}
+
+	appliedRateValue, _ := appliedRate.Float64()
+	return entity.Money{Amount: convertedAmount, Currency: target.Code}, entity.CurrencyConversion{
+		OriginalAmount:    amount,
+		MidRate:           rate.Rate,
+		AppliedRate:       appliedRateValue,
+		SpreadBasisPoints: e.SpreadBasisPoints,
+		RateTimestamp:     rate.Timestamp,
+	}, nil
+}
+
+func (e *ExchangeRateUseCaseImpl) GetRates(ctx context.Context) ([]model.ExchangeRateResponse, error) {
+	ctx, span := tracing.Start(ctx, "ExchangeRateUseCase.GetRates")
+	defer span.End()
+
+	rates, err := e.ExchangeRateRepository.LoadRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toExchangeRateResponses(rates), nil
+}
+
+// ReplaceRates publishes a new rate table. Every rate gets the current time as
+// its timestamp, which is what the staleness check in Convert measures against.
+func (e *ExchangeRateUseCaseImpl) ReplaceRates(ctx context.Context, adminId string, request model.ExchangeRateRequest) ([]model.ExchangeRateResponse, error) {
+	ctx, span := tracing.Start(ctx, "ExchangeRateUseCase.ReplaceRates")
+	defer span.End()
+
+	rates, err := toExchangeRates(request, time.Now())
+	if err != nil {
+		return nil, e.handleLogAdminHistory(ctx, adminId, entity.AuditErrorInvalidRequest, err.Error(), err)
+	}
+
+	if err := e.ExchangeRateRepository.SaveRates(ctx, rates); err != nil {
+		return nil, e.handleLogAdminHistory(ctx, adminId, entity.AuditErrorStorageFailed, "failed to save exchange rates", err)
+	}
+
+	errLog := e.handleLogAdminHistory(ctx, adminId, "", fmt.Sprintf("published %d exchange rates", len(rates)), nil)
+	if errLog != nil {
+		return nil, errLog
+	}
+	return toExchangeRateResponses(rates), nil
+}
+
+func toExchangeRates(request model.ExchangeRateRequest, now time.Time) ([]entity.ExchangeRate, error) {
+	if len(request.Rates) == 0 {
+		return nil, fmt.Errorf("at least one exchange rate is required")
+	}
+
+	seen := make(map[string]bool, len(request.Rates))
+	rates := make([]entity.ExchangeRate, 0, len(request.Rates))
+	for _, entry := range request.Rates {
+		base, err := entity.FindCurrency(entry.Base)
+		if err != nil {
+			return nil, err
+		}
+		quote, err := entity.FindCurrency(entry.Quote)
+		if err != nil {
+			return nil, err
+		}
+		if base.Code == quote.Code {
+			return nil, fmt.Errorf("exchange rate %s/%s needs two different currencies", base.Code, quote.Code)
+		}
+		if entry.Rate <= 0 {
+			return nil, fmt.Errorf("invalid exchange rate %s/%s: %v", base.Code, quote.Code, entry.Rate)
+		}
+
+		// An inverse pair would be ambiguous, since FindRate falls back to inverting a rate.
+		if seen[base.Code+"/"+quote.Code] || seen[quote.Code+"/"+base.Code] {
+			return nil, fmt.Errorf("exchange rate %s/%s is listed more than once", base.Code, quote.Code)
+		}
+		seen[base.Code+"/"+quote.Code] = true
+
+		rates = append(rates, entity.ExchangeRate{Base: base.Code, Quote: quote.Code, Rate: entry.Rate, Timestamp: now})
+	}
+	return rates, nil
+}
+
+func toExchangeRateResponses(rates []entity.ExchangeRate) []model.ExchangeRateResponse {
+	responses := make([]model.ExchangeRateResponse, 0, len(rates))
+	for _, rate := range rates {
+		responses = append(responses, model.ExchangeRateResponse{
+			Base:      rate.Base,
+			Quote:     rate.Quote,
+			Rate:      rate.Rate,
+			Timestamp: rate.Timestamp,
+		})
+	}
+	return responses
+}
+
+func (e *ExchangeRateUseCaseImpl) handleLogAdminHistory(ctx context.Context, adminId string, errorCode entity.AuditErrorCode,
+	message string, err error) error {
+	errLog := e.HistoryUseCase.LogAndAddHistory(ctx, adminId, entity.AuditActionExchangeRateUpdate, entity.AuditDetails{
+		ActorType:   entity.AuditActorAdmin,
+		SubjectType: entity.AuditSubjectExchangeRate,
+		ErrorCode:   errorCode,
+		Message:     message,
+	}, err)
+	if errLog != nil {
+		return errLog
+	}
+	return err
+}
+
+func pow10(exponent int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil)
+}
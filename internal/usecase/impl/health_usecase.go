@@ -0,0 +1,86 @@
+package impl
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"merchant_bank_payment_go_api/internal/buildinfo"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/internal/repository"
+	"merchant_bank_payment_go_api/internal/tracing"
+	"runtime"
+	"sort"
+	"time"
+)
+
+const (
+	HealthStatusUp   = "UP"
+	HealthStatusDown = "DOWN"
+
+	jwtKeyCheckName = "jwt_key"
+)
+
+type HealthUseCaseImpl struct {
+	Log          *logrus.Logger
+	Repositories map[string]repository.HealthCheckRepository
+	JwtKeyLoaded func() bool
+}
+
+// NewHealthUseCaseImpl checks every repository in repositories, reported under
+// its map key, and whether jwtKeyLoaded reports a signing key.
+func NewHealthUseCaseImpl(log *logrus.Logger, repositories map[string]repository.HealthCheckRepository,
+	jwtKeyLoaded func() bool) *HealthUseCaseImpl {
+	return &HealthUseCaseImpl{
+		Log:          log,
+		Repositories: repositories,
+		JwtKeyLoaded: jwtKeyLoaded,
+	}
+}
+
+func (h *HealthUseCaseImpl) CheckReadiness(ctx context.Context) model.ReadinessResponse {
+	ctx, span := tracing.Start(ctx, "HealthUseCase.CheckReadiness")
+	defer span.End()
+
+	names := make([]string, 0, len(h.Repositories))
+	for name := range h.Repositories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	response := model.ReadinessResponse{Status: HealthStatusUp}
+	addCheck := func(name, failure string) {
+		check := model.ReadinessCheckResponse{Name: name, Status: HealthStatusUp}
+		if failure != "" {
+			check.Status, check.Error = HealthStatusDown, failure
+			response.Status = HealthStatusDown
+			h.Log.WithContext(ctx).Warnf("Readiness check %s failed: %s", name, failure)
+		}
+		response.Checks = append(response.Checks, check)
+	}
+
+	for _, name := range names {
+		failure := ""
+		if err := h.Repositories[name].CheckHealth(ctx); err != nil {
+			failure = err.Error()
+		}
+		addCheck(name, failure)
+	}
+
+	failure := ""
+	if !h.JwtKeyLoaded() {
+		failure = "JWT signing key is not loaded"
+	}
+	addCheck(jwtKeyCheckName, failure)
+
+	return response
+}
+
+func (h *HealthUseCaseImpl) GetVersion() model.VersionResponse {
+	return model.VersionResponse{
+		Version:       buildinfo.Version,
+		Commit:        buildinfo.ResolvedCommit(),
+		BuildTime:     buildinfo.BuildTime,
+		GoVersion:     runtime.Version(),
+		StartedAt:     buildinfo.StartedAt,
+		UptimeSeconds: int64(time.Since(buildinfo.StartedAt).Seconds()),
+	}
+}
@@ -0,0 +1,140 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/repository"
+	"merchant_bank_payment_go_api/internal/tracing"
+	"merchant_bank_payment_go_api/internal/usecase"
+	"time"
+)
+
+type HistoryArchiveUseCaseImpl struct {
+	Log                      *logrus.Logger
+	HistoryRepository        repository.HistoryRepository
+	HistoryArchiveRepository repository.HistoryArchiveRepository
+	HistoryUseCase           usecase.HistoryUseCase
+	Retention                time.Duration
+}
+
+func NewHistoryArchiveUseCaseImpl(log *logrus.Logger, historyRepository repository.HistoryRepository,
+	historyArchiveRepository repository.HistoryArchiveRepository, historyUseCase usecase.HistoryUseCase,
+	retention time.Duration) *HistoryArchiveUseCaseImpl {
+	return &HistoryArchiveUseCaseImpl{
+		Log:                      log,
+		HistoryRepository:        historyRepository,
+		HistoryArchiveRepository: historyArchiveRepository,
+		HistoryUseCase:           historyUseCase,
+		Retention:                retention,
+	}
+}
+
+// ArchiveHistories moves the records older than the retention period into a
+// new archive. It returns nil when there is nothing to archive.
+func (h *HistoryArchiveUseCaseImpl) ArchiveHistories(ctx context.Context, now time.Time) (*entity.HistoryArchive, error) {
+	ctx, span := tracing.Start(ctx, "HistoryArchiveUseCase.ArchiveHistories")
+	defer span.End()
+
+	return h.archive(ctx, now, h.Retention)
+}
+
+// ArchiveNow archives on an admin's request instead of waiting for the worker.
+// A zero olderThan keeps the configured retention.
+func (h *HistoryArchiveUseCaseImpl) ArchiveNow(ctx context.Context, adminId string, olderThan time.Duration) (*entity.HistoryArchive, error) {
+	ctx, span := tracing.Start(ctx, "HistoryArchiveUseCase.ArchiveNow")
+	defer span.End()
+
+	if olderThan == 0 {
+		olderThan = h.Retention
+	}
+	if olderThan <= 0 {
+		err := fmt.Errorf("history archival is disabled, pass a retention to archive")
+		return nil, h.handleLogAdminHistory(ctx, adminId, "", entity.AuditErrorInvalidRequest, err.Error(), err)
+	}
+
+	archive, err := h.archive(ctx, time.Now(), olderThan)
+	if err != nil {
+		return nil, h.handleLogAdminHistory(ctx, adminId, "", entity.AuditErrorStorageFailed, "failed to archive history", err)
+	}
+
+	message := fmt.Sprintf("No history older than %s to archive", olderThan)
+	archiveId := ""
+	if archive != nil {
+		message = fmt.Sprintf("Archived %d histories into %s", archive.Records, archive.Filename)
+		archiveId = archive.Id.String()
+	}
+	errLog := h.handleLogAdminHistory(ctx, adminId, archiveId, "", message, nil)
+	if errLog != nil {
+		return nil, errLog
+	}
+	return archive, nil
+}
+
+func (h *HistoryArchiveUseCaseImpl) archive(ctx context.Context, now time.Time, retention time.Duration) (*entity.HistoryArchive, error) {
+	cutoff := now.Add(-retention)
+	h.Log.WithContext(ctx).Debugf("Archiving histories older than %s", cutoff.Format(time.RFC3339))
+
+	var archive *entity.HistoryArchive
+	_, err := h.HistoryRepository.PruneHistories(ctx, cutoff, func(histories []entity.History) error {
+		written, err := h.HistoryArchiveRepository.WriteArchive(ctx, histories, now)
+		if err != nil {
+			return err
+		}
+		archive = &written
+		return nil
+	})
+	if err != nil {
+		h.Log.WithContext(ctx).Errorf("Failed to archive histories older than %s: %v", cutoff.Format(time.RFC3339), err)
+		return nil, err
+	}
+
+	if archive != nil {
+		h.Log.WithContext(ctx).Infof("Archived %d histories up to %s into %s", archive.Records, archive.To.Format(time.RFC3339), archive.Filename)
+	}
+	return archive, nil
+}
+
+func (h *HistoryArchiveUseCaseImpl) handleLogAdminHistory(ctx context.Context, adminId, archiveId string,
+	errorCode entity.AuditErrorCode, message string, err error) error {
+	errLog := h.HistoryUseCase.LogAndAddHistory(ctx, adminId, entity.AuditActionHistoryArchive, entity.AuditDetails{
+		ActorType:   entity.AuditActorAdmin,
+		SubjectType: entity.AuditSubjectHistory,
+		SubjectId:   archiveId,
+		ErrorCode:   errorCode,
+		Message:     message,
+	}, err)
+	if errLog != nil {
+		return errLog
+	}
+	return err
+}
+
+func (h *HistoryArchiveUseCaseImpl) StartArchiveWorker(interval time.Duration) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				if _, err := h.ArchiveHistories(ctx, now); err != nil {
+					h.Log.WithContext(ctx).Errorf("History archival failed: %v", err)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		ticker.Stop()
+		close(done)
+		<-stopped
+	}
+}
@@ -1,49 +1,315 @@
 package impl
 
 import (
+	"context"
+	"encoding/csv"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
+	"io"
 	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/model"
 	"merchant_bank_payment_go_api/internal/repository"
+	"merchant_bank_payment_go_api/internal/tracing"
+	"merchant_bank_payment_go_api/internal/utils"
+	"strconv"
+	"strings"
 	"time"
 )
 
 type HistoryUseCaseImpl struct {
-	Log               *logrus.Logger
-	HistoryRepository repository.HistoryRepository
+	Log                      *logrus.Logger
+	HistoryRepository        repository.HistoryRepository
+	HistoryArchiveRepository repository.HistoryArchiveRepository
 }
 
-func NewHistoryUseCaseImpl(log *logrus.Logger, historyRepository repository.HistoryRepository) *HistoryUseCaseImpl {
+func NewHistoryUseCaseImpl(log *logrus.Logger, historyRepository repository.HistoryRepository,
+	historyArchiveRepository repository.HistoryArchiveRepository) *HistoryUseCaseImpl {
 	return &HistoryUseCaseImpl{
-		Log:               log,
-		HistoryRepository: historyRepository,
+		Log:                      log,
+		HistoryRepository:        historyRepository,
+		HistoryArchiveRepository: historyArchiveRepository,
 	}
 }
 
-func (h *HistoryUseCaseImpl) AddHistory(customerId, action, details string) error {
-	h.Log.Infof("Attempting to add %s history to the histories", customerId)
+func (h *HistoryUseCaseImpl) AddHistory(ctx context.Context, actorId string, action entity.AuditAction, details entity.AuditDetails) error {
+	ctx, span := tracing.Start(ctx, "HistoryUseCase.AddHistory")
+	defer span.End()
+
+	if !action.IsValid() {
+		return fmt.Errorf("unknown audit action %q", action)
+	}
+
+	h.Log.WithContext(ctx).Infof("Attempting to add %s history for %s", action, actorId)
+
+	if details.ActorType == "" {
+		details.ActorType = entity.AuditActorCustomer
+		if actorId == "" {
+			details.ActorType = entity.AuditActorAnonymous
+		}
+	}
+	if details.Outcome == "" {
+		details.Outcome = entity.AuditOutcomeSuccess
+	}
+	if details.RequestId == "" {
+		details.RequestId = utils.RequestIdFromContext(ctx)
+	}
+	if details.Ip == "" && details.UserAgent == "" {
+		details.Ip, details.UserAgent = utils.ClientFromContext(ctx)
+	}
 
 	newHistory := entity.History{
 		Id:         uuid.New(),
 		Action:     action,
-		CustomerId: customerId,
+		CustomerId: actorId,
 		Timestamp:  time.Now(),
 		Details:    details,
 	}
 
-	return h.HistoryRepository.AddHistory(newHistory)
+	// Audit entries are kept even when the request that caused them timed out
+	// or was cancelled by the client.
+	return h.HistoryRepository.AddHistory(context.WithoutCancel(ctx), newHistory)
 }
 
-func (h *HistoryUseCaseImpl) LogAndAddHistory(userId, action, message string, err error) error {
+// LogAndAddHistory logs details.Message and records it. A non-nil err marks
+// the entry as failed, with AuditErrorInternal unless the caller set a code.
+func (h *HistoryUseCaseImpl) LogAndAddHistory(ctx context.Context, actorId string, action entity.AuditAction, details entity.AuditDetails, err error) error {
+	// The failure belongs to the caller's span, not to the history write.
+	tracing.RecordError(ctx, err)
+
+	ctx, span := tracing.Start(ctx, "HistoryUseCase.LogAndAddHistory")
+	defer span.End()
+
 	if err != nil {
-		h.Log.Errorf(message+": %v", err)
+		h.Log.WithContext(ctx).Errorf(details.Message+": %v", err)
+		details.Outcome = entity.AuditOutcomeFailure
+		if details.ErrorCode == "" {
+			details.ErrorCode = entity.AuditErrorInternal
+		}
 	} else {
-		h.Log.Infof(message)
+		h.Log.WithContext(ctx).Infof(details.Message)
+		details.Outcome = entity.AuditOutcomeSuccess
 	}
 
-	historyErr := h.AddHistory(userId, action, message)
+	historyErr := h.AddHistory(ctx, actorId, action, details)
 	if historyErr != nil {
-		h.Log.Errorf("Failed to add history for %s: %v", action, historyErr)
+		h.Log.WithContext(ctx).Errorf("Failed to add history for %s: %v", action, historyErr)
 	}
 	return historyErr
 }
+
+const (
+	defaultHistoryPageSize = 20
+	maxHistoryPageSize     = 100
+)
+
+var historyCsvHeader = []string{"id", "sequence", "timestamp", "action", "customer_id", "actor_type", "subject_type",
+	"subject_id", "outcome", "error_code", "message", "ip", "user_agent", "request_id"}
+
+type historyFilter struct {
+	customerId string
+	action     entity.AuditAction
+	outcome    entity.AuditOutcome
+	from       time.Time
+	to         time.Time
+}
+
+func (f historyFilter) matches(history entity.History) bool {
+	return (f.customerId == "" || history.CustomerId == f.customerId) &&
+		(f.action == "" || history.Action == f.action) &&
+		(f.outcome == "" || history.Details.Outcome == f.outcome) &&
+		(f.from.IsZero() || !history.Timestamp.Before(f.from)) &&
+		(f.to.IsZero() || history.Timestamp.Before(f.to))
+}
+
+// GetCustomerHistory pages through the customer's own history; a customerId
+// in the request is ignored.
+func (h *HistoryUseCaseImpl) GetCustomerHistory(ctx context.Context, customerId string, request model.HistoryQueryRequest) (model.HistoryPage, error) {
+	ctx, span := tracing.Start(ctx, "HistoryUseCase.GetCustomerHistory")
+	defer span.End()
+
+	request.CustomerId = customerId
+	return h.queryHistory(ctx, request)
+}
+
+func (h *HistoryUseCaseImpl) SearchHistory(ctx context.Context, adminId string, request model.HistoryQueryRequest) (model.HistoryPage, error) {
+	ctx, span := tracing.Start(ctx, "HistoryUseCase.SearchHistory")
+	defer span.End()
+
+	page, err := h.queryHistory(ctx, request)
+	if err != nil {
+		return model.HistoryPage{}, err
+	}
+
+	errLog := h.logHistoryView(ctx, adminId, request, fmt.Sprintf("Searched history, %d matching entries", page.TotalItems))
+	if errLog != nil {
+		return model.HistoryPage{}, errLog
+	}
+	return page, nil
+}
+
+// ExportHistory writes every entry matching the filters as CSV, newest first,
+// ignoring pagination.
+func (h *HistoryUseCaseImpl) ExportHistory(ctx context.Context, adminId string, request model.HistoryQueryRequest, writer io.Writer) error {
+	ctx, span := tracing.Start(ctx, "HistoryUseCase.ExportHistory")
+	defer span.End()
+
+	histories, err := h.filterHistories(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	errLog := h.logHistoryView(ctx, adminId, request, fmt.Sprintf("Exported %d history entries", len(histories)))
+	if errLog != nil {
+		return errLog
+	}
+
+	csvWriter := csv.NewWriter(writer)
+	if err := csvWriter.Write(historyCsvHeader); err != nil {
+		return err
+	}
+	for _, history := range histories {
+		row := toHistoryResponse(history)
+		err := csvWriter.Write([]string{row.Id, strconv.FormatInt(row.Sequence, 10), row.Timestamp.Format(time.RFC3339Nano),
+			row.Action, row.CustomerId, row.ActorType, row.SubjectType, row.SubjectId, row.Outcome, row.ErrorCode,
+			row.Message, row.Ip, row.UserAgent, row.RequestId})
+		if err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func (h *HistoryUseCaseImpl) queryHistory(ctx context.Context, request model.HistoryQueryRequest) (model.HistoryPage, error) {
+	if request.Page < 0 || request.PageSize < 0 || request.PageSize > maxHistoryPageSize {
+		return model.HistoryPage{}, fmt.Errorf("page must be positive and pageSize between 1 and %d", maxHistoryPageSize)
+	}
+	if request.Page == 0 {
+		request.Page = 1
+	}
+	if request.PageSize == 0 {
+		request.PageSize = defaultHistoryPageSize
+	}
+
+	histories, err := h.filterHistories(ctx, request)
+	if err != nil {
+		return model.HistoryPage{}, err
+	}
+
+	page := model.HistoryPage{
+		Items:      make([]model.HistoryResponse, 0, request.PageSize),
+		Page:       request.Page,
+		PageSize:   request.PageSize,
+		TotalItems: len(histories),
+		TotalPages: (len(histories) + request.PageSize - 1) / request.PageSize,
+	}
+
+	start := (request.Page - 1) * request.PageSize
+	for i := start; i < len(histories) && i < start+request.PageSize; i++ {
+		page.Items = append(page.Items, toHistoryResponse(histories[i]))
+	}
+	return page, nil
+}
+
+// filterHistories returns the matching entries, newest first. Archived
+// entries are only searched when the request includes archives, and only in
+// archives overlapping the requested range.
+func (h *HistoryUseCaseImpl) filterHistories(ctx context.Context, request model.HistoryQueryRequest) ([]entity.History, error) {
+	filter := historyFilter{
+		customerId: request.CustomerId,
+		action:     entity.AuditAction(strings.ToUpper(request.Action)),
+		outcome:    entity.AuditOutcome(strings.ToUpper(request.Outcome)),
+	}
+	if filter.action != "" && !filter.action.IsValid() && filter.action != entity.AuditActionUnknown {
+		return nil, fmt.Errorf("unknown action %q", request.Action)
+	}
+	if filter.outcome != "" && filter.outcome != entity.AuditOutcomeSuccess && filter.outcome != entity.AuditOutcomeFailure {
+		return nil, fmt.Errorf("outcome must be %s or %s", entity.AuditOutcomeSuccess, entity.AuditOutcomeFailure)
+	}
+
+	var err error
+	if request.From != "" {
+		if filter.from, err = parseReportDate(request.From, false); err != nil {
+			return nil, fmt.Errorf("invalid from: %w", err)
+		}
+	}
+	if request.To != "" {
+		if filter.to, err = parseReportDate(request.To, true); err != nil {
+			return nil, fmt.Errorf("invalid to: %w", err)
+		}
+	}
+
+	histories, err := h.HistoryRepository.LoadHistories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.IncludeArchived {
+		archived, err := h.loadArchivedHistories(ctx, filter.from, filter.to)
+		if err != nil {
+			return nil, err
+		}
+		histories = append(archived, histories...)
+	}
+
+	matching := make([]entity.History, 0)
+	for i := len(histories) - 1; i >= 0; i-- {
+		if filter.matches(histories[i]) {
+			matching = append(matching, histories[i])
+		}
+	}
+	return matching, nil
+}
+
+func (h *HistoryUseCaseImpl) loadArchivedHistories(ctx context.Context, from, to time.Time) ([]entity.History, error) {
+	archives, err := h.HistoryArchiveRepository.LoadArchives(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var histories []entity.History
+	for _, archive := range archives {
+		if !archive.Overlaps(from, to) {
+			continue
+		}
+		archived, err := h.HistoryArchiveRepository.LoadArchivedHistories(ctx, archive)
+		if err != nil {
+			return nil, err
+		}
+		histories = append(histories, archived...)
+	}
+	return histories, nil
+}
+
+func (h *HistoryUseCaseImpl) logHistoryView(ctx context.Context, adminId string, request model.HistoryQueryRequest, message string) error {
+	details := entity.AuditDetails{
+		ActorType:   entity.AuditActorAdmin,
+		SubjectType: entity.AuditSubjectHistory,
+		Message:     message,
+	}
+	if request.CustomerId != "" {
+		details.SubjectType = entity.AuditSubjectCustomer
+		details.SubjectId = request.CustomerId
+	}
+	return h.LogAndAddHistory(ctx, adminId, entity.AuditActionHistoryView, details, nil)
+}
+
+func toHistoryResponse(history entity.History) model.HistoryResponse {
+	return model.HistoryResponse{
+		Id:          history.Id.String(),
+		Sequence:    history.Sequence,
+		Timestamp:   history.Timestamp,
+		Action:      string(history.Action),
+		CustomerId:  history.CustomerId,
+		ActorType:   string(history.Details.ActorType),
+		SubjectType: string(history.Details.SubjectType),
+		SubjectId:   history.Details.SubjectId,
+		Outcome:     string(history.Details.Outcome),
+		ErrorCode:   string(history.Details.ErrorCode),
+		Message:     history.Details.Message,
+		Ip:          history.Details.Ip,
+		UserAgent:   history.Details.UserAgent,
+		RequestId:   history.Details.RequestId,
+	}
+}
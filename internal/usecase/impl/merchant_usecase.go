@@ -1,15 +1,25 @@
 package impl
 
 import (
+	"context"
+	"fmt"
 	"github.com/google/uuid"
 	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/model"
 	"merchant_bank_payment_go_api/internal/repository"
+	"merchant_bank_payment_go_api/internal/tracing"
 	"merchant_bank_payment_go_api/internal/usecase"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 )
 
 type MerchantUseCaseImpl struct {
 	HistoryUseCase     usecase.HistoryUseCase
 	MerchantRepository repository.MerchantRepository
+	mu                 sync.Mutex
 }
 
 func NewMerchantUseCaseImpl(historyUseCase usecase.HistoryUseCase, merchantRepository repository.MerchantRepository) *MerchantUseCaseImpl {
@@ -19,28 +29,324 @@ func NewMerchantUseCaseImpl(historyUseCase usecase.HistoryUseCase, merchantRepos
 	}
 }
 
-func (m *MerchantUseCaseImpl) FindById(id string) (entity.Merchant, error) {
+func (m *MerchantUseCaseImpl) FindById(ctx context.Context, id string) (entity.Merchant, error) {
+	ctx, span := tracing.Start(ctx, "MerchantUseCase.FindById")
+	defer span.End()
+
 	parsedUUID, err := uuid.Parse(id)
 	if err != nil {
-		logHistoryErr := m.HistoryUseCase.LogAndAddHistory(id, "Failed to parse UUID", "Error parsing merchant UUID", err)
+		logHistoryErr := m.handleLogHistory(ctx, id, entity.AuditErrorInvalidId, "Error parsing merchant UUID", err)
 		if logHistoryErr != nil {
 			return entity.Merchant{}, logHistoryErr
 		}
 		return entity.Merchant{}, err
 	}
 
-	merchant, err := m.MerchantRepository.FindById(parsedUUID)
+	merchant, err := m.MerchantRepository.FindById(ctx, parsedUUID)
 	if err != nil {
-		logHistoryErr := m.HistoryUseCase.LogAndAddHistory(id, "Failed to find merchant by id", err.Error(), err)
+		logHistoryErr := m.handleLogHistory(ctx, id, entity.AuditErrorNotFound, err.Error(), err)
 		if logHistoryErr != nil {
 			return entity.Merchant{}, logHistoryErr
 		}
 		return entity.Merchant{}, err
 	}
 
-	logHistoryErr := m.HistoryUseCase.LogAndAddHistory(id, "Successfully found merchant by id", "Merchant found successfully", nil)
+	logHistoryErr := m.handleLogHistory(ctx, id, "", "Merchant found successfully", nil)
 	if logHistoryErr != nil {
 		return entity.Merchant{}, logHistoryErr
 	}
 	return merchant, nil
 }
+
+const (
+	defaultMerchantPageSize = 20
+	maxMerchantPageSize     = 100
+	maxMerchantNameLength   = 100
+)
+
+// GetMerchants pages through the merchants ordered by name.
+func (m *MerchantUseCaseImpl) GetMerchants(ctx context.Context, request model.MerchantQueryRequest) (model.MerchantPage, error) {
+	ctx, span := tracing.Start(ctx, "MerchantUseCase.GetMerchants")
+	defer span.End()
+
+	if request.Page < 0 || request.PageSize < 0 || request.PageSize > maxMerchantPageSize {
+		return model.MerchantPage{}, fmt.Errorf("page must be positive and pageSize between 1 and %d", maxMerchantPageSize)
+	}
+	if request.Page == 0 {
+		request.Page = 1
+	}
+	if request.PageSize == 0 {
+		request.PageSize = defaultMerchantPageSize
+	}
+	status := entity.MerchantStatus(strings.ToUpper(request.Status))
+	if status != "" && status != entity.MerchantStatusActive && status != entity.MerchantStatusSuspended {
+		return model.MerchantPage{}, fmt.Errorf("unknown merchant status %q", request.Status)
+	}
+
+	merchants, err := m.MerchantRepository.LoadMerchants(ctx)
+	if err != nil {
+		return model.MerchantPage{}, err
+	}
+
+	search := strings.ToLower(strings.TrimSpace(request.Search))
+	matching := make([]entity.Merchant, 0, len(merchants))
+	for _, merchant := range merchants {
+		if status != "" && merchantStatus(merchant) != status {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(merchant.Name), search) && merchant.Id.String() != search {
+			continue
+		}
+		matching = append(matching, merchant)
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		return strings.ToLower(matching[i].Name) < strings.ToLower(matching[j].Name)
+	})
+
+	page := model.MerchantPage{
+		Items:      make([]model.MerchantResponse, 0, request.PageSize),
+		Page:       request.Page,
+		PageSize:   request.PageSize,
+		TotalItems: len(matching),
+		TotalPages: (len(matching) + request.PageSize - 1) / request.PageSize,
+	}
+
+	start := (request.Page - 1) * request.PageSize
+	for i := start; i < len(matching) && i < start+request.PageSize; i++ {
+		page.Items = append(page.Items, toMerchantResponse(matching[i]))
+	}
+	return page, nil
+}
+
+func (m *MerchantUseCaseImpl) CreateMerchant(ctx context.Context, adminId string, request model.MerchantRequest) (model.MerchantResponse, error) {
+	ctx, span := tracing.Start(ctx, "MerchantUseCase.CreateMerchant")
+	defer span.End()
+
+	now := time.Now()
+	merchant := entity.Merchant{
+		Id:        uuid.New(),
+		Status:    entity.MerchantStatusActive,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := applyMerchantRequest(&merchant, request); err != nil {
+		return model.MerchantResponse{}, m.handleLogAdminHistory(ctx, adminId, entity.AuditActionMerchantCreate, "",
+			entity.AuditErrorInvalidRequest, fmt.Sprintf("Merchant creation failed: %v", err), err)
+	}
+
+	if err := m.MerchantRepository.Save(ctx, merchant); err != nil {
+		return model.MerchantResponse{}, m.handleLogAdminHistory(ctx, adminId, entity.AuditActionMerchantCreate, merchant.Id.String(),
+			entity.AuditErrorStorageFailed, fmt.Sprintf("Merchant creation failed: %v", err), err)
+	}
+
+	errLog := m.handleLogAdminHistory(ctx, adminId, entity.AuditActionMerchantCreate, merchant.Id.String(), "",
+		fmt.Sprintf("Merchant %q created", merchant.Name), nil)
+	if errLog != nil {
+		return model.MerchantResponse{}, errLog
+	}
+	return toMerchantResponse(merchant), nil
+}
+
+func (m *MerchantUseCaseImpl) UpdateMerchant(ctx context.Context, adminId, id string, request model.MerchantRequest) (model.MerchantResponse, error) {
+	ctx, span := tracing.Start(ctx, "MerchantUseCase.UpdateMerchant")
+	defer span.End()
+
+	return m.changeMerchant(ctx, adminId, id, entity.AuditActionMerchantUpdate, func(merchant *entity.Merchant) (string, error) {
+		if err := applyMerchantRequest(merchant, request); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Merchant %q updated", merchant.Name), nil
+	})
+}
+
+func (m *MerchantUseCaseImpl) SuspendMerchant(ctx context.Context, adminId, id string) (model.MerchantResponse, error) {
+	ctx, span := tracing.Start(ctx, "MerchantUseCase.SuspendMerchant")
+	defer span.End()
+
+	return m.changeMerchant(ctx, adminId, id, entity.AuditActionMerchantSuspend, func(merchant *entity.Merchant) (string, error) {
+		if merchant.IsSuspended() {
+			return "", fmt.Errorf("merchant %s is already suspended", merchant.Id)
+		}
+		merchant.Status = entity.MerchantStatusSuspended
+		return fmt.Sprintf("Merchant %q suspended", merchant.Name), nil
+	})
+}
+
+func (m *MerchantUseCaseImpl) ReactivateMerchant(ctx context.Context, adminId, id string) (model.MerchantResponse, error) {
+	ctx, span := tracing.Start(ctx, "MerchantUseCase.ReactivateMerchant")
+	defer span.End()
+
+	return m.changeMerchant(ctx, adminId, id, entity.AuditActionMerchantReactivate, func(merchant *entity.Merchant) (string, error) {
+		if !merchant.IsSuspended() {
+			return "", fmt.Errorf("merchant %s is not suspended", merchant.Id)
+		}
+		merchant.Status = entity.MerchantStatusActive
+		return fmt.Sprintf("Merchant %q reactivated", merchant.Name), nil
+	})
+}
+
+// changeMerchant applies change to the stored merchant and saves it with a new
+// UpdatedAt. change returns the history message, or an error when the change
+// is not allowed.
+func (m *MerchantUseCaseImpl) changeMerchant(ctx context.Context, adminId, id string, action entity.AuditAction,
+	change func(merchant *entity.Merchant) (string, error)) (model.MerchantResponse, error) {
+	parsedId, err := uuid.Parse(id)
+	if err != nil {
+		return model.MerchantResponse{}, m.handleLogAdminHistory(ctx, adminId, action, id, entity.AuditErrorInvalidId,
+			"Error parsing merchant UUID", fmt.Errorf("invalid merchant id %s: %w", id, err))
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	merchant, err := m.MerchantRepository.FindById(ctx, parsedId)
+	if err != nil {
+		return model.MerchantResponse{}, m.handleLogAdminHistory(ctx, adminId, action, id, entity.AuditErrorNotFound, err.Error(), err)
+	}
+
+	message, err := change(&merchant)
+	if err != nil {
+		return model.MerchantResponse{}, m.handleLogAdminHistory(ctx, adminId, action, id, entity.AuditErrorInvalidRequest,
+			fmt.Sprintf("Merchant change rejected: %v", err), err)
+	}
+	merchant.UpdatedAt = time.Now()
+
+	if err := m.MerchantRepository.Update(ctx, merchant); err != nil {
+		return model.MerchantResponse{}, m.handleLogAdminHistory(ctx, adminId, action, id, entity.AuditErrorStorageFailed,
+			fmt.Sprintf("Merchant change failed: %v", err), err)
+	}
+
+	errLog := m.handleLogAdminHistory(ctx, adminId, action, id, "", message, nil)
+	if errLog != nil {
+		return model.MerchantResponse{}, errLog
+	}
+	return toMerchantResponse(merchant), nil
+}
+
+// applyMerchantRequest validates the request and copies it onto the merchant.
+// Currencies are stored upper case; the first one is the settlement currency.
+func applyMerchantRequest(merchant *entity.Merchant, request model.MerchantRequest) error {
+	name := strings.TrimSpace(request.Name)
+	if name == "" || len(name) > maxMerchantNameLength {
+		return fmt.Errorf("name must be between 1 and %d characters", maxMerchantNameLength)
+	}
+
+	currencies := make([]string, 0, len(request.AcceptedCurrencies))
+	for _, code := range request.AcceptedCurrencies {
+		currency, err := entity.FindCurrency(code)
+		if err != nil {
+			return err
+		}
+		for _, existing := range currencies {
+			if existing == currency.Code {
+				return fmt.Errorf("currency %s is listed twice", currency.Code)
+			}
+		}
+		currencies = append(currencies, currency.Code)
+	}
+
+	feePlan := toFeePlan(request.FeePlan)
+	if err := feePlan.Validate(); err != nil {
+		return fmt.Errorf("invalid fee plan: %w", err)
+	}
+
+	webhookUrl := strings.TrimSpace(request.WebhookUrl)
+	if webhookUrl != "" {
+		parsedUrl, err := url.Parse(webhookUrl)
+		if err != nil || (parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https") || parsedUrl.Host == "" {
+			return fmt.Errorf("webhook url %q must be an absolute http or https url", webhookUrl)
+		}
+		if request.WebhookSecret == "" && merchant.WebhookSecret == "" {
+			return fmt.Errorf("a webhook secret is required with a webhook url")
+		}
+	}
+
+	merchant.Name = name
+	merchant.AcceptedCurrencies = currencies
+	merchant.FeePlan = feePlan
+	merchant.WebhookUrl = webhookUrl
+	if request.WebhookSecret != "" {
+		merchant.WebhookSecret = request.WebhookSecret
+	}
+	return nil
+}
+
+func merchantStatus(merchant entity.Merchant) entity.MerchantStatus {
+	if merchant.IsSuspended() {
+		return entity.MerchantStatusSuspended
+	}
+	return entity.MerchantStatusActive
+}
+
+func toFeePlan(plan model.FeePlan) entity.FeePlan {
+	feePlan := entity.FeePlan{
+		Type:                  entity.FeeType(strings.ToUpper(plan.Type)),
+		PercentageBasisPoints: plan.PercentageBasisPoints,
+		FixedAmount:           plan.FixedAmount,
+		MinFee:                plan.MinFee,
+		MaxFee:                plan.MaxFee,
+	}
+	for _, tier := range plan.Tiers {
+		feePlan.Tiers = append(feePlan.Tiers, entity.FeeTier{
+			UpTo:                  tier.UpTo,
+			PercentageBasisPoints: tier.PercentageBasisPoints,
+			FixedAmount:           tier.FixedAmount,
+		})
+	}
+	return feePlan
+}
+
+func toMerchantResponse(merchant entity.Merchant) model.MerchantResponse {
+	response := model.MerchantResponse{
+		Id:                 merchant.Id.String(),
+		Name:               merchant.Name,
+		AcceptedCurrencies: merchant.AcceptedCurrencies,
+		FeePlan: model.FeePlan{
+			Type:                  string(merchant.FeePlan.Type),
+			PercentageBasisPoints: merchant.FeePlan.PercentageBasisPoints,
+			FixedAmount:           merchant.FeePlan.FixedAmount,
+			MinFee:                merchant.FeePlan.MinFee,
+			MaxFee:                merchant.FeePlan.MaxFee,
+		},
+		WebhookUrl: merchant.WebhookUrl,
+		Status:     string(merchantStatus(merchant)),
+		CreatedAt:  merchant.CreatedAt,
+		UpdatedAt:  merchant.UpdatedAt,
+	}
+	if response.AcceptedCurrencies == nil {
+		response.AcceptedCurrencies = []string{}
+	}
+	for _, tier := range merchant.FeePlan.Tiers {
+		response.FeePlan.Tiers = append(response.FeePlan.Tiers, model.FeeTier{
+			UpTo:                  tier.UpTo,
+			PercentageBasisPoints: tier.PercentageBasisPoints,
+			FixedAmount:           tier.FixedAmount,
+		})
+	}
+	return response
+}
+
+func (m *MerchantUseCaseImpl) handleLogAdminHistory(ctx context.Context, adminId string, action entity.AuditAction, merchantId string,
+	errorCode entity.AuditErrorCode, message string, err error) error {
+	errLog := m.HistoryUseCase.LogAndAddHistory(ctx, adminId, action, entity.AuditDetails{
+		ActorType:   entity.AuditActorAdmin,
+		SubjectType: entity.AuditSubjectMerchant,
+		SubjectId:   merchantId,
+		ErrorCode:   errorCode,
+		Message:     message,
+	}, err)
+	if errLog != nil {
+		return errLog
+	}
+	return err
+}
+
+func (m *MerchantUseCaseImpl) handleLogHistory(ctx context.Context, id string, errorCode entity.AuditErrorCode, message string, err error) error {
+	return m.HistoryUseCase.LogAndAddHistory(ctx, "", entity.AuditActionMerchantLookup, entity.AuditDetails{
+		ActorType:   entity.AuditActorSystem,
+		SubjectType: entity.AuditSubjectMerchant,
+		SubjectId:   id,
+		ErrorCode:   errorCode,
+		Message:     message,
+	}, err)
+}
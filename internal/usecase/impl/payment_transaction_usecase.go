@@ -1,12 +1,18 @@
 package impl
 
 import (
+	"context"
 	"fmt"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"math/big"
 	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/metrics"
 	"merchant_bank_payment_go_api/internal/model"
 	"merchant_bank_payment_go_api/internal/repository"
+	"merchant_bank_payment_go_api/internal/tracing"
 	"merchant_bank_payment_go_api/internal/usecase"
+	"merchant_bank_payment_go_api/internal/utils"
 	"time"
 )
 
@@ -15,47 +21,216 @@ type PaymentTransactionUseCaseImpl struct {
 	CustomerUseCase              usecase.CustomerUseCase
 	MerchantUseCase              usecase.MerchantUseCase
 	HistoryUseCase               usecase.HistoryUseCase
+	ExchangeRateUseCase          usecase.ExchangeRateUseCase
+	EventBus                     usecase.EventBus
+	PaymentLimits                func() entity.PaymentLimits
 }
 
 func NewPaymentTransactionUseCaseImpl(transactionRepository repository.PaymentTransactionRepository,
-	customerUseCase usecase.CustomerUseCase, merchantUseCase usecase.MerchantUseCase, historyUseCase usecase.HistoryUseCase) *PaymentTransactionUseCaseImpl {
+	customerUseCase usecase.CustomerUseCase, merchantUseCase usecase.MerchantUseCase, historyUseCase usecase.HistoryUseCase,
+	exchangeRateUseCase usecase.ExchangeRateUseCase, eventBus usecase.EventBus, paymentLimits func() entity.PaymentLimits) *PaymentTransactionUseCaseImpl {
 	return &PaymentTransactionUseCaseImpl{
 		PaymentTransactionRepository: transactionRepository,
 		CustomerUseCase:              customerUseCase,
 		MerchantUseCase:              merchantUseCase,
 		HistoryUseCase:               historyUseCase,
+		ExchangeRateUseCase:          exchangeRateUseCase,
+		EventBus:                     eventBus,
+		PaymentLimits:                paymentLimits,
 	}
 }
 
-func (p *PaymentTransactionUseCaseImpl) AddPayment(customerId string, paymentRequest model.PaymentRequest) error {
-	customer, err := p.CustomerUseCase.FindById(customerId)
+func (p *PaymentTransactionUseCaseImpl) AddPayment(ctx context.Context, customerId string, paymentRequest model.PaymentRequest) error {
+	ctx, span := tracing.Start(ctx, "PaymentTransactionUseCase.AddPayment",
+		attribute.String("customer.id", customerId),
+		attribute.String("merchant.id", paymentRequest.MerchantId),
+		attribute.String("payment.currency", paymentRequest.Currency),
+	)
+	defer span.End()
+
+	customer, err := p.CustomerUseCase.FindById(ctx, customerId)
 	if err != nil {
-		return p.handleLogHistory("-", "PAYMENT", fmt.Sprintf("Payment failed: %v", err), err)
+		return p.handleLogHistory(ctx, customerId, entity.AuditActionPaymentCreate, "", entity.AuditErrorNotFound, fmt.Sprintf("Payment failed: %v", err), err)
+	}
+	if customer.IsDisabled() {
+		err = fmt.Errorf("customer %s is disabled and cannot make payments", customer.Id)
+		return p.handleLogHistory(ctx, customerId, entity.AuditActionPaymentCreate, "", entity.AuditErrorAccountDisabled, fmt.Sprintf("Payment failed: %v", err), err)
+	}
+
+	merchant, err := p.MerchantUseCase.FindById(ctx, paymentRequest.MerchantId)
+	if err != nil {
+		return p.handleLogHistory(ctx, customerId, entity.AuditActionPaymentCreate, "", entity.AuditErrorNotFound, fmt.Sprintf("Payment failed: %v", err), err)
+	}
+	if merchant.IsSuspended() {
+		err = fmt.Errorf("merchant %s is suspended and cannot accept payments", merchant.Id)
+		return p.handleLogHistory(ctx, customerId, entity.AuditActionPaymentCreate, "", entity.AuditErrorMerchantSuspended, fmt.Sprintf("Payment failed: %v", err), err)
+	}
+
+	money, err := entity.NewMoney(paymentRequest.Amount, paymentRequest.Currency)
+	if err != nil {
+		return p.handleLogHistory(ctx, customerId, entity.AuditActionPaymentCreate, "", entity.AuditErrorInvalidRequest, fmt.Sprintf("Payment failed: %v", err), err)
+	}
+
+	// The limits can be reloaded while the server runs, so they are read for
+	// every payment.
+	if err := p.PaymentLimits().Check(money); err != nil {
+		return p.handleLogHistory(ctx, customerId, entity.AuditActionPaymentCreate, "", entity.AuditErrorLimitExceeded, fmt.Sprintf("Payment failed: %v", err), err)
+	}
+
+	var conversion *entity.CurrencyConversion
+	if !merchant.AcceptsCurrency(money.Currency) {
+		converted, appliedConversion, err := p.ExchangeRateUseCase.Convert(ctx, money, merchant.SettlementCurrency())
+		if err != nil {
+			err = fmt.Errorf("merchant %s does not accept currency %s and conversion failed: %w", merchant.Id, money.Currency, err)
+			return p.handleLogHistory(ctx, customerId, entity.AuditActionPaymentCreate, "", entity.AuditErrorConversionFailed, fmt.Sprintf("Payment failed: %v", err), err)
+		}
+		money, conversion = converted, &appliedConversion
 	}
 
-	merchant, err := p.MerchantUseCase.FindById(paymentRequest.MerchantId)
+	// Fee plans are priced in the settlement currency, so a payment kept in
+	// another accepted currency is valued in the settlement currency before
+	// the plan is applied, and the fee is charged back at the same rate.
+	feeBase := money
+	if merchant.FeePlan.Type != "" && money.Currency != merchant.SettlementCurrency() {
+		feeBase, _, err = p.ExchangeRateUseCase.Convert(ctx, money, merchant.SettlementCurrency())
+		if err != nil {
+			err = fmt.Errorf("fee for merchant %s could not be priced in %s: %w", merchant.Id, merchant.SettlementCurrency(), err)
+			return p.handleLogHistory(ctx, customerId, entity.AuditActionPaymentCreate, "", entity.AuditErrorConversionFailed, fmt.Sprintf("Payment failed: %v", err), err)
+		}
+	}
+
+	fee, err := merchant.FeePlan.Calculate(feeBase.Amount)
 	if err != nil {
-		return p.handleLogHistory(customer.Id.String(), "PAYMENT", fmt.Sprintf("Payment failed: %v", err), err)
+		return p.handleLogHistory(ctx, customerId, entity.AuditActionPaymentCreate, "", entity.AuditErrorInternal, fmt.Sprintf("Payment failed: %v", err), err)
+	}
+	if feeBase.Currency != money.Currency {
+		fee = entity.RoundHalfUp.Round(new(big.Rat).SetFrac(
+			new(big.Int).Mul(big.NewInt(fee), big.NewInt(money.Amount)), big.NewInt(feeBase.Amount)))
 	}
 
 	transaction := entity.Payment{
 		Id:         uuid.New(),
 		CustomerId: customer.Id,
 		MerchantId: merchant.Id,
-		Amount:     paymentRequest.Amount,
+		Money:      money,
+		FeeAmount:  fee,
+		NetAmount:  money.Amount - fee,
+		Conversion: conversion,
+		Status:     entity.PaymentStatusCaptured,
 		Timestamp:  time.Now(),
 	}
 
-	err = p.PaymentTransactionRepository.AddPayment(transaction)
+	event, err := entity.NewDomainEvent(entity.EventPaymentCreated, transaction.Id.String(), transaction)
+	if err != nil {
+		return p.handleLogHistory(ctx, customerId, entity.AuditActionPaymentCreate, transaction.Id.String(), entity.AuditErrorInternal, fmt.Sprintf("Payment failed: %v", err), err)
+	}
+
+	err = p.EventBus.Publish(ctx, func(tx *utils.JsonFileTransaction) ([]entity.DomainEvent, error) {
+		if err := p.PaymentTransactionRepository.StageAddPayment(ctx, tx, transaction); err != nil {
+			return nil, err
+		}
+		return []entity.DomainEvent{event}, nil
+	})
+	if err != nil {
+		return p.handleLogHistory(ctx, customerId, entity.AuditActionPaymentCreate, transaction.Id.String(), entity.AuditErrorStorageFailed, fmt.Sprintf("Payment failed: %v", err), err)
+	}
+
+	metrics.Payments.WithLabelValues(merchant.Id.String(), transaction.Currency).Inc()
+	metrics.PaymentAmount.WithLabelValues(merchant.Id.String(), transaction.Currency).Add(float64(transaction.Amount))
+	return p.handleLogHistory(ctx, customerId, entity.AuditActionPaymentCreate, transaction.Id.String(), "",
+		fmt.Sprintf("Payment of %d %s to merchant %s created", transaction.Amount, transaction.Currency, merchant.Id), nil)
+}
+
+func (p *PaymentTransactionUseCaseImpl) GetPayments(ctx context.Context, customerId string) ([]model.PaymentResponse, error) {
+	ctx, span := tracing.Start(ctx, "PaymentTransactionUseCase.GetPayments")
+	defer span.End()
+
+	parsedCustomerId, err := uuid.Parse(customerId)
+	if err != nil {
+		return nil, p.handleLogHistory(ctx, customerId, entity.AuditActionPaymentView, "", entity.AuditErrorInvalidId, "Error parsing customer UUID", err)
+	}
+
+	payments, err := p.PaymentTransactionRepository.FindByCustomerId(ctx, parsedCustomerId)
+	if err != nil {
+		return nil, p.handleLogHistory(ctx, customerId, entity.AuditActionPaymentView, "", entity.AuditErrorStorageFailed, fmt.Sprintf("Failed to load payments: %v", err), err)
+	}
+
+	responses := make([]model.PaymentResponse, 0, len(payments))
+	for _, payment := range payments {
+		responses = append(responses, toPaymentResponse(payment))
+	}
+
+	errLog := p.handleLogHistory(ctx, customerId, entity.AuditActionPaymentView, "", "", fmt.Sprintf("Listed %d payments", len(responses)), nil)
+	if errLog != nil {
+		return nil, errLog
+	}
+	return responses, nil
+}
+
+func (p *PaymentTransactionUseCaseImpl) GetPaymentById(ctx context.Context, customerId, paymentId string) (model.PaymentResponse, error) {
+	ctx, span := tracing.Start(ctx, "PaymentTransactionUseCase.GetPaymentById")
+	defer span.End()
+
+	parsedPaymentId, err := uuid.Parse(paymentId)
+	if err != nil {
+		return model.PaymentResponse{}, p.handleLogHistory(ctx, customerId, entity.AuditActionPaymentView, paymentId, entity.AuditErrorInvalidId, "Error parsing payment UUID", err)
+	}
+
+	payment, err := p.PaymentTransactionRepository.FindById(ctx, parsedPaymentId)
 	if err != nil {
-		return p.handleLogHistory(customer.Id.String(), "PAYMENT", fmt.Sprintf("Payment failed: %v", err), err)
+		return model.PaymentResponse{}, p.handleLogHistory(ctx, customerId, entity.AuditActionPaymentView, paymentId, entity.AuditErrorNotFound, fmt.Sprintf("Failed to find payment: %v", err), err)
+	}
+
+	if payment.CustomerId.String() != customerId {
+		err = fmt.Errorf("payment transaction with id %s not found", paymentId)
+		return model.PaymentResponse{}, p.handleLogHistory(ctx, customerId, entity.AuditActionPaymentView, paymentId, entity.AuditErrorNotFound, "Payment belongs to another customer", err)
 	}
 
-	return nil
+	errLog := p.handleLogHistory(ctx, customerId, entity.AuditActionPaymentView, paymentId, "", fmt.Sprintf("Viewed payment %s", paymentId), nil)
+	if errLog != nil {
+		return model.PaymentResponse{}, errLog
+	}
+	return toPaymentResponse(payment), nil
+}
+
+func toPaymentResponse(payment entity.Payment) model.PaymentResponse {
+	response := model.PaymentResponse{
+		Id:         payment.Id.String(),
+		CustomerId: payment.CustomerId.String(),
+		MerchantId: payment.MerchantId.String(),
+		Amount:     payment.Amount,
+		Currency:   payment.CurrencyCode(),
+		FeeAmount:  payment.FeeAmount,
+		NetAmount:  payment.NetAmount,
+		Status:     string(payment.CurrentStatus()),
+		Timestamp:  payment.Timestamp,
+	}
+
+	if payment.SettlementId != nil {
+		response.SettlementId = payment.SettlementId.String()
+	}
+
+	if payment.Conversion != nil {
+		response.Conversion = &model.PaymentConversionResponse{
+			OriginalAmount:    payment.Conversion.OriginalAmount.Amount,
+			OriginalCurrency:  payment.Conversion.OriginalAmount.CurrencyCode(),
+			MidRate:           payment.Conversion.MidRate,
+			AppliedRate:       payment.Conversion.AppliedRate,
+			SpreadBasisPoints: payment.Conversion.SpreadBasisPoints,
+			RateTimestamp:     payment.Conversion.RateTimestamp,
+		}
+	}
+	return response
 }
 
-func (p *PaymentTransactionUseCaseImpl) handleLogHistory(customerId, action, message string, err error) error {
-	errLog := p.HistoryUseCase.LogAndAddHistory(customerId, action, message, err)
+func (p *PaymentTransactionUseCaseImpl) handleLogHistory(ctx context.Context, customerId string, action entity.AuditAction, paymentId string,
+	errorCode entity.AuditErrorCode, message string, err error) error {
+	errLog := p.HistoryUseCase.LogAndAddHistory(ctx, customerId, action, entity.AuditDetails{
+		SubjectType: entity.AuditSubjectPayment,
+		SubjectId:   paymentId,
+		ErrorCode:   errorCode,
+		Message:     message,
+	}, err)
 	if errLog != nil {
 		return errLog
 	}
@@ -0,0 +1,284 @@
+package impl
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/internal/repository"
+	"merchant_bank_payment_go_api/internal/tracing"
+	"merchant_bank_payment_go_api/internal/usecase"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ReportFormatCsv  = "csv"
+	ReportFormatJson = "json"
+
+	reconciliationRowPayment = "PAYMENT"
+	reconciliationRowRefund  = "REFUND"
+	reconciliationRowTotal   = "TOTAL"
+)
+
+var reconciliationCsvHeader = []string{"type", "payment_id", "customer_id", "timestamp", "currency",
+	"gross_amount", "fee_amount", "net_amount", "status", "settlement_id"}
+
+type ReportUseCaseImpl struct {
+	PaymentTransactionRepository repository.PaymentTransactionRepository
+	MerchantFinder               usecase.MerchantFinder
+}
+
+type reconciliationWriter interface {
+	WriteRow(row model.ReconciliationRow) error
+	Close(totals []model.ReconciliationTotal) error
+}
+
+func NewReportUseCaseImpl(paymentTransactionRepository repository.PaymentTransactionRepository,
+	merchantFinder usecase.MerchantFinder) *ReportUseCaseImpl {
+	return &ReportUseCaseImpl{
+		PaymentTransactionRepository: paymentTransactionRepository,
+		MerchantFinder:               merchantFinder,
+	}
+}
+
+func (r *ReportUseCaseImpl) ExportReconciliation(ctx context.Context, request model.ReconciliationReportRequest, writer io.Writer) error {
+	ctx, span := tracing.Start(ctx, "ReportUseCase.ExportReconciliation")
+	defer span.End()
+
+	from, err := parseReportDate(request.From, false)
+	if err != nil {
+		return fmt.Errorf("invalid from date: %w", err)
+	}
+
+	to, err := parseReportDate(request.To, true)
+	if err != nil {
+		return fmt.Errorf("invalid to date: %w", err)
+	}
+
+	if !from.Before(to) {
+		return fmt.Errorf("from date must be before to date")
+	}
+
+	merchant, err := r.MerchantFinder.FindById(ctx, request.MerchantId)
+	if err != nil {
+		return err
+	}
+
+	var reportWriter reconciliationWriter
+	switch strings.ToLower(request.Format) {
+	case "", ReportFormatCsv:
+		reportWriter = newCsvReconciliationWriter(writer)
+	case ReportFormatJson:
+		reportWriter = newJsonReconciliationWriter(writer, merchant.Id.String(), from, to)
+	default:
+		return fmt.Errorf("unsupported report format %q", request.Format)
+	}
+
+	totals := make(map[string]*model.ReconciliationTotal)
+	err = r.PaymentTransactionRepository.StreamPayments(ctx, func(payment entity.Payment) error {
+		if payment.MerchantId != merchant.Id || payment.Timestamp.Before(from) || !payment.Timestamp.Before(to) {
+			return nil
+		}
+
+		currency := payment.CurrencyCode()
+		total, ok := totals[currency]
+		if !ok {
+			total = &model.ReconciliationTotal{Currency: currency}
+			totals[currency] = total
+		}
+
+		row := toReconciliationRow(payment)
+		total.PaymentCount++
+		total.GrossAmount += row.GrossAmount
+		total.FeeAmount += row.FeeAmount
+		total.NetAmount += row.NetAmount
+		if err := reportWriter.WriteRow(row); err != nil {
+			return err
+		}
+
+		if payment.CurrentStatus() != entity.PaymentStatusRefunded {
+			return nil
+		}
+
+		refund := row
+		refund.Type = reconciliationRowRefund
+		refund.GrossAmount, refund.FeeAmount, refund.NetAmount = -row.GrossAmount, -row.FeeAmount, -row.NetAmount
+		total.RefundCount++
+		total.GrossAmount += refund.GrossAmount
+		total.FeeAmount += refund.FeeAmount
+		total.NetAmount += refund.NetAmount
+		return reportWriter.WriteRow(refund)
+	})
+	if err != nil {
+		return err
+	}
+
+	sortedTotals := make([]model.ReconciliationTotal, 0, len(totals))
+	for _, total := range totals {
+		sortedTotals = append(sortedTotals, *total)
+	}
+	sort.Slice(sortedTotals, func(i, j int) bool {
+		return sortedTotals[i].Currency < sortedTotals[j].Currency
+	})
+
+	return reportWriter.Close(sortedTotals)
+}
+
+// parseReportDate accepts RFC 3339 timestamps or plain dates; a plain end date
+// covers that whole day.
+func parseReportDate(value string, endOfRange bool) (time.Time, error) {
+	if timestamp, err := time.Parse(time.RFC3339, value); err == nil {
+		return timestamp, nil
+	}
+
+	date, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither a date (YYYY-MM-DD) nor an RFC 3339 timestamp", value)
+	}
+	if endOfRange {
+		date = date.AddDate(0, 0, 1)
+	}
+	return date, nil
+}
+
+func toReconciliationRow(payment entity.Payment) model.ReconciliationRow {
+	row := model.ReconciliationRow{
+		Type:        reconciliationRowPayment,
+		PaymentId:   payment.Id.String(),
+		CustomerId:  payment.CustomerId.String(),
+		Timestamp:   payment.Timestamp,
+		Currency:    payment.CurrencyCode(),
+		GrossAmount: payment.Amount,
+		FeeAmount:   payment.FeeAmount,
+		NetAmount:   payment.Amount - payment.FeeAmount,
+		Status:      string(payment.CurrentStatus()),
+	}
+	if payment.SettlementId != nil {
+		row.SettlementId = payment.SettlementId.String()
+	}
+	return row
+}
+
+type csvReconciliationWriter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func newCsvReconciliationWriter(writer io.Writer) *csvReconciliationWriter {
+	return &csvReconciliationWriter{writer: csv.NewWriter(writer)}
+}
+
+func (w *csvReconciliationWriter) writeHeader() error {
+	if w.headerWritten {
+		return nil
+	}
+	w.headerWritten = true
+	return w.writer.Write(reconciliationCsvHeader)
+}
+
+func (w *csvReconciliationWriter) WriteRow(row model.ReconciliationRow) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	return w.writer.Write([]string{row.Type, row.PaymentId, row.CustomerId, row.Timestamp.Format(time.RFC3339Nano),
+		row.Currency, strconv.FormatInt(row.GrossAmount, 10), strconv.FormatInt(row.FeeAmount, 10),
+		strconv.FormatInt(row.NetAmount, 10), row.Status, row.SettlementId})
+}
+
+func (w *csvReconciliationWriter) Close(totals []model.ReconciliationTotal) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	for _, total := range totals {
+		err := w.writer.Write([]string{reconciliationRowTotal, "", "", "", total.Currency,
+			strconv.FormatInt(total.GrossAmount, 10), strconv.FormatInt(total.FeeAmount, 10),
+			strconv.FormatInt(total.NetAmount, 10), "", ""})
+		if err != nil {
+			return err
+		}
+	}
+
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+type jsonReconciliationWriter struct {
+	writer     *bufio.Writer
+	merchantId string
+	from       time.Time
+	to         time.Time
+	rowCount   int
+}
+
+func newJsonReconciliationWriter(writer io.Writer, merchantId string, from, to time.Time) *jsonReconciliationWriter {
+	return &jsonReconciliationWriter{writer: bufio.NewWriter(writer), merchantId: merchantId, from: from, to: to}
+}
+
+func (w *jsonReconciliationWriter) writeHeader() error {
+	header, err := json.Marshal(struct {
+		MerchantId string    `json:"merchantId"`
+		From       time.Time `json:"from"`
+		To         time.Time `json:"to"`
+	}{w.merchantId, w.from, w.to})
+	if err != nil {
+		return err
+	}
+
+	_, err = w.writer.Write(header[:len(header)-1])
+	if err != nil {
+		return err
+	}
+	_, err = w.writer.WriteString(`,"rows":[`)
+	return err
+}
+
+func (w *jsonReconciliationWriter) WriteRow(row model.ReconciliationRow) error {
+	if w.rowCount == 0 {
+		if err := w.writeHeader(); err != nil {
+			return err
+		}
+	} else if err := w.writer.WriteByte(','); err != nil {
+		return err
+	}
+	w.rowCount++
+
+	encoded, err := json.Marshal(row)
+	if err != nil {
+		return err
+	}
+	_, err = w.writer.Write(encoded)
+	return err
+}
+
+func (w *jsonReconciliationWriter) Close(totals []model.ReconciliationTotal) error {
+	if w.rowCount == 0 {
+		if err := w.writeHeader(); err != nil {
+			return err
+		}
+	}
+
+	encodedTotals, err := json.Marshal(totals)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.writer.WriteString(`],"totals":`); err != nil {
+		return err
+	}
+	if _, err := w.writer.Write(encodedTotals); err != nil {
+		return err
+	}
+	if _, err := w.writer.WriteString("}\n"); err != nil {
+		return err
+	}
+	return w.writer.Flush()
+}
@@ -0,0 +1,247 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/internal/repository"
+	"merchant_bank_payment_go_api/internal/tracing"
+	"merchant_bank_payment_go_api/internal/usecase"
+	"merchant_bank_payment_go_api/internal/utils"
+	"sort"
+	"time"
+)
+
+type SettlementUseCaseImpl struct {
+	SettlementRepository         repository.SettlementRepository
+	PaymentTransactionRepository repository.PaymentTransactionRepository
+	HistoryUseCase               usecase.HistoryUseCase
+	EventBus                     usecase.EventBus
+	CutoffHour                   int
+}
+
+type settlementKey struct {
+	merchantId uuid.UUID
+	currency   string
+	windowEnd  time.Time
+}
+
+func NewSettlementUseCaseImpl(settlementRepository repository.SettlementRepository,
+	paymentTransactionRepository repository.PaymentTransactionRepository, historyUseCase usecase.HistoryUseCase,
+	eventBus usecase.EventBus, cutoffHour int) *SettlementUseCaseImpl {
+	return &SettlementUseCaseImpl{
+		SettlementRepository:         settlementRepository,
+		PaymentTransactionRepository: paymentTransactionRepository,
+		HistoryUseCase:               historyUseCase,
+		EventBus:                     eventBus,
+		CutoffHour:                   cutoffHour,
+	}
+}
+
+func (s *SettlementUseCaseImpl) RunSettlement(ctx context.Context, adminId string, cutoff time.Time) ([]model.SettlementResponse, error) {
+	ctx, span := tracing.Start(ctx, "SettlementUseCase.RunSettlement")
+	defer span.End()
+
+	// The payments are read inside Publish, which keeps payments from being
+	// added between reading the file and rewriting it with the settled ones.
+	var newSettlements []entity.Settlement
+	errorCode := entity.AuditErrorStorageFailed
+	err := s.EventBus.Publish(ctx, func(tx *utils.JsonFileTransaction) ([]entity.DomainEvent, error) {
+		existingSettlements, err := s.SettlementRepository.LoadSettlements(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		payments, err := s.PaymentTransactionRepository.LoadPayments(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		newSettlements = s.settle(payments, cutoff)
+		if len(newSettlements) == 0 {
+			return nil, nil
+		}
+
+		events := make([]entity.DomainEvent, 0, len(newSettlements))
+		for _, settlement := range newSettlements {
+			event, err := entity.NewDomainEvent(entity.EventSettlementCreated, settlement.Id.String(), settlement)
+			if err != nil {
+				errorCode = entity.AuditErrorInternal
+				return nil, err
+			}
+			events = append(events, event)
+		}
+
+		allSettlements := append(append(make([]entity.Settlement, 0, len(existingSettlements)+len(newSettlements)),
+			existingSettlements...), newSettlements...)
+		s.SettlementRepository.StageSaveSettlements(ctx, tx, allSettlements)
+		s.PaymentTransactionRepository.StageSavePayments(ctx, tx, payments)
+		return events, nil
+	})
+	if err != nil {
+		return nil, s.handleLogHistory(ctx, adminId, errorCode, fmt.Sprintf("Settlement failed: %v", err), err)
+	}
+
+	errLog := s.handleLogHistory(ctx, adminId, "",
+		fmt.Sprintf("Created %d settlement batches up to %s", len(newSettlements), cutoff.Format(time.RFC3339)), nil)
+	if errLog != nil {
+		return nil, errLog
+	}
+
+	responses := make([]model.SettlementResponse, 0, len(newSettlements))
+	for _, settlement := range newSettlements {
+		responses = append(responses, toSettlementResponse(settlement))
+	}
+	return responses, nil
+}
+
+// settle groups the settleable payments in windows that ended by cutoff into
+// new settlements and sets their SettlementId in payments.
+func (s *SettlementUseCaseImpl) settle(payments []entity.Payment, cutoff time.Time) []entity.Settlement {
+	now := time.Now()
+	batches := make(map[settlementKey]*entity.Settlement)
+	paymentIndexes := make(map[settlementKey][]int)
+	for i, payment := range payments {
+		if !payment.IsSettleable() {
+			continue
+		}
+
+		windowEnd := s.windowEnd(payment.Timestamp)
+		if windowEnd.After(cutoff) {
+			continue
+		}
+
+		key := settlementKey{merchantId: payment.MerchantId, currency: payment.CurrencyCode(), windowEnd: windowEnd}
+		batch, ok := batches[key]
+		if !ok {
+			batch = &entity.Settlement{
+				Id:          uuid.New(),
+				MerchantId:  payment.MerchantId,
+				Currency:    payment.CurrencyCode(),
+				WindowStart: windowEnd.AddDate(0, 0, -1),
+				WindowEnd:   windowEnd,
+				PaymentIds:  make([]uuid.UUID, 0),
+				Status:      entity.SettlementStatusPendingPayout,
+				CreatedAt:   now,
+			}
+			batches[key] = batch
+		}
+
+		batch.PaymentIds = append(batch.PaymentIds, payment.Id)
+		batch.PaymentCount++
+		batch.GrossAmount += payment.Amount
+		batch.FeeAmount += payment.FeeAmount
+		batch.NetAmount += payment.Amount - payment.FeeAmount
+		paymentIndexes[key] = append(paymentIndexes[key], i)
+	}
+
+	newSettlements := make([]entity.Settlement, 0, len(batches))
+	for key, batch := range batches {
+		settlementId := batch.Id
+		for _, i := range paymentIndexes[key] {
+			payments[i].SettlementId = &settlementId
+		}
+		newSettlements = append(newSettlements, *batch)
+	}
+	sort.Slice(newSettlements, func(i, j int) bool {
+		if !newSettlements[i].WindowEnd.Equal(newSettlements[j].WindowEnd) {
+			return newSettlements[i].WindowEnd.Before(newSettlements[j].WindowEnd)
+		}
+		if newSettlements[i].MerchantId != newSettlements[j].MerchantId {
+			return newSettlements[i].MerchantId.String() < newSettlements[j].MerchantId.String()
+		}
+		return newSettlements[i].Currency < newSettlements[j].Currency
+	})
+	return newSettlements
+}
+
+func (s *SettlementUseCaseImpl) GetSettlements(ctx context.Context, merchantId string) ([]model.SettlementResponse, error) {
+	ctx, span := tracing.Start(ctx, "SettlementUseCase.GetSettlements")
+	defer span.End()
+
+	var merchantFilter uuid.UUID
+	if merchantId != "" {
+		parsedMerchantId, err := uuid.Parse(merchantId)
+		if err != nil {
+			return nil, fmt.Errorf("invalid merchant id %s: %w", merchantId, err)
+		}
+		merchantFilter = parsedMerchantId
+	}
+
+	settlements, err := s.SettlementRepository.LoadSettlements(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]model.SettlementResponse, 0, len(settlements))
+	for _, settlement := range settlements {
+		if merchantFilter != uuid.Nil && settlement.MerchantId != merchantFilter {
+			continue
+		}
+		responses = append(responses, toSettlementResponse(settlement))
+	}
+	return responses, nil
+}
+
+func (s *SettlementUseCaseImpl) GetSettlementById(ctx context.Context, id string) (model.SettlementResponse, error) {
+	ctx, span := tracing.Start(ctx, "SettlementUseCase.GetSettlementById")
+	defer span.End()
+
+	parsedId, err := uuid.Parse(id)
+	if err != nil {
+		return model.SettlementResponse{}, fmt.Errorf("invalid settlement id %s: %w", id, err)
+	}
+
+	settlement, err := s.SettlementRepository.FindById(ctx, parsedId)
+	if err != nil {
+		return model.SettlementResponse{}, err
+	}
+	return toSettlementResponse(settlement), nil
+}
+
+// windowEnd returns the first cut-off strictly after the given time.
+func (s *SettlementUseCaseImpl) windowEnd(timestamp time.Time) time.Time {
+	local := timestamp.In(time.Local)
+	cutoff := time.Date(local.Year(), local.Month(), local.Day(), s.CutoffHour, 0, 0, 0, time.Local)
+	if !local.Before(cutoff) {
+		cutoff = cutoff.AddDate(0, 0, 1)
+	}
+	return cutoff
+}
+
+func (s *SettlementUseCaseImpl) handleLogHistory(ctx context.Context, adminId string, errorCode entity.AuditErrorCode, message string, err error) error {
+	errLog := s.HistoryUseCase.LogAndAddHistory(ctx, adminId, entity.AuditActionSettlementRun, entity.AuditDetails{
+		ActorType:   entity.AuditActorAdmin,
+		SubjectType: entity.AuditSubjectSettlement,
+		ErrorCode:   errorCode,
+		Message:     message,
+	}, err)
+	if errLog != nil {
+		return errLog
+	}
+	return err
+}
+
+func toSettlementResponse(settlement entity.Settlement) model.SettlementResponse {
+	paymentIds := make([]string, 0, len(settlement.PaymentIds))
+	for _, paymentId := range settlement.PaymentIds {
+		paymentIds = append(paymentIds, paymentId.String())
+	}
+
+	return model.SettlementResponse{
+		Id:           settlement.Id.String(),
+		MerchantId:   settlement.MerchantId.String(),
+		Currency:     settlement.Currency,
+		WindowStart:  settlement.WindowStart,
+		WindowEnd:    settlement.WindowEnd,
+		PaymentIds:   paymentIds,
+		PaymentCount: settlement.PaymentCount,
+		GrossAmount:  settlement.GrossAmount,
+		FeeAmount:    settlement.FeeAmount,
+		NetAmount:    settlement.NetAmount,
+		Status:       string(settlement.Status),
+		CreatedAt:    settlement.CreatedAt,
+	}
+}
@@ -0,0 +1,395 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"io"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/internal/repository"
+	"merchant_bank_payment_go_api/internal/tracing"
+	"merchant_bank_payment_go_api/internal/usecase"
+	"merchant_bank_payment_go_api/internal/utils"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const maxWebhookRetryDelay = 6 * time.Hour
+
+type WebhookUseCaseImpl struct {
+	Log                       *logrus.Logger
+	WebhookDeliveryRepository repository.WebhookDeliveryRepository
+	MerchantRepository        repository.MerchantRepository
+	HistoryUseCase            usecase.HistoryUseCase
+	HttpClient                *http.Client
+	MaxAttempts               int
+	RetryBaseDelay            time.Duration
+	// mu guards inFlight, the deliveries the worker or a redelivery is sending.
+	mu       sync.Mutex
+	inFlight map[uuid.UUID]bool
+}
+
+func NewWebhookUseCaseImpl(log *logrus.Logger, webhookDeliveryRepository repository.WebhookDeliveryRepository,
+	merchantRepository repository.MerchantRepository, historyUseCase usecase.HistoryUseCase, httpClient *http.Client,
+	maxAttempts int, retryBaseDelay time.Duration) *WebhookUseCaseImpl {
+	return &WebhookUseCaseImpl{
+		Log:                       log,
+		WebhookDeliveryRepository: webhookDeliveryRepository,
+		MerchantRepository:        merchantRepository,
+		HistoryUseCase:            historyUseCase,
+		HttpClient:                httpClient,
+		MaxAttempts:               maxAttempts,
+		RetryBaseDelay:            retryBaseDelay,
+		inFlight:                  make(map[uuid.UUID]bool),
+	}
+}
+
+// Enqueue queues one delivery per domain event; the event id doubles as the
+// delivery id so a redelivered event is not sent to the merchant twice.
+func (w *WebhookUseCaseImpl) Enqueue(ctx context.Context, eventId, merchantId uuid.UUID, eventType string, data interface{}) error {
+	ctx, span := tracing.Start(ctx, "WebhookUseCase.Enqueue")
+	defer span.End()
+
+	merchant, err := w.MerchantRepository.FindById(ctx, merchantId)
+	if err != nil {
+		return err
+	}
+
+	if merchant.WebhookUrl == "" {
+		w.Log.WithContext(ctx).Debugf("Merchant %s has no webhook url, skipping %s event", merchantId, eventType)
+		return nil
+	}
+
+	now := time.Now()
+	payload, err := json.Marshal(model.WebhookEvent{
+		Id:        eventId.String(),
+		Type:      eventType,
+		CreatedAt: now,
+		Data:      data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	return w.WebhookDeliveryRepository.AddDelivery(ctx, entity.WebhookDelivery{
+		Id:            eventId,
+		MerchantId:    merchantId,
+		EventType:     eventType,
+		Url:           merchant.WebhookUrl,
+		Payload:       payload,
+		Status:        entity.WebhookDeliveryStatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+}
+
+func (w *WebhookUseCaseImpl) HandlePaymentCreated(ctx context.Context, event entity.DomainEvent) error {
+	ctx, span := tracing.Start(ctx, "WebhookUseCase.HandlePaymentCreated")
+	defer span.End()
+
+	var payment entity.Payment
+	if err := json.Unmarshal(event.Payload, &payment); err != nil {
+		return fmt.Errorf("failed to decode %s event %s: %w", event.Type, event.Id, err)
+	}
+	return w.Enqueue(ctx, event.Id, payment.MerchantId, entity.WebhookEventPaymentCaptured, toPaymentResponse(payment))
+}
+
+func (w *WebhookUseCaseImpl) HandleSettlementCreated(ctx context.Context, event entity.DomainEvent) error {
+	ctx, span := tracing.Start(ctx, "WebhookUseCase.HandleSettlementCreated")
+	defer span.End()
+
+	var settlement entity.Settlement
+	if err := json.Unmarshal(event.Payload, &settlement); err != nil {
+		return fmt.Errorf("failed to decode %s event %s: %w", event.Type, event.Id, err)
+	}
+	return w.Enqueue(ctx, event.Id, settlement.MerchantId, entity.WebhookEventSettlementCreated, toSettlementResponse(settlement))
+}
+
+func (w *WebhookUseCaseImpl) DeliverDue(ctx context.Context, now time.Time) (int, error) {
+	ctx, span := tracing.Start(ctx, "WebhookUseCase.DeliverDue")
+	defer span.End()
+
+	deliveries, err := w.WebhookDeliveryRepository.LoadDeliveries(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	attempted := 0
+	for _, delivery := range deliveries {
+		if !delivery.IsDue(now) || !w.claim(delivery.Id) {
+			continue
+		}
+
+		// A redelivery may have sent it since the deliveries were loaded.
+		current, err := w.WebhookDeliveryRepository.FindById(ctx, delivery.Id)
+		if err != nil {
+			w.release(delivery.Id)
+			return attempted, err
+		}
+		if !current.IsDue(now) {
+			w.release(delivery.Id)
+			continue
+		}
+
+		attempted++
+		_, err = w.attempt(ctx, current, now)
+		w.release(delivery.Id)
+		if err != nil {
+			return attempted, err
+		}
+	}
+	return attempted, nil
+}
+
+// StartWorker polls for due deliveries until the returned stop function is
+// called. Stopping cancels the requests in flight and waits for the worker to
+// record their outcome.
+func (w *WebhookUseCaseImpl) StartWorker(interval time.Duration) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				if _, err := w.DeliverDue(ctx, now); err != nil {
+					w.Log.WithContext(ctx).Errorf("Webhook worker failed: %v", err)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		ticker.Stop()
+		close(done)
+		<-stopped
+	}
+}
+
+func (w *WebhookUseCaseImpl) GetDeliveries(ctx context.Context, status, merchantId string) ([]model.WebhookDeliveryResponse, error) {
+	ctx, span := tracing.Start(ctx, "WebhookUseCase.GetDeliveries")
+	defer span.End()
+
+	var merchantFilter uuid.UUID
+	if merchantId != "" {
+		parsedMerchantId, err := uuid.Parse(merchantId)
+		if err != nil {
+			return nil, fmt.Errorf("invalid merchant id %s: %w", merchantId, err)
+		}
+		merchantFilter = parsedMerchantId
+	}
+
+	deliveries, err := w.WebhookDeliveryRepository.LoadDeliveries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]model.WebhookDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		if status != "" && !strings.EqualFold(string(delivery.Status), status) {
+			continue
+		}
+		if merchantFilter != uuid.Nil && delivery.MerchantId != merchantFilter {
+			continue
+		}
+		responses = append(responses, toWebhookDeliveryResponse(delivery))
+	}
+	return responses, nil
+}
+
+func (w *WebhookUseCaseImpl) GetDeliveryById(ctx context.Context, id string) (model.WebhookDeliveryResponse, error) {
+	ctx, span := tracing.Start(ctx, "WebhookUseCase.GetDeliveryById")
+	defer span.End()
+
+	parsedId, err := uuid.Parse(id)
+	if err != nil {
+		return model.WebhookDeliveryResponse{}, fmt.Errorf("invalid webhook delivery id %s: %w", id, err)
+	}
+
+	delivery, err := w.WebhookDeliveryRepository.FindById(ctx, parsedId)
+	if err != nil {
+		return model.WebhookDeliveryResponse{}, err
+	}
+	return toWebhookDeliveryResponse(delivery), nil
+}
+
+func (w *WebhookUseCaseImpl) Redeliver(ctx context.Context, adminId, id string) (model.WebhookDeliveryResponse, error) {
+	ctx, span := tracing.Start(ctx, "WebhookUseCase.Redeliver")
+	defer span.End()
+
+	parsedId, err := uuid.Parse(id)
+	if err != nil {
+		return model.WebhookDeliveryResponse{}, fmt.Errorf("invalid webhook delivery id %s: %w", id, err)
+	}
+
+	if !w.claim(parsedId) {
+		return model.WebhookDeliveryResponse{}, fmt.Errorf("%w: %s, try again later", usecase.ErrWebhookDeliveryInFlight, id)
+	}
+	defer w.release(parsedId)
+
+	delivery, err := w.WebhookDeliveryRepository.FindById(ctx, parsedId)
+	if err != nil {
+		return model.WebhookDeliveryResponse{}, err
+	}
+
+	now := time.Now()
+	delivery.Status = entity.WebhookDeliveryStatusPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = now
+	delivery, err = w.attempt(ctx, delivery, now)
+	if err != nil {
+		return model.WebhookDeliveryResponse{}, w.handleLogHistory(ctx, adminId, id, entity.AuditErrorStorageFailed,
+			fmt.Sprintf("Redelivery of webhook %s failed: %v", id, err), err)
+	}
+
+	errLog := w.handleLogHistory(ctx, adminId, id, "",
+		fmt.Sprintf("Redelivered webhook %s, status %s", id, delivery.Status), nil)
+	if errLog != nil {
+		return model.WebhookDeliveryResponse{}, errLog
+	}
+	return toWebhookDeliveryResponse(delivery), nil
+}
+
+// claim reserves a delivery for one sender. The lock is only held for the
+// bookkeeping, never across a send, so a slow merchant endpoint does not hold
+// up other deliveries or an admin redelivery.
+func (w *WebhookUseCaseImpl) claim(id uuid.UUID) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.inFlight[id] {
+		return false
+	}
+	w.inFlight[id] = true
+	return true
+}
+
+func (w *WebhookUseCaseImpl) release(id uuid.UUID) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.inFlight, id)
+}
+
+// attempt sends one delivery and persists the outcome. Delivery failures are
+// recorded on the delivery itself; only storage failures are returned.
+func (w *WebhookUseCaseImpl) attempt(ctx context.Context, delivery entity.WebhookDelivery, now time.Time) (entity.WebhookDelivery, error) {
+	delivery.Attempts++
+	delivery.UpdatedAt = now
+
+	statusCode, err := w.send(ctx, delivery, now)
+	delivery.LastResponseCode = statusCode
+	if err == nil {
+		delivered := now
+		delivery.Status = entity.WebhookDeliveryStatusDelivered
+		delivery.DeliveredAt = &delivered
+		delivery.LastError = ""
+		w.Log.WithContext(ctx).Infof("Delivered webhook %s to %s after %d attempts", delivery.Id, delivery.Url, delivery.Attempts)
+	} else {
+		delivery.LastError = err.Error()
+		if delivery.Attempts >= w.MaxAttempts {
+			delivery.Status = entity.WebhookDeliveryStatusDeadLetter
+			w.Log.WithContext(ctx).Errorf("Webhook %s moved to dead letter after %d attempts: %v", delivery.Id, delivery.Attempts, err)
+		} else {
+			delivery.NextAttemptAt = now.Add(w.retryDelay(delivery.Attempts))
+			w.Log.WithContext(ctx).Warnf("Webhook %s attempt %d failed, retrying at %s: %v", delivery.Id, delivery.Attempts,
+				delivery.NextAttemptAt.Format(time.RFC3339), err)
+		}
+	}
+
+	// Record the outcome even when ctx ended during the attempt, so a delivered
+	// webhook is not sent again.
+	if err := w.WebhookDeliveryRepository.UpdateDelivery(context.WithoutCancel(ctx), delivery); err != nil {
+		return delivery, err
+	}
+	return delivery, nil
+}
+
+func (w *WebhookUseCaseImpl) send(ctx context.Context, delivery entity.WebhookDelivery, now time.Time) (int, error) {
+	merchant, err := w.MerchantRepository.FindById(ctx, delivery.MerchantId)
+	if err != nil {
+		return 0, err
+	}
+	// An empty key would produce a signature anyone can forge.
+	if merchant.WebhookSecret == "" {
+		return 0, fmt.Errorf("merchant %s has no webhook secret to sign with", merchant.Id)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Webhook-Id", delivery.Id.String())
+	request.Header.Set("X-Webhook-Event", delivery.EventType)
+	request.Header.Set(utils.WebhookSignatureHeader, utils.SignWebhookPayload(merchant.WebhookSecret, now, delivery.Payload))
+
+	response, err := w.HttpClient.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64*1024))
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return response.StatusCode, fmt.Errorf("merchant endpoint responded with status %d", response.StatusCode)
+	}
+	return response.StatusCode, nil
+}
+
+// retryDelay doubles the base delay for every failed attempt, capped at maxWebhookRetryDelay.
+func (w *WebhookUseCaseImpl) retryDelay(attempts int) time.Duration {
+	delay := w.RetryBaseDelay
+	for i := 1; i < attempts && delay < maxWebhookRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxWebhookRetryDelay {
+		delay = maxWebhookRetryDelay
+	}
+	return delay
+}
+
+func (w *WebhookUseCaseImpl) handleLogHistory(ctx context.Context, adminId, deliveryId string, errorCode entity.AuditErrorCode, message string, err error) error {
+	errLog := w.HistoryUseCase.LogAndAddHistory(ctx, adminId, entity.AuditActionWebhookRedeliver, entity.AuditDetails{
+		ActorType:   entity.AuditActorAdmin,
+		SubjectType: entity.AuditSubjectWebhookDelivery,
+		SubjectId:   deliveryId,
+		ErrorCode:   errorCode,
+		Message:     message,
+	}, err)
+	if errLog != nil {
+		return errLog
+	}
+	return err
+}
+
+func toWebhookDeliveryResponse(delivery entity.WebhookDelivery) model.WebhookDeliveryResponse {
+	return model.WebhookDeliveryResponse{
+		Id:               delivery.Id.String(),
+		MerchantId:       delivery.MerchantId.String(),
+		EventType:        delivery.EventType,
+		Url:              delivery.Url,
+		Payload:          delivery.Payload,
+		Status:           string(delivery.Status),
+		Attempts:         delivery.Attempts,
+		NextAttemptAt:    delivery.NextAttemptAt,
+		LastError:        delivery.LastError,
+		LastResponseCode: delivery.LastResponseCode,
+		DeliveredAt:      delivery.DeliveredAt,
+		CreatedAt:        delivery.CreatedAt,
+		UpdatedAt:        delivery.UpdatedAt,
+	}
+}
@@ -1,7 +1,22 @@
 package usecase
 
-import "merchant_bank_payment_go_api/internal/entity"
+import (
+	"context"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/model"
+)
 
 type MerchantUseCase interface {
-	FindById(id string) (entity.Merchant, error)
+	FindById(ctx context.Context, id string) (entity.Merchant, error)
+	GetMerchants(ctx context.Context, request model.MerchantQueryRequest) (model.MerchantPage, error)
+	CreateMerchant(ctx context.Context, adminId string, request model.MerchantRequest) (model.MerchantResponse, error)
+	UpdateMerchant(ctx context.Context, adminId, id string, request model.MerchantRequest) (model.MerchantResponse, error)
+	SuspendMerchant(ctx context.Context, adminId, id string) (model.MerchantResponse, error)
+	ReactivateMerchant(ctx context.Context, adminId, id string) (model.MerchantResponse, error)
+}
+
+// MerchantFinder looks a merchant up by id. MerchantUseCase records each lookup
+// in the history; the report CLI uses a finder that does not.
+type MerchantFinder interface {
+	FindById(ctx context.Context, id string) (entity.Merchant, error)
 }
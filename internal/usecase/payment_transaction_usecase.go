@@ -1,7 +1,12 @@
 package usecase
 
-import "merchant_bank_payment_go_api/internal/model"
+import (
+	"context"
+	"merchant_bank_payment_go_api/internal/model"
+)
 
 type PaymentTransactionUseCase interface {
-	AddPayment(customerId string, paymentRequest model.PaymentRequest) error
+	AddPayment(ctx context.Context, customerId string, paymentRequest model.PaymentRequest) error
+	GetPayments(ctx context.Context, customerId string) ([]model.PaymentResponse, error)
+	GetPaymentById(ctx context.Context, customerId, paymentId string) (model.PaymentResponse, error)
 }
@@ -0,0 +1,11 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"merchant_bank_payment_go_api/internal/model"
+)
+
+type ReportUseCase interface {
+	ExportReconciliation(ctx context.Context, request model.ReconciliationReportRequest, writer io.Writer) error
+}
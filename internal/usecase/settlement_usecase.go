@@ -0,0 +1,13 @@
+package usecase
+
+import (
+	"context"
+	"merchant_bank_payment_go_api/internal/model"
+	"time"
+)
+
+type SettlementUseCase interface {
+	RunSettlement(ctx context.Context, adminId string, cutoff time.Time) ([]model.SettlementResponse, error)
+	GetSettlements(ctx context.Context, merchantId string) ([]model.SettlementResponse, error)
+	GetSettlementById(ctx context.Context, id string) (model.SettlementResponse, error)
+}
@@ -0,0 +1,21 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"merchant_bank_payment_go_api/internal/model"
+	"time"
+)
+
+// ErrWebhookDeliveryInFlight is returned by Redeliver while the worker is
+// sending the same delivery.
+var ErrWebhookDeliveryInFlight = errors.New("webhook delivery is being sent")
+
+type WebhookUseCase interface {
+	Enqueue(ctx context.Context, eventId, merchantId uuid.UUID, eventType string, data interface{}) error
+	DeliverDue(ctx context.Context, now time.Time) (int, error)
+	GetDeliveries(ctx context.Context, status, merchantId string) ([]model.WebhookDeliveryResponse, error)
+	GetDeliveryById(ctx context.Context, id string) (model.WebhookDeliveryResponse, error)
+	Redeliver(ctx context.Context, adminId, id string) (model.WebhookDeliveryResponse, error)
+}
@@ -0,0 +1,24 @@
+package utils
+
+import "context"
+
+type clientKey struct{}
+
+type client struct {
+	ip        string
+	userAgent string
+}
+
+func WithClient(ctx context.Context, ip, userAgent string) context.Context {
+	return context.WithValue(ctx, clientKey{}, client{ip: ip, userAgent: userAgent})
+}
+
+// ClientFromContext returns the address and user agent of the HTTP client
+// that started the request, or empty strings outside a request.
+func ClientFromContext(ctx context.Context) (ip, userAgent string) {
+	if ctx == nil {
+		return "", ""
+	}
+	c, _ := ctx.Value(clientKey{}).(client)
+	return c.ip, c.userAgent
+}
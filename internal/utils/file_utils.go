@@ -1,13 +1,24 @@
 package utils
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/sirupsen/logrus"
+	"io"
+	"merchant_bank_payment_go_api/internal/metrics"
 	"os"
+	"path/filepath"
+	"time"
 )
 
-func ReadJsonFile(filename string, log *logrus.Logger) ([]byte, error) {
+func ReadJsonFile(ctx context.Context, filename string, log *logrus.Logger) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("error reading file %s: %w", filename, err)
+	}
+	defer metrics.ObserveRepositoryOperation("read", filename, time.Now())
+
 	fileContent, err := os.ReadFile(filename)
 	if err != nil {
 		log.Errorf("Error reading file %s: %v", filename, err)
@@ -16,7 +27,12 @@ func ReadJsonFile(filename string, log *logrus.Logger) ([]byte, error) {
 	return fileContent, nil
 }
 
-func WriteJsonFile(filename string, data interface{}, log *logrus.Logger) error {
+func WriteJsonFile(ctx context.Context, filename string, data interface{}, log *logrus.Logger) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("error writing file %s: %w", filename, err)
+	}
+	defer metrics.ObserveRepositoryOperation("write", filename, time.Now())
+
 	file, err := os.Create(filename)
 	if err != nil {
 		log.Errorf("Error creating file %s: %v", filename, err)
@@ -37,3 +53,89 @@ func WriteJsonFile(filename string, data interface{}, log *logrus.Logger) error
 
 	return nil
 }
+
+// StreamJsonArray decodes a file holding a JSON array one element at a time,
+// so callers never need the whole array in memory. It stops between elements
+// once ctx is done. The recorded latency includes the time spent in handler.
+func StreamJsonArray[T any](ctx context.Context, filename string, log *logrus.Logger, handler func(item T) error) error {
+	defer metrics.ObserveRepositoryOperation("stream", filename, time.Now())
+
+	file, err := os.Open(filename)
+	if err != nil {
+		log.Errorf("Error opening file %s: %v", filename, err)
+		return fmt.Errorf("error opening file %s: %w", filename, err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			log.Errorf("Error closing file %s: %v", filename, err)
+		}
+	}()
+
+	decoder := json.NewDecoder(bufio.NewReader(file))
+	token, err := decoder.Token()
+	if err != nil {
+		log.Errorf("Error decoding file %s: %v", filename, err)
+		return fmt.Errorf("error decoding file %s: %w", filename, err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("error decoding file %s: expected JSON array", filename)
+	}
+
+	for decoder.More() {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("error decoding file %s: %w", filename, err)
+		}
+
+		var item T
+		if err := decoder.Decode(&item); err != nil {
+			log.Errorf("Error decoding item from file %s: %v", filename, err)
+			return fmt.Errorf("error decoding item from file %s: %w", filename, err)
+		}
+		if err := handler(item); err != nil {
+			return err
+		}
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		log.Errorf("Error decoding file %s: %v", filename, err)
+		return fmt.Errorf("error decoding file %s: %w", filename, err)
+	}
+	return nil
+}
+
+// CheckFileAccess verifies that filename can be opened for reading and writing
+// and that its directory accepts new files, which the temporary files of
+// JsonFileTransaction need. It changes nothing on disk.
+func CheckFileAccess(ctx context.Context, filename string) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("error checking file %s: %w", filename, err)
+	}
+
+	file, err := os.OpenFile(filename, os.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("error opening file %s: %w", filename, err)
+	}
+	_, err = file.Read(make([]byte, 1))
+	if closeErr := file.Close(); err == nil || err == io.EOF {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("error reading file %s: %w", filename, err)
+	}
+
+	return CheckDirAccess(ctx, filepath.Dir(filename))
+}
+
+// CheckDirAccess verifies that a file can be created in dir.
+func CheckDirAccess(ctx context.Context, dir string) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("error checking directory %s: %w", dir, err)
+	}
+
+	probe, err := os.CreateTemp(dir, ".access-check-*")
+	if err != nil {
+		return fmt.Errorf("error writing to directory %s: %w", dir, err)
+	}
+	_ = probe.Close()
+	return os.Remove(probe.Name())
+}
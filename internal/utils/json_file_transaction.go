@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"merchant_bank_payment_go_api/internal/metrics"
+	"os"
+	"time"
+)
+
+const stagedFileSuffix = ".tmp"
+
+// JsonFileTransaction replaces several JSON files so that either all of them or
+// none of them change. Every file is first written next to its target, then a
+// journal naming the files is written, and only then are the files renamed into
+// place. RecoverJsonFileTransaction finishes a commit that was interrupted
+// after the journal was written.
+type JsonFileTransaction struct {
+	Log             *logrus.Logger
+	JournalFilename string
+	filenames       []string
+	data            map[string]interface{}
+}
+
+func NewJsonFileTransaction(log *logrus.Logger, journalFilename string) *JsonFileTransaction {
+	return &JsonFileTransaction{
+		Log:             log,
+		JournalFilename: journalFilename,
+		data:            make(map[string]interface{}),
+	}
+}
+
+// Stage schedules data to be written to filename on commit. Staging the same
+// file twice keeps the last data.
+func (t *JsonFileTransaction) Stage(filename string, data interface{}) {
+	if _, ok := t.data[filename]; !ok {
+		t.filenames = append(t.filenames, filename)
+	}
+	t.data[filename] = data
+}
+
+// Commit only checks ctx before it starts writing; once the first file is
+// staged the commit runs to the end.
+func (t *JsonFileTransaction) Commit(ctx context.Context) error {
+	if len(t.filenames) == 0 {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	defer metrics.ObserveRepositoryOperation("commit", "transaction", time.Now())
+
+	for i, filename := range t.filenames {
+		if err := writeSyncedJsonFile(filename+stagedFileSuffix, t.data[filename]); err != nil {
+			for _, written := range t.filenames[:i] {
+				_ = os.Remove(written + stagedFileSuffix)
+			}
+			t.Log.Errorf("Error staging file %s: %v", filename, err)
+			return fmt.Errorf("error staging file %s: %w", filename, err)
+		}
+	}
+
+	if err := writeSyncedJsonFile(t.JournalFilename+stagedFileSuffix, t.filenames); err != nil {
+		t.Log.Errorf("Error writing transaction journal %s: %v", t.JournalFilename, err)
+		return fmt.Errorf("error writing transaction journal %s: %w", t.JournalFilename, err)
+	}
+	if err := os.Rename(t.JournalFilename+stagedFileSuffix, t.JournalFilename); err != nil {
+		t.Log.Errorf("Error writing transaction journal %s: %v", t.JournalFilename, err)
+		return fmt.Errorf("error writing transaction journal %s: %w", t.JournalFilename, err)
+	}
+
+	return applyJournal(t.JournalFilename, t.filenames, t.Log)
+}
+
+// RecoverJsonFileTransaction rolls an interrupted commit forward. It is a no-op
+// when no journal exists.
+func RecoverJsonFileTransaction(journalFilename string, log *logrus.Logger) error {
+	content, err := os.ReadFile(journalFilename)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		log.Errorf("Error reading transaction journal %s: %v", journalFilename, err)
+		return fmt.Errorf("error reading transaction journal %s: %w", journalFilename, err)
+	}
+
+	var filenames []string
+	if err := json.Unmarshal(content, &filenames); err != nil {
+		log.Errorf("Error decoding transaction journal %s: %v", journalFilename, err)
+		return fmt.Errorf("error decoding transaction journal %s: %w", journalFilename, err)
+	}
+
+	log.Warnf("Recovering interrupted transaction over %d files from %s", len(filenames), journalFilename)
+	return applyJournal(journalFilename, filenames, log)
+}
+
+func applyJournal(journalFilename string, filenames []string, log *logrus.Logger) error {
+	for _, filename := range filenames {
+		err := os.Rename(filename+stagedFileSuffix, filename)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Errorf("Error replacing file %s: %v", filename, err)
+			return fmt.Errorf("error replacing file %s: %w", filename, err)
+		}
+	}
+
+	if err := os.Remove(journalFilename); err != nil {
+		log.Errorf("Error removing transaction journal %s: %v", journalFilename, err)
+		return fmt.Errorf("error removing transaction journal %s: %w", journalFilename, err)
+	}
+	return nil
+}
+
+func writeSyncedJsonFile(filename string, data interface{}) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	if err := json.NewEncoder(file).Encode(data); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
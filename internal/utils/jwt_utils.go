@@ -32,11 +32,18 @@ func InitJwtConfig(secretKey []byte, expireInMinutes int) {
 	}
 }
 
+// JwtKeyLoaded reports whether InitJwtConfig has set a signing key.
+func JwtKeyLoaded() bool {
+	return jwtConfig != nil && len(jwtConfig.SecretKey) > 0
+}
+
 func GenerateAccessToken(id string) (string, error) {
 	token := jwt.New(jwt.SigningMethodHS256)
 
+	now := time.Now()
 	claims := token.Claims.(jwt.MapClaims)
-	claims["exp"] = time.Now().Add(time.Duration(jwtConfig.ExpireInMinutes) * time.Minute).Unix()
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(time.Duration(jwtConfig.ExpireInMinutes) * time.Minute).Unix()
 	claims["authorized"] = true
 	claims["user"] = id
 
@@ -90,3 +97,27 @@ func ExtractIDFromToken(requestToken string) (string, error) {
 
 	return id, nil
 }
+
+// ExtractIssuedAtFromToken returns the zero time for tokens issued before the
+// iat claim was added.
+func ExtractIssuedAtFromToken(requestToken string) (time.Time, error) {
+	token, err := jwt.Parse(requestToken, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return jwtConfig.SecretKey, nil
+	})
+
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse token: %w", err)
+	}
+
+	issuedAt, err := token.Claims.GetIssuedAt()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("issued at invalid in token: %w", err)
+	}
+	if issuedAt == nil {
+		return time.Time{}, nil
+	}
+	return issuedAt.Time, nil
+}
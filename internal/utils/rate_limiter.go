@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimitPolicy allows Requests per Window, with bursts of up to Requests.
+// A policy with no requests does not limit anything.
+type RateLimitPolicy struct {
+	Requests int
+	Window   time.Duration
+}
+
+// ParseRateLimitPolicy reads a policy written as requests/window, such as
+// "10/1m" or "100/30s". "off" turns the limit off.
+func ParseRateLimitPolicy(value string) (RateLimitPolicy, error) {
+	value = strings.TrimSpace(value)
+	if strings.EqualFold(value, "off") {
+		return RateLimitPolicy{}, nil
+	}
+
+	requests, window, found := strings.Cut(value, "/")
+	if !found {
+		return RateLimitPolicy{}, fmt.Errorf("%q is not requests/window, such as 10/1m, or off", value)
+	}
+	parsedRequests, err := strconv.Atoi(strings.TrimSpace(requests))
+	if err != nil || parsedRequests <= 0 {
+		return RateLimitPolicy{}, fmt.Errorf("%q must allow a positive number of requests", value)
+	}
+	parsedWindow, err := time.ParseDuration(strings.TrimSpace(window))
+	if err != nil || parsedWindow <= 0 {
+		return RateLimitPolicy{}, fmt.Errorf("%q must have a positive window such as 1m", value)
+	}
+	return RateLimitPolicy{Requests: parsedRequests, Window: parsedWindow}, nil
+}
+
+func (p RateLimitPolicy) Enabled() bool {
+	return p.Requests > 0 && p.Window > 0
+}
+
+// interval is the time it takes to refill one token.
+func (p RateLimitPolicy) interval() time.Duration {
+	return max(p.Window/time.Duration(p.Requests), time.Nanosecond)
+}
+
+func (p RateLimitPolicy) String() string {
+	if !p.Enabled() {
+		return "off"
+	}
+	return fmt.Sprintf("%d/%s", p.Requests, p.Window)
+}
+
+// RateLimitStatus describes a bucket after a request was counted against it.
+// Reset is the time until the bucket is full again and RetryAfter the time
+// until the next request would be allowed.
+type RateLimitStatus struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// RateLimiter keeps a token bucket per key. Buckets that have filled up again
+// are dropped from time to time, so keys such as client addresses do not
+// accumulate.
+type RateLimiter struct {
+	Now       func() time.Time
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+	policy  RateLimitPolicy
+}
+
+const rateLimiterSweepInterval = time.Minute
+
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{
+		Now:     time.Now,
+		buckets: map[string]*tokenBucket{},
+	}
+}
+
+// Allow takes a token from the bucket of key, refilled at policy.Requests per
+// policy.Window. When the policy changes, the bucket keeps its tokens up to
+// the new limit.
+func (l *RateLimiter) Allow(key string, policy RateLimitPolicy) RateLimitStatus {
+	if !policy.Enabled() {
+		return RateLimitStatus{Allowed: true}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.Now()
+	l.sweep(now)
+
+	limit := float64(policy.Requests)
+	perToken := policy.interval()
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: limit, updated: now}
+		l.buckets[key] = bucket
+	}
+	bucket.policy = policy
+	bucket.tokens = math.Min(limit, bucket.tokens+float64(now.Sub(bucket.updated))/float64(perToken))
+	bucket.updated = now
+
+	status := RateLimitStatus{Limit: policy.Requests}
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		status.Allowed = true
+	} else {
+		status.RetryAfter = time.Duration((1 - bucket.tokens) * float64(perToken))
+	}
+	status.Remaining = int(bucket.tokens)
+	status.Reset = time.Duration((limit - bucket.tokens) * float64(perToken))
+	return status
+}
+
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimiterSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, bucket := range l.buckets {
+		perToken := bucket.policy.interval()
+		missing := float64(bucket.policy.Requests) - bucket.tokens
+		if now.Sub(bucket.updated) >= time.Duration(missing*float64(perToken)) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Size returns the number of buckets currently kept.
+func (l *RateLimiter) Size() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.buckets)
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+)
+
+const RequestIdHeader = "X-Request-ID"
+
+type requestIdKey struct{}
+
+func WithRequestId(ctx context.Context, requestId string) context.Context {
+	return context.WithValue(ctx, requestIdKey{}, requestId)
+}
+
+func RequestIdFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestId, _ := ctx.Value(requestIdKey{}).(string)
+	return requestId
+}
+
+// RequestIdHook adds the request id of the entry's context as the request_id
+// field, so every line logged through Log.WithContext(ctx) can be correlated.
+type RequestIdHook struct{}
+
+func (RequestIdHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (RequestIdHook) Fire(entry *logrus.Entry) error {
+	if requestId := RequestIdFromContext(entry.Context); requestId != "" {
+		entry.Data["request_id"] = requestId
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package utils
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const rotatedFileTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotatingFile is an io.WriteCloser over a log file that is moved aside once it
+// reaches MaxSize bytes or when a new RotateEvery period starts (periods are
+// aligned to UTC, so 24h rotates at midnight UTC). Rotated files are named
+// <name>-<timestamp><ext>, optionally gzip-compressed, and pruned beyond
+// MaxBackups files or MaxAge. A zero value disables the respective limit.
+type RotatingFile struct {
+	Filename    string
+	MaxSize     int64
+	RotateEvery time.Duration
+	MaxBackups  int
+	MaxAge      time.Duration
+	Compress    bool
+
+	// Now is replaced in tests.
+	Now func() time.Time
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	cleanup  sync.WaitGroup
+	cleanMu  sync.Mutex
+}
+
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	if r.shouldRotate(int64(len(p))) {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Close closes the current file and waits for pending compression and pruning.
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	var err error
+	if r.file != nil {
+		err = r.file.Close()
+		r.file = nil
+	}
+	r.mu.Unlock()
+
+	r.cleanup.Wait()
+	return err
+}
+
+func (r *RotatingFile) now() time.Time {
+	if r.Now != nil {
+		return r.Now()
+	}
+	return time.Now()
+}
+
+// open continues an existing file. Its modification time stands in for the
+// time it was opened, so a file last written in an earlier period is rotated
+// on the first write.
+func (r *RotatingFile) open() error {
+	if dir := filepath.Dir(r.Filename); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("error creating log directory %s: %w", dir, err)
+		}
+	}
+
+	file, err := os.OpenFile(r.Filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("error opening log file %s: %w", r.Filename, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("error reading log file %s: %w", r.Filename, err)
+	}
+
+	r.file = file
+	r.size = info.Size()
+	r.openedAt = r.now()
+	if info.Size() > 0 {
+		r.openedAt = info.ModTime()
+	}
+	return nil
+}
+
+func (r *RotatingFile) shouldRotate(incoming int64) bool {
+	if r.size == 0 {
+		return false
+	}
+	if r.MaxSize > 0 && r.size+incoming > r.MaxSize {
+		return true
+	}
+	return r.RotateEvery > 0 && !r.now().Truncate(r.RotateEvery).Equal(r.openedAt.Truncate(r.RotateEvery))
+}
+
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("error closing log file %s: %w", r.Filename, err)
+	}
+	r.file = nil
+
+	// Never overwrite an earlier backup rotated within the same millisecond.
+	rotatedAt := r.now()
+	backup := r.backupName(rotatedAt)
+	for fileExists(backup) || fileExists(backup+".gz") {
+		rotatedAt = rotatedAt.Add(time.Millisecond)
+		backup = r.backupName(rotatedAt)
+	}
+	if err := os.Rename(r.Filename, backup); err != nil {
+		return fmt.Errorf("error rotating log file %s: %w", r.Filename, err)
+	}
+
+	if err := r.open(); err != nil {
+		return err
+	}
+
+	r.cleanup.Add(1)
+	go func() {
+		defer r.cleanup.Done()
+		r.clean(rotatedAt)
+	}()
+	return nil
+}
+
+func (r *RotatingFile) backupName(at time.Time) string {
+	ext := filepath.Ext(r.Filename)
+	base := strings.TrimSuffix(r.Filename, ext)
+	return fmt.Sprintf("%s-%s%s", base, at.UTC().Format(rotatedFileTimeFormat), ext)
+}
+
+type rotatedFile struct {
+	path      string
+	rotatedAt time.Time
+}
+
+// backups lists the rotated files of Filename, newest first.
+func (r *RotatingFile) backups() []rotatedFile {
+	ext := filepath.Ext(r.Filename)
+	prefix := filepath.Base(strings.TrimSuffix(r.Filename, ext)) + "-"
+
+	entries, err := os.ReadDir(filepath.Dir(r.Filename))
+	if err != nil {
+		return nil
+	}
+
+	var backups []rotatedFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".gz"), ext)
+		rotatedAt, err := time.Parse(rotatedFileTimeFormat, stamp)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, rotatedFile{path: filepath.Join(filepath.Dir(r.Filename), name), rotatedAt: rotatedAt})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].rotatedAt.After(backups[j].rotatedAt)
+	})
+	return backups
+}
+
+// clean enforces retention and compresses what is kept. Failures are written
+// to stderr since the log itself is what is being cleaned up.
+func (r *RotatingFile) clean(now time.Time) {
+	r.cleanMu.Lock()
+	defer r.cleanMu.Unlock()
+
+	for i, backup := range r.backups() {
+		expired := r.MaxAge > 0 && now.Sub(backup.rotatedAt) > r.MaxAge
+		if (r.MaxBackups > 0 && i >= r.MaxBackups) || expired {
+			if err := os.Remove(backup.path); err != nil && !os.IsNotExist(err) {
+				fmt.Fprintf(os.Stderr, "error removing rotated log %s: %v\n", backup.path, err)
+			}
+			continue
+		}
+
+		if r.Compress && !strings.HasSuffix(backup.path, ".gz") {
+			if err := compressFile(backup.path); err != nil {
+				fmt.Fprintf(os.Stderr, "error compressing rotated log %s: %v\n", backup.path, err)
+			}
+		}
+	}
+}
+
+func compressFile(filename string) error {
+	source, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	target, err := os.OpenFile(filename+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	writer := gzip.NewWriter(target)
+	_, err = io.Copy(writer, source)
+	if closeErr := writer.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := target.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(filename + ".gz")
+		return err
+	}
+
+	return os.Remove(filename)
+}
+
+func fileExists(filename string) bool {
+	_, err := os.Stat(filename)
+	return err == nil
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const WebhookSignatureHeader = "X-Webhook-Signature"
+
+// SignWebhookPayload signs "<unix timestamp>.<payload>" so a receiver can reject
+// replayed bodies, and formats it as "t=<timestamp>,v1=<hex hmac>".
+func SignWebhookPayload(secret string, timestamp time.Time, payload []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", unix, computeWebhookHmac(secret, unix, payload))
+}
+
+func VerifyWebhookSignature(secret, header string, payload []byte, tolerance time.Duration, now time.Time) error {
+	var unix, signature string
+	for _, part := range strings.Split(header, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "t":
+			unix = value
+		case "v1":
+			signature = value
+		}
+	}
+
+	if unix == "" || signature == "" {
+		return fmt.Errorf("malformed webhook signature header")
+	}
+
+	seconds, err := strconv.ParseInt(unix, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid webhook signature timestamp: %w", err)
+	}
+	if tolerance > 0 && now.Sub(time.Unix(seconds, 0)).Abs() > tolerance {
+		return fmt.Errorf("webhook signature timestamp outside tolerance")
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(computeWebhookHmac(secret, unix, payload))) {
+		return fmt.Errorf("webhook signature mismatch")
+	}
+	return nil
+}
+
+func computeWebhookHmac(secret, unix string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,209 @@
+package config_test
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"merchant_bank_payment_go_api/internal/config"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/repository"
+	"merchant_bank_payment_go_api/internal/utils"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, dir, content string) string {
+	path := filepath.Join(dir, "config.yaml")
+	assert.Nil(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestLoadConfig_ShouldUseDefaults_WithoutFileOrDotEnv(t *testing.T) {
+	chdirTemp(t)
+	t.Setenv("SECRET_KEY", "test-secret")
+
+	cfg, err := config.LoadConfig("")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("test-secret"), cfg.SecretKey)
+	assert.Equal(t, "4000", cfg.Port)
+	assert.Equal(t, 10, cfg.ExpireInMinutes)
+	assert.Equal(t, config.StorageBackendJson, cfg.StorageBackend)
+	assert.Equal(t, filepath.Join("internal", "repository", "data", "Customer.json"), cfg.DataFiles.Customers)
+	assert.Equal(t, filepath.Join("internal", "repository", "data", "archive", "history"), cfg.HistoryArchiveDir)
+	assert.Equal(t, 130*time.Second, cfg.ServerWriteTimeout)
+	assert.Equal(t, logrus.InfoLevel, cfg.LogLevel)
+	assert.Equal(t, entity.RoundHalfEven, cfg.FxRounding)
+	assert.Equal(t, repository.HistoryOverflowBlock, cfg.HistoryOverflowPolicy)
+}
+
+func TestLoadConfig_ShouldLetEnvironmentOverrideFile(t *testing.T) {
+	dir := chdirTemp(t)
+	path := writeConfigFile(t, dir, `
+server:
+  port: "9000"
+auth:
+  secret_key: from-file
+storage:
+  data_dir: /var/lib/payments
+  files:
+    merchants: /srv/merchants.json
+logging:
+  level: debug
+  redact_patterns: ["ACCT-\\d+"]
+tracing:
+  otlp_headers:
+    x-team: payments
+limits:
+  export_timeout_seconds: 300
+history:
+  overflow_policy: drop
+`)
+	t.Setenv("PORT", "9100")
+	t.Setenv("LOG_LEVEL", "warn")
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "authorization=Bearer abc, x-team=risk")
+
+	cfg, err := config.LoadConfig(path)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "9100", cfg.Port)
+	assert.Equal(t, []byte("from-file"), cfg.SecretKey)
+	assert.Equal(t, "/srv/merchants.json", cfg.DataFiles.Merchants)
+	assert.Equal(t, filepath.Join("/var/lib/payments", "Customer.json"), cfg.DataFiles.Customers)
+	assert.Equal(t, logrus.WarnLevel, cfg.LogLevel)
+	assert.Equal(t, "[REDACTED]", cfg.LogRedactor.Redact("ACCT-42"))
+	assert.Equal(t, map[string]string{"authorization": "Bearer abc", "x-team": "risk"}, cfg.TracingOtlpHeaders)
+	assert.Equal(t, 310*time.Second, cfg.ServerWriteTimeout)
+	assert.Equal(t, repository.HistoryOverflowDrop, cfg.HistoryOverflowPolicy)
+}
+
+func TestLoadConfig_ShouldReportEveryProblemAtOnce(t *testing.T) {
+	dir := chdirTemp(t)
+	path := writeConfigFile(t, dir, `
+server:
+  port: "http"
+  read_timeout: 15
+logging:
+  format: xml
+  max_backups: many
+history:
+  queue_size: 0
+`)
+	t.Setenv("SECRET_KEY", "")
+	t.Setenv("WEBHOOK_MAX_ATTEMPTS", "three")
+	t.Setenv("TRACING_SAMPLE_RATIO", "2")
+
+	cfg, err := config.LoadConfig(path)
+
+	assert.Nil(t, cfg)
+	assert.ErrorContains(t, err, "field read_timeout not found")
+	assert.ErrorContains(t, err, "cannot unmarshal !!str `many`")
+	assert.ErrorContains(t, err, `WEBHOOK_MAX_ATTEMPTS: "three" is not a whole number`)
+	assert.ErrorContains(t, err, "auth.secret_key (SECRET_KEY) must be set")
+	assert.ErrorContains(t, err, `server.port (PORT) must be a port number, got "http"`)
+	assert.ErrorContains(t, err, "logging.format (LOG_FORMAT) must be one of json, text")
+	assert.ErrorContains(t, err, "history.queue_size (HISTORY_QUEUE_SIZE) must be greater than 0")
+	assert.ErrorContains(t, err, "tracing.sample_ratio (TRACING_SAMPLE_RATIO) must be between 0 and 1")
+}
+
+func TestLoadConfig_ShouldRejectWriteTimeoutShorterThanExports(t *testing.T) {
+	chdirTemp(t)
+	t.Setenv("SECRET_KEY", "test-secret")
+	t.Setenv("SERVER_WRITE_TIMEOUT_SECONDS", "60")
+
+	_, err := config.LoadConfig("")
+
+	assert.ErrorContains(t, err, "server.write_timeout_seconds (SERVER_WRITE_TIMEOUT_SECONDS) must be greater than")
+}
+
+func TestLoadConfig_ShouldReturnError_WhenFileIsMissing(t *testing.T) {
+	dir := chdirTemp(t)
+	t.Setenv("SECRET_KEY", "test-secret")
+
+	_, err := config.LoadConfig(filepath.Join(dir, "missing.yaml"))
+
+	assert.ErrorContains(t, err, "error reading config file")
+}
+
+func TestLoadConfig_ShouldReadDotEnvFromWorkingDirectory(t *testing.T) {
+	dir := chdirTemp(t)
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, ".env"), []byte("SECRET_KEY=from-dotenv\nHISTORY_BATCH_SIZE=25\n"), 0o644))
+	t.Setenv("SECRET_KEY", "")
+	t.Setenv("HISTORY_BATCH_SIZE", "")
+	os.Unsetenv("SECRET_KEY")
+	os.Unsetenv("HISTORY_BATCH_SIZE")
+
+	cfg, err := config.LoadConfig("")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("from-dotenv"), cfg.SecretKey)
+	assert.Equal(t, 25, cfg.HistoryBatchSize)
+}
+
+func TestLoadConfig_ShouldAcceptExampleFile(t *testing.T) {
+	example, err := filepath.Abs(filepath.Join("..", "..", "config.example.yaml"))
+	assert.Nil(t, err)
+	chdirTemp(t)
+	t.Setenv("SECRET_KEY", "test-secret")
+
+	cfg, err := config.LoadConfig(example)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "8000", cfg.Port)
+}
+
+func TestLoadConfig_ShouldParsePaymentLimitsFromEnvironment(t *testing.T) {
+	chdirTemp(t)
+	t.Setenv("SECRET_KEY", "test-secret")
+	t.Setenv("PAYMENT_MAX_AMOUNTS", "usd=100000, IDR=50000000")
+	t.Setenv("MAINTENANCE_MODE", "true")
+
+	cfg, err := config.LoadConfig("")
+
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int64{"USD": 100000, "IDR": 50000000}, cfg.PaymentLimits.MaxAmounts)
+	assert.True(t, cfg.MaintenanceMode)
+	assert.Equal(t, 5*time.Second, cfg.ConfigWatchInterval)
+}
+
+func TestLoadConfig_ShouldRejectInvalidPaymentLimits(t *testing.T) {
+	chdirTemp(t)
+	t.Setenv("SECRET_KEY", "test-secret")
+	t.Setenv("PAYMENT_MAX_AMOUNTS", "USD=ten")
+
+	_, err := config.LoadConfig("")
+
+	assert.ErrorContains(t, err, `PAYMENT_MAX_AMOUNTS: USD: "ten" is not a whole number`)
+
+	t.Setenv("PAYMENT_MAX_AMOUNTS", "USD=0,XXX=5")
+
+	_, err = config.LoadConfig("")
+
+	assert.ErrorContains(t, err, "payments.max_amounts (PAYMENT_MAX_AMOUNTS) must be greater than 0 for USD")
+	assert.ErrorContains(t, err, `payments.max_amounts (PAYMENT_MAX_AMOUNTS) is invalid: unsupported currency "XXX"`)
+}
+
+func TestLoadConfig_ShouldParseRateLimitsAndTrustedProxies(t *testing.T) {
+	chdirTemp(t)
+	t.Setenv("SECRET_KEY", "test-secret")
+	t.Setenv("RATE_LIMIT_LOGIN_PER_IP", "5/30s")
+	t.Setenv("RATE_LIMIT_PAYMENT_PER_MERCHANT", "off")
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8, 192.168.1.1")
+
+	cfg, err := config.LoadConfig("")
+
+	assert.Nil(t, err)
+	assert.Equal(t, utils.RateLimitPolicy{Requests: 5, Window: 30 * time.Second}, cfg.RateLimits.LoginPerIp)
+	assert.Equal(t, utils.RateLimitPolicy{Requests: 120, Window: time.Minute}, cfg.RateLimits.ApiPerUser)
+	assert.False(t, cfg.RateLimits.PaymentPerMerchant.Enabled())
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.1"}, cfg.TrustedProxies)
+
+	t.Setenv("RATE_LIMIT_API_PER_USER", "many")
+	t.Setenv("TRUSTED_PROXIES", "proxy.internal")
+
+	_, err = config.LoadConfig("")
+
+	assert.ErrorContains(t, err, "rate_limits.api_per_user (RATE_LIMIT_API_PER_USER) is invalid")
+	assert.ErrorContains(t, err, `server.trusted_proxies (TRUSTED_PROXIES) must hold IP addresses or CIDR ranges, got "proxy.internal"`)
+}
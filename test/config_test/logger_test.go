@@ -0,0 +1,169 @@
+package config_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"merchant_bank_payment_go_api/internal/config"
+	"merchant_bank_payment_go_api/internal/delivery/http/controller"
+	"merchant_bank_payment_go_api/internal/delivery/http/middleware"
+	repositoryImpl "merchant_bank_payment_go_api/internal/repository/impl"
+	"merchant_bank_payment_go_api/internal/tracing"
+	"merchant_bank_payment_go_api/internal/utils"
+	"merchant_bank_payment_go_api/test/helper"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// chdirTemp runs the test from an empty directory so NewLogger creates its
+// app_history.log there.
+func chdirTemp(t *testing.T) string {
+	dir := t.TempDir()
+	previous, err := os.Getwd()
+	assert.Nil(t, err)
+	assert.Nil(t, os.Chdir(dir))
+	t.Cleanup(func() {
+		_ = os.Chdir(previous)
+	})
+	return dir
+}
+
+func newLogConfig(redactor *utils.Redactor, output string) *config.Config {
+	return &config.Config{
+		LogRedactor: redactor,
+		LogLevel:    logrus.DebugLevel,
+		LogFormat:   config.LogFormatJson,
+		LogOutput:   output,
+		LogFile:     "app_history.log",
+	}
+}
+
+func TestNewLogger_ShouldNeverWriteSecretsToAppHistoryLog(t *testing.T) {
+	dir := chdirTemp(t)
+	redactor, err := utils.NewRedactor(nil)
+	assert.Nil(t, err)
+	logger, closeLog, err := config.NewLogger(newLogConfig(redactor, config.LogOutputFile))
+	assert.Nil(t, err)
+	defer closeLog()
+
+	utils.InitJwtConfig([]byte("abc"), 10)
+	userId := uuid.New().String()
+	token, _ := utils.GenerateAccessToken(userId)
+
+	// Logout through the real middleware and controller.
+	mockAuthUseCase := new(helper.MockAuthUseCase)
+	mockAuthUseCase.On("IsTokenBlacklisted", mock.Anything, token).Return(false, nil)
+	mockAuthUseCase.On("Logout", mock.Anything, token).Return(nil)
+	r := gin.New()
+	r.Use(middleware.AuthenticationMiddleware(logger, mockAuthUseCase))
+	r.POST("/logout", controller.NewAuthenticationController(logger, mockAuthUseCase).Logout)
+	req := httptest.NewRequest("POST", "/logout", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Blacklist the token twice and look it up through the file repository.
+	authRepository := repositoryImpl.NewAuthRepository(logger, filepath.Join(dir, "Blacklist.json"))
+	assert.Nil(t, authRepository.SaveBlacklist(context.Background(), []string{}))
+	assert.Nil(t, authRepository.AddToBlacklist(context.Background(), token))
+	assert.NotNil(t, authRepository.AddToBlacklist(context.Background(), token))
+	blacklisted, err := authRepository.IsTokenBlacklisted(context.Background(), token)
+	assert.Nil(t, err)
+	assert.True(t, blacklisted)
+
+	// Careless log statements are masked by the hook.
+	logger.Infof("blacklisted token for user: %s", token)
+	logger.Infof("Authorization: Bearer %s", token)
+	logger.WithField("token", token).Info("field named token")
+	logger.WithField("header", "Bearer "+token).Info("field holding a token")
+	logger.WithError(fmt.Errorf("cannot verify %s", token)).Error("error holding a token")
+	logger.Infof("login attempt with password=hunter2 and card 4111 1111 1111 1111")
+
+	content, err := os.ReadFile(filepath.Join(dir, "app_history.log"))
+	assert.Nil(t, err)
+	log := string(content)
+	assert.NotEmpty(t, log)
+	assert.NotContains(t, log, token)
+	for _, part := range strings.Split(token, ".") {
+		assert.NotContains(t, log, part)
+	}
+	assert.NotContains(t, log, "hunter2")
+	assert.NotContains(t, log, "4111 1111 1111 1111")
+	assert.Contains(t, log, userId)
+	assert.Contains(t, log, utils.RedactedPlaceholder)
+}
+
+func TestNewLogger_ShouldRedactMessageBeforeMarkingSpanFailed(t *testing.T) {
+	chdirTemp(t)
+	redactor, err := utils.NewRedactor(nil)
+	assert.Nil(t, err)
+	logger, closeLog, err := config.NewLogger(newLogConfig(redactor, config.LogOutputStdout))
+	assert.Nil(t, err)
+	defer closeLog()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ctx, span := provider.Tracer(tracing.InstrumentationName).Start(context.Background(), "AuthUseCase.Login")
+	logger.WithContext(ctx).Error("login failed with password=hunter2")
+	span.End()
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.NotContains(t, spans[0].Status().Description, "hunter2")
+	assert.Contains(t, spans[0].Status().Description, utils.RedactedPlaceholder)
+}
+
+func TestNewLogger_ShouldApplyLevelAndTextFormat(t *testing.T) {
+	dir := chdirTemp(t)
+	cfg := newLogConfig(nil, config.LogOutputFile)
+	cfg.LogLevel = logrus.WarnLevel
+	cfg.LogFormat = config.LogFormatText
+	logger, closeLog, err := config.NewLogger(cfg)
+	assert.Nil(t, err)
+
+	logger.Info("hidden below warn")
+	logger.Warn("visible warning")
+	assert.Nil(t, closeLog())
+
+	content, err := os.ReadFile(filepath.Join(dir, "app_history.log"))
+	assert.Nil(t, err)
+	assert.NotContains(t, string(content), "hidden below warn")
+	assert.Contains(t, string(content), `level=warning msg="visible warning"`)
+}
+
+func TestNewLogger_ShouldNotCreateLogFile_WhenOutputIsStdout(t *testing.T) {
+	dir := chdirTemp(t)
+	logger, closeLog, err := config.NewLogger(newLogConfig(nil, config.LogOutputStdout))
+	assert.Nil(t, err)
+
+	logger.Info("to stdout")
+	assert.Nil(t, closeLog())
+
+	_, err = os.Stat(filepath.Join(dir, "app_history.log"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestNewLogger_ShouldReturnError_WhenLogFileCannotBeOpened(t *testing.T) {
+	dir := chdirTemp(t)
+	cfg := newLogConfig(nil, config.LogOutputBoth)
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "not-a-dir"), nil, 0o644))
+	cfg.LogFile = filepath.Join(dir, "not-a-dir", "app.log")
+
+	logger, _, err := config.NewLogger(cfg)
+
+	assert.Nil(t, logger)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,119 @@
+package config_test
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merchant_bank_payment_go_api/internal/config"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/test/helper"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+const reloadBaseConfig = `
+auth:
+  secret_key: test-secret
+logging:
+  level: info
+  output: stdout
+`
+
+func newReloader(t *testing.T, content string) (*config.ConfigReloader, *helper.MockHistoryUseCase, string) {
+	dir := chdirTemp(t)
+	path := writeConfigFile(t, dir, content)
+	cfg, err := config.LoadConfig(path)
+	assert.Nil(t, err)
+
+	logger := logrus.New()
+	logger.SetLevel(cfg.LogLevel)
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	return config.NewConfigReloader(logger, cfg, mockHistoryUseCase), mockHistoryUseCase, path
+}
+
+func reloadDetails(errorCode entity.AuditErrorCode, message string) interface{} {
+	return mock.MatchedBy(func(details entity.AuditDetails) bool {
+		return details.ActorType == entity.AuditActorSystem && details.SubjectType == entity.AuditSubjectConfig &&
+			details.ErrorCode == errorCode && (errorCode == "") == (details.Outcome == entity.AuditOutcomeSuccess) && strings.Contains(details.Message, message)
+	})
+}
+
+func TestConfigReloader_ShouldApplyReloadableSettings(t *testing.T) {
+	reloader, mockHistoryUseCase, path := newReloader(t, reloadBaseConfig)
+	mockHistoryUseCase.On("AddHistory", mock.Anything, "", entity.AuditActionConfigReload,
+		reloadDetails("", "Configuration reloaded on SIGHUP, applied logging.level, payments.max_amounts, maintenance.enabled")).Return(nil)
+
+	assert.Nil(t, os.WriteFile(path, []byte(strings.Replace(reloadBaseConfig, "level: info", "level: debug", 1)+`
+payments:
+  max_amounts:
+    usd: 500000
+maintenance:
+  enabled: true
+`), 0o644))
+
+	err := reloader.Reload(context.Background(), "SIGHUP")
+
+	assert.Nil(t, err)
+	assert.Equal(t, logrus.DebugLevel, reloader.Log.GetLevel())
+	assert.Equal(t, map[string]int64{"USD": 500000}, reloader.PaymentLimits().MaxAmounts)
+	assert.True(t, reloader.MaintenanceMode())
+	mockHistoryUseCase.AssertExpectations(t)
+}
+
+func TestConfigReloader_ShouldKeepCurrentConfig_WhenNewConfigIsInvalid(t *testing.T) {
+	reloader, mockHistoryUseCase, path := newReloader(t, reloadBaseConfig)
+	mockHistoryUseCase.On("AddHistory", mock.Anything, "", entity.AuditActionConfigReload,
+		reloadDetails(entity.AuditErrorInvalidConfig, "rejected, keeping the current configuration")).Return(nil)
+	current := reloader.Config()
+
+	assert.Nil(t, os.WriteFile(path, []byte(reloadBaseConfig+`
+maintenance:
+  enabled: true
+payments:
+  max_amounts:
+    XXX: 100
+`), 0o644))
+
+	err := reloader.Reload(context.Background(), "file change")
+
+	assert.ErrorContains(t, err, "payments.max_amounts (PAYMENT_MAX_AMOUNTS) is invalid")
+	assert.Same(t, current, reloader.Config())
+	assert.False(t, reloader.MaintenanceMode())
+	mockHistoryUseCase.AssertExpectations(t)
+}
+
+func TestConfigReloader_ShouldNotApplySettingsThatNeedRestart(t *testing.T) {
+	reloader, mockHistoryUseCase, path := newReloader(t, reloadBaseConfig)
+	mockHistoryUseCase.On("AddHistory", mock.Anything, "", entity.AuditActionConfigReload,
+		reloadDetails("", "applied maintenance.enabled; restart required for server.port")).Return(nil)
+
+	assert.Nil(t, os.WriteFile(path, []byte(reloadBaseConfig+`
+server:
+  port: "9999"
+maintenance:
+  enabled: true
+`), 0o644))
+
+	err := reloader.Reload(context.Background(), "SIGHUP")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "4000", reloader.Config().Port)
+	assert.True(t, reloader.MaintenanceMode())
+	mockHistoryUseCase.AssertExpectations(t)
+}
+
+func TestConfigReloader_ShouldReload_WhenFileChanges(t *testing.T) {
+	reloader, mockHistoryUseCase, path := newReloader(t, reloadBaseConfig)
+	mockHistoryUseCase.On("AddHistory", mock.Anything, "", entity.AuditActionConfigReload,
+		reloadDetails("", "Configuration reloaded on file change, applied maintenance.enabled")).Return(nil)
+
+	stop := reloader.StartWatcher(10 * time.Millisecond)
+	assert.Nil(t, os.WriteFile(path, []byte(reloadBaseConfig+"maintenance:\n  enabled: true\n"), 0o644))
+
+	assert.Eventually(t, reloader.MaintenanceMode, time.Second, 10*time.Millisecond)
+	stop()
+	mockHistoryUseCase.AssertExpectations(t)
+}
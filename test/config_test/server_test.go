@@ -0,0 +1,69 @@
+package config_test
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"merchant_bank_payment_go_api/internal/config"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNewServer_ShouldApplyConfiguredTimeouts(t *testing.T) {
+	cfg := &config.Config{
+		Port:               "8000",
+		ServerReadTimeout:  15 * time.Second,
+		ServerWriteTimeout: 130 * time.Second,
+		ServerIdleTimeout:  60 * time.Second,
+	}
+
+	server := config.NewServer(cfg, http.NotFoundHandler())
+
+	assert.Equal(t, ":8000", server.Addr)
+	assert.Equal(t, 15*time.Second, server.ReadHeaderTimeout)
+	assert.Equal(t, 15*time.Second, server.ReadTimeout)
+	assert.Equal(t, 130*time.Second, server.WriteTimeout)
+	assert.Equal(t, 60*time.Second, server.IdleTimeout)
+}
+
+func TestShutdownHooks_ShouldRunInOrderAndCollectErrors(t *testing.T) {
+	var ran []string
+	hooks := config.NewShutdownHooks()
+	hooks.Add("event dispatcher", func(ctx context.Context) error {
+		ran = append(ran, "event dispatcher")
+		return errors.New("outbox unavailable")
+	})
+	hooks.Add("history writer", func(ctx context.Context) error {
+		ran = append(ran, "history writer")
+		return nil
+	})
+
+	err := hooks.Run(context.Background())
+
+	assert.Equal(t, []string{"event dispatcher", "history writer"}, ran)
+	assert.EqualError(t, err, "event dispatcher: outbox unavailable")
+}
+
+func TestShutdownHooks_ShouldStopWaiting_WhenDeadlinePasses(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	hooks := config.NewShutdownHooks()
+	hooks.Add("webhook worker", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+	hooks.Add("log file", func(ctx context.Context) error {
+		t.Error("hooks after the deadline must be skipped")
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := hooks.Run(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "webhook worker")
+	assert.ErrorContains(t, err, "log file: skipped")
+}
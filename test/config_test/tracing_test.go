@@ -0,0 +1,109 @@
+package config_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"io"
+	"merchant_bank_payment_go_api/internal/config"
+	"merchant_bank_payment_go_api/internal/tracing"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func restoreTracerProvider(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+	})
+}
+
+func TestNewTracerProvider_ShouldWriteSpansToFile(t *testing.T) {
+	restoreTracerProvider(t)
+	dir := t.TempDir()
+	cfg := &config.Config{
+		TracingExporter:    config.TracingExporterFile,
+		TracingFile:        filepath.Join(dir, "traces.json"),
+		TracingSampleRatio: 1,
+		TracingServiceName: "payment-api-test",
+	}
+
+	shutdown, err := config.NewTracerProvider(cfg)
+	assert.Nil(t, err)
+
+	_, span := tracing.Start(context.Background(), "MerchantUseCase.FindById")
+	span.End()
+	assert.Nil(t, shutdown(context.Background()))
+
+	content, err := os.ReadFile(cfg.TracingFile)
+	assert.Nil(t, err)
+	assert.Contains(t, string(content), `"Name":"MerchantUseCase.FindById"`)
+	assert.Contains(t, string(content), "payment-api-test")
+}
+
+func TestNewTracerProvider_ShouldNotSample_WhenRatioIsZero(t *testing.T) {
+	restoreTracerProvider(t)
+	dir := t.TempDir()
+	cfg := &config.Config{
+		TracingExporter:    config.TracingExporterFile,
+		TracingFile:        filepath.Join(dir, "traces.json"),
+		TracingSampleRatio: 0,
+	}
+
+	shutdown, err := config.NewTracerProvider(cfg)
+	assert.Nil(t, err)
+
+	_, span := tracing.Start(context.Background(), "MerchantUseCase.FindById")
+	assert.False(t, span.SpanContext().IsSampled())
+	span.End()
+	assert.Nil(t, shutdown(context.Background()))
+
+	content, err := os.ReadFile(cfg.TracingFile)
+	assert.Nil(t, err)
+	assert.Empty(t, content)
+}
+
+func TestNewTracerProvider_ShouldReturnError_WhenExporterIsUnknown(t *testing.T) {
+	restoreTracerProvider(t)
+
+	shutdown, err := config.NewTracerProvider(&config.Config{TracingExporter: "zipkin"})
+
+	assert.Nil(t, shutdown)
+	assert.NotNil(t, err)
+}
+
+func TestNewTracerProvider_ShouldSendSpansToOtlpCollector(t *testing.T) {
+	restoreTracerProvider(t)
+	var path, contentType, authorization string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, contentType, authorization = r.URL.Path, r.Header.Get("Content-Type"), r.Header.Get("Authorization")
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		TracingExporter:     config.TracingExporterOtlp,
+		TracingOtlpEndpoint: server.URL + "/",
+		TracingOtlpHeaders:  map[string]string{"Authorization": "Bearer collector"},
+		TracingSampleRatio:  1,
+		TracingServiceName:  "payment-api-test",
+	}
+
+	shutdown, err := config.NewTracerProvider(cfg)
+	assert.Nil(t, err)
+
+	_, span := tracing.Start(context.Background(), "PaymentTransactionRepository.AddPayment")
+	span.End()
+	assert.Nil(t, shutdown(context.Background()))
+
+	assert.Equal(t, "/v1/traces", path)
+	assert.Equal(t, "application/x-protobuf", contentType)
+	assert.Equal(t, "Bearer collector", authorization)
+	assert.Contains(t, string(body), "PaymentTransactionRepository.AddPayment")
+	assert.Contains(t, string(body), "payment-api-test")
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"merchant_bank_payment_go_api/internal/delivery/http/controller"
 	"merchant_bank_payment_go_api/internal/delivery/http/middleware"
 	"merchant_bank_payment_go_api/internal/model"
@@ -36,7 +37,7 @@ func TestLogin_ShouldReturnAccessToken(t *testing.T) {
 
 	log := logrus.New()
 	mockAuthUseCase := new(helper.MockAuthUseCase)
-	mockAuthUseCase.On("Login", loginRequest).Return(loginResponse, nil)
+	mockAuthUseCase.On("Login", mock.Anything, loginRequest).Return(loginResponse, nil)
 
 	authController := controller.NewAuthenticationController(log, mockAuthUseCase)
 
@@ -114,7 +115,7 @@ func TestLogin_ShouldReturnError_WhenInvalidCredential(t *testing.T) {
 
 	log := logrus.New()
 	mockAuthUseCase := new(helper.MockAuthUseCase)
-	mockAuthUseCase.On("Login", loginRequest).Return(model.LoginResponse{}, errors.New("invalid credential"))
+	mockAuthUseCase.On("Login", mock.Anything, loginRequest).Return(model.LoginResponse{}, errors.New("invalid credential"))
 
 	authController := controller.NewAuthenticationController(log, mockAuthUseCase)
 
@@ -152,12 +153,12 @@ func TestLogout_ShouldReturnSuccess_WhenTokenIsValid(t *testing.T) {
 	mockAuthUseCase := new(helper.MockAuthUseCase)
 
 	authController := controller.NewAuthenticationController(log, mockAuthUseCase)
-	mockAuthUseCase.On("IsTokenBlacklisted", token).Return(false, nil)
-	mockAuthUseCase.On("AddToBlacklist", token).Return(nil)
-	mockAuthUseCase.On("Logout", token).Return(nil)
+	mockAuthUseCase.On("IsTokenBlacklisted", mock.Anything, token).Return(false, nil)
+	mockAuthUseCase.On("AddToBlacklist", mock.Anything, token).Return(nil)
+	mockAuthUseCase.On("Logout", mock.Anything, token).Return(nil)
 
 	r := gin.Default()
-	r.Use(middleware.AuthenticationMiddleware(mockAuthUseCase))
+	r.Use(middleware.AuthenticationMiddleware(logrus.New(), mockAuthUseCase))
 	r.POST("/logout", authController.Logout)
 
 	req := httptest.NewRequest("POST", "/logout", nil)
@@ -218,12 +219,12 @@ func TestLogout_ShouldReturnError_WhenErrorLogout(t *testing.T) {
 	mockAuthUseCase := new(helper.MockAuthUseCase)
 
 	authController := controller.NewAuthenticationController(log, mockAuthUseCase)
-	mockAuthUseCase.On("IsTokenBlacklisted", token).Return(false, nil)
-	mockAuthUseCase.On("AddToBlacklist", token).Return(nil)
-	mockAuthUseCase.On("Logout", token).Return(errors.New("error on log out"))
+	mockAuthUseCase.On("IsTokenBlacklisted", mock.Anything, token).Return(false, nil)
+	mockAuthUseCase.On("AddToBlacklist", mock.Anything, token).Return(nil)
+	mockAuthUseCase.On("Logout", mock.Anything, token).Return(errors.New("error on log out"))
 
 	r := gin.Default()
-	r.Use(middleware.AuthenticationMiddleware(mockAuthUseCase))
+	r.Use(middleware.AuthenticationMiddleware(logrus.New(), mockAuthUseCase))
 	r.POST("/logout", authController.Logout)
 
 	req := httptest.NewRequest("POST", "/logout", nil)
@@ -0,0 +1,119 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merchant_bank_payment_go_api/internal/delivery/http/controller"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/test/helper"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetCustomers_ShouldPassQuery(t *testing.T) {
+	query := model.CustomerQueryRequest{Search: "bu", Status: "DISABLED", Page: 2, PageSize: 5}
+	customerPage := model.CustomerPage{Items: []model.CustomerResponse{{Id: uuid.New().String(), Username: "budi",
+		Role: "CUSTOMER", Status: "DISABLED"}}, Page: 2, PageSize: 5, TotalItems: 6, TotalPages: 2}
+
+	mockCustomerUseCase := new(helper.MockCustomerUseCase)
+	mockCustomerUseCase.On("GetCustomers", mock.Anything, query).Return(customerPage, nil)
+
+	log := logrus.New()
+	customerController := controller.NewCustomerController(log, mockCustomerUseCase)
+
+	r := gin.Default()
+	r.GET("/admin/customers", customerController.GetCustomers)
+
+	req := httptest.NewRequest("GET", "/admin/customers?search=bu&status=DISABLED&page=2&pageSize=5", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	response := new(model.CommonResponse[model.CustomerPage])
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Nil(t, err)
+	assert.Equal(t, customerPage, response.Data)
+}
+
+func TestGetCustomerDetail_ShouldReturnNotFound_WhenUseCaseFails(t *testing.T) {
+	customerId := uuid.New().String()
+	mockCustomerUseCase := new(helper.MockCustomerUseCase)
+	mockCustomerUseCase.On("GetCustomerDetail", mock.Anything, "", customerId).
+		Return(model.CustomerDetailResponse{}, errors.New("customer not found"))
+
+	log := logrus.New()
+	customerController := controller.NewCustomerController(log, mockCustomerUseCase)
+
+	r := gin.Default()
+	r.GET("/admin/customers/:id", customerController.GetCustomerDetail)
+
+	req := httptest.NewRequest("GET", "/admin/customers/"+customerId, nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestForceLogout_ShouldReturnCustomer(t *testing.T) {
+	adminId := uuid.New().String()
+	customerId := uuid.New().String()
+	customer := model.CustomerResponse{Id: customerId, Username: "budi", Role: "CUSTOMER", Status: "ACTIVE"}
+
+	mockCustomerUseCase := new(helper.MockCustomerUseCase)
+	mockCustomerUseCase.On("ForceLogout", mock.Anything, adminId, customerId).Return(customer, nil)
+
+	log := logrus.New()
+	customerController := controller.NewCustomerController(log, mockCustomerUseCase)
+
+	r := gin.Default()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", adminId)
+	})
+	r.POST("/admin/customers/:id/logout", customerController.ForceLogout)
+
+	req := httptest.NewRequest("POST", "/admin/customers/"+customerId+"/logout", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	response := new(model.CommonResponse[model.CustomerResponse])
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Nil(t, err)
+	assert.Equal(t, customer, response.Data)
+}
+
+func TestDisableCustomer_ShouldReturnBadRequest_WhenUseCaseFails(t *testing.T) {
+	customerId := uuid.New().String()
+	mockCustomerUseCase := new(helper.MockCustomerUseCase)
+	mockCustomerUseCase.On("DisableCustomer", mock.Anything, "", customerId).
+		Return(model.CustomerResponse{}, errors.New("customer "+customerId+" is already disabled"))
+
+	log := logrus.New()
+	customerController := controller.NewCustomerController(log, mockCustomerUseCase)
+
+	r := gin.Default()
+	r.POST("/admin/customers/:id/disable", customerController.DisableCustomer)
+
+	req := httptest.NewRequest("POST", "/admin/customers/"+customerId+"/disable", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	response := new(model.CommonResponse[interface{}])
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Nil(t, err)
+	assert.Contains(t, response.Message, "already disabled")
+}
@@ -0,0 +1,73 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merchant_bank_payment_go_api/internal/delivery/http/controller"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/test/helper"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReplaceRates_ShouldPublishRates(t *testing.T) {
+	adminId := uuid.New().String()
+	request := model.ExchangeRateRequest{Rates: []model.ExchangeRateEntry{{Base: "USD", Quote: "IDR", Rate: 15850}}}
+	rates := []model.ExchangeRateResponse{{Base: "USD", Quote: "IDR", Rate: 15850, Timestamp: time.Now().UTC()}}
+
+	mockExchangeRateUseCase := new(helper.MockExchangeRateUseCase)
+	mockExchangeRateUseCase.On("ReplaceRates", mock.Anything, adminId, request).Return(rates, nil)
+
+	log := logrus.New()
+	exchangeRateController := controller.NewExchangeRateController(log, mockExchangeRateUseCase)
+
+	r := gin.Default()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", adminId)
+	})
+	r.PUT("/admin/exchange-rates", exchangeRateController.ReplaceRates)
+
+	req := httptest.NewRequest("PUT", "/admin/exchange-rates", strings.NewReader(`{"rates":[{"base":"USD","quote":"IDR","rate":15850}]}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	response := new(model.CommonResponse[[]model.ExchangeRateResponse])
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "Successfully replaced exchange rates", response.Message)
+	assert.Equal(t, rates, response.Data)
+	mockExchangeRateUseCase.AssertExpectations(t)
+}
+
+func TestReplaceRates_ShouldReturnError_WhenRateMissing(t *testing.T) {
+	mockExchangeRateUseCase := new(helper.MockExchangeRateUseCase)
+
+	log := logrus.New()
+	exchangeRateController := controller.NewExchangeRateController(log, mockExchangeRateUseCase)
+
+	r := gin.Default()
+	r.PUT("/admin/exchange-rates", exchangeRateController.ReplaceRates)
+
+	req := httptest.NewRequest("PUT", "/admin/exchange-rates", strings.NewReader(`{"rates":[{"base":"USD","quote":"IDR"}]}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockExchangeRateUseCase.AssertNotCalled(t, "ReplaceRates", mock.Anything, mock.Anything, mock.Anything)
+}
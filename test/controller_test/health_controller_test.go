@@ -0,0 +1,92 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merchant_bank_payment_go_api/internal/delivery/http/controller"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/internal/usecase/impl"
+	"merchant_bank_payment_go_api/test/helper"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHealthz_ShouldReturnOk(t *testing.T) {
+	mockHealthUseCase := new(helper.MockHealthUseCase)
+	healthController := controller.NewHealthController(logrus.New(), mockHealthUseCase)
+
+	r := gin.Default()
+	r.GET("/healthz", healthController.Healthz)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/healthz", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockHealthUseCase.AssertNotCalled(t, "CheckReadiness", mock.Anything)
+}
+
+func TestReadyz_ShouldReturnOk_WhenReady(t *testing.T) {
+	readiness := model.ReadinessResponse{Status: impl.HealthStatusUp,
+		Checks: []model.ReadinessCheckResponse{{Name: "jwt_key", Status: impl.HealthStatusUp}}}
+
+	mockHealthUseCase := new(helper.MockHealthUseCase)
+	mockHealthUseCase.On("CheckReadiness", mock.Anything).Return(readiness)
+	healthController := controller.NewHealthController(logrus.New(), mockHealthUseCase)
+
+	r := gin.Default()
+	r.GET("/readyz", healthController.Readyz)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/readyz", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	response := new(model.CommonResponse[model.ReadinessResponse])
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, readiness, response.Data)
+}
+
+func TestReadyz_ShouldReturnServiceUnavailable_WhenNotReady(t *testing.T) {
+	readiness := model.ReadinessResponse{Status: impl.HealthStatusDown,
+		Checks: []model.ReadinessCheckResponse{{Name: "customer", Status: impl.HealthStatusDown, Error: "error opening file"}}}
+
+	mockHealthUseCase := new(helper.MockHealthUseCase)
+	mockHealthUseCase.On("CheckReadiness", mock.Anything).Return(readiness)
+	healthController := controller.NewHealthController(logrus.New(), mockHealthUseCase)
+
+	r := gin.Default()
+	r.GET("/readyz", healthController.Readyz)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/readyz", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	response := new(model.CommonResponse[model.ReadinessResponse])
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Service is not ready", response.Message)
+	assert.Equal(t, readiness, response.Data)
+}
+
+func TestVersion_ShouldReturnBuildInfo(t *testing.T) {
+	version := model.VersionResponse{Version: "v1.4.0", Commit: "0123abc", GoVersion: "go1.22.2",
+		StartedAt: time.Date(2024, 8, 23, 0, 0, 0, 0, time.UTC), UptimeSeconds: 42}
+
+	mockHealthUseCase := new(helper.MockHealthUseCase)
+	mockHealthUseCase.On("GetVersion").Return(version)
+	healthController := controller.NewHealthController(logrus.New(), mockHealthUseCase)
+
+	r := gin.Default()
+	r.GET("/version", healthController.Version)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/version", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	response := new(model.CommonResponse[model.VersionResponse])
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, version, response.Data)
+}
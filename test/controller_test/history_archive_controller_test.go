@@ -0,0 +1,67 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merchant_bank_payment_go_api/internal/delivery/http/controller"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/test/helper"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestArchiveHistory_ShouldPassRetentionFromQuery(t *testing.T) {
+	adminId := uuid.New().String()
+	archive := &entity.HistoryArchive{Id: uuid.New(), Filename: "history-20240801-20240823-1a2b3c4d.json.gz", Records: 12}
+
+	mockHistoryArchiveUseCase := new(helper.MockHistoryArchiveUseCase)
+	mockHistoryArchiveUseCase.On("ArchiveNow", mock.Anything, adminId, 30*24*time.Hour).Return(archive, nil)
+
+	log := logrus.New()
+	historyArchiveController := controller.NewHistoryArchiveController(log, mockHistoryArchiveUseCase)
+
+	r := gin.Default()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", adminId)
+	})
+	r.POST("/admin/history/archive", historyArchiveController.ArchiveHistory)
+
+	req := httptest.NewRequest("POST", "/admin/history/archive?olderThanDays=30", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	response := new(model.CommonResponse[model.HistoryArchiveResponse])
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Nil(t, err)
+	assert.Equal(t, archive.Id.String(), response.Data.Id)
+	assert.Equal(t, 12, response.Data.Records)
+	mockHistoryArchiveUseCase.AssertExpectations(t)
+}
+
+func TestArchiveHistory_ShouldReturnError_WhenRetentionNegative(t *testing.T) {
+	mockHistoryArchiveUseCase := new(helper.MockHistoryArchiveUseCase)
+
+	log := logrus.New()
+	historyArchiveController := controller.NewHistoryArchiveController(log, mockHistoryArchiveUseCase)
+
+	r := gin.Default()
+	r.POST("/admin/history/archive", historyArchiveController.ArchiveHistory)
+
+	req := httptest.NewRequest("POST", "/admin/history/archive?olderThanDays=-1", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockHistoryArchiveUseCase.AssertNotCalled(t, "ArchiveNow", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,116 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merchant_bank_payment_go_api/internal/delivery/http/controller"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/test/helper"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newHistoryRouter(userId string, historyController *controller.HistoryController) *gin.Engine {
+	r := gin.Default()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", userId)
+	})
+	r.GET("/history", historyController.GetMyHistory)
+	r.GET("/admin/history", historyController.SearchHistory)
+	return r
+}
+
+func TestGetMyHistory_ShouldReturnCustomerPage(t *testing.T) {
+	customerId := uuid.New().String()
+	page := model.HistoryPage{
+		Items:      []model.HistoryResponse{{Id: uuid.New().String(), Action: "AUTH_LOGIN", CustomerId: customerId, Outcome: "SUCCESS"}},
+		Page:       2,
+		PageSize:   1,
+		TotalItems: 3,
+		TotalPages: 3,
+	}
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("GetCustomerHistory", mock.Anything, customerId, model.HistoryQueryRequest{Action: "AUTH_LOGIN", Page: 2, PageSize: 1}).
+		Return(page, nil)
+
+	historyController := controller.NewHistoryController(logrus.New(), mockHistoryUseCase)
+	r := newHistoryRouter(customerId, historyController)
+
+	req := httptest.NewRequest("GET", "/history?action=AUTH_LOGIN&page=2&pageSize=1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	response := new(model.CommonResponse[model.HistoryPage])
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Nil(t, err)
+	assert.Equal(t, page, response.Data)
+	mockHistoryUseCase.AssertExpectations(t)
+}
+
+func TestGetMyHistory_ShouldReturnBadRequest_WhenQueryInvalid(t *testing.T) {
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+
+	historyController := controller.NewHistoryController(logrus.New(), mockHistoryUseCase)
+	r := newHistoryRouter(uuid.New().String(), historyController)
+
+	req := httptest.NewRequest("GET", "/history?page=abc", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockHistoryUseCase.AssertNotCalled(t, "GetCustomerHistory", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSearchHistory_ShouldReturnBadRequest_WhenFilterInvalid(t *testing.T) {
+	adminId := uuid.New().String()
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("SearchHistory", mock.Anything, adminId, model.HistoryQueryRequest{Outcome: "MAYBE"}).
+		Return(model.HistoryPage{}, errors.New("outcome must be SUCCESS or FAILURE"))
+
+	historyController := controller.NewHistoryController(logrus.New(), mockHistoryUseCase)
+	r := newHistoryRouter(adminId, historyController)
+
+	req := httptest.NewRequest("GET", "/admin/history?outcome=MAYBE", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	response := new(model.CommonResponse[interface{}])
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Nil(t, err)
+	assert.Equal(t, "outcome must be SUCCESS or FAILURE", response.Message)
+}
+
+func TestSearchHistory_ShouldStreamCsv_WhenFormatCsv(t *testing.T) {
+	adminId := uuid.New().String()
+	customerId := uuid.New().String()
+	csvContent := "id,sequence,timestamp\n"
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("ExportHistory", mock.Anything, adminId, model.HistoryQueryRequest{CustomerId: customerId, Format: "csv"}, mock.Anything).
+		Return(csvContent, nil)
+
+	historyController := controller.NewHistoryController(logrus.New(), mockHistoryUseCase)
+	r := newHistoryRouter(adminId, historyController)
+
+	req := httptest.NewRequest("GET", "/admin/history?customerId="+customerId+"&format=csv", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "history.csv")
+	assert.Equal(t, csvContent, w.Body.String())
+	mockHistoryUseCase.AssertNotCalled(t, "SearchHistory", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,123 @@
+package controller_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merchant_bank_payment_go_api/internal/delivery/http/controller"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/test/helper"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetMerchants_ShouldPassQuery(t *testing.T) {
+	query := model.MerchantQueryRequest{Search: "toko", Status: "SUSPENDED", Page: 2, PageSize: 5}
+	merchantPage := model.MerchantPage{Items: []model.MerchantResponse{{Id: uuid.New().String(), Name: "toko harapan",
+		AcceptedCurrencies: []string{"IDR"}, Status: "SUSPENDED"}}, Page: 2, PageSize: 5, TotalItems: 6, TotalPages: 2}
+
+	mockMerchantUseCase := new(helper.MockMerchantUseCase)
+	mockMerchantUseCase.On("GetMerchants", mock.Anything, query).Return(merchantPage, nil)
+
+	log := logrus.New()
+	merchantController := controller.NewMerchantController(log, mockMerchantUseCase)
+
+	r := gin.Default()
+	r.GET("/admin/merchants", merchantController.GetMerchants)
+
+	req := httptest.NewRequest("GET", "/admin/merchants?search=toko&status=SUSPENDED&page=2&pageSize=5", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	response := new(model.CommonResponse[model.MerchantPage])
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Nil(t, err)
+	assert.Equal(t, merchantPage, response.Data)
+}
+
+func TestCreateMerchant_ShouldReturnCreated(t *testing.T) {
+	adminId := uuid.New().String()
+	request := model.MerchantRequest{Name: "toko baru", AcceptedCurrencies: []string{"IDR"},
+		FeePlan: model.FeePlan{Type: "FIXED", FixedAmount: 500}}
+	merchant := model.MerchantResponse{Id: uuid.New().String(), Name: "toko baru", AcceptedCurrencies: []string{"IDR"},
+		FeePlan: request.FeePlan, Status: "ACTIVE"}
+
+	mockMerchantUseCase := new(helper.MockMerchantUseCase)
+	mockMerchantUseCase.On("CreateMerchant", mock.Anything, adminId, request).Return(merchant, nil)
+
+	log := logrus.New()
+	merchantController := controller.NewMerchantController(log, mockMerchantUseCase)
+
+	r := gin.Default()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", adminId)
+	})
+	r.POST("/admin/merchants", merchantController.CreateMerchant)
+
+	body, _ := json.Marshal(request)
+	req := httptest.NewRequest("POST", "/admin/merchants", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	response := new(model.CommonResponse[model.MerchantResponse])
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Nil(t, err)
+	assert.Equal(t, merchant, response.Data)
+}
+
+func TestCreateMerchant_ShouldReturnBadRequest_WhenNameMissing(t *testing.T) {
+	mockMerchantUseCase := new(helper.MockMerchantUseCase)
+
+	log := logrus.New()
+	merchantController := controller.NewMerchantController(log, mockMerchantUseCase)
+
+	r := gin.Default()
+	r.POST("/admin/merchants", merchantController.CreateMerchant)
+
+	req := httptest.NewRequest("POST", "/admin/merchants", bytes.NewReader([]byte(`{"acceptedCurrencies":["IDR"]}`)))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockMerchantUseCase.AssertNotCalled(t, "CreateMerchant", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSuspendMerchant_ShouldReturnBadRequest_WhenUseCaseFails(t *testing.T) {
+	merchantId := uuid.New().String()
+	mockMerchantUseCase := new(helper.MockMerchantUseCase)
+	mockMerchantUseCase.On("SuspendMerchant", mock.Anything, "", merchantId).
+		Return(model.MerchantResponse{}, errors.New("merchant "+merchantId+" is already suspended"))
+
+	log := logrus.New()
+	merchantController := controller.NewMerchantController(log, mockMerchantUseCase)
+
+	r := gin.Default()
+	r.POST("/admin/merchants/:id/suspend", merchantController.SuspendMerchant)
+
+	req := httptest.NewRequest("POST", "/admin/merchants/"+merchantId+"/suspend", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	response := new(model.CommonResponse[interface{}])
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Nil(t, err)
+	assert.Contains(t, response.Message, "already suspended")
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"merchant_bank_payment_go_api/internal/delivery/http/controller"
 	"merchant_bank_payment_go_api/internal/delivery/http/middleware"
 	"merchant_bank_payment_go_api/internal/model"
@@ -37,18 +38,18 @@ func TestAddPayment_ShouldReturnSuccess(t *testing.T) {
 	assert.Nil(t, err)
 
 	mockPaymentTransactionUseCase := new(helper.MockPaymentTransactionUseCase)
-	mockPaymentTransactionUseCase.On("AddPayment", customerId.String(), paymentRequest).Return(nil)
+	mockPaymentTransactionUseCase.On("AddPayment", mock.Anything, customerId.String(), paymentRequest).Return(nil)
 
 	mockAuthUseCase := new(helper.MockAuthUseCase)
-	mockAuthUseCase.On("IsTokenBlacklisted", token).Return(false, nil)
-	mockAuthUseCase.On("AddToBlacklist", token).Return(nil)
-	mockAuthUseCase.On("Logout", token).Return(nil)
+	mockAuthUseCase.On("IsTokenBlacklisted", mock.Anything, token).Return(false, nil)
+	mockAuthUseCase.On("AddToBlacklist", mock.Anything, token).Return(nil)
+	mockAuthUseCase.On("Logout", mock.Anything, token).Return(nil)
 
 	log := logrus.New()
 	paymentController := controller.NewPaymentTransactionController(log, mockPaymentTransactionUseCase)
 
 	r := gin.Default()
-	r.Use(middleware.AuthenticationMiddleware(mockAuthUseCase))
+	r.Use(middleware.AuthenticationMiddleware(logrus.New(), mockAuthUseCase))
 	r.POST("/payment", paymentController.AddPayment)
 
 	req := httptest.NewRequest("POST", "/payment", strings.NewReader(string(bodyJson)))
@@ -127,12 +128,12 @@ func TestAddPayment_ShouldReturnError_WhenNotUserIdOnContext(t *testing.T) {
 	assert.Nil(t, err)
 
 	mockPaymentTransactionUseCase := new(helper.MockPaymentTransactionUseCase)
-	mockPaymentTransactionUseCase.On("AddPayment", customerId.String(), paymentRequest).Return(nil)
+	mockPaymentTransactionUseCase.On("AddPayment", mock.Anything, customerId.String(), paymentRequest).Return(nil)
 
 	mockAuthUseCase := new(helper.MockAuthUseCase)
-	mockAuthUseCase.On("IsTokenBlacklisted", token).Return(false, nil)
-	mockAuthUseCase.On("AddToBlacklist", token).Return(nil)
-	mockAuthUseCase.On("Logout", token).Return(nil)
+	mockAuthUseCase.On("IsTokenBlacklisted", mock.Anything, token).Return(false, nil)
+	mockAuthUseCase.On("AddToBlacklist", mock.Anything, token).Return(nil)
+	mockAuthUseCase.On("Logout", mock.Anything, token).Return(nil)
 
 	log := logrus.New()
 	paymentController := controller.NewPaymentTransactionController(log, mockPaymentTransactionUseCase)
@@ -176,18 +177,18 @@ func TestAddPayment_ShouldReturnError_WhenInvalidMerchantId(t *testing.T) {
 	assert.Nil(t, err)
 
 	mockPaymentTransactionUseCase := new(helper.MockPaymentTransactionUseCase)
-	mockPaymentTransactionUseCase.On("AddPayment", customerId.String(), paymentRequest).Return(errors.New("invalid merchant id"))
+	mockPaymentTransactionUseCase.On("AddPayment", mock.Anything, customerId.String(), paymentRequest).Return(errors.New("invalid merchant id"))
 
 	mockAuthUseCase := new(helper.MockAuthUseCase)
-	mockAuthUseCase.On("IsTokenBlacklisted", token).Return(false, nil)
-	mockAuthUseCase.On("AddToBlacklist", token).Return(nil)
-	mockAuthUseCase.On("Logout", token).Return(nil)
+	mockAuthUseCase.On("IsTokenBlacklisted", mock.Anything, token).Return(false, nil)
+	mockAuthUseCase.On("AddToBlacklist", mock.Anything, token).Return(nil)
+	mockAuthUseCase.On("Logout", mock.Anything, token).Return(nil)
 
 	log := logrus.New()
 	paymentController := controller.NewPaymentTransactionController(log, mockPaymentTransactionUseCase)
 
 	r := gin.Default()
-	r.Use(middleware.AuthenticationMiddleware(mockAuthUseCase))
+	r.Use(middleware.AuthenticationMiddleware(logrus.New(), mockAuthUseCase))
 	r.POST("/payment", paymentController.AddPayment)
 
 	req := httptest.NewRequest("POST", "/payment", strings.NewReader(string(bodyJson)))
@@ -208,3 +209,86 @@ func TestAddPayment_ShouldReturnError_WhenInvalidMerchantId(t *testing.T) {
 	assert.Equal(t, expectedCommonResponse.Message, response.Message)
 	assert.Equal(t, expectedCommonResponse.HttpStatus, response.HttpStatus)
 }
+
+func TestGetPayments_ShouldReturnSuccess(t *testing.T) {
+	utils.InitJwtConfig([]byte("abc"), 10)
+
+	customerId := uuid.New()
+	token, _ := utils.GenerateAccessToken(customerId.String())
+	payments := []model.PaymentResponse{{
+		Id:         uuid.New().String(),
+		CustomerId: customerId.String(),
+		MerchantId: uuid.New().String(),
+		Amount:     15000,
+		Currency:   "IDR",
+		FeeAmount:  105,
+		NetAmount:  14895,
+	}}
+
+	mockPaymentTransactionUseCase := new(helper.MockPaymentTransactionUseCase)
+	mockPaymentTransactionUseCase.On("GetPayments", mock.Anything, customerId.String()).Return(payments, nil)
+
+	mockAuthUseCase := new(helper.MockAuthUseCase)
+	mockAuthUseCase.On("IsTokenBlacklisted", mock.Anything, token).Return(false, nil)
+
+	log := logrus.New()
+	paymentController := controller.NewPaymentTransactionController(log, mockPaymentTransactionUseCase)
+
+	r := gin.Default()
+	r.Use(middleware.AuthenticationMiddleware(logrus.New(), mockAuthUseCase))
+	r.GET("/payment", paymentController.GetPayments)
+
+	req := httptest.NewRequest("GET", "/payment", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	response := new(model.CommonResponse[[]model.PaymentResponse])
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "Successfully got payments", response.Message)
+	assert.Equal(t, payments[0].FeeAmount, response.Data[0].FeeAmount)
+	assert.Equal(t, payments[0].NetAmount, response.Data[0].NetAmount)
+}
+
+func TestGetPaymentById_ShouldReturnNotFound_WhenUseCaseFails(t *testing.T) {
+	utils.InitJwtConfig([]byte("abc"), 10)
+
+	customerId := uuid.New()
+	paymentId := uuid.New()
+	token, _ := utils.GenerateAccessToken(customerId.String())
+
+	mockPaymentTransactionUseCase := new(helper.MockPaymentTransactionUseCase)
+	mockPaymentTransactionUseCase.On("GetPaymentById", mock.Anything, customerId.String(), paymentId.String()).
+		Return(model.PaymentResponse{}, errors.New("payment not found"))
+
+	mockAuthUseCase := new(helper.MockAuthUseCase)
+	mockAuthUseCase.On("IsTokenBlacklisted", mock.Anything, token).Return(false, nil)
+
+	log := logrus.New()
+	paymentController := controller.NewPaymentTransactionController(log, mockPaymentTransactionUseCase)
+
+	r := gin.Default()
+	r.Use(middleware.AuthenticationMiddleware(logrus.New(), mockAuthUseCase))
+	r.GET("/payment/:id", paymentController.GetPaymentById)
+
+	req := httptest.NewRequest("GET", "/payment/"+paymentId.String(), nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	response := new(model.CommonResponse[interface{}])
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "Payment not found", response.Message)
+}
@@ -0,0 +1,85 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merchant_bank_payment_go_api/internal/delivery/http/controller"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/test/helper"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExportReconciliation_ShouldStreamCsvAttachment(t *testing.T) {
+	merchantId := helper.MerchantId.String()
+	request := model.ReconciliationReportRequest{MerchantId: merchantId, From: "2024-11-01", To: "2024-11-30"}
+
+	mockReportUseCase := new(helper.MockReportUseCase)
+	mockReportUseCase.On("ExportReconciliation", mock.Anything, request, mock.Anything).Return("type,payment_id\n", nil)
+
+	log := logrus.New()
+	reportController := controller.NewReportController(log, mockReportUseCase)
+
+	r := gin.Default()
+	r.GET("/admin/reports/reconciliation", reportController.ExportReconciliation)
+
+	req := httptest.NewRequest("GET", "/admin/reports/reconciliation?merchantId="+merchantId+"&from=2024-11-01&to=2024-11-30", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "reconciliation_"+merchantId+"_2024-11-01_2024-11-30.csv")
+	assert.Equal(t, "type,payment_id\n", w.Body.String())
+	mockReportUseCase.AssertExpectations(t)
+}
+
+func TestExportReconciliation_ShouldReturnBadRequest_WhenMissingQuery(t *testing.T) {
+	mockReportUseCase := new(helper.MockReportUseCase)
+
+	log := logrus.New()
+	reportController := controller.NewReportController(log, mockReportUseCase)
+
+	r := gin.Default()
+	r.GET("/admin/reports/reconciliation", reportController.ExportReconciliation)
+
+	req := httptest.NewRequest("GET", "/admin/reports/reconciliation?from=2024-11-01", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockReportUseCase.AssertNotCalled(t, "ExportReconciliation", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestExportReconciliation_ShouldReturnJsonError_WhenUseCaseFailsBeforeWriting(t *testing.T) {
+	merchantId := helper.MerchantId.String()
+
+	mockReportUseCase := new(helper.MockReportUseCase)
+	mockReportUseCase.On("ExportReconciliation", mock.Anything, mock.Anything, mock.Anything).Return("", errors.New("invalid to date"))
+
+	log := logrus.New()
+	reportController := controller.NewReportController(log, mockReportUseCase)
+
+	r := gin.Default()
+	r.GET("/admin/reports/reconciliation", reportController.ExportReconciliation)
+
+	req := httptest.NewRequest("GET", "/admin/reports/reconciliation?merchantId="+merchantId+"&from=2024-11-01&to=nope&format=json", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, w.Header().Get("Content-Disposition"))
+
+	response := new(model.CommonResponse[interface{}])
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Nil(t, err)
+	assert.Equal(t, "invalid to date", response.Message)
+}
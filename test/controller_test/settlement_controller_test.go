@@ -0,0 +1,123 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merchant_bank_payment_go_api/internal/delivery/http/controller"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/test/helper"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunSettlement_ShouldUseCutoffFromBody(t *testing.T) {
+	adminId := uuid.New().String()
+	cutoff := time.Date(2024, 11, 26, 17, 0, 0, 0, time.UTC)
+	settlements := []model.SettlementResponse{{Id: uuid.New().String(), PaymentCount: 2, NetAmount: 29700}}
+
+	mockSettlementUseCase := new(helper.MockSettlementUseCase)
+	mockSettlementUseCase.On("RunSettlement", mock.Anything, adminId, mock.MatchedBy(func(value time.Time) bool {
+		return value.Equal(cutoff)
+	})).Return(settlements, nil)
+
+	log := logrus.New()
+	settlementController := controller.NewSettlementController(log, mockSettlementUseCase)
+
+	r := gin.Default()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", adminId)
+	})
+	r.POST("/admin/settlements", settlementController.RunSettlement)
+
+	req := httptest.NewRequest("POST", "/admin/settlements", strings.NewReader(`{"cutoff":"2024-11-26T17:00:00Z"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	response := new(model.CommonResponse[[]model.SettlementResponse])
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "Successfully ran settlement", response.Message)
+	assert.Equal(t, settlements, response.Data)
+	mockSettlementUseCase.AssertExpectations(t)
+}
+
+func TestRunSettlement_ShouldReturnError_WhenInvalidBody(t *testing.T) {
+	mockSettlementUseCase := new(helper.MockSettlementUseCase)
+
+	log := logrus.New()
+	settlementController := controller.NewSettlementController(log, mockSettlementUseCase)
+
+	r := gin.Default()
+	r.POST("/admin/settlements", settlementController.RunSettlement)
+
+	req := httptest.NewRequest("POST", "/admin/settlements", strings.NewReader(`{"cutoff":"tomorrow"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSettlementUseCase.AssertNotCalled(t, "RunSettlement", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetSettlements_ShouldPassMerchantFilter(t *testing.T) {
+	merchantId := uuid.New().String()
+	settlements := []model.SettlementResponse{{Id: uuid.New().String(), MerchantId: merchantId}}
+
+	mockSettlementUseCase := new(helper.MockSettlementUseCase)
+	mockSettlementUseCase.On("GetSettlements", mock.Anything, merchantId).Return(settlements, nil)
+
+	log := logrus.New()
+	settlementController := controller.NewSettlementController(log, mockSettlementUseCase)
+
+	r := gin.Default()
+	r.GET("/admin/settlements", settlementController.GetSettlements)
+
+	req := httptest.NewRequest("GET", "/admin/settlements?merchantId="+merchantId, nil)
+
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	response := new(model.CommonResponse[[]model.SettlementResponse])
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Nil(t, err)
+	assert.Equal(t, settlements, response.Data)
+}
+
+func TestGetSettlementById_ShouldReturnNotFound(t *testing.T) {
+	settlementId := uuid.New().String()
+
+	mockSettlementUseCase := new(helper.MockSettlementUseCase)
+	mockSettlementUseCase.On("GetSettlementById", mock.Anything, settlementId).Return(model.SettlementResponse{}, assert.AnError)
+
+	log := logrus.New()
+	settlementController := controller.NewSettlementController(log, mockSettlementUseCase)
+
+	r := gin.Default()
+	r.GET("/admin/settlements/:id", settlementController.GetSettlementById)
+
+	req := httptest.NewRequest("GET", "/admin/settlements/"+settlementId, nil)
+
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
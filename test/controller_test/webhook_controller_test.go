@@ -0,0 +1,115 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merchant_bank_payment_go_api/internal/delivery/http/controller"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/internal/usecase"
+	"merchant_bank_payment_go_api/test/helper"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetWebhookDeliveries_ShouldPassFilters(t *testing.T) {
+	merchantId := uuid.New().String()
+	deliveries := []model.WebhookDeliveryResponse{{Id: uuid.New().String(), MerchantId: merchantId, Status: "DEAD_LETTER",
+		Payload: []byte(`{"type":"payment.captured"}`)}}
+
+	mockWebhookUseCase := new(helper.MockWebhookUseCase)
+	mockWebhookUseCase.On("GetDeliveries", mock.Anything, "DEAD_LETTER", merchantId).Return(deliveries, nil)
+
+	log := logrus.New()
+	webhookController := controller.NewWebhookController(log, mockWebhookUseCase)
+
+	r := gin.Default()
+	r.GET("/admin/webhooks", webhookController.GetDeliveries)
+
+	req := httptest.NewRequest("GET", "/admin/webhooks?status=DEAD_LETTER&merchantId="+merchantId, nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	response := new(model.CommonResponse[[]model.WebhookDeliveryResponse])
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Nil(t, err)
+	assert.Equal(t, deliveries, response.Data)
+}
+
+func TestRedeliverWebhook_ShouldReturnDelivery(t *testing.T) {
+	adminId := uuid.New().String()
+	deliveryId := uuid.New().String()
+	delivery := model.WebhookDeliveryResponse{Id: deliveryId, Status: "DELIVERED", Attempts: 1, Payload: []byte(`{}`)}
+
+	mockWebhookUseCase := new(helper.MockWebhookUseCase)
+	mockWebhookUseCase.On("Redeliver", mock.Anything, adminId, deliveryId).Return(delivery, nil)
+
+	log := logrus.New()
+	webhookController := controller.NewWebhookController(log, mockWebhookUseCase)
+
+	r := gin.Default()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", adminId)
+	})
+	r.POST("/admin/webhooks/:id/redeliver", webhookController.Redeliver)
+
+	req := httptest.NewRequest("POST", "/admin/webhooks/"+deliveryId+"/redeliver", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	response := new(model.CommonResponse[model.WebhookDeliveryResponse])
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Nil(t, err)
+	assert.Equal(t, delivery, response.Data)
+}
+
+func TestRedeliverWebhook_ShouldReturnConflict_WhenDeliveryBeingSent(t *testing.T) {
+	deliveryId := uuid.New().String()
+
+	mockWebhookUseCase := new(helper.MockWebhookUseCase)
+	mockWebhookUseCase.On("Redeliver", mock.Anything, mock.Anything, deliveryId).
+		Return(model.WebhookDeliveryResponse{}, fmt.Errorf("%w: %s", usecase.ErrWebhookDeliveryInFlight, deliveryId))
+
+	log := logrus.New()
+	webhookController := controller.NewWebhookController(log, mockWebhookUseCase)
+
+	r := gin.Default()
+	r.POST("/admin/webhooks/:id/redeliver", webhookController.Redeliver)
+
+	req := httptest.NewRequest("POST", "/admin/webhooks/"+deliveryId+"/redeliver", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestGetWebhookDeliveryById_ShouldReturnNotFound(t *testing.T) {
+	mockWebhookUseCase := new(helper.MockWebhookUseCase)
+	mockWebhookUseCase.On("GetDeliveryById", mock.Anything, "missing").Return(model.WebhookDeliveryResponse{}, errors.New("not found"))
+
+	log := logrus.New()
+	webhookController := controller.NewWebhookController(log, mockWebhookUseCase)
+
+	r := gin.Default()
+	r.GET("/admin/webhooks/:id", webhookController.GetDeliveryById)
+
+	req := httptest.NewRequest("GET", "/admin/webhooks/missing", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
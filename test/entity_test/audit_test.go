@@ -0,0 +1,45 @@
+package entity_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"merchant_bank_payment_go_api/internal/entity"
+	"testing"
+)
+
+func TestConvertLegacyAudit_ShouldMapKnownActions(t *testing.T) {
+	tests := []struct {
+		legacyAction string
+		customerId   string
+		details      string
+		action       entity.AuditAction
+		subjectType  entity.AuditSubjectType
+		outcome      entity.AuditOutcome
+		actorType    entity.AuditActorType
+	}{
+		{"LOGIN", "685729de-cd87-4524-80bc-9b19cf58df22", "Login successful", entity.AuditActionLogin, entity.AuditSubjectSession, entity.AuditOutcomeSuccess, entity.AuditActorCustomer},
+		{"LOGIN", "-", "Login failed because customer with username x not exists", entity.AuditActionLogin, entity.AuditSubjectSession, entity.AuditOutcomeFailure, entity.AuditActorAnonymous},
+		{"LOGOUT", "685729de-cd87-4524-80bc-9b19cf58df22", "Token blacklisted successfully", entity.AuditActionLogout, entity.AuditSubjectSession, entity.AuditOutcomeSuccess, entity.AuditActorCustomer},
+		{"PAYMENT", "685729de-cd87-4524-80bc-9b19cf58df22", "Payment failed: merchant not found", entity.AuditActionPaymentCreate, entity.AuditSubjectPayment, entity.AuditOutcomeFailure, entity.AuditActorCustomer},
+		{"Failed to parse UUID", "abc", "Error parsing merchant UUID", entity.AuditActionMerchantLookup, entity.AuditSubjectMerchant, entity.AuditOutcomeFailure, entity.AuditActorCustomer},
+		{"Successfully found merchant by id", "66e02583-71d2-4ae2-9d74-d5d9f9b9d618", "Merchant found successfully", entity.AuditActionMerchantLookup, entity.AuditSubjectMerchant, entity.AuditOutcomeSuccess, entity.AuditActorCustomer},
+	}
+
+	for _, test := range tests {
+		action, details := entity.ConvertLegacyAudit(test.legacyAction, test.customerId, test.details)
+
+		assert.Equal(t, test.action, action, test.legacyAction)
+		assert.Equal(t, test.subjectType, details.SubjectType, test.legacyAction)
+		assert.Equal(t, test.outcome, details.Outcome, test.details)
+		assert.Equal(t, test.actorType, details.ActorType, test.customerId)
+		assert.Equal(t, test.details, details.Message)
+		assert.True(t, details.Legacy)
+	}
+}
+
+func TestConvertLegacyAudit_ShouldKeepUnknownActionText(t *testing.T) {
+	action, details := entity.ConvertLegacyAudit("Something else", "budi", "happened")
+
+	assert.Equal(t, entity.AuditActionUnknown, action)
+	assert.Equal(t, "Something else: happened", details.Message)
+	assert.False(t, action.IsValid())
+}
@@ -0,0 +1,36 @@
+package entity_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"merchant_bank_payment_go_api/internal/entity"
+	"testing"
+)
+
+func TestRoundingModeRound_ShouldRoundHalves(t *testing.T) {
+	half := big.NewRat(5, 2)
+	oddHalf := big.NewRat(7, 2)
+
+	assert.Equal(t, int64(2), entity.RoundHalfEven.Round(half))
+	assert.Equal(t, int64(4), entity.RoundHalfEven.Round(oddHalf))
+	assert.Equal(t, int64(3), entity.RoundHalfUp.Round(half))
+	assert.Equal(t, int64(2), entity.RoundDown.Round(half))
+	assert.Equal(t, int64(3), entity.RoundUp.Round(half))
+}
+
+func TestRoundingModeRound_ShouldKeepWholeNumbers(t *testing.T) {
+	assert.Equal(t, int64(4), entity.RoundUp.Round(big.NewRat(8, 2)))
+}
+
+func TestParseRoundingMode_ShouldDefaultToHalfEven(t *testing.T) {
+	mode, err := entity.ParseRoundingMode("")
+
+	assert.Nil(t, err)
+	assert.Equal(t, entity.RoundHalfEven, mode)
+}
+
+func TestParseRoundingMode_ShouldReturnError_WhenUnknown(t *testing.T) {
+	_, err := entity.ParseRoundingMode("CEILING")
+
+	assert.NotNil(t, err)
+}
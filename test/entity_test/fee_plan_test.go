@@ -0,0 +1,126 @@
+package entity_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"merchant_bank_payment_go_api/internal/entity"
+	"testing"
+)
+
+func TestFeePlanCalculate_ShouldReturnZero_WhenNoPlan(t *testing.T) {
+	fee, err := entity.FeePlan{}.Calculate(15000)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), fee)
+}
+
+func TestFeePlanCalculate_ShouldApplyPercentageWithFixedPart(t *testing.T) {
+	plan := entity.FeePlan{Type: entity.FeeTypePercentage, PercentageBasisPoints: 150, FixedAmount: 500}
+
+	fee, err := plan.Calculate(15000)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(725), fee)
+}
+
+func TestFeePlanCalculate_ShouldRoundPercentageHalfUp(t *testing.T) {
+	plan := entity.FeePlan{Type: entity.FeeTypePercentage, PercentageBasisPoints: 70}
+
+	fee, err := plan.Calculate(1500)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(11), fee)
+}
+
+func TestFeePlanCalculate_ShouldApplyFixed(t *testing.T) {
+	plan := entity.FeePlan{Type: entity.FeeTypeFixed, FixedAmount: 2500}
+
+	fee, err := plan.Calculate(15000)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2500), fee)
+}
+
+func TestFeePlanCalculate_ShouldPickMatchingTier(t *testing.T) {
+	plan := entity.FeePlan{
+		Type: entity.FeeTypeTiered,
+		Tiers: []entity.FeeTier{
+			{UpTo: 100000, PercentageBasisPoints: 200},
+			{UpTo: 1000000, PercentageBasisPoints: 150},
+			{PercentageBasisPoints: 100},
+		},
+	}
+
+	smallFee, err := plan.Calculate(100000)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2000), smallFee)
+
+	mediumFee, err := plan.Calculate(500000)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(7500), mediumFee)
+
+	largeFee, err := plan.Calculate(2000000)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(20000), largeFee)
+}
+
+func TestFeePlanCalculate_ShouldReturnError_WhenNoTierMatches(t *testing.T) {
+	plan := entity.FeePlan{Type: entity.FeeTypeTiered, Tiers: []entity.FeeTier{{UpTo: 1000, PercentageBasisPoints: 100}}}
+
+	_, err := plan.Calculate(5000)
+
+	assert.NotNil(t, err)
+}
+
+func TestFeePlanCalculate_ShouldApplyCapAndMinimum(t *testing.T) {
+	plan := entity.FeePlan{Type: entity.FeeTypePercentage, PercentageBasisPoints: 100, MinFee: 500, MaxFee: 10000}
+
+	minimumFee, err := plan.Calculate(20000)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(500), minimumFee)
+
+	cappedFee, err := plan.Calculate(5000000)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(10000), cappedFee)
+}
+
+func TestFeePlanCalculate_ShouldReturnError_WhenFeeExceedsAmount(t *testing.T) {
+	plan := entity.FeePlan{Type: entity.FeeTypeFixed, FixedAmount: 2500}
+
+	_, err := plan.Calculate(1000)
+
+	assert.NotNil(t, err)
+}
+
+func TestFeePlanCalculate_ShouldReturnError_WhenUnknownType(t *testing.T) {
+	_, err := entity.FeePlan{Type: "FLAT"}.Calculate(1000)
+
+	assert.NotNil(t, err)
+}
+
+func TestFeePlanValidate_ShouldAcceptOpenEndedTiers(t *testing.T) {
+	plan := entity.FeePlan{Type: entity.FeeTypeTiered, MaxFee: 5000, Tiers: []entity.FeeTier{
+		{UpTo: 100000, FixedAmount: 1000},
+		{UpTo: 1000000, PercentageBasisPoints: 100},
+		{PercentageBasisPoints: 50},
+	}}
+
+	assert.Nil(t, plan.Validate())
+	assert.Nil(t, entity.FeePlan{}.Validate())
+}
+
+func TestFeePlanValidate_ShouldRejectInvalidPlans(t *testing.T) {
+	plans := map[string]entity.FeePlan{
+		"unknown type":         {Type: "FLAT"},
+		"negative fixed":       {Type: entity.FeeTypeFixed, FixedAmount: -1},
+		"over 100%":            {Type: entity.FeeTypePercentage, PercentageBasisPoints: 10001},
+		"max below min":        {Type: entity.FeeTypeFixed, FixedAmount: 100, MinFee: 500, MaxFee: 200},
+		"tiered without tiers": {Type: entity.FeeTypeTiered},
+		"tiers on fixed":       {Type: entity.FeeTypeFixed, Tiers: []entity.FeeTier{{FixedAmount: 1}}},
+		"open tier not last":   {Type: entity.FeeTypeTiered, Tiers: []entity.FeeTier{{FixedAmount: 1}, {UpTo: 100}}},
+		"tiers out of order":   {Type: entity.FeeTypeTiered, Tiers: []entity.FeeTier{{UpTo: 500}, {UpTo: 100}}},
+	}
+
+	for name, plan := range plans {
+		assert.NotNil(t, plan.Validate(), name)
+	}
+}
@@ -0,0 +1,70 @@
+package entity_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"merchant_bank_payment_go_api/internal/entity"
+	"testing"
+)
+
+func TestNewMoney_ShouldDefaultToIDR(t *testing.T) {
+	money, err := entity.NewMoney(15000, "")
+
+	assert.Nil(t, err)
+	assert.Equal(t, entity.Money{Amount: 15000, Currency: "IDR"}, money)
+}
+
+func TestNewMoney_ShouldNormalizeCurrencyCode(t *testing.T) {
+	money, err := entity.NewMoney(500, "jpy")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "JPY", money.Currency)
+}
+
+func TestNewMoney_ShouldReturnError_WhenCurrencyUnsupported(t *testing.T) {
+	_, err := entity.NewMoney(15000, "ABC")
+
+	assert.NotNil(t, err)
+}
+
+func TestNewMoney_ShouldReturnError_WhenAmountNotPositive(t *testing.T) {
+	_, err := entity.NewMoney(0, "IDR")
+
+	assert.NotNil(t, err)
+}
+
+func TestMoneyString_ShouldRespectMinorUnits(t *testing.T) {
+	assert.Equal(t, "15000 IDR", entity.Money{Amount: 15000, Currency: "IDR"}.String())
+	assert.Equal(t, "1500 JPY", entity.Money{Amount: 1500, Currency: "JPY"}.String())
+	assert.Equal(t, "1.250 KWD", entity.Money{Amount: 1250, Currency: "KWD"}.String())
+	assert.Equal(t, "0.05 USD", entity.Money{Amount: 5, Currency: "USD"}.String())
+	assert.Equal(t, "-12.34 USD", entity.Money{Amount: -1234, Currency: "USD"}.String())
+}
+
+func TestMoneyMajorUnits_ShouldScaleByMinorUnits(t *testing.T) {
+	assert.Equal(t, 1.25, entity.Money{Amount: 1250, Currency: "KWD"}.MajorUnits())
+	assert.Equal(t, float64(15000), entity.Money{Amount: 15000}.MajorUnits())
+}
+
+func TestMerchantAcceptsCurrency_ShouldDefaultToIDR(t *testing.T) {
+	merchant := entity.Merchant{}
+
+	assert.True(t, merchant.AcceptsCurrency("IDR"))
+	assert.False(t, merchant.AcceptsCurrency("USD"))
+}
+
+func TestMerchantAcceptsCurrency_ShouldUseAcceptedList(t *testing.T) {
+	merchant := entity.Merchant{AcceptedCurrencies: []string{"IDR", "KWD"}}
+
+	assert.True(t, merchant.AcceptsCurrency("kwd"))
+	assert.False(t, merchant.AcceptsCurrency("JPY"))
+}
+
+func TestPaymentLimitsCheck_ShouldOnlyCapListedCurrencies(t *testing.T) {
+	limits := entity.PaymentLimits{MaxAmounts: map[string]int64{"USD": 100000}}
+
+	assert.Nil(t, limits.Check(entity.Money{Amount: 100000, Currency: "USD"}))
+	assert.EqualError(t, limits.Check(entity.Money{Amount: 100001, Currency: "USD"}),
+		"amount 1000.01 USD exceeds the limit of 1000.00 USD per payment")
+	assert.Nil(t, limits.Check(entity.Money{Amount: 999999999, Currency: "IDR"}))
+	assert.Nil(t, entity.PaymentLimits{}.Check(entity.Money{Amount: 999999999, Currency: "USD"}))
+}
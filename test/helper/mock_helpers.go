@@ -1,60 +1,105 @@
 package helper
 
 import (
+	"context"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
+	"io"
 	"merchant_bank_payment_go_api/internal/entity"
 	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/internal/usecase"
+	"merchant_bank_payment_go_api/internal/utils"
+	"time"
 )
 
 type MockCustomerRepository struct {
 	mock.Mock
 }
 
-func (m *MockCustomerRepository) LoadCustomers() ([]entity.Customer, error) {
-	args := m.Called()
+func (m *MockCustomerRepository) LoadCustomers(ctx context.Context) ([]entity.Customer, error) {
+	args := m.Called(ctx)
 	return args.Get(0).([]entity.Customer), args.Error(1)
 }
 
-func (m *MockCustomerRepository) FindById(id uuid.UUID) (entity.Customer, error) {
-	args := m.Called(id)
+func (m *MockCustomerRepository) FindById(ctx context.Context, id uuid.UUID) (entity.Customer, error) {
+	args := m.Called(ctx, id)
 	return args.Get(0).(entity.Customer), args.Error(1)
 }
 
-func (m *MockCustomerRepository) FindByUsername(username string) (entity.Customer, error) {
-	args := m.Called(username)
+func (m *MockCustomerRepository) FindByUsername(ctx context.Context, username string) (entity.Customer, error) {
+	args := m.Called(ctx, username)
 	return args.Get(0).(entity.Customer), args.Error(1)
 }
 
+func (m *MockCustomerRepository) Update(ctx context.Context, customer entity.Customer) error {
+	args := m.Called(ctx, customer)
+	return args.Error(0)
+}
+
 type MockMerchantRepository struct {
 	mock.Mock
 }
 
-func (m *MockMerchantRepository) LoadMerchants() ([]entity.Merchant, error) {
-	args := m.Called()
+func (m *MockMerchantRepository) LoadMerchants(ctx context.Context) ([]entity.Merchant, error) {
+	args := m.Called(ctx)
 	return args.Get(0).([]entity.Merchant), args.Error(1)
 }
 
-func (m *MockMerchantRepository) FindById(id uuid.UUID) (entity.Merchant, error) {
-	args := m.Called(id)
+func (m *MockMerchantRepository) FindById(ctx context.Context, id uuid.UUID) (entity.Merchant, error) {
+	args := m.Called(ctx, id)
 	return args.Get(0).(entity.Merchant), args.Error(1)
 }
 
+func (m *MockMerchantRepository) Save(ctx context.Context, merchant entity.Merchant) error {
+	args := m.Called(ctx, merchant)
+	return args.Error(0)
+}
+
+func (m *MockMerchantRepository) Update(ctx context.Context, merchant entity.Merchant) error {
+	args := m.Called(ctx, merchant)
+	return args.Error(0)
+}
+
 type MockMerchantUseCase struct {
 	mock.Mock
 }
 
-func (m *MockMerchantUseCase) FindById(id string) (entity.Merchant, error) {
-	args := m.Called(id)
+func (m *MockMerchantUseCase) FindById(ctx context.Context, id string) (entity.Merchant, error) {
+	args := m.Called(ctx, id)
 	return args.Get(0).(entity.Merchant), args.Error(1)
 }
 
+func (m *MockMerchantUseCase) GetMerchants(ctx context.Context, request model.MerchantQueryRequest) (model.MerchantPage, error) {
+	args := m.Called(ctx, request)
+	return args.Get(0).(model.MerchantPage), args.Error(1)
+}
+
+func (m *MockMerchantUseCase) CreateMerchant(ctx context.Context, adminId string, request model.MerchantRequest) (model.MerchantResponse, error) {
+	args := m.Called(ctx, adminId, request)
+	return args.Get(0).(model.MerchantResponse), args.Error(1)
+}
+
+func (m *MockMerchantUseCase) UpdateMerchant(ctx context.Context, adminId, id string, request model.MerchantRequest) (model.MerchantResponse, error) {
+	args := m.Called(ctx, adminId, id, request)
+	return args.Get(0).(model.MerchantResponse), args.Error(1)
+}
+
+func (m *MockMerchantUseCase) SuspendMerchant(ctx context.Context, adminId, id string) (model.MerchantResponse, error) {
+	args := m.Called(ctx, adminId, id)
+	return args.Get(0).(model.MerchantResponse), args.Error(1)
+}
+
+func (m *MockMerchantUseCase) ReactivateMerchant(ctx context.Context, adminId, id string) (model.MerchantResponse, error) {
+	args := m.Called(ctx, adminId, id)
+	return args.Get(0).(model.MerchantResponse), args.Error(1)
+}
+
 type MockAuthRepository struct {
 	mock.Mock
 }
 
-func (m *MockAuthRepository) LoadBlacklist() ([]string, error) {
-	args := m.Called()
+func (m *MockAuthRepository) LoadBlacklist(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
 	return args.Get(0).([]string), args.Error(1)
 }
 
@@ -62,85 +107,193 @@ type MockCustomerUseCase struct {
 	mock.Mock
 }
 
-func (m *MockCustomerUseCase) FindById(id string) (entity.Customer, error) {
-	args := m.Called(id)
+func (m *MockCustomerUseCase) FindById(ctx context.Context, id string) (entity.Customer, error) {
+	args := m.Called(ctx, id)
 	return args.Get(0).(entity.Customer), args.Error(1)
 }
 
-func (m *MockCustomerUseCase) FindByUsername(username string) (entity.Customer, error) {
-	args := m.Called(username)
+func (m *MockCustomerUseCase) FindByUsername(ctx context.Context, username string) (entity.Customer, error) {
+	args := m.Called(ctx, username)
 	return args.Get(0).(entity.Customer), args.Error(1)
 }
 
+func (m *MockCustomerUseCase) IsSessionRevoked(ctx context.Context, customerId string, issuedAt time.Time) (bool, error) {
+	args := m.Called(ctx, customerId, issuedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCustomerUseCase) GetCustomers(ctx context.Context, request model.CustomerQueryRequest) (model.CustomerPage, error) {
+	args := m.Called(ctx, request)
+	return args.Get(0).(model.CustomerPage), args.Error(1)
+}
+
+func (m *MockCustomerUseCase) GetCustomerDetail(ctx context.Context, adminId, id string) (model.CustomerDetailResponse, error) {
+	args := m.Called(ctx, adminId, id)
+	return args.Get(0).(model.CustomerDetailResponse), args.Error(1)
+}
+
+func (m *MockCustomerUseCase) DisableCustomer(ctx context.Context, adminId, id string) (model.CustomerResponse, error) {
+	args := m.Called(ctx, adminId, id)
+	return args.Get(0).(model.CustomerResponse), args.Error(1)
+}
+
+func (m *MockCustomerUseCase) EnableCustomer(ctx context.Context, adminId, id string) (model.CustomerResponse, error) {
+	args := m.Called(ctx, adminId, id)
+	return args.Get(0).(model.CustomerResponse), args.Error(1)
+}
+
+func (m *MockCustomerUseCase) ForceLogout(ctx context.Context, adminId, id string) (model.CustomerResponse, error) {
+	args := m.Called(ctx, adminId, id)
+	return args.Get(0).(model.CustomerResponse), args.Error(1)
+}
+
 type MockHistoryRepository struct {
 	mock.Mock
 }
 
-func (m *MockHistoryRepository) LoadHistories() ([]entity.History, error) {
-	args := m.Called()
+func (m *MockHistoryRepository) LoadHistories(ctx context.Context) ([]entity.History, error) {
+	args := m.Called(ctx)
 	return args.Get(0).([]entity.History), args.Error(1)
 }
 
-func (m *MockHistoryRepository) SaveHistories(histories []entity.History) error {
-	args := m.Called(histories)
+func (m *MockHistoryRepository) SaveHistories(ctx context.Context, histories []entity.History) error {
+	args := m.Called(ctx, histories)
+	return args.Error(0)
+}
+
+func (m *MockHistoryRepository) AddHistory(ctx context.Context, history entity.History) error {
+	args := m.Called(ctx, history)
 	return args.Error(0)
 }
 
-func (m *MockHistoryRepository) AddHistory(history entity.History) error {
-	args := m.Called(history)
+func (m *MockHistoryRepository) AddHistories(ctx context.Context, histories []entity.History) error {
+	args := m.Called(ctx, histories)
 	return args.Error(0)
 }
 
+func (m *MockHistoryRepository) PruneHistories(ctx context.Context, before time.Time, archive func(histories []entity.History) error) (int, error) {
+	args := m.Called(ctx, before, archive)
+	if histories, ok := args.Get(2).([]entity.History); ok {
+		if err := archive(histories); err != nil {
+			return 0, err
+		}
+	}
+	return args.Int(0), args.Error(1)
+}
+
+type MockHistoryArchiveRepository struct {
+	mock.Mock
+}
+
+func (m *MockHistoryArchiveRepository) LoadArchives(ctx context.Context) ([]entity.HistoryArchive, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entity.HistoryArchive), args.Error(1)
+}
+
+func (m *MockHistoryArchiveRepository) WriteArchive(ctx context.Context, histories []entity.History, createdAt time.Time) (entity.HistoryArchive, error) {
+	args := m.Called(ctx, histories, createdAt)
+	return args.Get(0).(entity.HistoryArchive), args.Error(1)
+}
+
+func (m *MockHistoryArchiveRepository) LoadArchivedHistories(ctx context.Context, archive entity.HistoryArchive) ([]entity.History, error) {
+	args := m.Called(ctx, archive)
+	return args.Get(0).([]entity.History), args.Error(1)
+}
+
 type MockHistoryUseCase struct {
 	mock.Mock
 }
 
-func (m *MockHistoryUseCase) AddHistory(customerId, action, details string) error {
-	args := m.Called(customerId, action, details)
+func (m *MockHistoryUseCase) AddHistory(ctx context.Context, actorId string, action entity.AuditAction, details entity.AuditDetails) error {
+	args := m.Called(ctx, actorId, action, details)
 	return args.Error(0)
 }
 
-func (m *MockHistoryUseCase) LogAndAddHistory(userId, action, message string, err error) error {
-	args := m.Called(userId, action, message, err)
+func (m *MockHistoryUseCase) GetCustomerHistory(ctx context.Context, customerId string, request model.HistoryQueryRequest) (model.HistoryPage, error) {
+	args := m.Called(ctx, customerId, request)
+	return args.Get(0).(model.HistoryPage), args.Error(1)
+}
+
+func (m *MockHistoryUseCase) SearchHistory(ctx context.Context, adminId string, request model.HistoryQueryRequest) (model.HistoryPage, error) {
+	args := m.Called(ctx, adminId, request)
+	return args.Get(0).(model.HistoryPage), args.Error(1)
+}
+
+func (m *MockHistoryUseCase) ExportHistory(ctx context.Context, adminId string, request model.HistoryQueryRequest, writer io.Writer) error {
+	args := m.Called(ctx, adminId, request, writer)
+	if output, ok := args.Get(0).(string); ok && output != "" {
+		_, _ = io.WriteString(writer, output)
+	}
+	return args.Error(1)
+}
+
+func (m *MockHistoryUseCase) LogAndAddHistory(ctx context.Context, actorId string, action entity.AuditAction, details entity.AuditDetails, err error) error {
+	args := m.Called(ctx, actorId, action, details, err)
 	return args.Error(0)
 }
 
-func (m *MockAuthRepository) SaveBlacklist(blacklistedTokens []string) error {
-	args := m.Called(blacklistedTokens)
+func (m *MockAuthRepository) SaveBlacklist(ctx context.Context, blacklistedTokens []string) error {
+	args := m.Called(ctx, blacklistedTokens)
 	return args.Error(0)
 }
 
-func (m *MockAuthRepository) AddToBlacklist(token string) error {
-	args := m.Called(token)
+func (m *MockAuthRepository) AddToBlacklist(ctx context.Context, token string) error {
+	args := m.Called(ctx, token)
 	return args.Error(0)
 }
 
-func (m *MockAuthRepository) IsTokenBlacklisted(token string) (bool, error) {
-	args := m.Called(token)
+func (m *MockAuthRepository) StageAddToBlacklist(ctx context.Context, tx *utils.JsonFileTransaction, token string) error {
+	args := m.Called(ctx, tx, token)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) IsTokenBlacklisted(ctx context.Context, token string) (bool, error) {
+	args := m.Called(ctx, token)
 	return args.Get(0).(bool), args.Error(1)
 }
 
+type MockHistoryArchiveUseCase struct {
+	mock.Mock
+}
+
+func (m *MockHistoryArchiveUseCase) ArchiveHistories(ctx context.Context, now time.Time) (*entity.HistoryArchive, error) {
+	args := m.Called(ctx, now)
+	archive, _ := args.Get(0).(*entity.HistoryArchive)
+	return archive, args.Error(1)
+}
+
+func (m *MockHistoryArchiveUseCase) ArchiveNow(ctx context.Context, adminId string, olderThan time.Duration) (*entity.HistoryArchive, error) {
+	args := m.Called(ctx, adminId, olderThan)
+	archive, _ := args.Get(0).(*entity.HistoryArchive)
+	return archive, args.Error(1)
+}
+
+func (m *MockHistoryArchiveUseCase) StartArchiveWorker(interval time.Duration) func() {
+	args := m.Called(interval)
+	return args.Get(0).(func())
+}
+
 type MockAuthUseCase struct {
 	mock.Mock
 }
 
-func (m *MockAuthUseCase) Login(request model.LoginRequest) (model.LoginResponse, error) {
-	args := m.Called(request)
+func (m *MockAuthUseCase) Login(ctx context.Context, request model.LoginRequest) (model.LoginResponse, error) {
+	args := m.Called(ctx, request)
 	return args.Get(0).(model.LoginResponse), args.Error(1)
 }
 
-func (m *MockAuthUseCase) IsTokenBlacklisted(accessToken string) (bool, error) {
-	args := m.Called(accessToken)
+func (m *MockAuthUseCase) IsTokenBlacklisted(ctx context.Context, accessToken string) (bool, error) {
+	args := m.Called(ctx, accessToken)
 	return args.Get(0).(bool), args.Error(1)
 }
 
-func (m *MockAuthUseCase) AddToBlacklist(accessToken string) error {
-	args := m.Called(accessToken)
+func (m *MockAuthUseCase) AddToBlacklist(ctx context.Context, accessToken string) error {
+	args := m.Called(ctx, accessToken)
 	return args.Error(0)
 }
 
-func (m *MockAuthUseCase) Logout(token string) error {
-	args := m.Called(token)
+func (m *MockAuthUseCase) Logout(ctx context.Context, token string) error {
+	args := m.Called(ctx, token)
 	return args.Error(0)
 }
 
@@ -148,26 +301,321 @@ type MockPaymentTransactionRepository struct {
 	mock.Mock
 }
 
-func (m *MockPaymentTransactionRepository) LoadPayments() ([]entity.Payment, error) {
-	args := m.Called()
+func (m *MockPaymentTransactionRepository) LoadPayments(ctx context.Context) ([]entity.Payment, error) {
+	args := m.Called(ctx)
 	return args.Get(0).([]entity.Payment), args.Error(1)
 }
 
-func (m *MockPaymentTransactionRepository) SavePayments(paymentTransactions []entity.Payment) error {
-	args := m.Called(paymentTransactions)
+func (m *MockPaymentTransactionRepository) SavePayments(ctx context.Context, paymentTransactions []entity.Payment) error {
+	args := m.Called(ctx, paymentTransactions)
 	return args.Error(0)
 }
 
-func (m *MockPaymentTransactionRepository) AddPayment(paymentTransaction entity.Payment) error {
-	args := m.Called(paymentTransaction)
+func (m *MockPaymentTransactionRepository) AddPayment(ctx context.Context, paymentTransaction entity.Payment) error {
+	args := m.Called(ctx, paymentTransaction)
 	return args.Error(0)
 }
 
+func (m *MockPaymentTransactionRepository) StageAddPayment(ctx context.Context, tx *utils.JsonFileTransaction, payment entity.Payment) error {
+	args := m.Called(ctx, tx, payment)
+	return args.Error(0)
+}
+
+func (m *MockPaymentTransactionRepository) StageSavePayments(ctx context.Context, tx *utils.JsonFileTransaction, payments []entity.Payment) {
+	m.Called(ctx, tx, payments)
+}
+
+func (m *MockPaymentTransactionRepository) FindById(ctx context.Context, id uuid.UUID) (entity.Payment, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(entity.Payment), args.Error(1)
+}
+
+func (m *MockPaymentTransactionRepository) FindByCustomerId(ctx context.Context, customerId uuid.UUID) ([]entity.Payment, error) {
+	args := m.Called(ctx, customerId)
+	return args.Get(0).([]entity.Payment), args.Error(1)
+}
+
+func (m *MockPaymentTransactionRepository) StreamPayments(ctx context.Context, handler func(payment entity.Payment) error) error {
+	args := m.Called(ctx)
+	for _, payment := range args.Get(0).([]entity.Payment) {
+		if err := handler(payment); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
 type MockPaymentTransactionUseCase struct {
 	mock.Mock
 }
 
-func (m *MockPaymentTransactionUseCase) AddPayment(customerId string, paymentRequest model.PaymentRequest) error {
-	args := m.Called(customerId, paymentRequest)
+func (m *MockPaymentTransactionUseCase) AddPayment(ctx context.Context, customerId string, paymentRequest model.PaymentRequest) error {
+	args := m.Called(ctx, customerId, paymentRequest)
+	return args.Error(0)
+}
+
+func (m *MockPaymentTransactionUseCase) GetPayments(ctx context.Context, customerId string) ([]model.PaymentResponse, error) {
+	args := m.Called(ctx, customerId)
+	return args.Get(0).([]model.PaymentResponse), args.Error(1)
+}
+
+func (m *MockPaymentTransactionUseCase) GetPaymentById(ctx context.Context, customerId, paymentId string) (model.PaymentResponse, error) {
+	args := m.Called(ctx, customerId, paymentId)
+	return args.Get(0).(model.PaymentResponse), args.Error(1)
+}
+
+type MockExchangeRateRepository struct {
+	mock.Mock
+}
+
+func (m *MockExchangeRateRepository) LoadRates(ctx context.Context) ([]entity.ExchangeRate, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entity.ExchangeRate), args.Error(1)
+}
+
+func (m *MockExchangeRateRepository) FindRate(ctx context.Context, base, quote string) (entity.ExchangeRate, error) {
+	args := m.Called(ctx, base, quote)
+	return args.Get(0).(entity.ExchangeRate), args.Error(1)
+}
+
+func (m *MockExchangeRateRepository) SaveRates(ctx context.Context, rates []entity.ExchangeRate) error {
+	args := m.Called(ctx, rates)
+	return args.Error(0)
+}
+
+type MockExchangeRateUseCase struct {
+	mock.Mock
+}
+
+func (m *MockExchangeRateUseCase) Convert(ctx context.Context, amount entity.Money, targetCurrency string) (entity.Money, entity.CurrencyConversion, error) {
+	args := m.Called(ctx, amount, targetCurrency)
+	return args.Get(0).(entity.Money), args.Get(1).(entity.CurrencyConversion), args.Error(2)
+}
+
+func (m *MockExchangeRateUseCase) GetRates(ctx context.Context) ([]model.ExchangeRateResponse, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.ExchangeRateResponse), args.Error(1)
+}
+
+func (m *MockExchangeRateUseCase) ReplaceRates(ctx context.Context, adminId string, request model.ExchangeRateRequest) ([]model.ExchangeRateResponse, error) {
+	args := m.Called(ctx, adminId, request)
+	return args.Get(0).([]model.ExchangeRateResponse), args.Error(1)
+}
+
+type MockSettlementRepository struct {
+	mock.Mock
+}
+
+func (m *MockSettlementRepository) LoadSettlements(ctx context.Context) ([]entity.Settlement, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entity.Settlement), args.Error(1)
+}
+
+func (m *MockSettlementRepository) SaveSettlements(ctx context.Context, settlements []entity.Settlement) error {
+	args := m.Called(ctx, settlements)
 	return args.Error(0)
 }
+
+func (m *MockSettlementRepository) StageSaveSettlements(ctx context.Context, tx *utils.JsonFileTransaction, settlements []entity.Settlement) {
+	m.Called(ctx, tx, settlements)
+}
+
+func (m *MockSettlementRepository) FindById(ctx context.Context, id uuid.UUID) (entity.Settlement, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(entity.Settlement), args.Error(1)
+}
+
+type MockSettlementUseCase struct {
+	mock.Mock
+}
+
+func (m *MockSettlementUseCase) RunSettlement(ctx context.Context, adminId string, cutoff time.Time) ([]model.SettlementResponse, error) {
+	args := m.Called(ctx, adminId, cutoff)
+	return args.Get(0).([]model.SettlementResponse), args.Error(1)
+}
+
+func (m *MockSettlementUseCase) GetSettlements(ctx context.Context, merchantId string) ([]model.SettlementResponse, error) {
+	args := m.Called(ctx, merchantId)
+	return args.Get(0).([]model.SettlementResponse), args.Error(1)
+}
+
+func (m *MockSettlementUseCase) GetSettlementById(ctx context.Context, id string) (model.SettlementResponse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(model.SettlementResponse), args.Error(1)
+}
+
+type MockReportUseCase struct {
+	mock.Mock
+}
+
+func (m *MockReportUseCase) ExportReconciliation(ctx context.Context, request model.ReconciliationReportRequest, writer io.Writer) error {
+	args := m.Called(ctx, request, writer)
+	if output, ok := args.Get(0).(string); ok && output != "" {
+		_, _ = io.WriteString(writer, output)
+	}
+	return args.Error(1)
+}
+
+type MockWebhookDeliveryRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookDeliveryRepository) LoadDeliveries(ctx context.Context) ([]entity.WebhookDelivery, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entity.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookDeliveryRepository) SaveDeliveries(ctx context.Context, deliveries []entity.WebhookDelivery) error {
+	args := m.Called(ctx, deliveries)
+	return args.Error(0)
+}
+
+func (m *MockWebhookDeliveryRepository) AddDelivery(ctx context.Context, delivery entity.WebhookDelivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+func (m *MockWebhookDeliveryRepository) UpdateDelivery(ctx context.Context, delivery entity.WebhookDelivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+func (m *MockWebhookDeliveryRepository) FindById(ctx context.Context, id uuid.UUID) (entity.WebhookDelivery, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(entity.WebhookDelivery), args.Error(1)
+}
+
+type MockWebhookUseCase struct {
+	mock.Mock
+}
+
+func (m *MockWebhookUseCase) Enqueue(ctx context.Context, eventId, merchantId uuid.UUID, eventType string, data interface{}) error {
+	args := m.Called(ctx, eventId, merchantId, eventType, data)
+	return args.Error(0)
+}
+
+func (m *MockWebhookUseCase) DeliverDue(ctx context.Context, now time.Time) (int, error) {
+	args := m.Called(ctx, now)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockWebhookUseCase) GetDeliveries(ctx context.Context, status, merchantId string) ([]model.WebhookDeliveryResponse, error) {
+	args := m.Called(ctx, status, merchantId)
+	return args.Get(0).([]model.WebhookDeliveryResponse), args.Error(1)
+}
+
+func (m *MockWebhookUseCase) GetDeliveryById(ctx context.Context, id string) (model.WebhookDeliveryResponse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(model.WebhookDeliveryResponse), args.Error(1)
+}
+
+func (m *MockWebhookUseCase) Redeliver(ctx context.Context, adminId, id string) (model.WebhookDeliveryResponse, error) {
+	args := m.Called(ctx, adminId, id)
+	return args.Get(0).(model.WebhookDeliveryResponse), args.Error(1)
+}
+
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+// CommitWithEvents runs stage on a fresh transaction and records the call with
+// that transaction and the events stage returned.
+func (m *MockOutboxRepository) CommitWithEvents(ctx context.Context, stage func(tx *utils.JsonFileTransaction) ([]entity.DomainEvent, error)) error {
+	tx := utils.NewJsonFileTransaction(nil, "")
+	events, err := stage(tx)
+	if err != nil {
+		return err
+	}
+	args := m.Called(ctx, tx, events)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) LoadEvents(ctx context.Context) ([]entity.DomainEvent, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entity.DomainEvent), args.Error(1)
+}
+
+func (m *MockOutboxRepository) UpdateEvent(ctx context.Context, event entity.DomainEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) PruneDispatched(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockOutboxRepository) Recover(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+// MockEventBus runs the stage function of Publish on a fresh transaction and
+// records the call with that transaction and the events it returned, so tests
+// can check what was staged together.
+type MockEventBus struct {
+	mock.Mock
+}
+
+func (m *MockEventBus) Publish(ctx context.Context, stage func(tx *utils.JsonFileTransaction) ([]entity.DomainEvent, error)) error {
+	tx := utils.NewJsonFileTransaction(nil, "")
+	events, err := stage(tx)
+	if err != nil {
+		return err
+	}
+	args := m.Called(ctx, tx, events)
+	return args.Error(0)
+}
+
+func (m *MockEventBus) Subscribe(name string, eventType entity.DomainEventType, handler usecase.EventHandler) {
+	m.Called(name, eventType, handler)
+}
+
+func (m *MockEventBus) DispatchPending(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+// AuditMessage matches history details carrying the given message.
+func AuditMessage(message string) interface{} {
+	return mock.MatchedBy(func(details entity.AuditDetails) bool {
+		return details.Message == message
+	})
+}
+
+type MockAuditCheckpointRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditCheckpointRepository) LoadCheckpoints(ctx context.Context) ([]entity.AuditCheckpoint, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entity.AuditCheckpoint), args.Error(1)
+}
+
+func (m *MockAuditCheckpointRepository) AddCheckpoint(ctx context.Context, checkpoint entity.AuditCheckpoint) error {
+	args := m.Called(ctx, checkpoint)
+	return args.Error(0)
+}
+
+type MockHealthCheckRepository struct {
+	mock.Mock
+}
+
+func (m *MockHealthCheckRepository) CheckHealth(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+type MockHealthUseCase struct {
+	mock.Mock
+}
+
+func (m *MockHealthUseCase) CheckReadiness(ctx context.Context) model.ReadinessResponse {
+	args := m.Called(ctx)
+	return args.Get(0).(model.ReadinessResponse)
+}
+
+func (m *MockHealthUseCase) GetVersion() model.VersionResponse {
+	args := m.Called()
+	return args.Get(0).(model.VersionResponse)
+}
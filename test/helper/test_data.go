@@ -13,6 +13,11 @@ const HistoryTempFilename = "test_history.json"
 const PaymentTransactionTempFilename = "test_payment_transaction.json"
 const BlacklistTempFilename = "test_blacklist_token.json"
 const FileUtilsFileName = "test_read_file.json"
+const ExchangeRateTempFilename = "test_exchange_rates.json"
+const SettlementTempFilename = "test_settlement.json"
+const WebhookDeliveryTempFilename = "test_webhook_deliveries.json"
+const OutboxTempFilename = "test_outbox.json"
+const AuditCheckpointTempFilename = "test_audit_checkpoints.json"
 
 var CustomerId = uuid.New()
 var MerchantId = uuid.New()
@@ -38,24 +43,39 @@ var ExpectedMerchants = []entity.Merchant{{
 var ExpectedHistories = []entity.History{
 	{
 		Id:         uuid.New(),
-		Action:     "LOGIN",
+		Action:     entity.AuditActionLogin,
 		CustomerId: CustomerId.String(),
 		Timestamp:  CreatedAt,
-		Details:    "Login successful",
+		Details: entity.AuditDetails{
+			ActorType:   entity.AuditActorCustomer,
+			SubjectType: entity.AuditSubjectSession,
+			Outcome:     entity.AuditOutcomeSuccess,
+			Message:     "Login successful",
+		},
 	},
 	{
 		Id:         uuid.New(),
-		Action:     "PAYMENT",
+		Action:     entity.AuditActionPaymentCreate,
 		CustomerId: CustomerId.String(),
 		Timestamp:  CreatedAt.Add(1),
-		Details:    fmt.Sprintf("Payment of 20000 to Merchant Id %s", MerchantId),
+		Details: entity.AuditDetails{
+			ActorType:   entity.AuditActorCustomer,
+			SubjectType: entity.AuditSubjectPayment,
+			Outcome:     entity.AuditOutcomeSuccess,
+			Message:     fmt.Sprintf("Payment of 20000 IDR to merchant %s created", MerchantId),
+		},
 	},
 	{
 		Id:         uuid.New(),
-		Action:     "LOGOUT",
+		Action:     entity.AuditActionLogout,
 		CustomerId: CustomerId.String(),
 		Timestamp:  CreatedAt.Add(2),
-		Details:    "Logout successful",
+		Details: entity.AuditDetails{
+			ActorType:   entity.AuditActorCustomer,
+			SubjectType: entity.AuditSubjectSession,
+			Outcome:     entity.AuditOutcomeSuccess,
+			Message:     "Logout successful",
+		},
 	},
 }
 
@@ -64,9 +84,57 @@ var ExpectedPayments = []entity.Payment{
 		Id:         uuid.New(),
 		CustomerId: CustomerId,
 		MerchantId: MerchantId,
-		Amount:     50000,
+		Money:      entity.Money{Amount: 50000, Currency: "IDR"},
+		FeeAmount:  350,
+		NetAmount:  49650,
 		Timestamp:  CreatedAt,
 	},
 }
 
 var ExpectedTokens = []string{"token1", "token2", "token3"}
+
+var ExpectedExchangeRates = []entity.ExchangeRate{
+	{
+		Base:      "USD",
+		Quote:     "IDR",
+		Rate:      15850.5,
+		Timestamp: CreatedAt,
+	},
+	{
+		Base:      "KWD",
+		Quote:     "IDR",
+		Rate:      51540.75,
+		Timestamp: CreatedAt,
+	},
+}
+
+var ExpectedSettlements = []entity.Settlement{
+	{
+		Id:           uuid.New(),
+		MerchantId:   MerchantId,
+		Currency:     "IDR",
+		WindowStart:  CreatedAt.AddDate(0, 0, -1),
+		WindowEnd:    CreatedAt,
+		PaymentIds:   []uuid.UUID{uuid.New()},
+		PaymentCount: 1,
+		GrossAmount:  50000,
+		FeeAmount:    350,
+		NetAmount:    49650,
+		Status:       entity.SettlementStatusPendingPayout,
+		CreatedAt:    CreatedAt,
+	},
+}
+
+var ExpectedWebhookDeliveries = []entity.WebhookDelivery{
+	{
+		Id:            uuid.New(),
+		MerchantId:    MerchantId,
+		EventType:     entity.WebhookEventPaymentCaptured,
+		Url:           "http://localhost:9999/webhooks",
+		Payload:       []byte(`{"type":"payment.captured"}`),
+		Status:        entity.WebhookDeliveryStatusPending,
+		NextAttemptAt: CreatedAt,
+		CreatedAt:     CreatedAt,
+		UpdatedAt:     CreatedAt,
+	},
+}
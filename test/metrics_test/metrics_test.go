@@ -0,0 +1,42 @@
+package metrics_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"merchant_bank_payment_go_api/internal/metrics"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func scrape(t *testing.T) string {
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain; version=0.0.4"))
+	return w.Body.String()
+}
+
+func TestHandler_ShouldServeApplicationAndRuntimeMetrics(t *testing.T) {
+	metrics.RateLimitedRequests.WithLabelValues("handler-test").Inc()
+
+	output := scrape(t)
+
+	assert.Contains(t, output, "# TYPE http_rate_limited_requests_total counter")
+	assert.Contains(t, output, `http_rate_limited_requests_total{policy="handler-test"} 1`)
+	assert.Contains(t, output, "go_goroutines ")
+}
+
+func TestSetGaugeFunc_ShouldReplacePreviousFunc(t *testing.T) {
+	metrics.SetGaugeFunc("test_queue_depth", "Queue depth.", func() float64 { return 1 })
+	metrics.SetGaugeFunc("test_queue_depth", "Queue depth.", func() float64 { return 7 })
+
+	assert.Contains(t, scrape(t), "test_queue_depth 7\n")
+}
+
+func TestObserveRepositoryOperation_ShouldLabelWithBaseName(t *testing.T) {
+	metrics.ObserveRepositoryOperation("read", "/data/dir/ObserveTest.json", time.Now())
+
+	assert.Contains(t, scrape(t), `repository_operation_duration_seconds_count{file="ObserveTest.json",operation="read"} 1`)
+}
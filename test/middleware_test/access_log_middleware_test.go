@@ -0,0 +1,41 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"merchant_bank_payment_go_api/internal/delivery/http/middleware"
+	"merchant_bank_payment_go_api/internal/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAccessLogMiddleware_ShouldLogRequestToInjectedLogger(t *testing.T) {
+	var buffer bytes.Buffer
+	log := logrus.New()
+	log.SetOutput(&buffer)
+	log.SetFormatter(&logrus.JSONFormatter{})
+	log.AddHook(utils.RequestIdHook{})
+
+	r := gin.New()
+	r.Use(middleware.RequestIdMiddleware(), middleware.AccessLogMiddleware(log))
+	r.GET("/missing", func(c *gin.Context) {
+		c.Status(http.StatusNotFound)
+	})
+
+	req := httptest.NewRequest("GET", "/missing", nil)
+	req.Header.Set("X-Request-ID", "access-1")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var line map[string]interface{}
+	assert.Nil(t, json.Unmarshal(buffer.Bytes(), &line))
+	assert.Equal(t, "warning", line["level"])
+	assert.Equal(t, "GET", line["method"])
+	assert.Equal(t, "/missing", line["path"])
+	assert.Equal(t, float64(http.StatusNotFound), line["status"])
+	assert.Equal(t, "access-1", line["request_id"])
+}
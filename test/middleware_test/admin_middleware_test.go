@@ -0,0 +1,69 @@
+package middleware_test
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merchant_bank_payment_go_api/internal/delivery/http/middleware"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/test/helper"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newAdminRouter(userId string, mockCustomerUseCase *helper.MockCustomerUseCase) *gin.Engine {
+	r := gin.Default()
+	r.Use(func(c *gin.Context) {
+		if userId != "" {
+			c.Set("user_id", userId)
+		}
+	})
+	r.Use(middleware.AdminMiddleware(logrus.New(), mockCustomerUseCase))
+	r.GET("/admin", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+func TestAdminMiddleware_ShouldAllowAdmin(t *testing.T) {
+	adminId := uuid.New()
+	mockCustomerUseCase := new(helper.MockCustomerUseCase)
+	mockCustomerUseCase.On("FindById", mock.Anything, adminId.String()).Return(entity.Customer{Id: adminId, Role: entity.RoleAdmin}, nil)
+
+	w := httptest.NewRecorder()
+	newAdminRouter(adminId.String(), mockCustomerUseCase).ServeHTTP(w, httptest.NewRequest("GET", "/admin", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAdminMiddleware_ShouldReturnForbidden_WhenNotAdmin(t *testing.T) {
+	mockCustomerUseCase := new(helper.MockCustomerUseCase)
+	mockCustomerUseCase.On("FindById", mock.Anything, helper.CustomerId.String()).Return(helper.ExpectedCustomers[0], nil)
+
+	w := httptest.NewRecorder()
+	newAdminRouter(helper.CustomerId.String(), mockCustomerUseCase).ServeHTTP(w, httptest.NewRequest("GET", "/admin", nil))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestAdminMiddleware_ShouldReturnForbidden_WhenCustomerNotFound(t *testing.T) {
+	userId := uuid.New()
+	mockCustomerUseCase := new(helper.MockCustomerUseCase)
+	mockCustomerUseCase.On("FindById", mock.Anything, userId.String()).Return(entity.Customer{}, errors.New("not found"))
+
+	w := httptest.NewRecorder()
+	newAdminRouter(userId.String(), mockCustomerUseCase).ServeHTTP(w, httptest.NewRequest("GET", "/admin", nil))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestAdminMiddleware_ShouldReturnUnauthorized_WhenNoUserId(t *testing.T) {
+	w := httptest.NewRecorder()
+	newAdminRouter("", new(helper.MockCustomerUseCase)).ServeHTTP(w, httptest.NewRequest("GET", "/admin", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
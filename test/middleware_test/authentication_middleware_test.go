@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"merchant_bank_payment_go_api/internal/delivery/http/controller"
 	"merchant_bank_payment_go_api/internal/delivery/http/middleware"
 	"merchant_bank_payment_go_api/internal/model"
@@ -37,18 +38,18 @@ func TestAuthenticationMiddleware_ShouldReturnError_WhenNoHeader(t *testing.T) {
 	assert.Nil(t, err)
 
 	mockPaymentTransactionUseCase := new(helper.MockPaymentTransactionUseCase)
-	mockPaymentTransactionUseCase.On("AddPayment", customerId.String(), paymentRequest).Return(nil)
+	mockPaymentTransactionUseCase.On("AddPayment", mock.Anything, customerId.String(), paymentRequest).Return(nil)
 
 	mockAuthUseCase := new(helper.MockAuthUseCase)
-	mockAuthUseCase.On("IsTokenBlacklisted", token).Return(false, nil)
-	mockAuthUseCase.On("AddToBlacklist", token).Return(nil)
-	mockAuthUseCase.On("Logout", token).Return(nil)
+	mockAuthUseCase.On("IsTokenBlacklisted", mock.Anything, token).Return(false, nil)
+	mockAuthUseCase.On("AddToBlacklist", mock.Anything, token).Return(nil)
+	mockAuthUseCase.On("Logout", mock.Anything, token).Return(nil)
 
 	log := logrus.New()
 	paymentController := controller.NewPaymentTransactionController(log, mockPaymentTransactionUseCase)
 
 	r := gin.Default()
-	r.Use(middleware.AuthenticationMiddleware(mockAuthUseCase))
+	r.Use(middleware.AuthenticationMiddleware(logrus.New(), mockAuthUseCase))
 	r.POST("/payment", paymentController.AddPayment)
 
 	req := httptest.NewRequest("POST", "/payment", strings.NewReader(string(bodyJson)))
@@ -88,18 +89,18 @@ func TestAuthenticationMiddleware_ShouldReturnError_WhenInvalidHeaderFormat(t *t
 	assert.Nil(t, err)
 
 	mockPaymentTransactionUseCase := new(helper.MockPaymentTransactionUseCase)
-	mockPaymentTransactionUseCase.On("AddPayment", customerId.String(), paymentRequest).Return(nil)
+	mockPaymentTransactionUseCase.On("AddPayment", mock.Anything, customerId.String(), paymentRequest).Return(nil)
 
 	mockAuthUseCase := new(helper.MockAuthUseCase)
-	mockAuthUseCase.On("IsTokenBlacklisted", token).Return(false, nil)
-	mockAuthUseCase.On("AddToBlacklist", token).Return(nil)
-	mockAuthUseCase.On("Logout", token).Return(nil)
+	mockAuthUseCase.On("IsTokenBlacklisted", mock.Anything, token).Return(false, nil)
+	mockAuthUseCase.On("AddToBlacklist", mock.Anything, token).Return(nil)
+	mockAuthUseCase.On("Logout", mock.Anything, token).Return(nil)
 
 	log := logrus.New()
 	paymentController := controller.NewPaymentTransactionController(log, mockPaymentTransactionUseCase)
 
 	r := gin.Default()
-	r.Use(middleware.AuthenticationMiddleware(mockAuthUseCase))
+	r.Use(middleware.AuthenticationMiddleware(logrus.New(), mockAuthUseCase))
 	r.POST("/payment", paymentController.AddPayment)
 
 	req := httptest.NewRequest("POST", "/payment", strings.NewReader(string(bodyJson)))
@@ -140,18 +141,18 @@ func TestAuthenticationMiddleware_ShouldReturnError_WhenHaveHeaderButNoToken(t *
 	assert.Nil(t, err)
 
 	mockPaymentTransactionUseCase := new(helper.MockPaymentTransactionUseCase)
-	mockPaymentTransactionUseCase.On("AddPayment", customerId.String(), paymentRequest).Return(nil)
+	mockPaymentTransactionUseCase.On("AddPayment", mock.Anything, customerId.String(), paymentRequest).Return(nil)
 
 	mockAuthUseCase := new(helper.MockAuthUseCase)
-	mockAuthUseCase.On("IsTokenBlacklisted", token).Return(false, nil)
-	mockAuthUseCase.On("AddToBlacklist", token).Return(nil)
-	mockAuthUseCase.On("Logout", token).Return(nil)
+	mockAuthUseCase.On("IsTokenBlacklisted", mock.Anything, token).Return(false, nil)
+	mockAuthUseCase.On("AddToBlacklist", mock.Anything, token).Return(nil)
+	mockAuthUseCase.On("Logout", mock.Anything, token).Return(nil)
 
 	log := logrus.New()
 	paymentController := controller.NewPaymentTransactionController(log, mockPaymentTransactionUseCase)
 
 	r := gin.Default()
-	r.Use(middleware.AuthenticationMiddleware(mockAuthUseCase))
+	r.Use(middleware.AuthenticationMiddleware(logrus.New(), mockAuthUseCase))
 	r.POST("/payment", paymentController.AddPayment)
 
 	req := httptest.NewRequest("POST", "/payment", strings.NewReader(string(bodyJson)))
@@ -192,18 +193,18 @@ func TestAuthenticationMiddleware_ShouldReturnError_WhenErrorCheckIsTokenBlackli
 	assert.Nil(t, err)
 
 	mockPaymentTransactionUseCase := new(helper.MockPaymentTransactionUseCase)
-	mockPaymentTransactionUseCase.On("AddPayment", customerId.String(), paymentRequest).Return(nil)
+	mockPaymentTransactionUseCase.On("AddPayment", mock.Anything, customerId.String(), paymentRequest).Return(nil)
 
 	mockAuthUseCase := new(helper.MockAuthUseCase)
-	mockAuthUseCase.On("IsTokenBlacklisted", token).Return(false, errors.New("internal error"))
-	mockAuthUseCase.On("AddToBlacklist", token).Return(nil)
-	mockAuthUseCase.On("Logout", token).Return(nil)
+	mockAuthUseCase.On("IsTokenBlacklisted", mock.Anything, token).Return(false, errors.New("internal error"))
+	mockAuthUseCase.On("AddToBlacklist", mock.Anything, token).Return(nil)
+	mockAuthUseCase.On("Logout", mock.Anything, token).Return(nil)
 
 	log := logrus.New()
 	paymentController := controller.NewPaymentTransactionController(log, mockPaymentTransactionUseCase)
 
 	r := gin.Default()
-	r.Use(middleware.AuthenticationMiddleware(mockAuthUseCase))
+	r.Use(middleware.AuthenticationMiddleware(logrus.New(), mockAuthUseCase))
 	r.POST("/payment", paymentController.AddPayment)
 
 	req := httptest.NewRequest("POST", "/payment", strings.NewReader(string(bodyJson)))
@@ -244,18 +245,18 @@ func TestAuthenticationMiddleware_ShouldReturnError_WhenTokenAlreadyBlacklisted(
 	assert.Nil(t, err)
 
 	mockPaymentTransactionUseCase := new(helper.MockPaymentTransactionUseCase)
-	mockPaymentTransactionUseCase.On("AddPayment", customerId.String(), paymentRequest).Return(nil)
+	mockPaymentTransactionUseCase.On("AddPayment", mock.Anything, customerId.String(), paymentRequest).Return(nil)
 
 	mockAuthUseCase := new(helper.MockAuthUseCase)
-	mockAuthUseCase.On("IsTokenBlacklisted", token).Return(true, nil)
-	mockAuthUseCase.On("AddToBlacklist", token).Return(nil)
-	mockAuthUseCase.On("Logout", token).Return(nil)
+	mockAuthUseCase.On("IsTokenBlacklisted", mock.Anything, token).Return(true, nil)
+	mockAuthUseCase.On("AddToBlacklist", mock.Anything, token).Return(nil)
+	mockAuthUseCase.On("Logout", mock.Anything, token).Return(nil)
 
 	log := logrus.New()
 	paymentController := controller.NewPaymentTransactionController(log, mockPaymentTransactionUseCase)
 
 	r := gin.Default()
-	r.Use(middleware.AuthenticationMiddleware(mockAuthUseCase))
+	r.Use(middleware.AuthenticationMiddleware(logrus.New(), mockAuthUseCase))
 	r.POST("/payment", paymentController.AddPayment)
 
 	req := httptest.NewRequest("POST", "/payment", strings.NewReader(string(bodyJson)))
@@ -0,0 +1,31 @@
+package middleware_test
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"merchant_bank_payment_go_api/internal/delivery/http/middleware"
+	"merchant_bank_payment_go_api/internal/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientMiddleware_ShouldStoreClientIpAndUserAgent(t *testing.T) {
+	var ip, userAgent string
+	r := gin.Default()
+	r.Use(middleware.ClientMiddleware())
+	r.GET("/ping", func(c *gin.Context) {
+		ip, userAgent = utils.ClientFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest("GET", "/ping", nil)
+	req.RemoteAddr = "203.0.113.7:51234"
+	req.Header.Set("User-Agent", "checkout-app/2.1")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "203.0.113.7", ip)
+	assert.Equal(t, "checkout-app/2.1", userAgent)
+}
@@ -0,0 +1,35 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"merchant_bank_payment_go_api/internal/delivery/http/middleware"
+	"merchant_bank_payment_go_api/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMaintenanceMiddleware_ShouldRespondServiceUnavailable_WhenEnabled(t *testing.T) {
+	enabled := true
+	r := gin.Default()
+	r.GET("/work", middleware.MaintenanceMiddleware(logrus.New(), func() bool { return enabled }), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "done"})
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/work", nil))
+
+	var response model.CommonResponse[interface{}]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "Service is under maintenance, please try again later", response.Message)
+
+	enabled = false
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/work", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,31 @@
+package middleware_test
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"merchant_bank_payment_go_api/internal/delivery/http/middleware"
+	"merchant_bank_payment_go_api/internal/metrics"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMetricsMiddleware_ShouldRecordRequestsByRouteTemplate(t *testing.T) {
+	r := gin.New()
+	r.Use(middleware.MetricsMiddleware())
+	r.GET("/metrics-test/:id", func(c *gin.Context) {
+		c.Status(http.StatusAccepted)
+	})
+
+	for _, path := range []string{"/metrics-test/1", "/metrics-test/2", "/metrics-test-missing"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	output := w.Body.String()
+	assert.Contains(t, output, `http_requests_total{method="GET",route="/metrics-test/:id",status="202"} 2`)
+	assert.Contains(t, output, `http_request_duration_seconds_count{method="GET",route="/metrics-test/:id",status="202"} 2`)
+	assert.Contains(t, output, `http_requests_total{method="GET",route="unmatched",status="404"}`)
+	assert.NotContains(t, output, `route="/metrics-test/1"`)
+}
@@ -0,0 +1,115 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"merchant_bank_payment_go_api/internal/delivery/http/middleware"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/internal/utils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func fixedPolicy(requests int) func() utils.RateLimitPolicy {
+	return func() utils.RateLimitPolicy {
+		return utils.RateLimitPolicy{Requests: requests, Window: time.Minute}
+	}
+}
+
+func TestRateLimitMiddleware_ShouldRespondTooManyRequests_WhenLimitExceeded(t *testing.T) {
+	r := gin.Default()
+	r.GET("/work", middleware.RateLimitMiddleware(logrus.New(), utils.NewRateLimiter(), "test", middleware.RateLimitByIp, fixedPolicy(2)),
+		func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"message": "done"})
+		})
+
+	var w *httptest.ResponseRecorder
+	for i := 0; i < 2; i++ {
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/work", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "60", w.Header().Get("RateLimit-Reset"))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/work", nil))
+
+	var response model.CommonResponse[interface{}]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, http.StatusTooManyRequests, response.HttpStatus)
+	assert.Equal(t, "Too many requests, retry after 30 seconds", response.Message)
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+
+	other := httptest.NewRequest("GET", "/work", nil)
+	other.RemoteAddr = "10.0.0.2:1234"
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, other)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRateLimitMiddleware_ShouldSkipRequestsWithoutKey(t *testing.T) {
+	r := gin.Default()
+	r.GET("/work", middleware.RateLimitMiddleware(logrus.New(), utils.NewRateLimiter(), "test", middleware.RateLimitByUser, fixedPolicy(1)),
+		func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"message": "done"})
+		})
+
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/work", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+	}
+}
+
+func TestRateLimitMiddleware_ShouldLimitPerMerchantAndKeepBody(t *testing.T) {
+	r := gin.Default()
+	r.POST("/payment", middleware.RateLimitMiddleware(logrus.New(), utils.NewRateLimiter(), "test", middleware.RateLimitByMerchant, fixedPolicy(1)),
+		func(c *gin.Context) {
+			var request model.PaymentRequest
+			if err := c.ShouldBind(&request); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{"message": request.MerchantId})
+		})
+
+	send := func(merchantId string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest("POST", "/payment", strings.NewReader(`{"merchantId":"`+merchantId+`","amount":100}`))
+		request.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, request)
+		return w
+	}
+
+	w := send("merchant-a")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "merchant-a")
+	assert.Equal(t, http.StatusTooManyRequests, send("merchant-a").Code)
+	assert.Equal(t, http.StatusOK, send("merchant-b").Code)
+}
+
+func TestRateLimitMiddleware_ShouldReportMostRestrictiveLimit(t *testing.T) {
+	limiter := utils.NewRateLimiter()
+	r := gin.Default()
+	r.GET("/work",
+		middleware.RateLimitMiddleware(logrus.New(), limiter, "narrow", middleware.RateLimitByIp, fixedPolicy(2)),
+		middleware.RateLimitMiddleware(logrus.New(), limiter, "wide", middleware.RateLimitByIp, fixedPolicy(100)),
+		func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"message": "done"})
+		})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/work", nil))
+
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+}
@@ -0,0 +1,63 @@
+package middleware_test
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"merchant_bank_payment_go_api/internal/delivery/http/middleware"
+	"merchant_bank_payment_go_api/internal/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newRequestIdRouter(seen *string) *gin.Engine {
+	r := gin.Default()
+	r.Use(middleware.RequestIdMiddleware())
+	r.GET("/ping", func(c *gin.Context) {
+		*seen = utils.RequestIdFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+func TestRequestIdMiddleware_ShouldKeepIncomingRequestId(t *testing.T) {
+	var seen string
+	r := newRequestIdRouter(&seen)
+
+	req := httptest.NewRequest("GET", "/ping", nil)
+	req.Header.Set("X-Request-ID", "checkout-42.retry:1")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "checkout-42.retry:1", seen)
+	assert.Equal(t, "checkout-42.retry:1", w.Header().Get("X-Request-ID"))
+}
+
+func TestRequestIdMiddleware_ShouldGenerateRequestId_WhenMissing(t *testing.T) {
+	var seen string
+	r := newRequestIdRouter(&seen)
+
+	req := httptest.NewRequest("GET", "/ping", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	_, err := uuid.Parse(seen)
+	assert.Nil(t, err)
+	assert.Equal(t, seen, w.Header().Get("X-Request-ID"))
+}
+
+func TestRequestIdMiddleware_ShouldReplaceRequestId_WhenInvalid(t *testing.T) {
+	var seen string
+	r := newRequestIdRouter(&seen)
+
+	req := httptest.NewRequest("GET", "/ping", nil)
+	req.Header.Set("X-Request-ID", "bad id\nX-Injected: 1")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	_, err := uuid.Parse(seen)
+	assert.Nil(t, err)
+	assert.Equal(t, seen, w.Header().Get("X-Request-ID"))
+}
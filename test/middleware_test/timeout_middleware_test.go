@@ -0,0 +1,67 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"merchant_bank_payment_go_api/internal/delivery/http/middleware"
+	"merchant_bank_payment_go_api/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTimeoutRouter(timeout time.Duration, handler gin.HandlerFunc) *gin.Engine {
+	r := gin.Default()
+	r.GET("/work", middleware.TimeoutMiddleware(logrus.New(), timeout), handler)
+	return r
+}
+
+func TestTimeoutMiddleware_ShouldRespondGatewayTimeout_WhenDeadlineExceeded(t *testing.T) {
+	r := newTimeoutRouter(20*time.Millisecond, func(c *gin.Context) {
+		<-c.Request.Context().Done()
+		c.JSON(http.StatusBadRequest, gin.H{"message": c.Request.Context().Err().Error()})
+	})
+
+	req := httptest.NewRequest("GET", "/work", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var response model.CommonResponse[interface{}]
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.Equal(t, "Request timed out", response.Message)
+}
+
+func TestTimeoutMiddleware_ShouldPassThrough_WhenHandlerFinishesInTime(t *testing.T) {
+	var deadlineSet bool
+	r := newTimeoutRouter(time.Second, func(c *gin.Context) {
+		_, deadlineSet = c.Request.Context().Deadline()
+		c.JSON(http.StatusOK, gin.H{"message": "done"})
+	})
+
+	req := httptest.NewRequest("GET", "/work", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, deadlineSet)
+	assert.JSONEq(t, `{"message":"done"}`, w.Body.String())
+}
+
+func TestTimeoutMiddleware_ShouldKeepStreamedResponse_WhenDeadlinePassesMidway(t *testing.T) {
+	r := newTimeoutRouter(20*time.Millisecond, func(c *gin.Context) {
+		c.Status(http.StatusOK)
+		_, _ = c.Writer.WriteString("partial")
+		<-c.Request.Context().Done()
+	})
+
+	req := httptest.NewRequest("GET", "/work", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "partial", w.Body.String())
+}
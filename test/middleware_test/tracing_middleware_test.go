@@ -0,0 +1,91 @@
+package middleware_test
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"merchant_bank_payment_go_api/internal/delivery/http/middleware"
+	"merchant_bank_payment_go_api/internal/tracing"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func setupTracingRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+	return recorder
+}
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key string) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if string(kv.Key) == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestTracingMiddleware_ShouldContinueIncomingTraceAndParentChildSpans(t *testing.T) {
+	recorder := setupTracingRecorder(t)
+
+	r := gin.New()
+	r.Use(middleware.RequestIdMiddleware(), middleware.TracingMiddleware())
+	r.GET("/payments/:id", func(c *gin.Context) {
+		_, span := tracing.Start(c.Request.Context(), "PaymentTransactionUseCase.GetPaymentById")
+		span.End()
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest("GET", "/payments/123", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	req.Header.Set("X-Request-ID", "req-trace")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	child, server := spans[0], spans[1]
+
+	assert.Equal(t, "GET /payments/:id", server.Name())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent().SpanID().String())
+	assert.Equal(t, "/payments/:id", spanAttribute(server, "http.route").AsString())
+	assert.Equal(t, "req-trace", spanAttribute(server, "request.id").AsString())
+	assert.Equal(t, int64(http.StatusOK), spanAttribute(server, "http.response.status_code").AsInt64())
+
+	assert.Equal(t, "PaymentTransactionUseCase.GetPaymentById", child.Name())
+	assert.Equal(t, server.SpanContext().SpanID(), child.Parent().SpanID())
+	assert.Equal(t, server.SpanContext().TraceID(), child.SpanContext().TraceID())
+}
+
+func TestTracingMiddleware_ShouldMarkServerErrorsAsFailed(t *testing.T) {
+	recorder := setupTracingRecorder(t)
+
+	r := gin.New()
+	r.Use(middleware.TracingMiddleware())
+	r.GET("/broken", func(c *gin.Context) {
+		c.Status(http.StatusInternalServerError)
+	})
+	r.GET("/missing", func(c *gin.Context) {
+		c.Status(http.StatusNotFound)
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/broken", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/missing", nil))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+}
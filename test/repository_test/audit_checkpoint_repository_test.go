@@ -0,0 +1,57 @@
+package repository_test
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/repository/impl"
+	"merchant_bank_payment_go_api/test/helper"
+	"os"
+	"testing"
+	"time"
+)
+
+func CreateAuditCheckpointTempFile() {
+	err := os.WriteFile(helper.AuditCheckpointTempFilename, []byte(`[]`), 0644)
+	if err != nil {
+		logrus.Error("Error writing to file:", err)
+	}
+}
+
+func DeleteAuditCheckpointTempFile() {
+	err := os.Remove(helper.AuditCheckpointTempFilename)
+	if err != nil && !os.IsNotExist(err) {
+		logrus.Error("Error removing file:", err)
+	}
+}
+
+func TestAddCheckpoint_ShouldAppendCheckpoint(t *testing.T) {
+	t.Cleanup(DeleteAuditCheckpointTempFile)
+	CreateAuditCheckpointTempFile()
+
+	history := helper.ExpectedHistories[0]
+	history.Seal(nil, nil)
+	checkpoint := entity.NewAuditCheckpoint(history, []byte("audit-key"), helper.CreatedAt.Truncate(time.Second))
+
+	log := logrus.New()
+	repo := impl.NewAuditCheckpointRepositoryImpl(log, helper.AuditCheckpointTempFilename)
+
+	err := repo.AddCheckpoint(context.Background(), checkpoint)
+	assert.Nil(t, err)
+
+	checkpoints, err := repo.LoadCheckpoints(context.Background())
+	assert.Nil(t, err)
+	assert.Len(t, checkpoints, 1)
+	assert.Equal(t, checkpoint.Hash, checkpoints[0].Hash)
+	assert.True(t, checkpoints[0].ValidSignature([]byte("audit-key")))
+}
+
+func TestAddCheckpoint_ShouldReturnError_WhenFileMissing(t *testing.T) {
+	log := logrus.New()
+	repo := impl.NewAuditCheckpointRepositoryImpl(log, "nonexistent_folder/test_audit_checkpoints.json")
+
+	err := repo.AddCheckpoint(context.Background(), entity.AuditCheckpoint{})
+
+	assert.NotNil(t, err)
+}
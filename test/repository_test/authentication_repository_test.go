@@ -1,6 +1,7 @@
 package repository_test
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
@@ -37,7 +38,7 @@ func TestLoadBlackList_ShouldReturnBlackListToken(t *testing.T) {
 	log := logrus.New()
 	repo := impl.NewAuthRepository(log, helper.BlacklistTempFilename)
 
-	loadedToken, err := repo.LoadBlacklist()
+	loadedToken, err := repo.LoadBlacklist(context.Background())
 
 	assert.Nil(t, err)
 	assert.Equal(t, len(helper.ExpectedTokens), len(loadedToken))
@@ -49,7 +50,7 @@ func TestLoadBlacklist_ShouldReturnError_WhenInvalidFilename(t *testing.T) {
 	log := logrus.New()
 	repo := impl.NewAuthRepository(log, invalidFilename)
 
-	tokenResults, err := repo.LoadBlacklist()
+	tokenResults, err := repo.LoadBlacklist(context.Background())
 
 	assert.Nil(t, tokenResults)
 	assert.NotNil(t, err)
@@ -65,7 +66,7 @@ func TestLoadBlacklist_ShouldReturnError_WhenInvalidContent(t *testing.T) {
 	log := logrus.New()
 	repo := impl.NewAuthRepository(log, helper.BlacklistTempFilename)
 
-	blacklistResult, err := repo.LoadBlacklist()
+	blacklistResult, err := repo.LoadBlacklist(context.Background())
 
 	assert.Nil(t, blacklistResult)
 	assert.NotNil(t, err)
@@ -86,7 +87,7 @@ func TestSaveBlacklist_ShouldReturnSuccess(t *testing.T) {
 	log := logrus.New()
 	repo := impl.NewAuthRepository(log, helper.BlacklistTempFilename)
 
-	err := repo.SaveBlacklist(newExpectedTokens)
+	err := repo.SaveBlacklist(context.Background(), newExpectedTokens)
 
 	fileContent, err := os.ReadFile(helper.BlacklistTempFilename)
 	assert.Nil(t, err)
@@ -103,7 +104,7 @@ func TestSaveBlacklist_ShouldReturnError(t *testing.T) {
 	log := logrus.New()
 	repo := impl.NewAuthRepository(log, invalidFilename)
 
-	err := repo.SaveBlacklist(helper.ExpectedTokens)
+	err := repo.SaveBlacklist(context.Background(), helper.ExpectedTokens)
 
 	assert.NotNil(t, err)
 }
@@ -118,7 +119,7 @@ func TestAddToBlacklist_ShouldAddNewToken(t *testing.T) {
 	log := logrus.New()
 	repo := impl.NewAuthRepository(log, helper.BlacklistTempFilename)
 
-	err := repo.AddToBlacklist(token)
+	err := repo.AddToBlacklist(context.Background(), token)
 	assert.Nil(t, err)
 
 	fileContent, err := os.ReadFile(helper.BlacklistTempFilename)
@@ -140,7 +141,7 @@ func TestAddToBlacklist_ShouldReturnErrorWhenAlreadyBlacklisted(t *testing.T) {
 	log := logrus.New()
 	repo := impl.NewAuthRepository(log, helper.BlacklistTempFilename)
 
-	err := repo.AddToBlacklist(token)
+	err := repo.AddToBlacklist(context.Background(), token)
 	assert.NotNil(t, err)
 }
 
@@ -150,7 +151,7 @@ func TestAddToBlacklist_ShouldReturnErrorIfLoadFails(t *testing.T) {
 	log := logrus.New()
 	repo := impl.NewAuthRepository(log, invalidFilename)
 
-	err := repo.AddToBlacklist("token4")
+	err := repo.AddToBlacklist(context.Background(), "token4")
 
 	assert.NotNil(t, err)
 }
@@ -164,7 +165,7 @@ func TestIsTokenBlacklist_ShouldReturnTrue(t *testing.T) {
 	log := logrus.New()
 	repo := impl.NewAuthRepository(log, helper.BlacklistTempFilename)
 
-	blacklisted, err := repo.IsTokenBlacklisted(token)
+	blacklisted, err := repo.IsTokenBlacklisted(context.Background(), token)
 	assert.True(t, blacklisted)
 	assert.Nil(t, err)
 }
@@ -178,7 +179,7 @@ func TestIsTokenBlacklist_ShouldReturnFalse(t *testing.T) {
 	log := logrus.New()
 	repo := impl.NewAuthRepository(log, helper.BlacklistTempFilename)
 
-	blacklisted, err := repo.IsTokenBlacklisted(token)
+	blacklisted, err := repo.IsTokenBlacklisted(context.Background(), token)
 	assert.False(t, blacklisted)
 	assert.Nil(t, err)
 }
@@ -189,6 +190,6 @@ func TestIsTokenBlacklist_ShouldReturnError_WhenLoadError(t *testing.T) {
 	log := logrus.New()
 	repo := impl.NewAuthRepository(log, helper.BlacklistTempFilename)
 
-	_, err := repo.IsTokenBlacklisted(token)
+	_, err := repo.IsTokenBlacklisted(context.Background(), token)
 	assert.NotNil(t, err)
 }
@@ -0,0 +1,197 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/repository"
+	"merchant_bank_payment_go_api/internal/repository/impl"
+	"merchant_bank_payment_go_api/test/helper"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newHistoryEntry(action entity.AuditAction) entity.History {
+	return entity.History{Id: uuid.New(), Action: action, CustomerId: helper.CustomerId.String(), Timestamp: helper.CreatedAt}
+}
+
+func TestBufferedAddHistory_ShouldWriteThrough_WhenNotStarted(t *testing.T) {
+	history := newHistoryEntry(entity.AuditActionLogin)
+
+	mockHistoryRepository := new(helper.MockHistoryRepository)
+	mockHistoryRepository.On("AddHistory", mock.Anything, history).Return(nil)
+
+	repo := impl.NewBufferedHistoryRepositoryImpl(logrus.New(), mockHistoryRepository, 10, 10, time.Hour, repository.HistoryOverflowBlock)
+
+	err := repo.AddHistory(context.Background(), history)
+
+	assert.Nil(t, err)
+	mockHistoryRepository.AssertExpectations(t)
+}
+
+func TestBufferedAddHistory_ShouldWriteFullBatchAtOnce(t *testing.T) {
+	mockHistoryRepository := new(helper.MockHistoryRepository)
+	written := make(chan []entity.History, 1)
+	mockHistoryRepository.On("AddHistories", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		written <- args.Get(1).([]entity.History)
+	})
+
+	repo := impl.NewBufferedHistoryRepositoryImpl(logrus.New(), mockHistoryRepository, 10, 2, time.Hour, repository.HistoryOverflowBlock)
+	stop := repo.Start()
+	defer stop()
+
+	assert.Nil(t, repo.AddHistory(context.Background(), newHistoryEntry(entity.AuditActionLogin)))
+	assert.Nil(t, repo.AddHistory(context.Background(), newHistoryEntry(entity.AuditActionPaymentCreate)))
+
+	select {
+	case batch := <-written:
+		assert.Len(t, batch, 2)
+		assert.Equal(t, entity.AuditActionLogin, batch[0].Action)
+		assert.Equal(t, entity.AuditActionPaymentCreate, batch[1].Action)
+	case <-time.After(time.Second):
+		t.Fatal("batch was not written")
+	}
+}
+
+func TestBufferedAddHistory_ShouldWritePartialBatchAfterInterval(t *testing.T) {
+	mockHistoryRepository := new(helper.MockHistoryRepository)
+	written := make(chan []entity.History, 1)
+	mockHistoryRepository.On("AddHistories", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		written <- args.Get(1).([]entity.History)
+	})
+
+	repo := impl.NewBufferedHistoryRepositoryImpl(logrus.New(), mockHistoryRepository, 10, 100, 10*time.Millisecond, repository.HistoryOverflowBlock)
+	stop := repo.Start()
+	defer stop()
+
+	assert.Nil(t, repo.AddHistory(context.Background(), newHistoryEntry(entity.AuditActionLogin)))
+
+	select {
+	case batch := <-written:
+		assert.Len(t, batch, 1)
+	case <-time.After(time.Second):
+		t.Fatal("batch was not written")
+	}
+}
+
+func TestBufferedStop_ShouldFlushQueuedHistories(t *testing.T) {
+	mockHistoryRepository := new(helper.MockHistoryRepository)
+	mockHistoryRepository.On("AddHistories", mock.Anything, mock.MatchedBy(func(histories []entity.History) bool {
+		return len(histories) == 3
+	})).Return(nil).Once()
+	mockHistoryRepository.On("AddHistory", mock.Anything, mock.Anything).Return(nil).Once()
+
+	repo := impl.NewBufferedHistoryRepositoryImpl(logrus.New(), mockHistoryRepository, 10, 100, time.Hour, repository.HistoryOverflowBlock)
+	stop := repo.Start()
+
+	for i := 0; i < 3; i++ {
+		assert.Nil(t, repo.AddHistory(context.Background(), newHistoryEntry(entity.AuditActionPaymentCreate)))
+	}
+	stop()
+
+	stats := repo.Stats()
+	assert.Equal(t, int64(3), stats.Written)
+	assert.Zero(t, stats.Queued)
+
+	assert.Nil(t, repo.AddHistory(context.Background(), newHistoryEntry(entity.AuditActionLogout)))
+	mockHistoryRepository.AssertExpectations(t)
+}
+
+func TestBufferedLoadHistories_ShouldFlushBeforeReading(t *testing.T) {
+	history := newHistoryEntry(entity.AuditActionLogin)
+
+	mockHistoryRepository := new(helper.MockHistoryRepository)
+	mockHistoryRepository.On("AddHistories", mock.Anything, []entity.History{history}).Return(nil).Once()
+	mockHistoryRepository.On("LoadHistories", mock.Anything).Return([]entity.History{history}, nil)
+
+	repo := impl.NewBufferedHistoryRepositoryImpl(logrus.New(), mockHistoryRepository, 10, 100, time.Hour, repository.HistoryOverflowBlock)
+	stop := repo.Start()
+	defer stop()
+
+	assert.Nil(t, repo.AddHistory(context.Background(), history))
+	histories, err := repo.LoadHistories(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, []entity.History{history}, histories)
+	mockHistoryRepository.AssertExpectations(t)
+}
+
+func TestBufferedAddHistory_ShouldDropAndCount_WhenQueueFull(t *testing.T) {
+	mockHistoryRepository := new(helper.MockHistoryRepository)
+	writing := make(chan struct{}, 2)
+	release := make(chan struct{})
+	mockHistoryRepository.On("AddHistories", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		writing <- struct{}{}
+		<-release
+	})
+
+	repo := impl.NewBufferedHistoryRepositoryImpl(logrus.New(), mockHistoryRepository, 1, 1, time.Hour, repository.HistoryOverflowDrop)
+	stop := repo.Start()
+
+	// The first entry occupies the writer, the second fills the queue.
+	assert.Nil(t, repo.AddHistory(context.Background(), newHistoryEntry(entity.AuditActionLogin)))
+	<-writing
+	assert.Nil(t, repo.AddHistory(context.Background(), newHistoryEntry(entity.AuditActionPaymentCreate)))
+	assert.Nil(t, repo.AddHistory(context.Background(), newHistoryEntry(entity.AuditActionLogout)))
+
+	stats := repo.Stats()
+	assert.Equal(t, int64(1), stats.Dropped)
+	assert.Equal(t, int64(2), stats.Queued)
+
+	close(release)
+	stop()
+	assert.Equal(t, int64(2), repo.Stats().Written)
+}
+
+func TestBufferedFlush_ShouldCountFailedEntries(t *testing.T) {
+	mockHistoryRepository := new(helper.MockHistoryRepository)
+	mockHistoryRepository.On("AddHistories", mock.Anything, mock.Anything).Return(errors.New("disk full"))
+
+	repo := impl.NewBufferedHistoryRepositoryImpl(logrus.New(), mockHistoryRepository, 10, 100, time.Hour, repository.HistoryOverflowBlock)
+	stop := repo.Start()
+	defer stop()
+
+	assert.Nil(t, repo.AddHistory(context.Background(), newHistoryEntry(entity.AuditActionLogin)))
+	err := repo.Flush()
+
+	assert.NotNil(t, err)
+	assert.Equal(t, int64(1), repo.Stats().Failed)
+	assert.Zero(t, repo.Stats().Queued)
+}
+
+func TestBufferedCheckHealth_ShouldFail_WhenQueueFull(t *testing.T) {
+	mockHistoryRepository := new(helper.MockHistoryRepository)
+	writing := make(chan struct{}, 1)
+	release := make(chan struct{})
+	mockHistoryRepository.On("AddHistories", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		writing <- struct{}{}
+		<-release
+	})
+
+	repo := impl.NewBufferedHistoryRepositoryImpl(logrus.New(), mockHistoryRepository, 1, 1, time.Hour, repository.HistoryOverflowDrop)
+	stop := repo.Start()
+
+	assert.Nil(t, repo.AddHistory(context.Background(), newHistoryEntry(entity.AuditActionLogin)))
+	<-writing
+	assert.Nil(t, repo.CheckHealth(context.Background()))
+
+	assert.Nil(t, repo.AddHistory(context.Background(), newHistoryEntry(entity.AuditActionLogout)))
+	assert.ErrorContains(t, repo.CheckHealth(context.Background()), "history queue is full")
+
+	close(release)
+	stop()
+}
+
+func TestBufferedCheckHealth_ShouldCheckWrappedRepository(t *testing.T) {
+	historyRepository := impl.NewHistoryRepositoryImpl(logrus.New(), filepath.Join(t.TempDir(), "missing.json"), nil, nil)
+	repo := impl.NewBufferedHistoryRepositoryImpl(logrus.New(), historyRepository, 10, 10, time.Hour, repository.HistoryOverflowBlock)
+
+	err := repo.CheckHealth(context.Background())
+
+	assert.ErrorContains(t, err, "missing.json")
+}
@@ -1,6 +1,7 @@
 package repository_test
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
@@ -39,7 +40,7 @@ func TestLoadCustomers_ShouldReturnCustomerList(t *testing.T) {
 	log := logrus.New()
 	repo := impl.NewCustomerRepositoryImpl(log, helper.CustomerFilename)
 
-	customerResults, err := repo.LoadCustomers()
+	customerResults, err := repo.LoadCustomers(context.Background())
 
 	assert.Nil(t, err)
 	assert.Equal(t, len(helper.ExpectedCustomers), len(customerResults))
@@ -56,7 +57,7 @@ func TestLoadCustomers_ShouldReturnError_WhenInvalidFile(t *testing.T) {
 	log := logrus.New()
 	repo := impl.NewCustomerRepositoryImpl(log, invalidFilename)
 
-	customerResults, err := repo.LoadCustomers()
+	customerResults, err := repo.LoadCustomers(context.Background())
 
 	assert.Nil(t, customerResults)
 	assert.NotNil(t, err)
@@ -72,7 +73,7 @@ func TestLoadCustomers_ShouldReturnError_WhenInvalidContent(t *testing.T) {
 	log := logrus.New()
 	repo := impl.NewCustomerRepositoryImpl(log, helper.CustomerFilename)
 
-	customerResults, err := repo.LoadCustomers()
+	customerResults, err := repo.LoadCustomers(context.Background())
 
 	assert.Nil(t, customerResults)
 	assert.NotNil(t, err)
@@ -90,7 +91,7 @@ func TestFindById_ShouldReturnCustomer(t *testing.T) {
 	log := logrus.New()
 	repo := impl.NewCustomerRepositoryImpl(log, helper.CustomerFilename)
 
-	customerResult, err := repo.FindById(helper.CustomerId)
+	customerResult, err := repo.FindById(context.Background(), helper.CustomerId)
 	assert.Nil(t, err)
 	assert.Equal(t, helper.ExpectedCustomers[0].Id, customerResult.Id)
 	assert.Equal(t, helper.ExpectedCustomers[0].Username, customerResult.Username)
@@ -106,7 +107,7 @@ func TestFindByCustomerId_ShouldReturnError_WhenNotFound(t *testing.T) {
 	log := logrus.New()
 	repo := impl.NewCustomerRepositoryImpl(log, helper.CustomerFilename)
 
-	customerResult, err := repo.FindById(uuid.New())
+	customerResult, err := repo.FindById(context.Background(), uuid.New())
 	assert.NotNil(t, err)
 	assert.Equal(t, entity.Customer{}, customerResult)
 }
@@ -115,7 +116,7 @@ func TestFindByCustomerId_ShouldReturnError_WhenLoadCustomerError(t *testing.T)
 	log := logrus.New()
 	repo := impl.NewCustomerRepositoryImpl(log, "abc/invalidpath")
 
-	_, err := repo.FindById(uuid.New())
+	_, err := repo.FindById(context.Background(), uuid.New())
 
 	assert.NotNil(t, err)
 }
@@ -127,7 +128,7 @@ func TestFindByUsername_ShouldReturnCustomer(t *testing.T) {
 	log := logrus.New()
 	repo := impl.NewCustomerRepositoryImpl(log, helper.CustomerFilename)
 
-	customerResult, err := repo.FindByUsername(helper.ExpectedCustomers[0].Username)
+	customerResult, err := repo.FindByUsername(context.Background(), helper.ExpectedCustomers[0].Username)
 	assert.Nil(t, err)
 	assert.Equal(t, helper.ExpectedCustomers[0].Id, customerResult.Id)
 	assert.Equal(t, helper.ExpectedCustomers[0].Username, customerResult.Username)
@@ -143,7 +144,7 @@ func TestFindByUsername_ShouldReturnError_WhenNotFound(t *testing.T) {
 	log := logrus.New()
 	repo := impl.NewCustomerRepositoryImpl(log, helper.CustomerFilename)
 
-	customerResult, err := repo.FindByUsername("aaa")
+	customerResult, err := repo.FindByUsername(context.Background(), "aaa")
 	assert.NotNil(t, err)
 	assert.Equal(t, entity.Customer{}, customerResult)
 }
@@ -152,7 +153,59 @@ func TestFindByUsername_ShouldReturnError_WhenLoadCustomerError(t *testing.T) {
 	log := logrus.New()
 	repo := impl.NewCustomerRepositoryImpl(log, "abc/invalidpath")
 
-	_, err := repo.FindByUsername("budi")
+	_, err := repo.FindByUsername(context.Background(), "budi")
 
 	assert.NotNil(t, err)
 }
+
+func TestCheckHealth_ShouldSucceed_WhenCustomerFileIsAccessible(t *testing.T) {
+	t.Cleanup(DeleteCustomerTempfile)
+	CreateCustomerTempFile()
+	before, _ := os.ReadFile(helper.CustomerFilename)
+
+	repo := impl.NewCustomerRepositoryImpl(logrus.New(), helper.CustomerFilename)
+
+	err := repo.CheckHealth(context.Background())
+
+	assert.Nil(t, err)
+	after, _ := os.ReadFile(helper.CustomerFilename)
+	assert.Equal(t, before, after)
+}
+
+func TestCheckHealth_ShouldReturnError_WhenCustomerFileIsMissing(t *testing.T) {
+	DeleteCustomerTempfile()
+	repo := impl.NewCustomerRepositoryImpl(logrus.New(), helper.CustomerFilename)
+
+	err := repo.CheckHealth(context.Background())
+
+	assert.NotNil(t, err)
+}
+
+func TestUpdateCustomer_ShouldReplaceStoredCustomer(t *testing.T) {
+	t.Cleanup(DeleteCustomerTempfile)
+	CreateCustomerTempFile()
+
+	log := logrus.New()
+	repo := impl.NewCustomerRepositoryImpl(log, helper.CustomerFilename)
+
+	customer := helper.ExpectedCustomers[0]
+	customer.Status = entity.CustomerStatusDisabled
+	err := repo.Update(context.Background(), customer)
+	assert.Nil(t, err)
+
+	customerResult, err := repo.FindById(context.Background(), helper.CustomerId)
+	assert.Nil(t, err)
+	assert.True(t, customerResult.IsDisabled())
+	assert.Equal(t, customer.Password, customerResult.Password)
+}
+
+func TestUpdateCustomer_ShouldReturnError_WhenNotFound(t *testing.T) {
+	t.Cleanup(DeleteCustomerTempfile)
+	CreateCustomerTempFile()
+
+	log := logrus.New()
+	repo := impl.NewCustomerRepositoryImpl(log, helper.CustomerFilename)
+
+	err := repo.Update(context.Background(), entity.Customer{Id: uuid.New(), Username: "ghost"})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,113 @@
+package repository_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"merchant_bank_payment_go_api/internal/repository/impl"
+	"merchant_bank_payment_go_api/test/helper"
+	"os"
+	"testing"
+)
+
+func CreateExchangeRateTempFile() {
+	fileContent, err := json.Marshal(helper.ExpectedExchangeRates)
+	if err != nil {
+		logrus.Error("Error marshalling data:", err)
+		return
+	}
+
+	err = os.WriteFile(helper.ExchangeRateTempFilename, fileContent, 0644)
+	if err != nil {
+		logrus.Error("Error writing to file:", err)
+	}
+}
+
+func DeleteExchangeRateTempFile() {
+	err := os.Remove(helper.ExchangeRateTempFilename)
+	if err != nil && !os.IsNotExist(err) {
+		logrus.Error("Error removing file:", err)
+	}
+}
+
+func TestLoadRates_ShouldReturnExchangeRates(t *testing.T) {
+	t.Cleanup(DeleteExchangeRateTempFile)
+	CreateExchangeRateTempFile()
+
+	log := logrus.New()
+	repo := impl.NewExchangeRateRepositoryImpl(log, helper.ExchangeRateTempFilename)
+
+	ratesResult, err := repo.LoadRates(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, helper.ExpectedExchangeRates, ratesResult)
+}
+
+func TestLoadRates_ShouldReturnError_WhenInvalidFilename(t *testing.T) {
+	log := logrus.New()
+	repo := impl.NewExchangeRateRepositoryImpl(log, "empty.json")
+
+	ratesResult, err := repo.LoadRates(context.Background())
+
+	assert.Nil(t, ratesResult)
+	assert.NotNil(t, err)
+}
+
+func TestFindRate_ShouldReturnDirectRate(t *testing.T) {
+	t.Cleanup(DeleteExchangeRateTempFile)
+	CreateExchangeRateTempFile()
+
+	log := logrus.New()
+	repo := impl.NewExchangeRateRepositoryImpl(log, helper.ExchangeRateTempFilename)
+
+	rateResult, err := repo.FindRate(context.Background(), "usd", "IDR")
+
+	assert.Nil(t, err)
+	assert.Equal(t, helper.ExpectedExchangeRates[0], rateResult)
+}
+
+func TestFindRate_ShouldReturnInverseRate(t *testing.T) {
+	t.Cleanup(DeleteExchangeRateTempFile)
+	CreateExchangeRateTempFile()
+
+	log := logrus.New()
+	repo := impl.NewExchangeRateRepositoryImpl(log, helper.ExchangeRateTempFilename)
+
+	rateResult, err := repo.FindRate(context.Background(), "IDR", "USD")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "IDR", rateResult.Base)
+	assert.Equal(t, "USD", rateResult.Quote)
+	assert.InDelta(t, 1/helper.ExpectedExchangeRates[0].Rate, rateResult.Rate, 1e-12)
+	assert.Equal(t, helper.ExpectedExchangeRates[0].Timestamp, rateResult.Timestamp)
+}
+
+func TestFindRate_ShouldReturnError_WhenRateNotFound(t *testing.T) {
+	t.Cleanup(DeleteExchangeRateTempFile)
+	CreateExchangeRateTempFile()
+
+	log := logrus.New()
+	repo := impl.NewExchangeRateRepositoryImpl(log, helper.ExchangeRateTempFilename)
+
+	_, err := repo.FindRate(context.Background(), "EUR", "JPY")
+
+	assert.NotNil(t, err)
+}
+
+func TestSaveRates_ShouldReplaceRateTable(t *testing.T) {
+	t.Cleanup(DeleteExchangeRateTempFile)
+	CreateExchangeRateTempFile()
+
+	log := logrus.New()
+	repo := impl.NewExchangeRateRepositoryImpl(log, helper.ExchangeRateTempFilename)
+	rates := helper.ExpectedExchangeRates[:1]
+
+	err := repo.SaveRates(context.Background(), rates)
+	assert.Nil(t, err)
+
+	ratesResult, err := repo.LoadRates(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, rates, ratesResult)
+}
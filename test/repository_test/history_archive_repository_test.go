@@ -0,0 +1,110 @@
+package repository_test
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/repository/impl"
+	"merchant_bank_payment_go_api/test/helper"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sealedHistories() []entity.History {
+	histories := make([]entity.History, 0, len(helper.ExpectedHistories))
+	for _, history := range helper.ExpectedHistories {
+		var prev *entity.History
+		if len(histories) > 0 {
+			prev = &histories[len(histories)-1]
+		}
+		history.Seal(prev, nil)
+		histories = append(histories, history)
+	}
+	return histories
+}
+
+func TestLoadArchives_ShouldReturnEmpty_WhenNoIndex(t *testing.T) {
+	repo := impl.NewHistoryArchiveRepositoryImpl(logrus.New(), t.TempDir())
+
+	archives, err := repo.LoadArchives(context.Background())
+
+	assert.Nil(t, err)
+	assert.Empty(t, archives)
+}
+
+func TestWriteArchive_ShouldWriteGzipFileAndIndex(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "archive")
+	histories := sealedHistories()
+	createdAt := helper.CreatedAt.Add(90 * 24 * time.Hour)
+
+	repo := impl.NewHistoryArchiveRepositoryImpl(logrus.New(), dir)
+
+	archive, err := repo.WriteArchive(context.Background(), histories, createdAt)
+	assert.Nil(t, err)
+	assert.Equal(t, len(histories), archive.Records)
+	assert.Equal(t, int64(1), archive.FirstSequence)
+	assert.Equal(t, int64(len(histories)), archive.LastSequence)
+	assert.Equal(t, histories[len(histories)-1].Hash, archive.LastHash)
+	assert.True(t, strings.HasPrefix(archive.Filename, "history-"+helper.CreatedAt.UTC().Format("20060102")))
+	assert.True(t, strings.HasSuffix(archive.Filename, ".json.gz"))
+
+	archives, err := repo.LoadArchives(context.Background())
+	assert.Nil(t, err)
+	assert.Len(t, archives, 1)
+	assert.Equal(t, archive.Id, archives[0].Id)
+	assert.Equal(t, archive.Checksum, archives[0].Checksum)
+
+	archived, err := repo.LoadArchivedHistories(context.Background(), archives[0])
+	assert.Nil(t, err)
+	assert.Len(t, archived, len(histories))
+	for i := range histories {
+		assert.Equal(t, histories[i].Id, archived[i].Id)
+		assert.Equal(t, histories[i].Hash, archived[i].Hash)
+		assert.Equal(t, histories[i].Hash, archived[i].ComputeHash())
+	}
+}
+
+func TestLoadArchivedHistories_ShouldReturnError_WhenFileModified(t *testing.T) {
+	dir := t.TempDir()
+	repo := impl.NewHistoryArchiveRepositoryImpl(logrus.New(), dir)
+
+	archive, err := repo.WriteArchive(context.Background(), sealedHistories(), time.Now())
+	assert.Nil(t, err)
+
+	file, err := os.OpenFile(filepath.Join(dir, archive.Filename), os.O_APPEND|os.O_WRONLY, 0644)
+	assert.Nil(t, err)
+	_, err = file.Write([]byte{0})
+	assert.Nil(t, err)
+	assert.Nil(t, file.Close())
+
+	archived, err := repo.LoadArchivedHistories(context.Background(), archive)
+
+	assert.Nil(t, archived)
+	assert.NotNil(t, err)
+}
+
+func TestWriteArchive_ShouldReturnError_WhenNothingToArchive(t *testing.T) {
+	repo := impl.NewHistoryArchiveRepositoryImpl(logrus.New(), t.TempDir())
+
+	_, err := repo.WriteArchive(context.Background(), nil, time.Now())
+
+	assert.NotNil(t, err)
+}
+
+func TestCheckHealth_ShouldCreateMissingArchiveDirectory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "archive", "history")
+	repo := impl.NewHistoryArchiveRepositoryImpl(logrus.New(), dir)
+
+	err := repo.CheckHealth(context.Background())
+
+	assert.Nil(t, err)
+	info, err := os.Stat(dir)
+	assert.Nil(t, err)
+	assert.True(t, info.IsDir())
+	entries, _ := os.ReadDir(dir)
+	assert.Empty(t, entries)
+}
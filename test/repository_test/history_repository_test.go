@@ -1,10 +1,13 @@
 package repository_test
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"merchant_bank_payment_go_api/internal/entity"
 	"merchant_bank_payment_go_api/internal/repository/impl"
 	"merchant_bank_payment_go_api/test/helper"
@@ -38,9 +41,9 @@ func TestLoadHistories_ShouldReturnHistories(t *testing.T) {
 	CreateHistoryTempFile()
 
 	log := logrus.New()
-	repo := impl.NewHistoryRepositoryImpl(log, helper.HistoryTempFilename)
+	repo := impl.NewHistoryRepositoryImpl(log, helper.HistoryTempFilename, nil, nil)
 
-	historiesResult, err := repo.LoadHistories()
+	historiesResult, err := repo.LoadHistories(context.Background())
 
 	assert.Nil(t, err)
 	assert.Equal(t, len(helper.ExpectedHistories), len(historiesResult))
@@ -51,9 +54,9 @@ func TestLoadHistories_ShouldReturnError_WhenInvalidFilename(t *testing.T) {
 	invalidFilename := "empty.json"
 
 	log := logrus.New()
-	repo := impl.NewHistoryRepositoryImpl(log, invalidFilename)
+	repo := impl.NewHistoryRepositoryImpl(log, invalidFilename, nil, nil)
 
-	historiesResult, err := repo.LoadHistories()
+	historiesResult, err := repo.LoadHistories(context.Background())
 
 	assert.Nil(t, historiesResult)
 	assert.NotNil(t, err)
@@ -67,9 +70,9 @@ func TestLoadHistories_ShouldReturnError_WhenInvalidContent(t *testing.T) {
 	}
 
 	log := logrus.New()
-	repo := impl.NewHistoryRepositoryImpl(log, helper.HistoryTempFilename)
+	repo := impl.NewHistoryRepositoryImpl(log, helper.HistoryTempFilename, nil, nil)
 
-	historiesResult, err := repo.LoadHistories()
+	historiesResult, err := repo.LoadHistories(context.Background())
 
 	assert.Nil(t, historiesResult)
 	assert.NotNil(t, err)
@@ -85,17 +88,17 @@ func TestSaveHistories_ShouldReturnSuccess(t *testing.T) {
 	CreateHistoryTempFile()
 
 	log := logrus.New()
-	repo := impl.NewHistoryRepositoryImpl(log, helper.HistoryTempFilename)
+	repo := impl.NewHistoryRepositoryImpl(log, helper.HistoryTempFilename, nil, nil)
 
 	newExpectedHistories := helper.ExpectedHistories
 	newExpectedHistories = append(newExpectedHistories, entity.History{
 		Id:         uuid.New(),
-		Action:     "LOGIN",
+		Action:     entity.AuditActionLogin,
 		CustomerId: uuid.New().String(),
 		Timestamp:  helper.CreatedAt,
-		Details:    "Login successful",
+		Details:    entity.AuditDetails{ActorType: entity.AuditActorCustomer, Outcome: entity.AuditOutcomeSuccess, Message: "Login successful"},
 	})
-	err := repo.SaveHistories(newExpectedHistories)
+	err := repo.SaveHistories(context.Background(), newExpectedHistories)
 
 	fileContent, err := os.ReadFile(helper.HistoryTempFilename)
 	assert.Nil(t, err)
@@ -110,9 +113,9 @@ func TestSaveHistories_ShouldReturnError(t *testing.T) {
 	invalidFilename := "abc/test_history.json"
 
 	log := logrus.New()
-	repo := impl.NewHistoryRepositoryImpl(log, invalidFilename)
+	repo := impl.NewHistoryRepositoryImpl(log, invalidFilename, nil, nil)
 
-	err := repo.SaveHistories(helper.ExpectedHistories)
+	err := repo.SaveHistories(context.Background(), helper.ExpectedHistories)
 
 	assert.NotNil(t, err)
 }
@@ -123,18 +126,20 @@ func TestAddToHistory_ShouldAddNewHistory(t *testing.T) {
 
 	newHistory := entity.History{
 		Id:         uuid.New(),
-		Action:     "LOGIN",
+		Action:     entity.AuditActionLogin,
 		CustomerId: uuid.New().String(),
 		Timestamp:  helper.CreatedAt,
-		Details:    "Login successful",
+		Details:    entity.AuditDetails{ActorType: entity.AuditActorCustomer, Outcome: entity.AuditOutcomeSuccess, Message: "Login successful"},
 	}
+	sealedHistory := newHistory
+	sealedHistory.Seal(nil, nil)
 	newExpectedHistories := helper.ExpectedHistories
-	newExpectedHistories = append(newExpectedHistories, newHistory)
+	newExpectedHistories = append(newExpectedHistories, sealedHistory)
 
 	log := logrus.New()
-	repo := impl.NewHistoryRepositoryImpl(log, helper.HistoryTempFilename)
+	repo := impl.NewHistoryRepositoryImpl(log, helper.HistoryTempFilename, nil, nil)
 
-	err := repo.AddHistory(newHistory)
+	err := repo.AddHistory(context.Background(), newHistory)
 	assert.Nil(t, err)
 
 	fileContent, err := os.ReadFile(helper.HistoryTempFilename)
@@ -152,16 +157,162 @@ func TestAddToHistory_ShouldReturnErrorWhenLoadFails(t *testing.T) {
 
 	newHistory := entity.History{
 		Id:         uuid.New(),
-		Action:     "LOGIN",
+		Action:     entity.AuditActionLogin,
 		CustomerId: uuid.New().String(),
 		Timestamp:  time.Now(),
-		Details:    "Login successful",
+		Details:    entity.AuditDetails{ActorType: entity.AuditActorCustomer, Outcome: entity.AuditOutcomeSuccess, Message: "Login successful"},
 	}
 
 	log := logrus.New()
-	repo := impl.NewHistoryRepositoryImpl(log, invalidFilename)
+	repo := impl.NewHistoryRepositoryImpl(log, invalidFilename, nil, nil)
 
-	err := repo.AddHistory(newHistory)
+	err := repo.AddHistory(context.Background(), newHistory)
 
 	assert.NotNil(t, err)
 }
+
+func TestAddHistories_ShouldAppendAllHistoriesInOneWrite(t *testing.T) {
+	t.Cleanup(DeleteHistoryTempFile)
+	CreateHistoryTempFile()
+
+	newHistories := []entity.History{
+		{Id: uuid.New(), Action: entity.AuditActionLogin, CustomerId: uuid.New().String(), Timestamp: helper.CreatedAt,
+			Details: entity.AuditDetails{ActorType: entity.AuditActorCustomer, Outcome: entity.AuditOutcomeSuccess, Message: "Login successful"}},
+		{Id: uuid.New(), Action: entity.AuditActionLogout, CustomerId: uuid.New().String(), Timestamp: helper.CreatedAt,
+			Details: entity.AuditDetails{ActorType: entity.AuditActorCustomer, Outcome: entity.AuditOutcomeSuccess, Message: "Logout successful"}},
+	}
+	first, second := newHistories[0], newHistories[1]
+	first.Seal(nil, nil)
+	second.Seal(&first, nil)
+	newExpectedHistories := append(append([]entity.History{}, helper.ExpectedHistories...), first, second)
+
+	log := logrus.New()
+	repo := impl.NewHistoryRepositoryImpl(log, helper.HistoryTempFilename, nil, nil)
+
+	err := repo.AddHistories(context.Background(), newHistories)
+	assert.Nil(t, err)
+
+	historyResult, err := repo.LoadHistories(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, newExpectedHistories, historyResult)
+}
+
+func TestLoadHistories_ShouldConvertLegacyRecords(t *testing.T) {
+	t.Cleanup(DeleteHistoryTempFile)
+	legacyContent := `[{"id":"dcc2bc56-8f71-4d27-b714-ec84bab7bd37","action":"Successfully found customer by username","customer_id":"budi","timestamp":"2024-11-25T14:31:55+07:00","details":"Customer found successfully"},` +
+		`{"id":"f04f4bd0-aa18-41e7-b8ac-4851c6069bbe","action":"LOGIN","customer_id":"685729de-cd87-4524-80bc-9b19cf58df22","timestamp":"2024-11-25T14:31:56+07:00","details":"Invalid credentials"}]`
+	err := os.WriteFile(helper.HistoryTempFilename, []byte(legacyContent), 0644)
+	assert.Nil(t, err)
+
+	log := logrus.New()
+	repo := impl.NewHistoryRepositoryImpl(log, helper.HistoryTempFilename, nil, nil)
+
+	historiesResult, err := repo.LoadHistories(context.Background())
+
+	assert.Nil(t, err)
+	assert.Len(t, historiesResult, 2)
+	assert.Equal(t, entity.AuditActionCustomerLookup, historiesResult[0].Action)
+	assert.Equal(t, entity.AuditDetails{
+		ActorType:   entity.AuditActorCustomer,
+		SubjectType: entity.AuditSubjectCustomer,
+		SubjectId:   "budi",
+		Outcome:     entity.AuditOutcomeSuccess,
+		Message:     "Customer found successfully",
+		Legacy:      true,
+	}, historiesResult[0].Details)
+	assert.Equal(t, entity.AuditActionLogin, historiesResult[1].Action)
+	assert.Equal(t, entity.AuditOutcomeFailure, historiesResult[1].Details.Outcome)
+}
+
+func TestAddHistories_ShouldChainRecordsAndMacWithKey(t *testing.T) {
+	t.Cleanup(DeleteHistoryTempFile)
+	CreateHistoryTempFile()
+
+	key := []byte("audit-key")
+	log := logrus.New()
+	repo := impl.NewHistoryRepositoryImpl(log, helper.HistoryTempFilename, key, nil)
+
+	err := repo.AddHistory(context.Background(), helper.ExpectedHistories[0])
+	assert.Nil(t, err)
+	err = repo.AddHistory(context.Background(), helper.ExpectedHistories[1])
+	assert.Nil(t, err)
+
+	historyResult, err := repo.LoadHistories(context.Background())
+	assert.Nil(t, err)
+
+	first, second := historyResult[3], historyResult[4]
+	assert.Equal(t, int64(1), first.Sequence)
+	assert.Empty(t, first.PrevHash)
+	assert.Equal(t, int64(2), second.Sequence)
+	assert.Equal(t, first.Hash, second.PrevHash)
+	assert.Equal(t, second.ComputeHash(), second.Hash)
+	assert.True(t, entity.ValidAuditMac(key, second.Hash, second.Mac))
+}
+
+func TestPruneHistories_ShouldArchiveLeadingOldRecords(t *testing.T) {
+	t.Cleanup(DeleteHistoryTempFile)
+	CreateHistoryTempFile()
+
+	log := logrus.New()
+	repo := impl.NewHistoryRepositoryImpl(log, helper.HistoryTempFilename, nil, nil)
+
+	var archived []entity.History
+	count, err := repo.PruneHistories(context.Background(), helper.ExpectedHistories[2].Timestamp, func(histories []entity.History) error {
+		archived = histories
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+	assert.Len(t, archived, 2)
+	assert.Equal(t, helper.ExpectedHistories[0].Id, archived[0].Id)
+	assert.Equal(t, helper.ExpectedHistories[1].Id, archived[1].Id)
+
+	historyResult, err := repo.LoadHistories(context.Background())
+	assert.Nil(t, err)
+	assert.Len(t, historyResult, 1)
+	assert.Equal(t, helper.ExpectedHistories[2].Id, historyResult[0].Id)
+}
+
+func TestPruneHistories_ShouldKeepRecords_WhenArchiveFails(t *testing.T) {
+	t.Cleanup(DeleteHistoryTempFile)
+	CreateHistoryTempFile()
+
+	log := logrus.New()
+	repo := impl.NewHistoryRepositoryImpl(log, helper.HistoryTempFilename, nil, nil)
+
+	count, err := repo.PruneHistories(context.Background(), time.Now().Add(time.Hour), func(histories []entity.History) error {
+		return errors.New("disk full")
+	})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, count)
+
+	historyResult, err := repo.LoadHistories(context.Background())
+	assert.Nil(t, err)
+	assert.Len(t, historyResult, len(helper.ExpectedHistories))
+}
+
+func TestAddHistories_ShouldContinueChainFromArchive_WhenHistoryFileEmpty(t *testing.T) {
+	t.Cleanup(DeleteHistoryTempFile)
+	err := os.WriteFile(helper.HistoryTempFilename, []byte("[]"), 0644)
+	assert.Nil(t, err)
+
+	key := []byte("audit-key")
+	archive := entity.HistoryArchive{Id: uuid.New(), Records: 3, FirstSequence: 1, LastSequence: 3, LastHash: "last-archived-hash"}
+	mockHistoryArchiveRepository := new(helper.MockHistoryArchiveRepository)
+	mockHistoryArchiveRepository.On("LoadArchives", mock.Anything).Return([]entity.HistoryArchive{archive}, nil)
+
+	log := logrus.New()
+	repo := impl.NewHistoryRepositoryImpl(log, helper.HistoryTempFilename, key, mockHistoryArchiveRepository)
+
+	err = repo.AddHistory(context.Background(), helper.ExpectedHistories[0])
+	assert.Nil(t, err)
+
+	historyResult, err := repo.LoadHistories(context.Background())
+	assert.Nil(t, err)
+	assert.Len(t, historyResult, 1)
+	assert.Equal(t, int64(4), historyResult[0].Sequence)
+	assert.Equal(t, "last-archived-hash", historyResult[0].PrevHash)
+	assert.Equal(t, historyResult[0].ComputeHash(), historyResult[0].Hash)
+}
@@ -1,6 +1,7 @@
 package repository_test
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
@@ -39,7 +40,7 @@ func TestLoadMerchant_ShouldReturnMerchantList(t *testing.T) {
 	log := logrus.New()
 	repo := impl.NewMerchantRepositoryImpl(log, helper.MerchantFilename)
 
-	merchantResult, err := repo.LoadMerchants()
+	merchantResult, err := repo.LoadMerchants(context.Background())
 
 	assert.Nil(t, err)
 	assert.Equal(t, len(helper.ExpectedMerchants), len(merchantResult))
@@ -55,7 +56,7 @@ func TestLoadMerchant_ShouldReturnError_WhenInvalidFileName(t *testing.T) {
 	log := logrus.New()
 	repo := impl.NewMerchantRepositoryImpl(log, invalidFilename)
 
-	merchantResult, err := repo.LoadMerchants()
+	merchantResult, err := repo.LoadMerchants(context.Background())
 
 	assert.Nil(t, merchantResult)
 	assert.NotNil(t, err)
@@ -71,7 +72,7 @@ func TestLoadMerchant_ShouldReturnError_WhenInvalidContent(t *testing.T) {
 	log := logrus.New()
 	repo := impl.NewMerchantRepositoryImpl(log, helper.MerchantFilename)
 
-	customerResults, err := repo.LoadMerchants()
+	customerResults, err := repo.LoadMerchants(context.Background())
 
 	assert.Nil(t, customerResults)
 	assert.NotNil(t, err)
@@ -89,7 +90,7 @@ func TestFindById_ShouldReturnMerchant(t *testing.T) {
 	log := logrus.New()
 	repo := impl.NewMerchantRepositoryImpl(log, helper.MerchantFilename)
 
-	merchantResult, err := repo.FindById(helper.MerchantId)
+	merchantResult, err := repo.FindById(context.Background(), helper.MerchantId)
 	assert.Nil(t, err)
 	assert.Equal(t, helper.ExpectedMerchants[0].Id, merchantResult.Id)
 	assert.Equal(t, helper.ExpectedMerchants[0].Name, merchantResult.Name)
@@ -105,7 +106,7 @@ func TestFindById_ShouldReturnError(t *testing.T) {
 	log := logrus.New()
 	repo := impl.NewMerchantRepositoryImpl(log, helper.MerchantFilename)
 
-	loadedMerchant, err := repo.FindById(merchantUuid)
+	loadedMerchant, err := repo.FindById(context.Background(), merchantUuid)
 	assert.NotNil(t, err)
 	assert.Equal(t, entity.Merchant{}, loadedMerchant)
 }
@@ -114,7 +115,57 @@ func TestFindById_ShouldReturnError_WhenLoadMerchantError(t *testing.T) {
 	log := logrus.New()
 	repo := impl.NewMerchantRepositoryImpl(log, "abc/invalidpath")
 
-	_, err := repo.FindById(uuid.New())
+	_, err := repo.FindById(context.Background(), uuid.New())
+
+	assert.NotNil(t, err)
+}
+
+func TestSaveMerchant_ShouldAppendMerchant(t *testing.T) {
+	t.Cleanup(DeleteMerchantTempFile)
+	CreateMerchantTempFile()
+
+	log := logrus.New()
+	repo := impl.NewMerchantRepositoryImpl(log, helper.MerchantFilename)
+	merchant := entity.Merchant{Id: uuid.New(), Name: "toko baru", Status: entity.MerchantStatusActive}
+
+	err := repo.Save(context.Background(), merchant)
+	assert.Nil(t, err)
+
+	saved, err := repo.FindById(context.Background(), merchant.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, merchant.Name, saved.Name)
+
+	err = repo.Save(context.Background(), merchant)
+	assert.NotNil(t, err)
+	merchants, _ := repo.LoadMerchants(context.Background())
+	assert.Equal(t, len(helper.ExpectedMerchants)+1, len(merchants))
+}
+
+func TestUpdateMerchant_ShouldReplaceMerchant(t *testing.T) {
+	t.Cleanup(DeleteMerchantTempFile)
+	CreateMerchantTempFile()
+
+	log := logrus.New()
+	repo := impl.NewMerchantRepositoryImpl(log, helper.MerchantFilename)
+	merchant := helper.ExpectedMerchants[0]
+	merchant.Status = entity.MerchantStatusSuspended
+
+	err := repo.Update(context.Background(), merchant)
+	assert.Nil(t, err)
+
+	updated, err := repo.FindById(context.Background(), merchant.Id)
+	assert.Nil(t, err)
+	assert.True(t, updated.IsSuspended())
+}
+
+func TestUpdateMerchant_ShouldReturnError_WhenNotFound(t *testing.T) {
+	t.Cleanup(DeleteMerchantTempFile)
+	CreateMerchantTempFile()
+
+	log := logrus.New()
+	repo := impl.NewMerchantRepositoryImpl(log, helper.MerchantFilename)
+
+	err := repo.Update(context.Background(), entity.Merchant{Id: uuid.New()})
 
 	assert.NotNil(t, err)
 }
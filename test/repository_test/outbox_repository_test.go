@@ -0,0 +1,180 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/repository/impl"
+	"merchant_bank_payment_go_api/internal/utils"
+	"merchant_bank_payment_go_api/test/helper"
+	"os"
+	"sync"
+	"testing"
+)
+
+func CreateOutboxTempFile() {
+	err := os.WriteFile(helper.OutboxTempFilename, []byte(`[]`), 0644)
+	if err != nil {
+		logrus.Error("Error writing to file:", err)
+	}
+}
+
+func DeleteOutboxTempFile() {
+	err := os.Remove(helper.OutboxTempFilename)
+	if err != nil && !os.IsNotExist(err) {
+		logrus.Error("Error removing file:", err)
+	}
+}
+
+func TestCommitWithEvents_ShouldStoreStateAndEventsTogether(t *testing.T) {
+	t.Cleanup(DeleteOutboxTempFile)
+	t.Cleanup(DeletePaymentTransactionTempFile)
+	CreateOutboxTempFile()
+	CreatePaymentTransactionTempFile()
+
+	log := logrus.New()
+	outboxRepository := impl.NewOutboxRepositoryImpl(log, helper.OutboxTempFilename)
+	paymentRepository := impl.NewPaymentTransactionImpl(log, helper.PaymentTransactionTempFilename)
+
+	payment := helper.ExpectedPayments[0]
+	payment.Id = uuid.New()
+	event, err := entity.NewDomainEvent(entity.EventPaymentCreated, payment.Id.String(), payment)
+	assert.Nil(t, err)
+
+	err = outboxRepository.CommitWithEvents(context.Background(), func(tx *utils.JsonFileTransaction) ([]entity.DomainEvent, error) {
+		return []entity.DomainEvent{event}, paymentRepository.StageAddPayment(context.Background(), tx, payment)
+	})
+	assert.Nil(t, err)
+
+	payments, err := paymentRepository.LoadPayments(context.Background())
+	assert.Nil(t, err)
+	assert.Len(t, payments, 2)
+
+	events, err := outboxRepository.LoadEvents(context.Background())
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, event.Id, events[0].Id)
+	assert.Equal(t, entity.DomainEventStatusPending, events[0].Status)
+}
+
+func TestCommitWithEvents_ShouldKeepEveryPayment_WhenCommittedConcurrently(t *testing.T) {
+	t.Cleanup(DeleteOutboxTempFile)
+	t.Cleanup(DeletePaymentTransactionTempFile)
+	CreateOutboxTempFile()
+	CreatePaymentTransactionTempFile()
+
+	log := logrus.New()
+	outboxRepository := impl.NewOutboxRepositoryImpl(log, helper.OutboxTempFilename)
+	paymentRepository := impl.NewPaymentTransactionImpl(log, helper.PaymentTransactionTempFilename)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			payment := helper.ExpectedPayments[0]
+			payment.Id = uuid.New()
+			event, _ := entity.NewDomainEvent(entity.EventPaymentCreated, payment.Id.String(), payment)
+			err := outboxRepository.CommitWithEvents(context.Background(), func(tx *utils.JsonFileTransaction) ([]entity.DomainEvent, error) {
+				return []entity.DomainEvent{event}, paymentRepository.StageAddPayment(context.Background(), tx, payment)
+			})
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+
+	payments, err := paymentRepository.LoadPayments(context.Background())
+	assert.Nil(t, err)
+	assert.Len(t, payments, 21)
+
+	events, err := outboxRepository.LoadEvents(context.Background())
+	assert.Nil(t, err)
+	assert.Len(t, events, 20)
+}
+
+func TestCommitWithEvents_ShouldNotCommit_WhenStageFails(t *testing.T) {
+	t.Cleanup(DeleteOutboxTempFile)
+	CreateOutboxTempFile()
+
+	log := logrus.New()
+	outboxRepository := impl.NewOutboxRepositoryImpl(log, helper.OutboxTempFilename)
+
+	event, _ := entity.NewDomainEvent(entity.EventTokenRevoked, "customer", nil)
+	err := outboxRepository.CommitWithEvents(context.Background(), func(tx *utils.JsonFileTransaction) ([]entity.DomainEvent, error) {
+		return []entity.DomainEvent{event}, errors.New("payments unreadable")
+	})
+	assert.NotNil(t, err)
+
+	events, _ := outboxRepository.LoadEvents(context.Background())
+	assert.Empty(t, events)
+}
+
+func TestCommitWithEvents_ShouldReturnError_WhenOutboxMissing(t *testing.T) {
+	log := logrus.New()
+	outboxRepository := impl.NewOutboxRepositoryImpl(log, "empty.json")
+
+	event, _ := entity.NewDomainEvent(entity.EventLoginSucceeded, "customer", nil)
+	err := outboxRepository.CommitWithEvents(context.Background(), withEvents(event))
+
+	assert.NotNil(t, err)
+}
+
+func TestUpdateOutboxEvent_ShouldReplaceEvent(t *testing.T) {
+	t.Cleanup(DeleteOutboxTempFile)
+	CreateOutboxTempFile()
+
+	log := logrus.New()
+	outboxRepository := impl.NewOutboxRepositoryImpl(log, helper.OutboxTempFilename)
+
+	event, _ := entity.NewDomainEvent(entity.EventLoginSucceeded, "customer", nil)
+	assert.Nil(t, outboxRepository.CommitWithEvents(context.Background(), withEvents(event)))
+
+	event.Status = entity.DomainEventStatusDispatched
+	err := outboxRepository.UpdateEvent(context.Background(), event)
+	assert.Nil(t, err)
+
+	events, _ := outboxRepository.LoadEvents(context.Background())
+	assert.Equal(t, entity.DomainEventStatusDispatched, events[0].Status)
+
+	event.Id = uuid.New()
+	assert.NotNil(t, outboxRepository.UpdateEvent(context.Background(), event))
+}
+
+func TestPruneDispatched_ShouldKeepPendingEvents(t *testing.T) {
+	t.Cleanup(DeleteOutboxTempFile)
+	CreateOutboxTempFile()
+
+	log := logrus.New()
+	outboxRepository := impl.NewOutboxRepositoryImpl(log, helper.OutboxTempFilename)
+
+	dispatched, _ := entity.NewDomainEvent(entity.EventPaymentCreated, "payment", nil)
+	pending, _ := entity.NewDomainEvent(entity.EventPaymentCreated, "payment", nil)
+	assert.Nil(t, outboxRepository.CommitWithEvents(context.Background(), withEvents(dispatched, pending)))
+
+	dispatched.Status = entity.DomainEventStatusDispatched
+	assert.Nil(t, outboxRepository.UpdateEvent(context.Background(), dispatched))
+
+	pruned, err := outboxRepository.PruneDispatched(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, pruned)
+
+	events, _ := outboxRepository.LoadEvents(context.Background())
+	assert.Len(t, events, 1)
+	assert.Equal(t, pending.Id, events[0].Id)
+}
+
+func TestRecoverOutbox_ShouldSucceed_WhenNothingToRecover(t *testing.T) {
+	log := logrus.New()
+	outboxRepository := impl.NewOutboxRepositoryImpl(log, helper.OutboxTempFilename)
+
+	assert.Nil(t, outboxRepository.Recover(context.Background()))
+}
+
+func withEvents(events ...entity.DomainEvent) func(tx *utils.JsonFileTransaction) ([]entity.DomainEvent, error) {
+	return func(tx *utils.JsonFileTransaction) ([]entity.DomainEvent, error) {
+		return events, nil
+	}
+}
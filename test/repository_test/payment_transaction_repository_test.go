@@ -1,6 +1,7 @@
 package repository_test
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
@@ -40,7 +41,7 @@ func TestLoadPaymentTransaction_ShouldReturnPaymentTransactionToken(t *testing.T
 	log := logrus.New()
 	repo := impl.NewPaymentTransactionImpl(log, helper.PaymentTransactionTempFilename)
 
-	paymentTransactionResult, err := repo.LoadPayments()
+	paymentTransactionResult, err := repo.LoadPayments(context.Background())
 
 	assert.Nil(t, err)
 	assert.Equal(t, len(helper.ExpectedPayments), len(paymentTransactionResult))
@@ -52,7 +53,7 @@ func TestLoadPaymentTransaction_ShouldReturnError_WhenInvalidFilename(t *testing
 	log := logrus.New()
 	repo := impl.NewPaymentTransactionImpl(log, invalidFilename)
 
-	paymentTransactionResult, err := repo.LoadPayments()
+	paymentTransactionResult, err := repo.LoadPayments(context.Background())
 
 	assert.Nil(t, paymentTransactionResult)
 	assert.NotNil(t, err)
@@ -68,7 +69,7 @@ func TestLoadPayment_ShouldReturnError_WhenInvalidContent(t *testing.T) {
 	log := logrus.New()
 	repo := impl.NewPaymentTransactionImpl(log, helper.PaymentTransactionTempFilename)
 
-	paymentResult, err := repo.LoadPayments()
+	paymentResult, err := repo.LoadPayments(context.Background())
 
 	assert.Nil(t, paymentResult)
 	assert.NotNil(t, err)
@@ -87,7 +88,7 @@ func TestSavePaymentTransaction_ShouldReturnSuccess(t *testing.T) {
 		Id:         uuid.New(),
 		CustomerId: helper.CustomerId,
 		MerchantId: helper.MerchantId,
-		Amount:     10000,
+		Money:      entity.Money{Amount: 10000, Currency: "IDR"},
 		Timestamp:  helper.CreatedAt,
 	}
 	addedPayments := helper.ExpectedPayments
@@ -96,7 +97,7 @@ func TestSavePaymentTransaction_ShouldReturnSuccess(t *testing.T) {
 	log := logrus.New()
 	repo := impl.NewPaymentTransactionImpl(log, helper.PaymentTransactionTempFilename)
 
-	err := repo.SavePayments(addedPayments)
+	err := repo.SavePayments(context.Background(), addedPayments)
 
 	fileContent, err := os.ReadFile(helper.PaymentTransactionTempFilename)
 	assert.Nil(t, err)
@@ -113,7 +114,7 @@ func TestSavePaymentTransaction_ShouldReturnError(t *testing.T) {
 	log := logrus.New()
 	repo := impl.NewPaymentTransactionImpl(log, invalidFilename)
 
-	err := repo.SavePayments(helper.ExpectedPayments)
+	err := repo.SavePayments(context.Background(), helper.ExpectedPayments)
 
 	assert.NotNil(t, err)
 }
@@ -126,7 +127,7 @@ func TestAddToPaymentTransaction_ShouldAddNewPayment(t *testing.T) {
 		Id:         uuid.New(),
 		CustomerId: helper.CustomerId,
 		MerchantId: helper.MerchantId,
-		Amount:     10000,
+		Money:      entity.Money{Amount: 10000, Currency: "IDR"},
 		Timestamp:  helper.CreatedAt,
 	}
 	addedPayments := helper.ExpectedPayments
@@ -135,7 +136,7 @@ func TestAddToPaymentTransaction_ShouldAddNewPayment(t *testing.T) {
 	log := logrus.New()
 	repo := impl.NewPaymentTransactionImpl(log, helper.PaymentTransactionTempFilename)
 
-	err := repo.AddPayment(newPayments)
+	err := repo.AddPayment(context.Background(), newPayments)
 	assert.Nil(t, err)
 
 	fileContent, err := os.ReadFile(helper.PaymentTransactionTempFilename)
@@ -155,14 +156,84 @@ func TestAddToPaymentTransaction_ShouldReturnError_WhenLoadFails(t *testing.T) {
 		Id:         uuid.New(),
 		CustomerId: helper.CustomerId,
 		MerchantId: helper.MerchantId,
-		Amount:     10000,
+		Money:      entity.Money{Amount: 10000, Currency: "IDR"},
 		Timestamp:  time.Now(),
 	}
 
 	log := logrus.New()
 	repo := impl.NewPaymentTransactionImpl(log, invalidFilename)
 
-	err := repo.AddPayment(newPayments)
+	err := repo.AddPayment(context.Background(), newPayments)
+
+	assert.NotNil(t, err)
+}
+
+func TestFindPaymentById_ShouldReturnPayment(t *testing.T) {
+	t.Cleanup(DeletePaymentTransactionTempFile)
+	CreatePaymentTransactionTempFile()
+
+	log := logrus.New()
+	repo := impl.NewPaymentTransactionImpl(log, helper.PaymentTransactionTempFilename)
+
+	paymentResult, err := repo.FindById(context.Background(), helper.ExpectedPayments[0].Id)
+
+	assert.Nil(t, err)
+	assert.Equal(t, helper.ExpectedPayments[0].Id, paymentResult.Id)
+	assert.Equal(t, helper.ExpectedPayments[0].Money, paymentResult.Money)
+}
+
+func TestFindPaymentById_ShouldReturnError_WhenNotFound(t *testing.T) {
+	t.Cleanup(DeletePaymentTransactionTempFile)
+	CreatePaymentTransactionTempFile()
+
+	log := logrus.New()
+	repo := impl.NewPaymentTransactionImpl(log, helper.PaymentTransactionTempFilename)
+
+	_, err := repo.FindById(context.Background(), uuid.New())
+
+	assert.NotNil(t, err)
+}
+
+func TestFindPaymentsByCustomerId_ShouldReturnCustomerPayments(t *testing.T) {
+	t.Cleanup(DeletePaymentTransactionTempFile)
+	CreatePaymentTransactionTempFile()
+
+	log := logrus.New()
+	repo := impl.NewPaymentTransactionImpl(log, helper.PaymentTransactionTempFilename)
+
+	paymentsResult, err := repo.FindByCustomerId(context.Background(), helper.CustomerId)
+	assert.Nil(t, err)
+	assert.Equal(t, len(helper.ExpectedPayments), len(paymentsResult))
+
+	otherPaymentsResult, err := repo.FindByCustomerId(context.Background(), uuid.New())
+	assert.Nil(t, err)
+	assert.Empty(t, otherPaymentsResult)
+}
+
+func TestStreamPayments_ShouldVisitEveryPayment(t *testing.T) {
+	t.Cleanup(DeletePaymentTransactionTempFile)
+	CreatePaymentTransactionTempFile()
+
+	log := logrus.New()
+	repo := impl.NewPaymentTransactionImpl(log, helper.PaymentTransactionTempFilename)
+
+	var visited []uuid.UUID
+	err := repo.StreamPayments(context.Background(), func(payment entity.Payment) error {
+		visited = append(visited, payment.Id)
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []uuid.UUID{helper.ExpectedPayments[0].Id}, visited)
+}
+
+func TestStreamPayments_ShouldReturnError_WhenInvalidFilename(t *testing.T) {
+	log := logrus.New()
+	repo := impl.NewPaymentTransactionImpl(log, "empty.json")
+
+	err := repo.StreamPayments(context.Background(), func(payment entity.Payment) error {
+		return nil
+	})
 
 	assert.NotNil(t, err)
 }
@@ -0,0 +1,111 @@
+package repository_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/repository/impl"
+	"merchant_bank_payment_go_api/test/helper"
+	"os"
+	"testing"
+)
+
+func CreateSettlementTempFile() {
+	fileContent, err := json.Marshal(helper.ExpectedSettlements)
+	if err != nil {
+		logrus.Error("Error marshalling data:", err)
+		return
+	}
+
+	err = os.WriteFile(helper.SettlementTempFilename, fileContent, 0644)
+	if err != nil {
+		logrus.Error("Error writing to file:", err)
+	}
+}
+
+func DeleteSettlementTempFile() {
+	err := os.Remove(helper.SettlementTempFilename)
+	if err != nil && !os.IsNotExist(err) {
+		logrus.Error("Error removing file:", err)
+	}
+}
+
+func TestLoadSettlements_ShouldReturnSettlements(t *testing.T) {
+	t.Cleanup(DeleteSettlementTempFile)
+	CreateSettlementTempFile()
+
+	log := logrus.New()
+	repo := impl.NewSettlementRepositoryImpl(log, helper.SettlementTempFilename)
+
+	settlementsResult, err := repo.LoadSettlements(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, helper.ExpectedSettlements, settlementsResult)
+}
+
+func TestLoadSettlements_ShouldReturnError_WhenInvalidFilename(t *testing.T) {
+	log := logrus.New()
+	repo := impl.NewSettlementRepositoryImpl(log, "empty.json")
+
+	settlementsResult, err := repo.LoadSettlements(context.Background())
+
+	assert.Nil(t, settlementsResult)
+	assert.NotNil(t, err)
+}
+
+func TestSaveSettlements_ShouldWriteSettlements(t *testing.T) {
+	t.Cleanup(DeleteSettlementTempFile)
+	CreateSettlementTempFile()
+
+	newSettlement := helper.ExpectedSettlements[0]
+	newSettlement.Id = uuid.New()
+	addedSettlements := append([]entity.Settlement{}, helper.ExpectedSettlements...)
+	addedSettlements = append(addedSettlements, newSettlement)
+
+	log := logrus.New()
+	repo := impl.NewSettlementRepositoryImpl(log, helper.SettlementTempFilename)
+
+	err := repo.SaveSettlements(context.Background(), addedSettlements)
+	assert.Nil(t, err)
+
+	settlementsResult, err := repo.LoadSettlements(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, addedSettlements, settlementsResult)
+}
+
+func TestSaveSettlements_ShouldReturnError(t *testing.T) {
+	log := logrus.New()
+	repo := impl.NewSettlementRepositoryImpl(log, "abc/test_settlement.json")
+
+	err := repo.SaveSettlements(context.Background(), helper.ExpectedSettlements)
+
+	assert.NotNil(t, err)
+}
+
+func TestFindSettlementById_ShouldReturnSettlement(t *testing.T) {
+	t.Cleanup(DeleteSettlementTempFile)
+	CreateSettlementTempFile()
+
+	log := logrus.New()
+	repo := impl.NewSettlementRepositoryImpl(log, helper.SettlementTempFilename)
+
+	settlementResult, err := repo.FindById(context.Background(), helper.ExpectedSettlements[0].Id)
+
+	assert.Nil(t, err)
+	assert.Equal(t, helper.ExpectedSettlements[0], settlementResult)
+}
+
+func TestFindSettlementById_ShouldReturnError_WhenNotFound(t *testing.T) {
+	t.Cleanup(DeleteSettlementTempFile)
+	CreateSettlementTempFile()
+
+	log := logrus.New()
+	repo := impl.NewSettlementRepositoryImpl(log, helper.SettlementTempFilename)
+
+	_, err := repo.FindById(context.Background(), uuid.New())
+
+	assert.NotNil(t, err)
+}
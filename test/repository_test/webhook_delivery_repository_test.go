@@ -0,0 +1,134 @@
+package repository_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/repository/impl"
+	"merchant_bank_payment_go_api/test/helper"
+	"os"
+	"testing"
+)
+
+func CreateWebhookDeliveryTempFile() {
+	fileContent, err := json.Marshal(helper.ExpectedWebhookDeliveries)
+	if err != nil {
+		logrus.Error("Error marshalling data:", err)
+		return
+	}
+
+	err = os.WriteFile(helper.WebhookDeliveryTempFilename, fileContent, 0644)
+	if err != nil {
+		logrus.Error("Error writing to file:", err)
+	}
+}
+
+func DeleteWebhookDeliveryTempFile() {
+	err := os.Remove(helper.WebhookDeliveryTempFilename)
+	if err != nil && !os.IsNotExist(err) {
+		logrus.Error("Error removing file:", err)
+	}
+}
+
+func TestLoadWebhookDeliveries_ShouldReturnDeliveries(t *testing.T) {
+	t.Cleanup(DeleteWebhookDeliveryTempFile)
+	CreateWebhookDeliveryTempFile()
+
+	log := logrus.New()
+	repo := impl.NewWebhookDeliveryRepositoryImpl(log, helper.WebhookDeliveryTempFilename)
+
+	deliveriesResult, err := repo.LoadDeliveries(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, helper.ExpectedWebhookDeliveries, deliveriesResult)
+}
+
+func TestLoadWebhookDeliveries_ShouldReturnError_WhenInvalidFilename(t *testing.T) {
+	log := logrus.New()
+	repo := impl.NewWebhookDeliveryRepositoryImpl(log, "empty.json")
+
+	_, err := repo.LoadDeliveries(context.Background())
+
+	assert.NotNil(t, err)
+}
+
+func TestAddWebhookDelivery_ShouldAppendDelivery(t *testing.T) {
+	t.Cleanup(DeleteWebhookDeliveryTempFile)
+	CreateWebhookDeliveryTempFile()
+
+	log := logrus.New()
+	repo := impl.NewWebhookDeliveryRepositoryImpl(log, helper.WebhookDeliveryTempFilename)
+
+	newDelivery := helper.ExpectedWebhookDeliveries[0]
+	newDelivery.Id = uuid.New()
+	err := repo.AddDelivery(context.Background(), newDelivery)
+	assert.Nil(t, err)
+
+	deliveriesResult, err := repo.LoadDeliveries(context.Background())
+	assert.Nil(t, err)
+	assert.Len(t, deliveriesResult, 2)
+	assert.Equal(t, newDelivery.Id, deliveriesResult[1].Id)
+}
+
+func TestUpdateWebhookDelivery_ShouldReplaceDelivery(t *testing.T) {
+	t.Cleanup(DeleteWebhookDeliveryTempFile)
+	CreateWebhookDeliveryTempFile()
+
+	log := logrus.New()
+	repo := impl.NewWebhookDeliveryRepositoryImpl(log, helper.WebhookDeliveryTempFilename)
+
+	updated := helper.ExpectedWebhookDeliveries[0]
+	updated.Status = entity.WebhookDeliveryStatusDeadLetter
+	updated.Attempts = 8
+	err := repo.UpdateDelivery(context.Background(), updated)
+	assert.Nil(t, err)
+
+	deliveryResult, err := repo.FindById(context.Background(), updated.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, entity.WebhookDeliveryStatusDeadLetter, deliveryResult.Status)
+	assert.Equal(t, 8, deliveryResult.Attempts)
+}
+
+func TestUpdateWebhookDelivery_ShouldReturnError_WhenNotFound(t *testing.T) {
+	t.Cleanup(DeleteWebhookDeliveryTempFile)
+	CreateWebhookDeliveryTempFile()
+
+	log := logrus.New()
+	repo := impl.NewWebhookDeliveryRepositoryImpl(log, helper.WebhookDeliveryTempFilename)
+
+	unknown := helper.ExpectedWebhookDeliveries[0]
+	unknown.Id = uuid.New()
+	err := repo.UpdateDelivery(context.Background(), unknown)
+
+	assert.NotNil(t, err)
+}
+
+func TestFindWebhookDeliveryById_ShouldReturnError_WhenNotFound(t *testing.T) {
+	t.Cleanup(DeleteWebhookDeliveryTempFile)
+	CreateWebhookDeliveryTempFile()
+
+	log := logrus.New()
+	repo := impl.NewWebhookDeliveryRepositoryImpl(log, helper.WebhookDeliveryTempFilename)
+
+	_, err := repo.FindById(context.Background(), uuid.New())
+
+	assert.NotNil(t, err)
+}
+
+func TestAddWebhookDelivery_ShouldIgnoreDuplicateId(t *testing.T) {
+	t.Cleanup(DeleteWebhookDeliveryTempFile)
+	CreateWebhookDeliveryTempFile()
+
+	log := logrus.New()
+	repo := impl.NewWebhookDeliveryRepositoryImpl(log, helper.WebhookDeliveryTempFilename)
+
+	err := repo.AddDelivery(context.Background(), helper.ExpectedWebhookDeliveries[0])
+	assert.Nil(t, err)
+
+	deliveriesResult, err := repo.LoadDeliveries(context.Background())
+	assert.Nil(t, err)
+	assert.Len(t, deliveriesResult, 1)
+}
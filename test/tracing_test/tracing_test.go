@@ -0,0 +1,72 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"merchant_bank_payment_go_api/internal/tracing"
+	"testing"
+)
+
+func setupTracingRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+	})
+	return recorder
+}
+
+func TestRecordError_ShouldMarkSpanAsFailed(t *testing.T) {
+	recorder := setupTracingRecorder(t)
+
+	ctx, span := tracing.Start(context.Background(), "CustomerUseCase.FindById")
+	tracing.RecordError(ctx, nil)
+	tracing.RecordError(ctx, errors.New("customer not found"))
+	span.End()
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "customer not found", spans[0].Status().Description)
+	assert.Len(t, spans[0].Events(), 1)
+}
+
+func TestLogHook_ShouldAddTraceIdsAndFailSpanOnErrorLogs(t *testing.T) {
+	recorder := setupTracingRecorder(t)
+
+	var output bytes.Buffer
+	log := logrus.New()
+	log.SetOutput(&output)
+	log.SetFormatter(&logrus.JSONFormatter{})
+	log.AddHook(tracing.LogHook{})
+
+	ctx, span := tracing.Start(context.Background(), "MerchantUseCase.FindById")
+	log.WithContext(ctx).Info("looking up merchant")
+	log.WithContext(context.Background()).Info("no span")
+	log.WithContext(ctx).Error("merchant lookup failed")
+	span.End()
+
+	lines := bytes.Split(bytes.TrimSpace(output.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 3)
+
+	var traced, untraced map[string]any
+	assert.NoError(t, json.Unmarshal(lines[0], &traced))
+	assert.NoError(t, json.Unmarshal(lines[1], &untraced))
+	assert.Equal(t, span.SpanContext().TraceID().String(), traced["trace_id"])
+	assert.Equal(t, span.SpanContext().SpanID().String(), traced["span_id"])
+	assert.NotContains(t, untraced, "trace_id")
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "merchant lookup failed", spans[0].Status().Description)
+}
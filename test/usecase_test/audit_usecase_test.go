@@ -0,0 +1,249 @@
+package usecase_test
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/usecase/impl"
+	"merchant_bank_payment_go_api/test/helper"
+	"testing"
+	"time"
+)
+
+var auditKey = []byte("audit-key")
+
+// chainedHistories seals copies of the fixture histories into a chain that
+// starts after one unchained legacy record.
+func chainedHistories(key []byte) []entity.History {
+	histories := []entity.History{helper.ExpectedHistories[0]}
+	for _, history := range helper.ExpectedHistories {
+		var prev *entity.History
+		if last := histories[len(histories)-1]; last.IsChained() {
+			prev = &last
+		}
+		history.Seal(prev, key)
+		histories = append(histories, history)
+	}
+	return histories
+}
+
+func newAuditUseCase(histories []entity.History, checkpoints []entity.AuditCheckpoint, key []byte) (*impl.AuditUseCaseImpl, *helper.MockAuditCheckpointRepository) {
+	mockHistoryRepository := new(helper.MockHistoryRepository)
+	mockHistoryRepository.On("LoadHistories", mock.Anything).Return(histories, nil)
+
+	mockHistoryArchiveRepository := new(helper.MockHistoryArchiveRepository)
+	mockHistoryArchiveRepository.On("LoadArchives", mock.Anything).Return([]entity.HistoryArchive{}, nil)
+
+	mockAuditCheckpointRepository := new(helper.MockAuditCheckpointRepository)
+	mockAuditCheckpointRepository.On("LoadCheckpoints", mock.Anything).Return(checkpoints, nil)
+
+	return impl.NewAuditUseCaseImpl(logrus.New(), mockHistoryRepository, mockHistoryArchiveRepository, mockAuditCheckpointRepository, key),
+		mockAuditCheckpointRepository
+}
+
+func TestVerifyChain_ShouldSucceed_WhenChainIntact(t *testing.T) {
+	histories := chainedHistories(auditKey)
+	checkpoint := entity.NewAuditCheckpoint(histories[2], auditKey, time.Now())
+	auditUseCase, _ := newAuditUseCase(histories, []entity.AuditCheckpoint{checkpoint}, auditKey)
+
+	result, err := auditUseCase.VerifyChain(context.Background())
+
+	assert.Nil(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, 4, result.Records)
+	assert.Equal(t, 1, result.LegacyRecords)
+	assert.Equal(t, int64(3), result.LastSequence)
+	assert.Equal(t, 1, result.CheckpointsVerified)
+	assert.True(t, result.MacChecked)
+	assert.Nil(t, result.BrokenLink)
+}
+
+func TestVerifyChain_ShouldReportFirstBrokenLink_WhenRecordEdited(t *testing.T) {
+	histories := chainedHistories(auditKey)
+	histories[2].Details.Message = "Payment of 1 IDR"
+	auditUseCase, _ := newAuditUseCase(histories, []entity.AuditCheckpoint{}, auditKey)
+
+	result, err := auditUseCase.VerifyChain(context.Background())
+
+	assert.Nil(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, int64(2), result.BrokenLink.Sequence)
+	assert.Equal(t, histories[2].Id.String(), result.BrokenLink.Id)
+}
+
+func TestVerifyChain_ShouldReportBrokenLink_WhenRecordRemoved(t *testing.T) {
+	histories := chainedHistories(auditKey)
+	histories = append(histories[:2], histories[3])
+	auditUseCase, _ := newAuditUseCase(histories, []entity.AuditCheckpoint{}, auditKey)
+
+	result, err := auditUseCase.VerifyChain(context.Background())
+
+	assert.Nil(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, int64(3), result.BrokenLink.Sequence)
+}
+
+func TestVerifyChain_ShouldReportBrokenLink_WhenChainRehashedWithoutKey(t *testing.T) {
+	histories := chainedHistories(auditKey)
+	histories[3].Details.Message = "edited"
+	histories[3].Seal(&histories[2], []byte("other-key"))
+	auditUseCase, _ := newAuditUseCase(histories, []entity.AuditCheckpoint{}, auditKey)
+
+	result, err := auditUseCase.VerifyChain(context.Background())
+
+	assert.Nil(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, "invalid record MAC", result.BrokenLink.Reason)
+}
+
+func TestVerifyChain_ShouldReportBrokenLink_WhenHistoryTruncatedBeforeCheckpoint(t *testing.T) {
+	histories := chainedHistories(auditKey)
+	checkpoint := entity.NewAuditCheckpoint(histories[3], auditKey, time.Now())
+	auditUseCase, _ := newAuditUseCase(histories[:3], []entity.AuditCheckpoint{checkpoint}, auditKey)
+
+	result, err := auditUseCase.VerifyChain(context.Background())
+
+	assert.Nil(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, int64(3), result.BrokenLink.Sequence)
+}
+
+func TestVerifyChain_ShouldReportBrokenLink_WhenCheckpointForged(t *testing.T) {
+	histories := chainedHistories(auditKey)
+	checkpoint := entity.NewAuditCheckpoint(histories[3], []byte("other-key"), time.Now())
+	auditUseCase, _ := newAuditUseCase(histories, []entity.AuditCheckpoint{checkpoint}, auditKey)
+
+	result, err := auditUseCase.VerifyChain(context.Background())
+
+	assert.Nil(t, err)
+	assert.False(t, result.Valid)
+}
+
+func TestCreateCheckpoint_ShouldSignHead(t *testing.T) {
+	histories := chainedHistories(auditKey)
+	auditUseCase, mockAuditCheckpointRepository := newAuditUseCase(histories, []entity.AuditCheckpoint{}, auditKey)
+	mockAuditCheckpointRepository.On("AddCheckpoint", mock.Anything, mock.MatchedBy(func(checkpoint entity.AuditCheckpoint) bool {
+		return checkpoint.Sequence == 3 && checkpoint.Hash == histories[3].Hash && checkpoint.ValidSignature(auditKey)
+	})).Return(nil)
+
+	checkpoint, err := auditUseCase.CreateCheckpoint(context.Background())
+
+	assert.Nil(t, err)
+	assert.NotNil(t, checkpoint)
+	mockAuditCheckpointRepository.AssertExpectations(t)
+}
+
+func TestCreateCheckpoint_ShouldSkip_WhenHeadAlreadyCheckpointed(t *testing.T) {
+	histories := chainedHistories(auditKey)
+	existing := entity.NewAuditCheckpoint(histories[3], auditKey, time.Now())
+	auditUseCase, mockAuditCheckpointRepository := newAuditUseCase(histories, []entity.AuditCheckpoint{existing}, auditKey)
+
+	checkpoint, err := auditUseCase.CreateCheckpoint(context.Background())
+
+	assert.Nil(t, err)
+	assert.Nil(t, checkpoint)
+	mockAuditCheckpointRepository.AssertNotCalled(t, "AddCheckpoint", mock.Anything, mock.Anything)
+}
+
+func TestCreateCheckpoint_ShouldReturnError_WhenNoKey(t *testing.T) {
+	auditUseCase, _ := newAuditUseCase(chainedHistories(nil), []entity.AuditCheckpoint{}, nil)
+
+	checkpoint, err := auditUseCase.CreateCheckpoint(context.Background())
+
+	assert.NotNil(t, err)
+	assert.Nil(t, checkpoint)
+}
+
+func newArchivedAuditUseCase(histories []entity.History, archived int) *impl.AuditUseCaseImpl {
+	archive := entity.HistoryArchive{
+		Id:            uuid.New(),
+		Filename:      "history-archive.json.gz",
+		Records:       archived,
+		FirstSequence: histories[0].Sequence,
+		LastSequence:  histories[archived-1].Sequence,
+		LastHash:      histories[archived-1].Hash,
+	}
+
+	mockHistoryRepository := new(helper.MockHistoryRepository)
+	mockHistoryRepository.On("LoadHistories", mock.Anything).Return(histories[archived:], nil)
+
+	mockHistoryArchiveRepository := new(helper.MockHistoryArchiveRepository)
+	mockHistoryArchiveRepository.On("LoadArchives", mock.Anything).Return([]entity.HistoryArchive{archive}, nil)
+	mockHistoryArchiveRepository.On("LoadArchivedHistories", mock.Anything, archive).Return(histories[:archived], nil)
+
+	mockAuditCheckpointRepository := new(helper.MockAuditCheckpointRepository)
+	mockAuditCheckpointRepository.On("LoadCheckpoints", mock.Anything).Return([]entity.AuditCheckpoint{}, nil)
+
+	return impl.NewAuditUseCaseImpl(logrus.New(), mockHistoryRepository, mockHistoryArchiveRepository, mockAuditCheckpointRepository, auditKey)
+}
+
+func TestVerifyChain_ShouldFollowChainIntoArchives(t *testing.T) {
+	auditUseCase := newArchivedAuditUseCase(chainedHistories(auditKey), 2)
+
+	result, err := auditUseCase.VerifyChain(context.Background())
+
+	assert.Nil(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, 4, result.Records)
+	assert.Equal(t, 1, result.Archives)
+	assert.Equal(t, int64(3), result.LastSequence)
+}
+
+func TestVerifyChain_ShouldReportBrokenLink_WhenArchivedRecordEdited(t *testing.T) {
+	histories := chainedHistories(auditKey)
+	histories[1].Details.Message = "edited"
+	auditUseCase := newArchivedAuditUseCase(histories, 2)
+
+	result, err := auditUseCase.VerifyChain(context.Background())
+
+	assert.Nil(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, int64(1), result.BrokenLink.Sequence)
+}
+
+func TestVerifyChain_ShouldReportBrokenLink_WhenArchiveDoesNotMatchIndex(t *testing.T) {
+	histories := chainedHistories(auditKey)
+	mockHistoryRepository := new(helper.MockHistoryRepository)
+	mockHistoryRepository.On("LoadHistories", mock.Anything).Return(histories[2:], nil)
+
+	archive := entity.HistoryArchive{Id: uuid.New(), Filename: "history-archive.json.gz", Records: 2, LastHash: histories[1].Hash}
+	mockHistoryArchiveRepository := new(helper.MockHistoryArchiveRepository)
+	mockHistoryArchiveRepository.On("LoadArchives", mock.Anything).Return([]entity.HistoryArchive{archive}, nil)
+	mockHistoryArchiveRepository.On("LoadArchivedHistories", mock.Anything, archive).Return(histories[:1], nil)
+
+	mockAuditCheckpointRepository := new(helper.MockAuditCheckpointRepository)
+	mockAuditCheckpointRepository.On("LoadCheckpoints", mock.Anything).Return([]entity.AuditCheckpoint{}, nil)
+
+	auditUseCase := impl.NewAuditUseCaseImpl(logrus.New(), mockHistoryRepository, mockHistoryArchiveRepository, mockAuditCheckpointRepository, auditKey)
+
+	result, err := auditUseCase.VerifyChain(context.Background())
+
+	assert.Nil(t, err)
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.BrokenLink.Reason, "does not match its index entry")
+}
+
+func TestVerifyChain_ShouldReportBrokenLink_WhenArchiveIsEmpty(t *testing.T) {
+	histories := chainedHistories(auditKey)
+	mockHistoryRepository := new(helper.MockHistoryRepository)
+	mockHistoryRepository.On("LoadHistories", mock.Anything).Return(histories, nil)
+
+	archive := entity.HistoryArchive{Id: uuid.New(), Filename: "history-archive.json.gz"}
+	mockHistoryArchiveRepository := new(helper.MockHistoryArchiveRepository)
+	mockHistoryArchiveRepository.On("LoadArchives", mock.Anything).Return([]entity.HistoryArchive{archive}, nil)
+	mockHistoryArchiveRepository.On("LoadArchivedHistories", mock.Anything, archive).Return([]entity.History{}, nil)
+
+	mockAuditCheckpointRepository := new(helper.MockAuditCheckpointRepository)
+	mockAuditCheckpointRepository.On("LoadCheckpoints", mock.Anything).Return([]entity.AuditCheckpoint{}, nil)
+
+	auditUseCase := impl.NewAuditUseCaseImpl(logrus.New(), mockHistoryRepository, mockHistoryArchiveRepository, mockAuditCheckpointRepository, auditKey)
+
+	result, err := auditUseCase.VerifyChain(context.Background())
+
+	assert.Nil(t, err)
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.BrokenLink.Reason, "does not match its index entry")
+}
@@ -1,12 +1,15 @@
 package usecase_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/metrics"
 	"merchant_bank_payment_go_api/internal/model"
 	"merchant_bank_payment_go_api/internal/usecase/impl"
 	"merchant_bank_payment_go_api/internal/utils"
@@ -18,20 +21,20 @@ func TestLogin_ShouldReturnLoginResponse(t *testing.T) {
 	utils.InitJwtConfig([]byte("abc"), 10)
 
 	mockCustomerUseCase := new(helper.MockCustomerUseCase)
-	mockCustomerUseCase.On("FindByUsername", helper.ExpectedCustomers[0].Username).Return(helper.ExpectedCustomers[0], nil)
+	mockCustomerUseCase.On("FindByUsername", mock.Anything, helper.ExpectedCustomers[0].Username).Return(helper.ExpectedCustomers[0], nil)
 
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	mockAuthRepository := new(helper.MockAuthRepository)
-	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, mockCustomerUseCase, mockHistoryUseCase)
+	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, mockCustomerUseCase, mockHistoryUseCase, acceptingEventBus())
 
 	request := model.LoginRequest{
 		Username: helper.ExpectedCustomers[0].Username,
 		Password: "password",
 	}
 
-	response, err := authUseCase.Login(request)
+	response, err := authUseCase.Login(context.Background(), request)
 
 	assert.Nil(t, err)
 	assert.NotEmpty(t, response.AccessToken)
@@ -39,20 +42,20 @@ func TestLogin_ShouldReturnLoginResponse(t *testing.T) {
 
 func TestLogin_ShouldReturnError_WhenInvalidUsername(t *testing.T) {
 	mockCustomerUseCase := new(helper.MockCustomerUseCase)
-	mockCustomerUseCase.On("FindByUsername", "susi").Return(entity.Customer{}, errors.New("customer not found"))
+	mockCustomerUseCase.On("FindByUsername", mock.Anything, "susi").Return(entity.Customer{}, errors.New("customer not found"))
 
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	mockAuthRepository := new(helper.MockAuthRepository)
-	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, mockCustomerUseCase, mockHistoryUseCase)
+	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, mockCustomerUseCase, mockHistoryUseCase, acceptingEventBus())
 
 	request := model.LoginRequest{
 		Username: "susi",
 		Password: "password",
 	}
 
-	response, err := authUseCase.Login(request)
+	response, err := authUseCase.Login(context.Background(), request)
 
 	assert.NotNil(t, err)
 	assert.Empty(t, response.AccessToken)
@@ -60,21 +63,21 @@ func TestLogin_ShouldReturnError_WhenInvalidUsername(t *testing.T) {
 
 func TestLogin_ShouldReturnError_WhenInvalidPassword(t *testing.T) {
 	mockCustomerUseCase := new(helper.MockCustomerUseCase)
-	mockCustomerUseCase.On("FindByUsername", helper.ExpectedCustomers[0].Username).Return(helper.ExpectedCustomers[0], nil)
+	mockCustomerUseCase.On("FindByUsername", mock.Anything, helper.ExpectedCustomers[0].Username).Return(helper.ExpectedCustomers[0], nil)
 
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	mockAuthRepository := new(helper.MockAuthRepository)
 
-	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, mockCustomerUseCase, mockHistoryUseCase)
+	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, mockCustomerUseCase, mockHistoryUseCase, acceptingEventBus())
 
 	request := model.LoginRequest{
 		Username: helper.ExpectedCustomers[0].Username,
 		Password: "password123",
 	}
 
-	response, err := authUseCase.Login(request)
+	response, err := authUseCase.Login(context.Background(), request)
 
 	assert.NotNil(t, err)
 	assert.Empty(t, response.AccessToken)
@@ -82,20 +85,20 @@ func TestLogin_ShouldReturnError_WhenInvalidPassword(t *testing.T) {
 
 func TestLogin_ShouldReturnError_WhenErrorLogOnInvalidUsername(t *testing.T) {
 	mockCustomerUseCase := new(helper.MockCustomerUseCase)
-	mockCustomerUseCase.On("FindByUsername", "susi").Return(entity.Customer{}, errors.New("customer not found"))
+	mockCustomerUseCase.On("FindByUsername", mock.Anything, "susi").Return(entity.Customer{}, errors.New("customer not found"))
 
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("file history not exists"))
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("file history not exists"))
 
 	mockAuthRepository := new(helper.MockAuthRepository)
-	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, mockCustomerUseCase, mockHistoryUseCase)
+	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, mockCustomerUseCase, mockHistoryUseCase, acceptingEventBus())
 
 	request := model.LoginRequest{
 		Username: "susi",
 		Password: "password",
 	}
 
-	response, err := authUseCase.Login(request)
+	response, err := authUseCase.Login(context.Background(), request)
 
 	assert.NotNil(t, err)
 	assert.Empty(t, response.AccessToken)
@@ -112,21 +115,21 @@ func TestLogin_ShouldReturnError_WhenErrorLogOnInvalidPassword(t *testing.T) {
 	}
 
 	mockCustomerUseCase := new(helper.MockCustomerUseCase)
-	mockCustomerUseCase.On("FindByUsername", "budi").Return(mockCustomer, nil)
+	mockCustomerUseCase.On("FindByUsername", mock.Anything, "budi").Return(mockCustomer, nil)
 
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("file history not exists"))
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("file history not exists"))
 
 	mockAuthRepository := new(helper.MockAuthRepository)
 
-	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, mockCustomerUseCase, mockHistoryUseCase)
+	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, mockCustomerUseCase, mockHistoryUseCase, acceptingEventBus())
 
 	request := model.LoginRequest{
 		Username: "budi",
 		Password: "password123",
 	}
 
-	response, err := authUseCase.Login(request)
+	response, err := authUseCase.Login(context.Background(), request)
 
 	assert.NotNil(t, err)
 	assert.Empty(t, response.AccessToken)
@@ -134,21 +137,21 @@ func TestLogin_ShouldReturnError_WhenErrorLogOnInvalidPassword(t *testing.T) {
 
 func TestLogin_ShouldReturnError_WhenErrorLogOnFailedToLog(t *testing.T) {
 	mockCustomerUseCase := new(helper.MockCustomerUseCase)
-	mockCustomerUseCase.On("FindByUsername", helper.ExpectedCustomers[0].Username).Return(helper.ExpectedCustomers[0], nil)
+	mockCustomerUseCase.On("FindByUsername", mock.Anything, helper.ExpectedCustomers[0].Username).Return(helper.ExpectedCustomers[0], nil)
 
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("file history not exists"))
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("file history not exists"))
 
 	mockAuthRepository := new(helper.MockAuthRepository)
 
-	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, mockCustomerUseCase, mockHistoryUseCase)
+	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, mockCustomerUseCase, mockHistoryUseCase, acceptingEventBus())
 
 	request := model.LoginRequest{
 		Username: helper.ExpectedCustomers[0].Username,
 		Password: "password",
 	}
 
-	response, err := authUseCase.Login(request)
+	response, err := authUseCase.Login(context.Background(), request)
 
 	assert.NotNil(t, err)
 	assert.Empty(t, response.AccessToken)
@@ -158,30 +161,30 @@ func TestLogout_ShouldBlacklistToken(t *testing.T) {
 	accessToken, _ := utils.GenerateAccessToken(uuid.New().String())
 
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	mockAuthRepository := new(helper.MockAuthRepository)
-	mockAuthRepository.On("IsTokenBlacklisted", accessToken).Return(false, nil)
-	mockAuthRepository.On("AddToBlacklist", accessToken).Return(nil)
+	mockAuthRepository.On("IsTokenBlacklisted", mock.Anything, accessToken).Return(false, nil)
+	mockAuthRepository.On("StageAddToBlacklist", mock.Anything, mock.Anything, accessToken).Return(nil)
 
-	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, nil, mockHistoryUseCase)
+	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, nil, mockHistoryUseCase, acceptingEventBus())
 
-	err := authUseCase.Logout(accessToken)
+	err := authUseCase.Logout(context.Background(), accessToken)
 
 	assert.Nil(t, err)
 }
 
 func TestLogout_ShouldReturnError_WhenAddToBlacklistFails(t *testing.T) {
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	mockAuthRepository := new(helper.MockAuthRepository)
-	mockAuthRepository.On("IsTokenBlacklisted", "invalid_token").Return(false, nil)
-	mockAuthRepository.On("AddToBlacklist", "invalid_token").Return(fmt.Errorf("repository error"))
+	mockAuthRepository.On("IsTokenBlacklisted", mock.Anything, "invalid_token").Return(false, nil)
+	mockAuthRepository.On("StageAddToBlacklist", mock.Anything, mock.Anything, "invalid_token").Return(fmt.Errorf("repository error"))
 
-	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, nil, mockHistoryUseCase)
+	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, nil, mockHistoryUseCase, acceptingEventBus())
 
-	err := authUseCase.Logout("invalid_token")
+	err := authUseCase.Logout(context.Background(), "invalid_token")
 
 	assert.NotNil(t, err)
 }
@@ -190,15 +193,15 @@ func TestLogout_ShouldReturnError_WhenErrorLog(t *testing.T) {
 	accessToken, _ := utils.GenerateAccessToken(uuid.New().String())
 
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("file not exists"))
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("file not exists"))
 
 	mockAuthRepository := new(helper.MockAuthRepository)
-	mockAuthRepository.On("IsTokenBlacklisted", accessToken).Return(false, nil)
-	mockAuthRepository.On("AddToBlacklist", accessToken).Return(nil)
+	mockAuthRepository.On("IsTokenBlacklisted", mock.Anything, accessToken).Return(false, nil)
+	mockAuthRepository.On("StageAddToBlacklist", mock.Anything, mock.Anything, accessToken).Return(nil)
 
-	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, nil, mockHistoryUseCase)
+	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, nil, mockHistoryUseCase, acceptingEventBus())
 
-	err := authUseCase.Logout(accessToken)
+	err := authUseCase.Logout(context.Background(), accessToken)
 
 	assert.NotNil(t, err)
 }
@@ -207,16 +210,16 @@ func TestLogout_ShouldReturnError_WhenErrorLogOnLogSuccessBlacklistToken(t *test
 	accessToken, _ := utils.GenerateAccessToken(uuid.New().String())
 
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, "LOGOUT", "Customer ID extracted successfully", nil).Return(nil)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, "Token blacklisted successfully", nil).Return(errors.New("file not exists"))
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, entity.AuditActionLogout, helper.AuditMessage("Customer ID extracted successfully"), nil).Return(nil)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, helper.AuditMessage("Token blacklisted successfully"), nil).Return(errors.New("file not exists"))
 
 	mockAuthRepository := new(helper.MockAuthRepository)
-	mockAuthRepository.On("IsTokenBlacklisted", accessToken).Return(false, nil)
-	mockAuthRepository.On("AddToBlacklist", accessToken).Return(nil)
+	mockAuthRepository.On("IsTokenBlacklisted", mock.Anything, accessToken).Return(false, nil)
+	mockAuthRepository.On("StageAddToBlacklist", mock.Anything, mock.Anything, accessToken).Return(nil)
 
-	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, nil, mockHistoryUseCase)
+	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, nil, mockHistoryUseCase, acceptingEventBus())
 
-	err := authUseCase.Logout(accessToken)
+	err := authUseCase.Logout(context.Background(), accessToken)
 
 	assert.NotNil(t, err)
 }
@@ -225,31 +228,31 @@ func TestLogout_ShouldReturnError_WhenErrorLogOnLogSuccessLogout(t *testing.T) {
 	accessToken, _ := utils.GenerateAccessToken(uuid.New().String())
 
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, "LOGOUT", "Customer ID extracted successfully", nil).Return(nil)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, "Token blacklisted successfully", nil).Return(nil)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, "Logout successful", nil).Return(errors.New("file not exists"))
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, entity.AuditActionLogout, helper.AuditMessage("Customer ID extracted successfully"), nil).Return(nil)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, helper.AuditMessage("Token blacklisted successfully"), nil).Return(nil)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, helper.AuditMessage("Logout successful"), nil).Return(errors.New("file not exists"))
 
 	mockAuthRepository := new(helper.MockAuthRepository)
-	mockAuthRepository.On("IsTokenBlacklisted", accessToken).Return(false, nil)
-	mockAuthRepository.On("AddToBlacklist", accessToken).Return(nil)
+	mockAuthRepository.On("IsTokenBlacklisted", mock.Anything, accessToken).Return(false, nil)
+	mockAuthRepository.On("StageAddToBlacklist", mock.Anything, mock.Anything, accessToken).Return(nil)
 
-	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, nil, mockHistoryUseCase)
+	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, nil, mockHistoryUseCase, acceptingEventBus())
 
-	err := authUseCase.Logout(accessToken)
+	err := authUseCase.Logout(context.Background(), accessToken)
 
 	assert.NotNil(t, err)
 }
 
 func TestLogout_ShouldReturnError_WhenErrorLogOnErrorExtractToken(t *testing.T) {
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("file not exists"))
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("file not exists"))
 
 	mockAuthRepository := new(helper.MockAuthRepository)
-	mockAuthRepository.On("IsTokenBlacklisted", "accessToken").Return(true, errors.New("access token already blacklisted"))
-	mockAuthRepository.On("AddToBlacklist", "accessToken").Return(errors.New("repository error"))
+	mockAuthRepository.On("IsTokenBlacklisted", mock.Anything, "accessToken").Return(true, errors.New("access token already blacklisted"))
+	mockAuthRepository.On("StageAddToBlacklist", mock.Anything, mock.Anything, "accessToken").Return(errors.New("repository error"))
 
-	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, nil, mockHistoryUseCase)
-	err := authUseCase.Logout("accessToken")
+	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, nil, mockHistoryUseCase, acceptingEventBus())
+	err := authUseCase.Logout(context.Background(), "accessToken")
 
 	assert.NotNil(t, err)
 }
@@ -257,29 +260,29 @@ func TestLogout_ShouldReturnError_WhenErrorLogOnErrorExtractToken(t *testing.T)
 func TestLogout_ShouldReturnError_WhenErrorLogOnAddToBlacklistFails(t *testing.T) {
 	accessToken, _ := utils.GenerateAccessToken(uuid.New().String())
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, "LOGOUT", "Customer ID extracted successfully", nil).Return(nil)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("file not exists"))
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, entity.AuditActionLogout, helper.AuditMessage("Customer ID extracted successfully"), nil).Return(nil)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("file not exists"))
 
 	mockAuthRepository := new(helper.MockAuthRepository)
-	mockAuthRepository.On("IsTokenBlacklisted", accessToken).Return(true, errors.New("access token already blacklisted"))
-	mockAuthRepository.On("AddToBlacklist", accessToken).Return(errors.New("repository error"))
+	mockAuthRepository.On("IsTokenBlacklisted", mock.Anything, accessToken).Return(true, errors.New("access token already blacklisted"))
+	mockAuthRepository.On("StageAddToBlacklist", mock.Anything, mock.Anything, accessToken).Return(errors.New("repository error"))
 
-	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, nil, mockHistoryUseCase)
-	err := authUseCase.Logout(accessToken)
+	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, nil, mockHistoryUseCase, acceptingEventBus())
+	err := authUseCase.Logout(context.Background(), accessToken)
 
 	assert.NotNil(t, err)
 }
 
 func TestIsTokenBlacklisted_ShouldReturnTrue(t *testing.T) {
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	mockAuthRepository := new(helper.MockAuthRepository)
-	mockAuthRepository.On("IsTokenBlacklisted", "blacklisted_token").Return(true, nil)
+	mockAuthRepository.On("IsTokenBlacklisted", mock.Anything, "blacklisted_token").Return(true, nil)
 
-	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, nil, mockHistoryUseCase)
+	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, nil, mockHistoryUseCase, acceptingEventBus())
 
-	isBlacklisted, err := authUseCase.IsTokenBlacklisted("blacklisted_token")
+	isBlacklisted, err := authUseCase.IsTokenBlacklisted(context.Background(), "blacklisted_token")
 
 	assert.Nil(t, err)
 	assert.True(t, isBlacklisted)
@@ -287,14 +290,14 @@ func TestIsTokenBlacklisted_ShouldReturnTrue(t *testing.T) {
 
 func TestIsTokenBlacklisted_ShouldReturnError_WhenRepositoryFails(t *testing.T) {
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	mockAuthRepository := new(helper.MockAuthRepository)
-	mockAuthRepository.On("IsTokenBlacklisted", "token_error").Return(false, fmt.Errorf("repository error"))
+	mockAuthRepository.On("IsTokenBlacklisted", mock.Anything, "token_error").Return(false, fmt.Errorf("repository error"))
 
-	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, nil, mockHistoryUseCase)
+	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, nil, mockHistoryUseCase, acceptingEventBus())
 
-	isBlacklisted, err := authUseCase.IsTokenBlacklisted("token_error")
+	isBlacklisted, err := authUseCase.IsTokenBlacklisted(context.Background(), "token_error")
 
 	assert.NotNil(t, err)
 	assert.False(t, isBlacklisted)
@@ -302,14 +305,14 @@ func TestIsTokenBlacklisted_ShouldReturnError_WhenRepositoryFails(t *testing.T)
 
 func TestAddToBlacklist_ShouldCallRepository(t *testing.T) {
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	mockAuthRepository := new(helper.MockAuthRepository)
-	mockAuthRepository.On("AddToBlacklist", "new_token").Return(nil)
+	mockAuthRepository.On("AddToBlacklist", mock.Anything, "new_token").Return(nil)
 
-	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, nil, mockHistoryUseCase)
+	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, nil, mockHistoryUseCase, acceptingEventBus())
 
-	err := authUseCase.AddToBlacklist("new_token")
+	err := authUseCase.AddToBlacklist(context.Background(), "new_token")
 
 	assert.Nil(t, err)
 	mockAuthRepository.AssertExpectations(t)
@@ -317,15 +320,159 @@ func TestAddToBlacklist_ShouldCallRepository(t *testing.T) {
 
 func TestAddToBlacklist_ShouldReturnError_WhenAddFails(t *testing.T) {
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	mockAuthRepository := new(helper.MockAuthRepository)
-	mockAuthRepository.On("AddToBlacklist", "token_error").Return(fmt.Errorf("repository error"))
+	mockAuthRepository.On("AddToBlacklist", mock.Anything, "token_error").Return(fmt.Errorf("repository error"))
 
-	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, nil, mockHistoryUseCase)
+	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, nil, mockHistoryUseCase, acceptingEventBus())
 
-	err := authUseCase.AddToBlacklist("token_error")
+	err := authUseCase.AddToBlacklist(context.Background(), "token_error")
 
 	assert.NotNil(t, err)
 	mockAuthRepository.AssertExpectations(t)
 }
+
+func acceptingEventBus() *helper.MockEventBus {
+	mockEventBus := new(helper.MockEventBus)
+	mockEventBus.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	return mockEventBus
+}
+
+func TestLogin_ShouldPublishLoginSucceededEvent(t *testing.T) {
+	utils.InitJwtConfig([]byte("abc"), 10)
+
+	mockCustomerUseCase := new(helper.MockCustomerUseCase)
+	mockCustomerUseCase.On("FindByUsername", mock.Anything, helper.ExpectedCustomers[0].Username).Return(helper.ExpectedCustomers[0], nil)
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	mockEventBus := new(helper.MockEventBus)
+	mockEventBus.On("Publish", mock.Anything, mock.Anything, mock.MatchedBy(func(events []entity.DomainEvent) bool {
+		return len(events) == 1 && events[0].Type == entity.EventLoginSucceeded &&
+			events[0].AggregateId == helper.ExpectedCustomers[0].Id.String()
+	})).Return(nil)
+
+	authUseCase := impl.NewAuthUseCaseImpl(new(helper.MockAuthRepository), mockCustomerUseCase, mockHistoryUseCase, mockEventBus)
+
+	_, err := authUseCase.Login(context.Background(), model.LoginRequest{Username: helper.ExpectedCustomers[0].Username, Password: "password"})
+
+	assert.Nil(t, err)
+	mockEventBus.AssertExpectations(t)
+}
+
+func TestLogout_ShouldStageBlacklistAndTokenRevokedEventTogether(t *testing.T) {
+	customerId := uuid.New().String()
+	accessToken, _ := utils.GenerateAccessToken(customerId)
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	var stagedTx *utils.JsonFileTransaction
+	mockAuthRepository := new(helper.MockAuthRepository)
+	mockAuthRepository.On("StageAddToBlacklist", mock.Anything, mock.Anything, accessToken).Run(func(args mock.Arguments) {
+		stagedTx = args.Get(1).(*utils.JsonFileTransaction)
+	}).Return(nil)
+
+	mockEventBus := new(helper.MockEventBus)
+	mockEventBus.On("Publish", mock.Anything, mock.MatchedBy(func(tx *utils.JsonFileTransaction) bool {
+		return tx == stagedTx
+	}), mock.MatchedBy(func(events []entity.DomainEvent) bool {
+		return len(events) == 1 && events[0].Type == entity.EventTokenRevoked && events[0].AggregateId == customerId
+	})).Return(nil)
+
+	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, nil, mockHistoryUseCase, mockEventBus)
+
+	err := authUseCase.Logout(context.Background(), accessToken)
+
+	assert.Nil(t, err)
+	mockEventBus.AssertExpectations(t)
+}
+
+func TestLogout_ShouldReturnError_WhenPublishFails(t *testing.T) {
+	accessToken, _ := utils.GenerateAccessToken(uuid.New().String())
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	mockAuthRepository := new(helper.MockAuthRepository)
+	mockAuthRepository.On("StageAddToBlacklist", mock.Anything, mock.Anything, accessToken).Return(nil)
+
+	mockEventBus := new(helper.MockEventBus)
+	mockEventBus.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("disk full"))
+
+	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, nil, mockHistoryUseCase, mockEventBus)
+
+	err := authUseCase.Logout(context.Background(), accessToken)
+
+	assert.NotNil(t, err)
+}
+
+func loginAttempts(result string) float64 {
+	return testutil.ToFloat64(metrics.LoginAttempts.WithLabelValues(result))
+}
+
+func TestLogin_ShouldCountSuccessfulAndFailedAttempts(t *testing.T) {
+	utils.InitJwtConfig([]byte("abc"), 10)
+
+	mockCustomerUseCase := new(helper.MockCustomerUseCase)
+	mockCustomerUseCase.On("FindByUsername", mock.Anything, helper.ExpectedCustomers[0].Username).Return(helper.ExpectedCustomers[0], nil)
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	authUseCase := impl.NewAuthUseCaseImpl(new(helper.MockAuthRepository), mockCustomerUseCase, mockHistoryUseCase, acceptingEventBus())
+	successBefore, failureBefore := loginAttempts(metrics.LoginSuccess), loginAttempts(metrics.LoginFailure)
+
+	_, err := authUseCase.Login(context.Background(), model.LoginRequest{Username: helper.ExpectedCustomers[0].Username, Password: "password"})
+	assert.Nil(t, err)
+	_, err = authUseCase.Login(context.Background(), model.LoginRequest{Username: helper.ExpectedCustomers[0].Username, Password: "wrong"})
+	assert.NotNil(t, err)
+
+	assert.Equal(t, successBefore+1, loginAttempts(metrics.LoginSuccess))
+	assert.Equal(t, failureBefore+1, loginAttempts(metrics.LoginFailure))
+}
+
+func TestLogin_ShouldReturnError_WhenCustomerDisabled(t *testing.T) {
+	utils.InitJwtConfig([]byte("abc"), 10)
+
+	customer := helper.ExpectedCustomers[0]
+	customer.Status = entity.CustomerStatusDisabled
+	mockCustomerUseCase := new(helper.MockCustomerUseCase)
+	mockCustomerUseCase.On("FindByUsername", mock.Anything, customer.Username).Return(customer, nil)
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, customer.Id.String(), entity.AuditActionLogin,
+		mock.MatchedBy(func(details entity.AuditDetails) bool {
+			return details.ErrorCode == entity.AuditErrorAccountDisabled
+		}), mock.Anything).Return(nil)
+
+	authUseCase := impl.NewAuthUseCaseImpl(new(helper.MockAuthRepository), mockCustomerUseCase, mockHistoryUseCase, acceptingEventBus())
+
+	response, err := authUseCase.Login(context.Background(), model.LoginRequest{Username: customer.Username, Password: "password"})
+
+	assert.ErrorContains(t, err, "disabled")
+	assert.Empty(t, response.AccessToken)
+	mockHistoryUseCase.AssertExpectations(t)
+}
+
+func TestIsTokenBlacklisted_ShouldReturnTrue_WhenSessionRevoked(t *testing.T) {
+	utils.InitJwtConfig([]byte("abc"), 10)
+	token, err := utils.GenerateAccessToken(helper.CustomerId.String())
+	assert.Nil(t, err)
+
+	mockAuthRepository := new(helper.MockAuthRepository)
+	mockAuthRepository.On("IsTokenBlacklisted", mock.Anything, token).Return(false, nil)
+
+	mockCustomerUseCase := new(helper.MockCustomerUseCase)
+	mockCustomerUseCase.On("IsSessionRevoked", mock.Anything, helper.CustomerId.String(), mock.Anything).Return(true, nil)
+
+	authUseCase := impl.NewAuthUseCaseImpl(mockAuthRepository, mockCustomerUseCase, new(helper.MockHistoryUseCase), acceptingEventBus())
+
+	isBlacklisted, err := authUseCase.IsTokenBlacklisted(context.Background(), token)
+
+	assert.Nil(t, err)
+	assert.True(t, isBlacklisted)
+	mockCustomerUseCase.AssertExpectations(t)
+}
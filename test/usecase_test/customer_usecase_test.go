@@ -1,25 +1,28 @@
 package usecase_test
 
 import (
+	"context"
 	"errors"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/model"
 	"merchant_bank_payment_go_api/internal/usecase/impl"
 	"merchant_bank_payment_go_api/test/helper"
 	"testing"
+	"time"
 )
 
 func TestFindById_ShouldReturnCustomer(t *testing.T) {
 	mockCustomerRepository := new(helper.MockCustomerRepository)
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
-	useCase := impl.NewCustomerUseCaseImpl(mockHistoryUseCase, mockCustomerRepository)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	useCase := impl.NewCustomerUseCaseImpl(mockHistoryUseCase, mockCustomerRepository, new(helper.MockPaymentTransactionRepository))
 
-	mockCustomerRepository.On("FindById", helper.CustomerId).Return(helper.ExpectedCustomers[0], nil)
+	mockCustomerRepository.On("FindById", mock.Anything, helper.CustomerId).Return(helper.ExpectedCustomers[0], nil)
 
-	customer, err := useCase.FindById(helper.CustomerId.String())
+	customer, err := useCase.FindById(context.Background(), helper.CustomerId.String())
 
 	assert.Nil(t, err)
 	assert.Equal(t, helper.ExpectedCustomers[0], customer)
@@ -29,14 +32,14 @@ func TestFindById_ShouldReturnCustomer(t *testing.T) {
 func TestFindById_ShouldReturnError_WhenFailedParseToken(t *testing.T) {
 	mockCustomerRepository := new(helper.MockCustomerRepository)
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
-	useCase := impl.NewCustomerUseCaseImpl(mockHistoryUseCase, mockCustomerRepository)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	useCase := impl.NewCustomerUseCaseImpl(mockHistoryUseCase, mockCustomerRepository, new(helper.MockPaymentTransactionRepository))
 
 	customerId := "abcdef"
 
-	mockCustomerRepository.On("FindById", customerId).Return(entity.Customer{}, errors.New("customer not found"))
+	mockCustomerRepository.On("FindById", mock.Anything, customerId).Return(entity.Customer{}, errors.New("customer not found"))
 
-	customer, err := useCase.FindById(customerId)
+	customer, err := useCase.FindById(context.Background(), customerId)
 
 	assert.NotNil(t, err)
 	assert.Equal(t, entity.Customer{}, customer)
@@ -45,14 +48,14 @@ func TestFindById_ShouldReturnError_WhenFailedParseToken(t *testing.T) {
 func TestFindById_ShouldReturnError_WhenCustomerNotFound(t *testing.T) {
 	mockCustomerRepository := new(helper.MockCustomerRepository)
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
-	useCase := impl.NewCustomerUseCaseImpl(mockHistoryUseCase, mockCustomerRepository)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	useCase := impl.NewCustomerUseCaseImpl(mockHistoryUseCase, mockCustomerRepository, new(helper.MockPaymentTransactionRepository))
 
 	customerId := uuid.New()
 
-	mockCustomerRepository.On("FindById", customerId).Return(entity.Customer{}, errors.New("customer not found"))
+	mockCustomerRepository.On("FindById", mock.Anything, customerId).Return(entity.Customer{}, errors.New("customer not found"))
 
-	customer, err := useCase.FindById(customerId.String())
+	customer, err := useCase.FindById(context.Background(), customerId.String())
 
 	assert.NotNil(t, err)
 	assert.Equal(t, entity.Customer{}, customer)
@@ -61,44 +64,44 @@ func TestFindById_ShouldReturnError_WhenCustomerNotFound(t *testing.T) {
 func TestFindById_ShouldReturnError_WhenLogOnErrorParseToken(t *testing.T) {
 	mockCustomerRepository := new(helper.MockCustomerRepository)
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("Error On Log"))
-	useCase := impl.NewCustomerUseCaseImpl(mockHistoryUseCase, mockCustomerRepository)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("Error On Log"))
+	useCase := impl.NewCustomerUseCaseImpl(mockHistoryUseCase, mockCustomerRepository, new(helper.MockPaymentTransactionRepository))
 
-	_, err := useCase.FindById("12345")
+	_, err := useCase.FindById(context.Background(), "12345")
 	assert.NotNil(t, err)
 }
 
 func TestFindById_ShouldReturnError_WhenLogOnReturnCustomerNotFound(t *testing.T) {
 	mockCustomerRepository := new(helper.MockCustomerRepository)
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("Error On Log"))
-	mockCustomerRepository.On("FindById", helper.CustomerId).Return(entity.Customer{}, errors.New("customer not found"))
-	useCase := impl.NewCustomerUseCaseImpl(mockHistoryUseCase, mockCustomerRepository)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("Error On Log"))
+	mockCustomerRepository.On("FindById", mock.Anything, helper.CustomerId).Return(entity.Customer{}, errors.New("customer not found"))
+	useCase := impl.NewCustomerUseCaseImpl(mockHistoryUseCase, mockCustomerRepository, new(helper.MockPaymentTransactionRepository))
 
-	_, err := useCase.FindById(helper.CustomerId.String())
+	_, err := useCase.FindById(context.Background(), helper.CustomerId.String())
 	assert.NotNil(t, err)
 }
 
 func TestFindById_ShouldReturnError_WhenLogOnReturnCustomerError(t *testing.T) {
 	mockCustomerRepository := new(helper.MockCustomerRepository)
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("Error On Log"))
-	mockCustomerRepository.On("FindById", helper.CustomerId).Return(helper.ExpectedCustomers[0], nil)
-	useCase := impl.NewCustomerUseCaseImpl(mockHistoryUseCase, mockCustomerRepository)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("Error On Log"))
+	mockCustomerRepository.On("FindById", mock.Anything, helper.CustomerId).Return(helper.ExpectedCustomers[0], nil)
+	useCase := impl.NewCustomerUseCaseImpl(mockHistoryUseCase, mockCustomerRepository, new(helper.MockPaymentTransactionRepository))
 
-	_, err := useCase.FindById(helper.CustomerId.String())
+	_, err := useCase.FindById(context.Background(), helper.CustomerId.String())
 	assert.NotNil(t, err)
 }
 
 func TestFindByUsername_ShouldReturnCustomer(t *testing.T) {
 	mockCustomerRepository := new(helper.MockCustomerRepository)
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
-	useCase := impl.NewCustomerUseCaseImpl(mockHistoryUseCase, mockCustomerRepository)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	useCase := impl.NewCustomerUseCaseImpl(mockHistoryUseCase, mockCustomerRepository, new(helper.MockPaymentTransactionRepository))
 
-	mockCustomerRepository.On("FindByUsername", helper.ExpectedCustomers[0].Username).Return(helper.ExpectedCustomers[0], nil)
+	mockCustomerRepository.On("FindByUsername", mock.Anything, helper.ExpectedCustomers[0].Username).Return(helper.ExpectedCustomers[0], nil)
 
-	customer, err := useCase.FindByUsername(helper.ExpectedCustomers[0].Username)
+	customer, err := useCase.FindByUsername(context.Background(), helper.ExpectedCustomers[0].Username)
 
 	assert.Nil(t, err)
 	assert.Equal(t, helper.ExpectedCustomers[0], customer)
@@ -108,14 +111,14 @@ func TestFindByUsername_ShouldReturnCustomer(t *testing.T) {
 func TestFindByUsername_ShouldReturnError_WhenCustomerNotFound(t *testing.T) {
 	mockCustomerRepository := new(helper.MockCustomerRepository)
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
-	useCase := impl.NewCustomerUseCaseImpl(mockHistoryUseCase, mockCustomerRepository)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	useCase := impl.NewCustomerUseCaseImpl(mockHistoryUseCase, mockCustomerRepository, new(helper.MockPaymentTransactionRepository))
 
 	username := "budi"
 
-	mockCustomerRepository.On("FindByUsername", username).Return(entity.Customer{}, errors.New("customer not found"))
+	mockCustomerRepository.On("FindByUsername", mock.Anything, username).Return(entity.Customer{}, errors.New("customer not found"))
 
-	customer, err := useCase.FindByUsername(username)
+	customer, err := useCase.FindByUsername(context.Background(), username)
 
 	assert.NotNil(t, err)
 	assert.Equal(t, entity.Customer{}, customer)
@@ -124,21 +127,175 @@ func TestFindByUsername_ShouldReturnError_WhenCustomerNotFound(t *testing.T) {
 func TestFindByUsername_ShouldReturnError_WhenLogOnReturnCustomerNotFound(t *testing.T) {
 	mockCustomerRepository := new(helper.MockCustomerRepository)
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("Error On Log"))
-	mockCustomerRepository.On("FindByUsername", helper.ExpectedCustomers[0].Username).Return(entity.Customer{}, errors.New("customer not found"))
-	useCase := impl.NewCustomerUseCaseImpl(mockHistoryUseCase, mockCustomerRepository)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("Error On Log"))
+	mockCustomerRepository.On("FindByUsername", mock.Anything, helper.ExpectedCustomers[0].Username).Return(entity.Customer{}, errors.New("customer not found"))
+	useCase := impl.NewCustomerUseCaseImpl(mockHistoryUseCase, mockCustomerRepository, new(helper.MockPaymentTransactionRepository))
 
-	_, err := useCase.FindByUsername(helper.ExpectedCustomers[0].Username)
+	_, err := useCase.FindByUsername(context.Background(), helper.ExpectedCustomers[0].Username)
 	assert.NotNil(t, err)
 }
 
 func TestFindByUsername_ShouldReturnError_WhenLogOnReturnCustomerError(t *testing.T) {
 	mockCustomerRepository := new(helper.MockCustomerRepository)
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("Error On Log"))
-	mockCustomerRepository.On("FindByUsername", helper.ExpectedCustomers[0].Username).Return(helper.ExpectedCustomers[0], nil)
-	useCase := impl.NewCustomerUseCaseImpl(mockHistoryUseCase, mockCustomerRepository)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("Error On Log"))
+	mockCustomerRepository.On("FindByUsername", mock.Anything, helper.ExpectedCustomers[0].Username).Return(helper.ExpectedCustomers[0], nil)
+	useCase := impl.NewCustomerUseCaseImpl(mockHistoryUseCase, mockCustomerRepository, new(helper.MockPaymentTransactionRepository))
 
-	_, err := useCase.FindByUsername(helper.ExpectedCustomers[0].Username)
+	_, err := useCase.FindByUsername(context.Background(), helper.ExpectedCustomers[0].Username)
 	assert.NotNil(t, err)
 }
+
+func TestIsSessionRevoked_ShouldReturnTrue_WhenTokenIssuedBeforeRevocation(t *testing.T) {
+	mockCustomerRepository := new(helper.MockCustomerRepository)
+	useCase := impl.NewCustomerUseCaseImpl(new(helper.MockHistoryUseCase), mockCustomerRepository, new(helper.MockPaymentTransactionRepository))
+
+	revokedAt := time.Now()
+	customer := helper.ExpectedCustomers[0]
+	customer.SessionsRevokedAt = &revokedAt
+	mockCustomerRepository.On("LoadCustomers", mock.Anything).Return([]entity.Customer{customer}, nil)
+
+	revoked, err := useCase.IsSessionRevoked(context.Background(), helper.CustomerId.String(), revokedAt.Add(-time.Minute))
+	assert.Nil(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = useCase.IsSessionRevoked(context.Background(), helper.CustomerId.String(), revokedAt.Add(time.Minute))
+	assert.Nil(t, err)
+	assert.False(t, revoked)
+}
+
+func TestIsSessionRevoked_ShouldReturnTrue_WhenCustomerUnknown(t *testing.T) {
+	mockCustomerRepository := new(helper.MockCustomerRepository)
+	useCase := impl.NewCustomerUseCaseImpl(new(helper.MockHistoryUseCase), mockCustomerRepository, new(helper.MockPaymentTransactionRepository))
+
+	mockCustomerRepository.On("LoadCustomers", mock.Anything).Return(helper.ExpectedCustomers, nil)
+
+	revoked, err := useCase.IsSessionRevoked(context.Background(), uuid.New().String(), time.Now())
+	assert.Nil(t, err)
+	assert.True(t, revoked)
+}
+
+func TestGetCustomers_ShouldFilterByStatusAndSearch(t *testing.T) {
+	mockCustomerRepository := new(helper.MockCustomerRepository)
+	useCase := impl.NewCustomerUseCaseImpl(new(helper.MockHistoryUseCase), mockCustomerRepository, new(helper.MockPaymentTransactionRepository))
+
+	customers := []entity.Customer{
+		{Id: uuid.New(), Username: "siti"},
+		{Id: uuid.New(), Username: "budi", Status: entity.CustomerStatusDisabled},
+		{Id: uuid.New(), Username: "bambang"},
+	}
+	mockCustomerRepository.On("LoadCustomers", mock.Anything).Return(customers, nil)
+
+	page, err := useCase.GetCustomers(context.Background(), model.CustomerQueryRequest{Search: "B", Status: "active"})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, page.TotalItems)
+	assert.Equal(t, "bambang", page.Items[0].Username)
+	assert.Equal(t, string(entity.CustomerStatusActive), page.Items[0].Status)
+
+	page, err = useCase.GetCustomers(context.Background(), model.CustomerQueryRequest{PageSize: 2, Page: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, page.TotalItems)
+	assert.Equal(t, 2, page.TotalPages)
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, "siti", page.Items[0].Username)
+}
+
+func TestGetCustomers_ShouldReturnError_WhenStatusUnknown(t *testing.T) {
+	useCase := impl.NewCustomerUseCaseImpl(new(helper.MockHistoryUseCase), new(helper.MockCustomerRepository), new(helper.MockPaymentTransactionRepository))
+
+	_, err := useCase.GetCustomers(context.Background(), model.CustomerQueryRequest{Status: "locked"})
+	assert.NotNil(t, err)
+}
+
+func TestGetCustomerDetail_ShouldReturnLatestPaymentsAndHistory(t *testing.T) {
+	mockCustomerRepository := new(helper.MockCustomerRepository)
+	mockPaymentRepository := new(helper.MockPaymentTransactionRepository)
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, entity.AuditActionCustomerLookup, mock.Anything, nil).Return(nil)
+	useCase := impl.NewCustomerUseCaseImpl(mockHistoryUseCase, mockCustomerRepository, mockPaymentRepository)
+
+	payments := make([]entity.Payment, 0, 12)
+	for i := 0; i < 12; i++ {
+		payments = append(payments, entity.Payment{Id: uuid.New(), CustomerId: helper.CustomerId, Timestamp: helper.CreatedAt.Add(time.Duration(i) * time.Hour)})
+	}
+	newestPaymentId := payments[11].Id.String()
+	history := model.HistoryPage{Items: []model.HistoryResponse{{Action: string(entity.AuditActionLogin)}}}
+	mockCustomerRepository.On("FindById", mock.Anything, helper.CustomerId).Return(helper.ExpectedCustomers[0], nil)
+	mockPaymentRepository.On("FindByCustomerId", mock.Anything, helper.CustomerId).Return(payments, nil)
+	mockHistoryUseCase.On("GetCustomerHistory", mock.Anything, helper.CustomerId.String(), model.HistoryQueryRequest{PageSize: 10}).Return(history, nil)
+
+	detail, err := useCase.GetCustomerDetail(context.Background(), uuid.New().String(), helper.CustomerId.String())
+
+	assert.Nil(t, err)
+	assert.Equal(t, "budi", detail.Customer.Username)
+	assert.Len(t, detail.RecentPayments, 10)
+	assert.Equal(t, newestPaymentId, detail.RecentPayments[0].Id)
+	assert.Equal(t, history.Items, detail.RecentHistory)
+	mockHistoryUseCase.AssertExpectations(t)
+}
+
+func TestDisableCustomer_ShouldDisableAndRevokeSessions(t *testing.T) {
+	mockCustomerRepository := new(helper.MockCustomerRepository)
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, entity.AuditActionCustomerDisable, mock.Anything, nil).Return(nil)
+	useCase := impl.NewCustomerUseCaseImpl(mockHistoryUseCase, mockCustomerRepository, new(helper.MockPaymentTransactionRepository))
+
+	mockCustomerRepository.On("FindById", mock.Anything, helper.CustomerId).Return(helper.ExpectedCustomers[0], nil)
+	mockCustomerRepository.On("Update", mock.Anything, mock.MatchedBy(func(customer entity.Customer) bool {
+		return customer.IsDisabled() && customer.SessionsRevokedAt != nil
+	})).Return(nil)
+
+	customer, err := useCase.DisableCustomer(context.Background(), uuid.New().String(), helper.CustomerId.String())
+
+	assert.Nil(t, err)
+	assert.Equal(t, string(entity.CustomerStatusDisabled), customer.Status)
+	assert.NotNil(t, customer.SessionsRevokedAt)
+	mockCustomerRepository.AssertExpectations(t)
+	mockHistoryUseCase.AssertExpectations(t)
+}
+
+func TestDisableCustomer_ShouldReturnError_WhenDisablingOwnAccount(t *testing.T) {
+	mockCustomerRepository := new(helper.MockCustomerRepository)
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, entity.AuditActionCustomerDisable, mock.Anything, mock.Anything).Return(nil)
+	useCase := impl.NewCustomerUseCaseImpl(mockHistoryUseCase, mockCustomerRepository, new(helper.MockPaymentTransactionRepository))
+
+	mockCustomerRepository.On("FindById", mock.Anything, helper.CustomerId).Return(helper.ExpectedCustomers[0], nil)
+
+	_, err := useCase.DisableCustomer(context.Background(), helper.CustomerId.String(), helper.CustomerId.String())
+
+	assert.NotNil(t, err)
+	mockCustomerRepository.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestEnableCustomer_ShouldReturnError_WhenCustomerNotDisabled(t *testing.T) {
+	mockCustomerRepository := new(helper.MockCustomerRepository)
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, entity.AuditActionCustomerEnable, mock.Anything, mock.Anything).Return(nil)
+	useCase := impl.NewCustomerUseCaseImpl(mockHistoryUseCase, mockCustomerRepository, new(helper.MockPaymentTransactionRepository))
+
+	mockCustomerRepository.On("FindById", mock.Anything, helper.CustomerId).Return(helper.ExpectedCustomers[0], nil)
+
+	_, err := useCase.EnableCustomer(context.Background(), uuid.New().String(), helper.CustomerId.String())
+
+	assert.NotNil(t, err)
+	mockCustomerRepository.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestForceLogout_ShouldRevokeSessionsWithoutDisabling(t *testing.T) {
+	mockCustomerRepository := new(helper.MockCustomerRepository)
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, entity.AuditActionCustomerForceLogout, mock.Anything, nil).Return(nil)
+	useCase := impl.NewCustomerUseCaseImpl(mockHistoryUseCase, mockCustomerRepository, new(helper.MockPaymentTransactionRepository))
+
+	mockCustomerRepository.On("FindById", mock.Anything, helper.CustomerId).Return(helper.ExpectedCustomers[0], nil)
+	mockCustomerRepository.On("Update", mock.Anything, mock.MatchedBy(func(customer entity.Customer) bool {
+		return !customer.IsDisabled() && customer.SessionsRevokedAt != nil
+	})).Return(nil)
+
+	customer, err := useCase.ForceLogout(context.Background(), uuid.New().String(), helper.CustomerId.String())
+
+	assert.Nil(t, err)
+	assert.Equal(t, string(entity.CustomerStatusActive), customer.Status)
+	mockCustomerRepository.AssertExpectations(t)
+}
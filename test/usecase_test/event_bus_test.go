@@ -0,0 +1,156 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/usecase/impl"
+	"merchant_bank_payment_go_api/internal/utils"
+	"merchant_bank_payment_go_api/test/helper"
+	"testing"
+)
+
+func pendingEvent(eventType entity.DomainEventType) entity.DomainEvent {
+	event, _ := entity.NewDomainEvent(eventType, helper.CustomerId.String(), entity.LoginSucceededPayload{CustomerId: helper.CustomerId.String()})
+	return event
+}
+
+func TestDispatchPending_ShouldDeliverToMatchingSubscribersAndMarkDispatched(t *testing.T) {
+	event := pendingEvent(entity.EventLoginSucceeded)
+
+	mockOutboxRepository := new(helper.MockOutboxRepository)
+	mockOutboxRepository.On("LoadEvents", mock.Anything).Return([]entity.DomainEvent{event}, nil)
+	mockOutboxRepository.On("UpdateEvent", mock.Anything, mock.MatchedBy(func(updated entity.DomainEvent) bool {
+		return updated.Id == event.Id && updated.Status == entity.DomainEventStatusDispatched &&
+			updated.DispatchedAt != nil && updated.Attempts == 1 && len(updated.DeliveredTo) == 1
+	})).Return(nil)
+	mockOutboxRepository.On("PruneDispatched", mock.Anything).Return(1, nil)
+
+	eventBus := impl.NewEventBusImpl(logrus.New(), mockOutboxRepository)
+	var received []entity.DomainEvent
+	eventBus.Subscribe("audit", entity.EventLoginSucceeded, func(ctx context.Context, event entity.DomainEvent) error {
+		received = append(received, event)
+		return nil
+	})
+	eventBus.Subscribe("other", entity.EventTokenRevoked, func(ctx context.Context, event entity.DomainEvent) error {
+		t.Fatal("subscriber for another event type must not be called")
+		return nil
+	})
+
+	dispatched, err := eventBus.DispatchPending(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, dispatched)
+	assert.Len(t, received, 1)
+	mockOutboxRepository.AssertExpectations(t)
+}
+
+func TestDispatchPending_ShouldRetryOnlyFailedSubscribers(t *testing.T) {
+	event := pendingEvent(entity.EventPaymentCreated)
+	event.DeliveredTo = []string{"history"}
+
+	mockOutboxRepository := new(helper.MockOutboxRepository)
+	mockOutboxRepository.On("LoadEvents", mock.Anything).Return([]entity.DomainEvent{event}, nil)
+	mockOutboxRepository.On("UpdateEvent", mock.Anything, mock.MatchedBy(func(updated entity.DomainEvent) bool {
+		return updated.Status == entity.DomainEventStatusPending && updated.Attempts == 1 &&
+			updated.LastError != "" && len(updated.DeliveredTo) == 2
+	})).Return(nil)
+
+	eventBus := impl.NewEventBusImpl(logrus.New(), mockOutboxRepository)
+	eventBus.Subscribe("history", entity.EventPaymentCreated, func(ctx context.Context, event entity.DomainEvent) error {
+		t.Fatal("subscriber that already handled the event must be skipped")
+		return nil
+	})
+	eventBus.Subscribe("ledger", entity.EventPaymentCreated, func(ctx context.Context, event entity.DomainEvent) error {
+		return nil
+	})
+	eventBus.Subscribe("webhook", entity.EventPaymentCreated, func(ctx context.Context, event entity.DomainEvent) error {
+		return errors.New("merchant not found")
+	})
+
+	dispatched, err := eventBus.DispatchPending(context.Background())
+
+	assert.Nil(t, err)
+	assert.Zero(t, dispatched)
+	mockOutboxRepository.AssertExpectations(t)
+}
+
+func TestDispatchPending_ShouldSkipDispatchedEvents(t *testing.T) {
+	event := pendingEvent(entity.EventLoginSucceeded)
+	event.Status = entity.DomainEventStatusDispatched
+
+	mockOutboxRepository := new(helper.MockOutboxRepository)
+	mockOutboxRepository.On("LoadEvents", mock.Anything).Return([]entity.DomainEvent{event}, nil)
+	mockOutboxRepository.On("PruneDispatched", mock.Anything).Return(1, nil)
+
+	eventBus := impl.NewEventBusImpl(logrus.New(), mockOutboxRepository)
+
+	dispatched, err := eventBus.DispatchPending(context.Background())
+
+	assert.Nil(t, err)
+	assert.Zero(t, dispatched)
+	mockOutboxRepository.AssertNotCalled(t, "UpdateEvent", mock.Anything, mock.Anything)
+	mockOutboxRepository.AssertExpectations(t)
+}
+
+func TestPublish_ShouldCommitEventsWithTransaction(t *testing.T) {
+	event := pendingEvent(entity.EventTokenRevoked)
+
+	mockOutboxRepository := new(helper.MockOutboxRepository)
+	eventBus := impl.NewEventBusImpl(logrus.New(), mockOutboxRepository)
+	eventBus.Subscribe("audit", entity.EventTokenRevoked, func(ctx context.Context, event entity.DomainEvent) error {
+		return nil
+	})
+	mockOutboxRepository.On("CommitWithEvents", mock.Anything, mock.Anything, []entity.DomainEvent{event}).Return(nil)
+
+	err := eventBus.Publish(context.Background(), func(tx *utils.JsonFileTransaction) ([]entity.DomainEvent, error) {
+		return []entity.DomainEvent{event}, nil
+	})
+
+	assert.Nil(t, err)
+	mockOutboxRepository.AssertExpectations(t)
+}
+
+func TestPublish_ShouldStoreEventsWithoutSubscribers(t *testing.T) {
+	event := pendingEvent(entity.EventLoginSucceeded)
+
+	mockOutboxRepository := new(helper.MockOutboxRepository)
+	eventBus := impl.NewEventBusImpl(logrus.New(), mockOutboxRepository)
+	eventBus.Subscribe("webhook", entity.EventPaymentCreated, func(ctx context.Context, event entity.DomainEvent) error {
+		return nil
+	})
+	mockOutboxRepository.On("CommitWithEvents", mock.Anything, mock.Anything, []entity.DomainEvent{event}).Return(nil)
+
+	err := eventBus.Publish(context.Background(), func(tx *utils.JsonFileTransaction) ([]entity.DomainEvent, error) {
+		return []entity.DomainEvent{event}, nil
+	})
+
+	assert.Nil(t, err)
+	mockOutboxRepository.AssertExpectations(t)
+}
+
+func TestDispatchPending_ShouldMarkEventsWithoutSubscribersDispatched(t *testing.T) {
+	event := pendingEvent(entity.EventLoginSucceeded)
+
+	mockOutboxRepository := new(helper.MockOutboxRepository)
+	mockOutboxRepository.On("LoadEvents", mock.Anything).Return([]entity.DomainEvent{event}, nil)
+	mockOutboxRepository.On("UpdateEvent", mock.Anything, mock.MatchedBy(func(updated entity.DomainEvent) bool {
+		return updated.Id == event.Id && updated.Status == entity.DomainEventStatusDispatched && len(updated.DeliveredTo) == 0
+	})).Return(nil)
+	mockOutboxRepository.On("PruneDispatched", mock.Anything).Return(1, nil)
+
+	eventBus := impl.NewEventBusImpl(logrus.New(), mockOutboxRepository)
+	eventBus.Subscribe("webhook", entity.EventPaymentCreated, func(ctx context.Context, event entity.DomainEvent) error {
+		t.Fatal("subscriber for another event type must not be called")
+		return nil
+	})
+
+	dispatched, err := eventBus.DispatchPending(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, dispatched)
+	mockOutboxRepository.AssertExpectations(t)
+}
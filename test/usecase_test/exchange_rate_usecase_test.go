@@ -0,0 +1,174 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/internal/usecase/impl"
+	"merchant_bank_payment_go_api/test/helper"
+	"testing"
+	"time"
+)
+
+func TestConvert_ShouldApplySpreadAndMinorUnits(t *testing.T) {
+	rate := entity.ExchangeRate{Base: "KWD", Quote: "IDR", Rate: 51540.75, Timestamp: time.Now()}
+	mockExchangeRateRepository := new(helper.MockExchangeRateRepository)
+	mockExchangeRateRepository.On("FindRate", mock.Anything, "KWD", "IDR").Return(rate, nil)
+
+	useCase := impl.NewExchangeRateUseCaseImpl(new(helper.MockHistoryUseCase), mockExchangeRateRepository, time.Hour, 50, entity.RoundHalfEven)
+
+	convertedResult, conversionResult, err := useCase.Convert(context.Background(), entity.Money{Amount: 1250, Currency: "KWD"}, "IDR")
+
+	assert.Nil(t, err)
+	assert.Equal(t, entity.Money{Amount: 64104, Currency: "IDR"}, convertedResult)
+	assert.Equal(t, entity.Money{Amount: 1250, Currency: "KWD"}, conversionResult.OriginalAmount)
+	assert.Equal(t, 51540.75, conversionResult.MidRate)
+	assert.InDelta(t, 51283.04625, conversionResult.AppliedRate, 1e-9)
+	assert.Equal(t, int64(50), conversionResult.SpreadBasisPoints)
+	assert.Equal(t, rate.Timestamp, conversionResult.RateTimestamp)
+	mockExchangeRateRepository.AssertExpectations(t)
+}
+
+func TestConvert_ShouldRoundIntoTargetMinorUnits(t *testing.T) {
+	mockExchangeRateRepository := new(helper.MockExchangeRateRepository)
+	mockExchangeRateRepository.On("FindRate", mock.Anything, "IDR", "USD").
+		Return(entity.ExchangeRate{Base: "IDR", Quote: "USD", Rate: 0.000063, Timestamp: time.Now()}, nil)
+
+	useCase := impl.NewExchangeRateUseCaseImpl(new(helper.MockHistoryUseCase), mockExchangeRateRepository, time.Hour, 0, entity.RoundDown)
+
+	convertedResult, _, err := useCase.Convert(context.Background(), entity.Money{Amount: 15000, Currency: "IDR"}, "USD")
+
+	assert.Nil(t, err)
+	assert.Equal(t, entity.Money{Amount: 94, Currency: "USD"}, convertedResult)
+}
+
+func TestConvert_ShouldReturnSameAmount_WhenSameCurrency(t *testing.T) {
+	mockExchangeRateRepository := new(helper.MockExchangeRateRepository)
+
+	useCase := impl.NewExchangeRateUseCaseImpl(new(helper.MockHistoryUseCase), mockExchangeRateRepository, time.Hour, 50, entity.RoundHalfEven)
+
+	convertedResult, conversionResult, err := useCase.Convert(context.Background(), entity.Money{Amount: 15000, Currency: "IDR"}, "idr")
+
+	assert.Nil(t, err)
+	assert.Equal(t, entity.Money{Amount: 15000, Currency: "IDR"}, convertedResult)
+	assert.Equal(t, float64(1), conversionResult.AppliedRate)
+	mockExchangeRateRepository.AssertNotCalled(t, "FindRate", mock.Anything)
+}
+
+func TestConvert_ShouldReturnError_WhenRateIsStale(t *testing.T) {
+	mockExchangeRateRepository := new(helper.MockExchangeRateRepository)
+	mockExchangeRateRepository.On("FindRate", mock.Anything, "USD", "IDR").Return(helper.ExpectedExchangeRates[0], nil)
+
+	useCase := impl.NewExchangeRateUseCaseImpl(new(helper.MockHistoryUseCase), mockExchangeRateRepository, time.Hour, 0, entity.RoundHalfEven)
+
+	_, _, err := useCase.Convert(context.Background(), entity.Money{Amount: 100, Currency: "USD"}, "IDR")
+
+	assert.NotNil(t, err)
+}
+
+func TestConvert_ShouldIgnoreStaleness_WhenMaxRateAgeDisabled(t *testing.T) {
+	mockExchangeRateRepository := new(helper.MockExchangeRateRepository)
+	mockExchangeRateRepository.On("FindRate", mock.Anything, "USD", "IDR").Return(helper.ExpectedExchangeRates[0], nil)
+
+	useCase := impl.NewExchangeRateUseCaseImpl(new(helper.MockHistoryUseCase), mockExchangeRateRepository, 0, 0, entity.RoundHalfEven)
+
+	convertedResult, _, err := useCase.Convert(context.Background(), entity.Money{Amount: 100, Currency: "USD"}, "IDR")
+
+	assert.Nil(t, err)
+	assert.Equal(t, entity.Money{Amount: 15850, Currency: "IDR"}, convertedResult)
+}
+
+func TestConvert_ShouldReturnError_WhenRateNotFound(t *testing.T) {
+	mockExchangeRateRepository := new(helper.MockExchangeRateRepository)
+	mockExchangeRateRepository.On("FindRate", mock.Anything, "EUR", "IDR").Return(entity.ExchangeRate{}, errors.New("not found"))
+
+	useCase := impl.NewExchangeRateUseCaseImpl(new(helper.MockHistoryUseCase), mockExchangeRateRepository, time.Hour, 0, entity.RoundHalfEven)
+
+	_, _, err := useCase.Convert(context.Background(), entity.Money{Amount: 100, Currency: "EUR"}, "IDR")
+
+	assert.NotNil(t, err)
+}
+
+func TestConvert_ShouldReturnError_WhenTargetCurrencyUnsupported(t *testing.T) {
+	mockExchangeRateRepository := new(helper.MockExchangeRateRepository)
+
+	useCase := impl.NewExchangeRateUseCaseImpl(new(helper.MockHistoryUseCase), mockExchangeRateRepository, time.Hour, 0, entity.RoundHalfEven)
+
+	_, _, err := useCase.Convert(context.Background(), entity.Money{Amount: 100, Currency: "USD"}, "ABC")
+
+	assert.NotNil(t, err)
+}
+
+func TestConvert_ShouldReturnError_WhenConvertedAmountRoundsToZero(t *testing.T) {
+	mockExchangeRateRepository := new(helper.MockExchangeRateRepository)
+	mockExchangeRateRepository.On("FindRate", mock.Anything, "IDR", "USD").
+		Return(entity.ExchangeRate{Base: "IDR", Quote: "USD", Rate: 0.000063, Timestamp: time.Now()}, nil)
+
+	useCase := impl.NewExchangeRateUseCaseImpl(new(helper.MockHistoryUseCase), mockExchangeRateRepository, time.Hour, 0, entity.RoundHalfEven)
+
+	_, _, err := useCase.Convert(context.Background(), entity.Money{Amount: 10, Currency: "IDR"}, "USD")
+
+	assert.NotNil(t, err)
+}
+
+func TestReplaceRates_ShouldStampRatesWithCurrentTime(t *testing.T) {
+	adminId := uuid.New().String()
+	mockExchangeRateRepository := new(helper.MockExchangeRateRepository)
+	mockExchangeRateRepository.On("SaveRates", mock.Anything, mock.Anything).Return(nil)
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, adminId, entity.AuditActionExchangeRateUpdate, mock.Anything, nil).Return(nil)
+
+	useCase := impl.NewExchangeRateUseCaseImpl(mockHistoryUseCase, mockExchangeRateRepository, time.Hour, 0, entity.RoundHalfEven)
+
+	before := time.Now()
+	ratesResult, err := useCase.ReplaceRates(context.Background(), adminId, model.ExchangeRateRequest{
+		Rates: []model.ExchangeRateEntry{{Base: "usd", Quote: "IDR", Rate: 15850}},
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, ratesResult, 1)
+	assert.Equal(t, "USD", ratesResult[0].Base)
+	assert.False(t, ratesResult[0].Timestamp.Before(before))
+	savedRates := mockExchangeRateRepository.Calls[0].Arguments.Get(1).([]entity.ExchangeRate)
+	assert.Equal(t, ratesResult[0].Timestamp, savedRates[0].Timestamp)
+	mockExchangeRateRepository.AssertExpectations(t)
+	mockHistoryUseCase.AssertExpectations(t)
+}
+
+func TestReplaceRates_ShouldReturnError_WhenPairListedTwice(t *testing.T) {
+	mockExchangeRateRepository := new(helper.MockExchangeRateRepository)
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, entity.AuditActionExchangeRateUpdate,
+		mock.MatchedBy(func(details entity.AuditDetails) bool {
+			return details.ErrorCode == entity.AuditErrorInvalidRequest
+		}), mock.Anything).Return(nil)
+
+	useCase := impl.NewExchangeRateUseCaseImpl(mockHistoryUseCase, mockExchangeRateRepository, time.Hour, 0, entity.RoundHalfEven)
+
+	_, err := useCase.ReplaceRates(context.Background(), uuid.New().String(), model.ExchangeRateRequest{
+		Rates: []model.ExchangeRateEntry{{Base: "USD", Quote: "IDR", Rate: 15850}, {Base: "IDR", Quote: "USD", Rate: 0.000063}},
+	})
+
+	assert.NotNil(t, err)
+	mockExchangeRateRepository.AssertNotCalled(t, "SaveRates", mock.Anything, mock.Anything)
+	mockHistoryUseCase.AssertExpectations(t)
+}
+
+func TestReplaceRates_ShouldReturnError_WhenCurrencyUnsupported(t *testing.T) {
+	mockExchangeRateRepository := new(helper.MockExchangeRateRepository)
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	useCase := impl.NewExchangeRateUseCaseImpl(mockHistoryUseCase, mockExchangeRateRepository, time.Hour, 0, entity.RoundHalfEven)
+
+	_, err := useCase.ReplaceRates(context.Background(), uuid.New().String(), model.ExchangeRateRequest{
+		Rates: []model.ExchangeRateEntry{{Base: "ABC", Quote: "IDR", Rate: 1}},
+	})
+
+	assert.NotNil(t, err)
+	mockExchangeRateRepository.AssertNotCalled(t, "SaveRates", mock.Anything, mock.Anything)
+}
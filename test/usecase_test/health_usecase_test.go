@@ -0,0 +1,85 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merchant_bank_payment_go_api/internal/buildinfo"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/internal/repository"
+	"merchant_bank_payment_go_api/internal/usecase/impl"
+	"merchant_bank_payment_go_api/test/helper"
+	"testing"
+)
+
+func TestCheckReadiness_ShouldBeUp_WhenAllChecksPass(t *testing.T) {
+	mockCustomerRepository := new(helper.MockHealthCheckRepository)
+	mockCustomerRepository.On("CheckHealth", mock.Anything).Return(nil)
+	mockMerchantRepository := new(helper.MockHealthCheckRepository)
+	mockMerchantRepository.On("CheckHealth", mock.Anything).Return(nil)
+
+	healthUseCase := impl.NewHealthUseCaseImpl(logrus.New(), map[string]repository.HealthCheckRepository{
+		"merchant": mockMerchantRepository,
+		"customer": mockCustomerRepository,
+	}, func() bool { return true })
+
+	readiness := healthUseCase.CheckReadiness(context.Background())
+
+	assert.Equal(t, model.ReadinessResponse{
+		Status: impl.HealthStatusUp,
+		Checks: []model.ReadinessCheckResponse{
+			{Name: "customer", Status: impl.HealthStatusUp},
+			{Name: "merchant", Status: impl.HealthStatusUp},
+			{Name: "jwt_key", Status: impl.HealthStatusUp},
+		},
+	}, readiness)
+	mockCustomerRepository.AssertExpectations(t)
+	mockMerchantRepository.AssertExpectations(t)
+}
+
+func TestCheckReadiness_ShouldBeDown_WhenRepositoryFails(t *testing.T) {
+	mockCustomerRepository := new(helper.MockHealthCheckRepository)
+	mockCustomerRepository.On("CheckHealth", mock.Anything).Return(errors.New("error opening file Customer.json: permission denied"))
+
+	healthUseCase := impl.NewHealthUseCaseImpl(logrus.New(), map[string]repository.HealthCheckRepository{
+		"customer": mockCustomerRepository,
+	}, func() bool { return true })
+
+	readiness := healthUseCase.CheckReadiness(context.Background())
+
+	assert.Equal(t, impl.HealthStatusDown, readiness.Status)
+	assert.Equal(t, model.ReadinessCheckResponse{Name: "customer", Status: impl.HealthStatusDown,
+		Error: "error opening file Customer.json: permission denied"}, readiness.Checks[0])
+	assert.Equal(t, impl.HealthStatusUp, readiness.Checks[1].Status)
+}
+
+func TestCheckReadiness_ShouldBeDown_WhenJwtKeyIsNotLoaded(t *testing.T) {
+	healthUseCase := impl.NewHealthUseCaseImpl(logrus.New(), nil, func() bool { return false })
+
+	readiness := healthUseCase.CheckReadiness(context.Background())
+
+	assert.Equal(t, impl.HealthStatusDown, readiness.Status)
+	assert.Equal(t, []model.ReadinessCheckResponse{
+		{Name: "jwt_key", Status: impl.HealthStatusDown, Error: "JWT signing key is not loaded"},
+	}, readiness.Checks)
+}
+
+func TestGetVersion_ShouldReportBuildInfo(t *testing.T) {
+	previousVersion, previousCommit := buildinfo.Version, buildinfo.Commit
+	buildinfo.Version, buildinfo.Commit = "v1.4.0", "0123abc"
+	t.Cleanup(func() {
+		buildinfo.Version, buildinfo.Commit = previousVersion, previousCommit
+	})
+
+	healthUseCase := impl.NewHealthUseCaseImpl(logrus.New(), nil, func() bool { return true })
+
+	version := healthUseCase.GetVersion()
+
+	assert.Equal(t, "v1.4.0", version.Version)
+	assert.Equal(t, "0123abc", version.Commit)
+	assert.Equal(t, buildinfo.StartedAt, version.StartedAt)
+	assert.NotEmpty(t, version.GoVersion)
+	assert.GreaterOrEqual(t, version.UptimeSeconds, int64(0))
+}
@@ -0,0 +1,146 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/usecase/impl"
+	"merchant_bank_payment_go_api/test/helper"
+	"testing"
+	"time"
+)
+
+func TestArchiveHistories_ShouldArchiveRecordsOlderThanRetention(t *testing.T) {
+	now := helper.CreatedAt.Add(100 * 24 * time.Hour)
+	old := helper.ExpectedHistories[:2]
+	archive := entity.HistoryArchive{Id: uuid.New(), Filename: "history-archive.json.gz", Records: len(old)}
+
+	mockHistoryRepository := new(helper.MockHistoryRepository)
+	mockHistoryRepository.On("PruneHistories", mock.Anything, now.Add(-90*24*time.Hour), mock.Anything).Return(len(old), nil, old)
+
+	mockHistoryArchiveRepository := new(helper.MockHistoryArchiveRepository)
+	mockHistoryArchiveRepository.On("WriteArchive", mock.Anything, old, now).Return(archive, nil)
+
+	historyArchiveUseCase := impl.NewHistoryArchiveUseCaseImpl(logrus.New(), mockHistoryRepository, mockHistoryArchiveRepository, new(helper.MockHistoryUseCase), 90*24*time.Hour)
+
+	result, err := historyArchiveUseCase.ArchiveHistories(context.Background(), now)
+
+	assert.Nil(t, err)
+	assert.Equal(t, &archive, result)
+	mockHistoryArchiveRepository.AssertExpectations(t)
+}
+
+func TestArchiveHistories_ShouldReturnNil_WhenNothingToArchive(t *testing.T) {
+	now := time.Now()
+
+	mockHistoryRepository := new(helper.MockHistoryRepository)
+	mockHistoryRepository.On("PruneHistories", mock.Anything, mock.Anything, mock.Anything).Return(0, nil, nil)
+
+	mockHistoryArchiveRepository := new(helper.MockHistoryArchiveRepository)
+
+	historyArchiveUseCase := impl.NewHistoryArchiveUseCaseImpl(logrus.New(), mockHistoryRepository, mockHistoryArchiveRepository, new(helper.MockHistoryUseCase), 90*24*time.Hour)
+
+	result, err := historyArchiveUseCase.ArchiveHistories(context.Background(), now)
+
+	assert.Nil(t, err)
+	assert.Nil(t, result)
+	mockHistoryArchiveRepository.AssertNotCalled(t, "WriteArchive", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestArchiveHistories_ShouldReturnError_WhenArchiveWriteFails(t *testing.T) {
+	now := time.Now()
+	old := helper.ExpectedHistories[:1]
+
+	mockHistoryRepository := new(helper.MockHistoryRepository)
+	mockHistoryRepository.On("PruneHistories", mock.Anything, mock.Anything, mock.Anything).Return(len(old), nil, old)
+
+	mockHistoryArchiveRepository := new(helper.MockHistoryArchiveRepository)
+	mockHistoryArchiveRepository.On("WriteArchive", mock.Anything, old, now).Return(entity.HistoryArchive{}, errors.New("disk full"))
+
+	historyArchiveUseCase := impl.NewHistoryArchiveUseCaseImpl(logrus.New(), mockHistoryRepository, mockHistoryArchiveRepository, new(helper.MockHistoryUseCase), 90*24*time.Hour)
+
+	result, err := historyArchiveUseCase.ArchiveHistories(context.Background(), now)
+
+	assert.NotNil(t, err)
+	assert.Nil(t, result)
+}
+
+func TestStartArchiveWorker_StopShouldWaitForRunningArchival(t *testing.T) {
+	running := make(chan struct{}, 1)
+	release := make(chan struct{})
+	mockHistoryRepository := new(helper.MockHistoryRepository)
+	mockHistoryRepository.On("PruneHistories", mock.Anything, mock.Anything, mock.Anything).Return(0, nil, nil).Run(func(args mock.Arguments) {
+		select {
+		case running <- struct{}{}:
+			<-release
+		default:
+		}
+	})
+
+	historyArchiveUseCase := impl.NewHistoryArchiveUseCaseImpl(logrus.New(), mockHistoryRepository, new(helper.MockHistoryArchiveRepository), new(helper.MockHistoryUseCase), time.Hour)
+	stop := historyArchiveUseCase.StartArchiveWorker(time.Millisecond)
+	<-running
+
+	stopped := make(chan struct{})
+	go func() {
+		stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		t.Fatal("stop returned while an archival was still running")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	<-stopped
+}
+
+func TestArchiveNow_ShouldUseRequestedRetentionAndRecordAdmin(t *testing.T) {
+	adminId := uuid.New().String()
+	old := helper.ExpectedHistories[:1]
+	archive := entity.HistoryArchive{Id: uuid.New(), Filename: "history-archive.json.gz", Records: len(old)}
+
+	mockHistoryRepository := new(helper.MockHistoryRepository)
+	mockHistoryRepository.On("PruneHistories", mock.Anything, mock.MatchedBy(func(cutoff time.Time) bool {
+		return time.Since(cutoff) > 29*24*time.Hour && time.Since(cutoff) < 31*24*time.Hour
+	}), mock.Anything).Return(len(old), nil, old)
+
+	mockHistoryArchiveRepository := new(helper.MockHistoryArchiveRepository)
+	mockHistoryArchiveRepository.On("WriteArchive", mock.Anything, old, mock.Anything).Return(archive, nil)
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, adminId, entity.AuditActionHistoryArchive,
+		mock.MatchedBy(func(details entity.AuditDetails) bool {
+			return details.SubjectId == archive.Id.String()
+		}), nil).Return(nil)
+
+	historyArchiveUseCase := impl.NewHistoryArchiveUseCaseImpl(logrus.New(), mockHistoryRepository, mockHistoryArchiveRepository, mockHistoryUseCase, 90*24*time.Hour)
+
+	result, err := historyArchiveUseCase.ArchiveNow(context.Background(), adminId, 30*24*time.Hour)
+
+	assert.Nil(t, err)
+	assert.Equal(t, &archive, result)
+	mockHistoryRepository.AssertExpectations(t)
+	mockHistoryUseCase.AssertExpectations(t)
+}
+
+func TestArchiveNow_ShouldReturnError_WhenArchivalDisabled(t *testing.T) {
+	mockHistoryRepository := new(helper.MockHistoryRepository)
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, entity.AuditActionHistoryArchive, mock.Anything, mock.Anything).Return(nil)
+
+	historyArchiveUseCase := impl.NewHistoryArchiveUseCaseImpl(logrus.New(), mockHistoryRepository, new(helper.MockHistoryArchiveRepository), mockHistoryUseCase, 0)
+
+	result, err := historyArchiveUseCase.ArchiveNow(context.Background(), uuid.New().String(), 0)
+
+	assert.NotNil(t, err)
+	assert.Nil(t, result)
+	mockHistoryRepository.AssertNotCalled(t, "PruneHistories", mock.Anything, mock.Anything, mock.Anything)
+}
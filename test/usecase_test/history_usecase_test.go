@@ -1,53 +1,133 @@
 package usecase_test
 
 import (
+	"context"
 	"errors"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/model"
 	"merchant_bank_payment_go_api/internal/usecase/impl"
+	"merchant_bank_payment_go_api/internal/utils"
 	"merchant_bank_payment_go_api/test/helper"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestAddHistory_ShouldCallRepository(t *testing.T) {
 	customerId := uuid.New().String()
-	action := "LOGIN"
-	details := "Login successful"
+	action := entity.AuditActionLogin
+	details := entity.AuditDetails{SubjectType: entity.AuditSubjectSession, Message: "Login successful"}
 
 	mockHistoryRepository := new(helper.MockHistoryRepository)
-	mockHistoryRepository.On("AddHistory", mock.MatchedBy(func(h entity.History) bool {
+	mockHistoryRepository.On("AddHistory", mock.Anything, mock.MatchedBy(func(h entity.History) bool {
 		return h.CustomerId == customerId &&
 			h.Action == action &&
-			h.Details == details
+			h.Details.Message == details.Message &&
+			h.Details.ActorType == entity.AuditActorCustomer &&
+			h.Details.Outcome == entity.AuditOutcomeSuccess
 	})).Return(nil)
 
 	log := logrus.New()
-	historyUseCase := impl.NewHistoryUseCaseImpl(log, mockHistoryRepository)
+	historyUseCase := impl.NewHistoryUseCaseImpl(log, mockHistoryRepository, new(helper.MockHistoryArchiveRepository))
 
-	err := historyUseCase.AddHistory(customerId, action, details)
+	err := historyUseCase.AddHistory(context.Background(), customerId, action, details)
 
 	assert.Nil(t, err)
 	mockHistoryRepository.AssertExpectations(t)
 }
 
+func TestAddHistory_ShouldRecordRequestIdFromContext(t *testing.T) {
+	mockHistoryRepository := new(helper.MockHistoryRepository)
+	mockHistoryRepository.On("AddHistory", mock.Anything, mock.MatchedBy(func(h entity.History) bool {
+		return h.Details.RequestId == "req-1"
+	})).Return(nil)
+
+	historyUseCase := impl.NewHistoryUseCaseImpl(logrus.New(), mockHistoryRepository, new(helper.MockHistoryArchiveRepository))
+
+	ctx := utils.WithRequestId(context.Background(), "req-1")
+	err := historyUseCase.AddHistory(ctx, uuid.New().String(), entity.AuditActionLogin, entity.AuditDetails{})
+
+	assert.Nil(t, err)
+	mockHistoryRepository.AssertExpectations(t)
+}
+
+func TestAddHistory_ShouldRecordClientFromContext(t *testing.T) {
+	mockHistoryRepository := new(helper.MockHistoryRepository)
+	mockHistoryRepository.On("AddHistory", mock.Anything, mock.MatchedBy(func(h entity.History) bool {
+		return h.Details.Ip == "203.0.113.7" && h.Details.UserAgent == "checkout-app/2.1"
+	})).Return(nil)
+
+	historyUseCase := impl.NewHistoryUseCaseImpl(logrus.New(), mockHistoryRepository, new(helper.MockHistoryArchiveRepository))
+
+	ctx := utils.WithClient(context.Background(), "203.0.113.7", "checkout-app/2.1")
+	err := historyUseCase.AddHistory(ctx, uuid.New().String(), entity.AuditActionLogin, entity.AuditDetails{})
+
+	assert.Nil(t, err)
+	mockHistoryRepository.AssertExpectations(t)
+}
+
+func TestAddHistory_ShouldWriteHistory_WhenContextCancelled(t *testing.T) {
+	mockHistoryRepository := new(helper.MockHistoryRepository)
+	mockHistoryRepository.On("AddHistory", mock.MatchedBy(func(ctx context.Context) bool {
+		return ctx.Err() == nil
+	}), mock.Anything).Return(nil)
+
+	historyUseCase := impl.NewHistoryUseCaseImpl(logrus.New(), mockHistoryRepository, new(helper.MockHistoryArchiveRepository))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := historyUseCase.AddHistory(ctx, uuid.New().String(), entity.AuditActionLogin, entity.AuditDetails{})
+
+	assert.Nil(t, err)
+	mockHistoryRepository.AssertExpectations(t)
+}
+
+func TestAddHistory_ShouldRecordAnonymousActor_WhenActorIdEmpty(t *testing.T) {
+	mockHistoryRepository := new(helper.MockHistoryRepository)
+	mockHistoryRepository.On("AddHistory", mock.Anything, mock.MatchedBy(func(h entity.History) bool {
+		return h.CustomerId == "" && h.Details.ActorType == entity.AuditActorAnonymous
+	})).Return(nil)
+
+	log := logrus.New()
+	historyUseCase := impl.NewHistoryUseCaseImpl(log, mockHistoryRepository, new(helper.MockHistoryArchiveRepository))
+
+	err := historyUseCase.AddHistory(context.Background(), "", entity.AuditActionLogin, entity.AuditDetails{Message: "Login failed"})
+
+	assert.Nil(t, err)
+	mockHistoryRepository.AssertExpectations(t)
+}
+
+func TestAddHistory_ShouldReturnError_WhenActionNotInCatalogue(t *testing.T) {
+	mockHistoryRepository := new(helper.MockHistoryRepository)
+
+	log := logrus.New()
+	historyUseCase := impl.NewHistoryUseCaseImpl(log, mockHistoryRepository, new(helper.MockHistoryArchiveRepository))
+
+	err := historyUseCase.AddHistory(context.Background(), uuid.New().String(), "Successfully found customer by id", entity.AuditDetails{})
+
+	assert.NotNil(t, err)
+	mockHistoryRepository.AssertNotCalled(t, "AddHistory", mock.Anything, mock.Anything)
+}
+
 func TestAddHistory_ShouldReturnErrorWhenInvalidCustomerId(t *testing.T) {
 	customerId := uuid.New().String()
-	action := "LOGIN"
-	details := "Login failed"
+	action := entity.AuditActionLogin
+	details := entity.AuditDetails{Message: "Login failed"}
 	log := logrus.New()
 
 	mockHistoryRepository := new(helper.MockHistoryRepository)
-	mockHistoryRepository.On("AddHistory", mock.MatchedBy(func(h entity.History) bool {
+	mockHistoryRepository.On("AddHistory", mock.Anything, mock.MatchedBy(func(h entity.History) bool {
 		return h.CustomerId == customerId &&
 			h.Action == action &&
-			h.Details == details
+			h.Details.Message == details.Message
 	})).Return(errors.New("error add history"))
-	authUseCase := impl.NewHistoryUseCaseImpl(log, mockHistoryRepository)
+	authUseCase := impl.NewHistoryUseCaseImpl(log, mockHistoryRepository, new(helper.MockHistoryArchiveRepository))
 
-	err := authUseCase.AddHistory(customerId, action, details)
+	err := authUseCase.AddHistory(context.Background(), customerId, action, details)
 
 	assert.NotNil(t, err)
 	mockHistoryRepository.AssertExpectations(t)
@@ -55,57 +135,222 @@ func TestAddHistory_ShouldReturnErrorWhenInvalidCustomerId(t *testing.T) {
 
 func TestLogAndAddHistory_ShouldReturnNilWhenErrorStatusIsNil(t *testing.T) {
 	customerId := uuid.New().String()
-	action := "LOGIN"
-	details := "Login successful"
+	action := entity.AuditActionLogin
+	details := entity.AuditDetails{Message: "Login successful"}
 
 	mockHistoryRepository := new(helper.MockHistoryRepository)
-	mockHistoryRepository.On("AddHistory", mock.MatchedBy(func(h entity.History) bool {
+	mockHistoryRepository.On("AddHistory", mock.Anything, mock.MatchedBy(func(h entity.History) bool {
 		return h.CustomerId == customerId &&
 			h.Action == action &&
-			h.Details == details
+			h.Details.Message == details.Message &&
+			h.Details.Outcome == entity.AuditOutcomeSuccess &&
+			h.Details.ErrorCode == ""
 	})).Return(nil)
 
 	log := logrus.New()
-	authUseCase := impl.NewHistoryUseCaseImpl(log, mockHistoryRepository)
+	authUseCase := impl.NewHistoryUseCaseImpl(log, mockHistoryRepository, new(helper.MockHistoryArchiveRepository))
 
-	err := authUseCase.LogAndAddHistory(customerId, action, details, nil)
+	err := authUseCase.LogAndAddHistory(context.Background(), customerId, action, details, nil)
 	assert.Nil(t, err)
+	mockHistoryRepository.AssertExpectations(t)
 }
 
 func TestLogAndAddHistory_ShouldReturnNilWhenErrorStatusIsNotNil(t *testing.T) {
 	customerId := uuid.New().String()
-	action := "LOGIN"
-	details := "Login failed"
+	action := entity.AuditActionLogin
+	details := entity.AuditDetails{ErrorCode: entity.AuditErrorInvalidCredentials, Message: "Login failed"}
 
 	mockHistoryRepository := new(helper.MockHistoryRepository)
-	mockHistoryRepository.On("AddHistory", mock.MatchedBy(func(h entity.History) bool {
+	mockHistoryRepository.On("AddHistory", mock.Anything, mock.MatchedBy(func(h entity.History) bool {
 		return h.CustomerId == customerId &&
 			h.Action == action &&
-			h.Details == details
+			h.Details.Outcome == entity.AuditOutcomeFailure &&
+			h.Details.ErrorCode == entity.AuditErrorInvalidCredentials
+	})).Return(nil)
+
+	log := logrus.New()
+	authUseCase := impl.NewHistoryUseCaseImpl(log, mockHistoryRepository, new(helper.MockHistoryArchiveRepository))
+
+	err := authUseCase.LogAndAddHistory(context.Background(), customerId, action, details, errors.New("something wrong"))
+	assert.Nil(t, err)
+	mockHistoryRepository.AssertExpectations(t)
+}
+
+func TestLogAndAddHistory_ShouldDefaultErrorCodeToInternal(t *testing.T) {
+	mockHistoryRepository := new(helper.MockHistoryRepository)
+	mockHistoryRepository.On("AddHistory", mock.Anything, mock.MatchedBy(func(h entity.History) bool {
+		return h.Details.Outcome == entity.AuditOutcomeFailure && h.Details.ErrorCode == entity.AuditErrorInternal
 	})).Return(nil)
 
 	log := logrus.New()
-	authUseCase := impl.NewHistoryUseCaseImpl(log, mockHistoryRepository)
+	historyUseCase := impl.NewHistoryUseCaseImpl(log, mockHistoryRepository, new(helper.MockHistoryArchiveRepository))
 
-	err := authUseCase.LogAndAddHistory(customerId, action, details, errors.New("something wrong"))
+	err := historyUseCase.LogAndAddHistory(context.Background(), uuid.New().String(), entity.AuditActionPaymentCreate,
+		entity.AuditDetails{Message: "Payment failed"}, errors.New("something wrong"))
 	assert.Nil(t, err)
+	mockHistoryRepository.AssertExpectations(t)
 }
 
 func TestLogAndAddHistory_ShouldReturnErrorWhenAddHistoryError(t *testing.T) {
 	customerId := uuid.New().String()
-	action := "LOGIN"
-	details := "Login failed"
+	action := entity.AuditActionLogin
+	details := entity.AuditDetails{Message: "Login failed"}
 
 	mockHistoryRepository := new(helper.MockHistoryRepository)
-	mockHistoryRepository.On("AddHistory", mock.MatchedBy(func(h entity.History) bool {
+	mockHistoryRepository.On("AddHistory", mock.Anything, mock.MatchedBy(func(h entity.History) bool {
 		return h.CustomerId == customerId &&
 			h.Action == action &&
-			h.Details == details
+			h.Details.Message == details.Message
 	})).Return(errors.New("error add"))
 
 	log := logrus.New()
-	authUseCase := impl.NewHistoryUseCaseImpl(log, mockHistoryRepository)
+	authUseCase := impl.NewHistoryUseCaseImpl(log, mockHistoryRepository, new(helper.MockHistoryArchiveRepository))
+
+	err := authUseCase.LogAndAddHistory(context.Background(), customerId, action, details, errors.New("something wrong"))
+	assert.NotNil(t, err)
+}
+
+func historyQueryFixture() []entity.History {
+	otherCustomer := uuid.New().String()
+	return []entity.History{
+		{Id: uuid.New(), Action: entity.AuditActionLogin, CustomerId: helper.CustomerId.String(), Timestamp: helper.CreatedAt,
+			Details: entity.AuditDetails{Outcome: entity.AuditOutcomeFailure, ErrorCode: entity.AuditErrorInvalidCredentials}},
+		{Id: uuid.New(), Action: entity.AuditActionLogin, CustomerId: helper.CustomerId.String(), Timestamp: helper.CreatedAt.Add(time.Minute),
+			Details: entity.AuditDetails{Outcome: entity.AuditOutcomeSuccess, Message: "Login successful"}},
+		{Id: uuid.New(), Action: entity.AuditActionPaymentCreate, CustomerId: helper.CustomerId.String(), Timestamp: helper.CreatedAt.Add(2 * time.Minute),
+			Details: entity.AuditDetails{Outcome: entity.AuditOutcomeSuccess, Message: "Payment, with comma"}},
+		{Id: uuid.New(), Action: entity.AuditActionLogin, CustomerId: otherCustomer, Timestamp: helper.CreatedAt.Add(3 * time.Minute),
+			Details: entity.AuditDetails{Outcome: entity.AuditOutcomeSuccess}},
+	}
+}
+
+func TestGetCustomerHistory_ShouldOnlyReturnOwnEntriesNewestFirst(t *testing.T) {
+	histories := historyQueryFixture()
+	mockHistoryRepository := new(helper.MockHistoryRepository)
+	mockHistoryRepository.On("LoadHistories", mock.Anything).Return(histories, nil)
+
+	historyUseCase := impl.NewHistoryUseCaseImpl(logrus.New(), mockHistoryRepository, new(helper.MockHistoryArchiveRepository))
+
+	page, err := historyUseCase.GetCustomerHistory(context.Background(), helper.CustomerId.String(),
+		model.HistoryQueryRequest{CustomerId: histories[3].CustomerId, PageSize: 2})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, page.TotalItems)
+	assert.Equal(t, 2, page.TotalPages)
+	assert.Len(t, page.Items, 2)
+	assert.Equal(t, histories[2].Id.String(), page.Items[0].Id)
+	assert.Equal(t, histories[1].Id.String(), page.Items[1].Id)
+}
+
+func TestGetCustomerHistory_ShouldApplyFilters(t *testing.T) {
+	histories := historyQueryFixture()
+	mockHistoryRepository := new(helper.MockHistoryRepository)
+	mockHistoryRepository.On("LoadHistories", mock.Anything).Return(histories, nil)
+
+	historyUseCase := impl.NewHistoryUseCaseImpl(logrus.New(), mockHistoryRepository, new(helper.MockHistoryArchiveRepository))
 
-	err := authUseCase.LogAndAddHistory(customerId, action, details, errors.New("something wrong"))
+	page, err := historyUseCase.GetCustomerHistory(context.Background(), helper.CustomerId.String(), model.HistoryQueryRequest{
+		Action:  "auth_login",
+		Outcome: "failure",
+		From:    helper.CreatedAt.Add(-time.Second).Format(time.RFC3339),
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, page.TotalItems)
+	assert.Equal(t, "INVALID_CREDENTIALS", page.Items[0].ErrorCode)
+	assert.Equal(t, 1, page.Page)
+	assert.Equal(t, 20, page.PageSize)
+}
+
+func TestGetCustomerHistory_ShouldReturnError_WhenFilterInvalid(t *testing.T) {
+	historyUseCase := impl.NewHistoryUseCaseImpl(logrus.New(), new(helper.MockHistoryRepository), new(helper.MockHistoryArchiveRepository))
+
+	_, err := historyUseCase.GetCustomerHistory(context.Background(), helper.CustomerId.String(), model.HistoryQueryRequest{Action: "LOGIN"})
+	assert.NotNil(t, err)
+
+	_, err = historyUseCase.GetCustomerHistory(context.Background(), helper.CustomerId.String(), model.HistoryQueryRequest{PageSize: 1000})
 	assert.NotNil(t, err)
+
+	_, err = historyUseCase.GetCustomerHistory(context.Background(), helper.CustomerId.String(), model.HistoryQueryRequest{To: "yesterday"})
+	assert.NotNil(t, err)
+}
+
+func TestSearchHistory_ShouldRecordHistoryView(t *testing.T) {
+	adminId := uuid.New().String()
+	histories := historyQueryFixture()
+	mockHistoryRepository := new(helper.MockHistoryRepository)
+	mockHistoryRepository.On("LoadHistories", mock.Anything).Return(histories, nil)
+	mockHistoryRepository.On("AddHistory", mock.Anything, mock.MatchedBy(func(h entity.History) bool {
+		return h.CustomerId == adminId && h.Action == entity.AuditActionHistoryView &&
+			h.Details.SubjectType == entity.AuditSubjectHistory && h.Details.ActorType == entity.AuditActorAdmin
+	})).Return(nil)
+
+	historyUseCase := impl.NewHistoryUseCaseImpl(logrus.New(), mockHistoryRepository, new(helper.MockHistoryArchiveRepository))
+
+	page, err := historyUseCase.SearchHistory(context.Background(), adminId, model.HistoryQueryRequest{Action: "AUTH_LOGIN"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, page.TotalItems)
+	mockHistoryRepository.AssertExpectations(t)
+}
+
+func TestExportHistory_ShouldWriteCsv(t *testing.T) {
+	adminId := uuid.New().String()
+	histories := historyQueryFixture()
+	mockHistoryRepository := new(helper.MockHistoryRepository)
+	mockHistoryRepository.On("LoadHistories", mock.Anything).Return(histories, nil)
+	mockHistoryRepository.On("AddHistory", mock.Anything, mock.Anything).Return(nil)
+
+	historyUseCase := impl.NewHistoryUseCaseImpl(logrus.New(), mockHistoryRepository, new(helper.MockHistoryArchiveRepository))
+
+	var output strings.Builder
+	err := historyUseCase.ExportHistory(context.Background(), adminId, model.HistoryQueryRequest{CustomerId: helper.CustomerId.String(), Action: "PAYMENT_CREATE"}, &output)
+
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[0], "id,sequence,timestamp,action,customer_id"))
+	assert.Contains(t, lines[1], histories[2].Id.String())
+	assert.Contains(t, lines[1], `"Payment, with comma"`)
+}
+
+func TestGetCustomerHistory_ShouldIncludeOverlappingArchives_WhenRequested(t *testing.T) {
+	histories := historyQueryFixture()
+	recent := entity.HistoryArchive{Id: uuid.New(), From: helper.CreatedAt, To: helper.CreatedAt.Add(time.Minute)}
+	old := entity.HistoryArchive{Id: uuid.New(), From: helper.CreatedAt.AddDate(-1, 0, 0), To: helper.CreatedAt.AddDate(-1, 0, 1)}
+
+	mockHistoryRepository := new(helper.MockHistoryRepository)
+	mockHistoryRepository.On("LoadHistories", mock.Anything).Return(histories[2:], nil)
+
+	mockHistoryArchiveRepository := new(helper.MockHistoryArchiveRepository)
+	mockHistoryArchiveRepository.On("LoadArchives", mock.Anything).Return([]entity.HistoryArchive{old, recent}, nil)
+	mockHistoryArchiveRepository.On("LoadArchivedHistories", mock.Anything, recent).Return(histories[:2], nil)
+
+	historyUseCase := impl.NewHistoryUseCaseImpl(logrus.New(), mockHistoryRepository, mockHistoryArchiveRepository)
+
+	page, err := historyUseCase.GetCustomerHistory(context.Background(), helper.CustomerId.String(), model.HistoryQueryRequest{
+		From:            helper.CreatedAt.Format("2006-01-02"),
+		IncludeArchived: true,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, page.TotalItems)
+	assert.Equal(t, histories[0].Id.String(), page.Items[2].Id)
+	mockHistoryArchiveRepository.AssertNotCalled(t, "LoadArchivedHistories", mock.Anything, old)
+}
+
+func TestGetCustomerHistory_ShouldSkipArchives_ByDefault(t *testing.T) {
+	histories := historyQueryFixture()
+	mockHistoryRepository := new(helper.MockHistoryRepository)
+	mockHistoryRepository.On("LoadHistories", mock.Anything).Return(histories[2:], nil)
+
+	mockHistoryArchiveRepository := new(helper.MockHistoryArchiveRepository)
+
+	historyUseCase := impl.NewHistoryUseCaseImpl(logrus.New(), mockHistoryRepository, mockHistoryArchiveRepository)
+
+	page, err := historyUseCase.GetCustomerHistory(context.Background(), helper.CustomerId.String(), model.HistoryQueryRequest{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, page.TotalItems)
+	mockHistoryArchiveRepository.AssertNotCalled(t, "LoadArchives", mock.Anything)
 }
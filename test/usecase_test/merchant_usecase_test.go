@@ -1,11 +1,13 @@
 package usecase_test
 
 import (
+	"context"
 	"errors"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/model"
 	"merchant_bank_payment_go_api/internal/usecase/impl"
 	"merchant_bank_payment_go_api/test/helper"
 	"testing"
@@ -14,12 +16,12 @@ import (
 func TestFindById_ShouldReturnMerchant(t *testing.T) {
 	mockMerchantRepository := new(helper.MockMerchantRepository)
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	useCase := impl.NewMerchantUseCaseImpl(mockHistoryUseCase, mockMerchantRepository)
 
-	mockMerchantRepository.On("FindById", helper.MerchantId).Return(helper.ExpectedMerchants[0], nil)
+	mockMerchantRepository.On("FindById", mock.Anything, helper.MerchantId).Return(helper.ExpectedMerchants[0], nil)
 
-	merchantResult, err := useCase.FindById(helper.MerchantId.String())
+	merchantResult, err := useCase.FindById(context.Background(), helper.MerchantId.String())
 
 	assert.Nil(t, err)
 	assert.Equal(t, helper.ExpectedMerchants[0], merchantResult)
@@ -29,13 +31,13 @@ func TestFindById_ShouldReturnMerchant(t *testing.T) {
 func TestFindById_ShouldReturnErrorParseToken(t *testing.T) {
 	mockMerchantRepository := new(helper.MockMerchantRepository)
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	useCase := impl.NewMerchantUseCaseImpl(mockHistoryUseCase, mockMerchantRepository)
 
 	merchantId := "nil"
-	mockMerchantRepository.On("FindById", merchantId).Return(entity.Merchant{}, errors.New("merchant not found"))
+	mockMerchantRepository.On("FindById", mock.Anything, merchantId).Return(entity.Merchant{}, errors.New("merchant not found"))
 
-	merchantResult, err := useCase.FindById(merchantId)
+	merchantResult, err := useCase.FindById(context.Background(), merchantId)
 
 	assert.NotNil(t, err)
 	assert.Equal(t, entity.Merchant{}, merchantResult)
@@ -44,13 +46,13 @@ func TestFindById_ShouldReturnErrorParseToken(t *testing.T) {
 func TestFindById_ShouldReturnError_WhenMerchantNotFound(t *testing.T) {
 	mockMerchantRepository := new(helper.MockMerchantRepository)
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	useCase := impl.NewMerchantUseCaseImpl(mockHistoryUseCase, mockMerchantRepository)
 
 	merchantId := uuid.New()
-	mockMerchantRepository.On("FindById", merchantId).Return(entity.Merchant{}, errors.New("merchant not found"))
+	mockMerchantRepository.On("FindById", mock.Anything, merchantId).Return(entity.Merchant{}, errors.New("merchant not found"))
 
-	merchantResult, err := useCase.FindById(merchantId.String())
+	merchantResult, err := useCase.FindById(context.Background(), merchantId.String())
 
 	assert.NotNil(t, err)
 	assert.Equal(t, entity.Merchant{}, merchantResult)
@@ -60,21 +62,21 @@ func TestFindById_ShouldReturnError_WhenMerchantNotFound(t *testing.T) {
 func TestFindMerchantById_ShouldReturnError_WhenLogOnErrorParseToken(t *testing.T) {
 	mockMerchantRepository := new(helper.MockMerchantRepository)
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("error On Log"))
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("error On Log"))
 	useCase := impl.NewMerchantUseCaseImpl(mockHistoryUseCase, mockMerchantRepository)
 
-	_, err := useCase.FindById("12345")
+	_, err := useCase.FindById(context.Background(), "12345")
 	assert.NotNil(t, err)
 }
 
 func TestFindById_ShouldReturnError_WhenLogOnReturnMerchantNotFound(t *testing.T) {
 	mockMerchantRepository := new(helper.MockMerchantRepository)
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("error On Log"))
-	mockMerchantRepository.On("FindById", helper.MerchantId).Return(entity.Merchant{}, errors.New("customer not found"))
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("error On Log"))
+	mockMerchantRepository.On("FindById", mock.Anything, helper.MerchantId).Return(entity.Merchant{}, errors.New("customer not found"))
 	useCase := impl.NewMerchantUseCaseImpl(mockHistoryUseCase, mockMerchantRepository)
 
-	_, err := useCase.FindById(helper.MerchantId.String())
+	_, err := useCase.FindById(context.Background(), helper.MerchantId.String())
 	assert.NotNil(t, err)
 	mockMerchantRepository.AssertExpectations(t)
 }
@@ -82,10 +84,147 @@ func TestFindById_ShouldReturnError_WhenLogOnReturnMerchantNotFound(t *testing.T
 func TestFindById_ShouldReturnError_WhenLogOnReturnMerchantError(t *testing.T) {
 	mockMerchantRepository := new(helper.MockMerchantRepository)
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("error On Log"))
-	mockMerchantRepository.On("FindById", helper.MerchantId).Return(helper.ExpectedMerchants[0], nil)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("error On Log"))
+	mockMerchantRepository.On("FindById", mock.Anything, helper.MerchantId).Return(helper.ExpectedMerchants[0], nil)
 	useCase := impl.NewMerchantUseCaseImpl(mockHistoryUseCase, mockMerchantRepository)
 
-	_, err := useCase.FindById(helper.MerchantId.String())
+	_, err := useCase.FindById(context.Background(), helper.MerchantId.String())
+	assert.NotNil(t, err)
+}
+
+func TestCreateMerchant_ShouldSaveActiveMerchant(t *testing.T) {
+	adminId := uuid.New().String()
+	mockMerchantRepository := new(helper.MockMerchantRepository)
+	mockMerchantRepository.On("Save", mock.Anything, mock.MatchedBy(func(merchant entity.Merchant) bool {
+		return merchant.Name == "toko baru" && merchant.Status == entity.MerchantStatusActive &&
+			merchant.WebhookSecret == "secret" && !merchant.CreatedAt.IsZero() && merchant.UpdatedAt.Equal(merchant.CreatedAt)
+	})).Return(nil)
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, adminId, entity.AuditActionMerchantCreate, mock.Anything, nil).Return(nil)
+	useCase := impl.NewMerchantUseCaseImpl(mockHistoryUseCase, mockMerchantRepository)
+
+	merchant, err := useCase.CreateMerchant(context.Background(), adminId, model.MerchantRequest{
+		Name:               " toko baru ",
+		AcceptedCurrencies: []string{"sgd", "IDR"},
+		FeePlan:            model.FeePlan{Type: "percentage", PercentageBasisPoints: 100},
+		WebhookUrl:         "https://example.com/hooks",
+		WebhookSecret:      "secret",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "toko baru", merchant.Name)
+	assert.Equal(t, []string{"SGD", "IDR"}, merchant.AcceptedCurrencies)
+	assert.Equal(t, "PERCENTAGE", merchant.FeePlan.Type)
+	assert.Equal(t, "ACTIVE", merchant.Status)
+	mockMerchantRepository.AssertExpectations(t)
+	mockHistoryUseCase.AssertExpectations(t)
+}
+
+func TestCreateMerchant_ShouldReturnError_WhenRequestInvalid(t *testing.T) {
+	requests := map[string]model.MerchantRequest{
+		"blank name":            {Name: "  "},
+		"unsupported currency":  {Name: "toko", AcceptedCurrencies: []string{"XXX"}},
+		"duplicate currency":    {Name: "toko", AcceptedCurrencies: []string{"IDR", "idr"}},
+		"invalid fee plan":      {Name: "toko", FeePlan: model.FeePlan{Type: "FIXED", FixedAmount: -5}},
+		"relative webhook url":  {Name: "toko", WebhookUrl: "/hooks", WebhookSecret: "secret"},
+		"webhook url no secret": {Name: "toko", WebhookUrl: "https://example.com/hooks"},
+	}
+
+	for name, request := range requests {
+		mockMerchantRepository := new(helper.MockMerchantRepository)
+		mockHistoryUseCase := new(helper.MockHistoryUseCase)
+		mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, entity.AuditActionMerchantCreate,
+			mock.MatchedBy(func(details entity.AuditDetails) bool {
+				return details.ErrorCode == entity.AuditErrorInvalidRequest
+			}), mock.Anything).Return(nil)
+		useCase := impl.NewMerchantUseCaseImpl(mockHistoryUseCase, mockMerchantRepository)
+
+		_, err := useCase.CreateMerchant(context.Background(), uuid.New().String(), request)
+
+		assert.NotNil(t, err, name)
+		mockMerchantRepository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	}
+}
+
+func TestUpdateMerchant_ShouldKeepWebhookSecretAndRefreshUpdatedAt(t *testing.T) {
+	existing := helper.ExpectedMerchants[0]
+	existing.WebhookUrl = "https://example.com/old"
+	existing.WebhookSecret = "kept"
+
+	mockMerchantRepository := new(helper.MockMerchantRepository)
+	mockMerchantRepository.On("FindById", mock.Anything, existing.Id).Return(existing, nil)
+	mockMerchantRepository.On("Update", mock.Anything, mock.MatchedBy(func(merchant entity.Merchant) bool {
+		return merchant.Name == "renamed" && merchant.WebhookSecret == "kept" &&
+			merchant.CreatedAt.Equal(existing.CreatedAt) && merchant.UpdatedAt.After(existing.UpdatedAt)
+	})).Return(nil)
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, entity.AuditActionMerchantUpdate, mock.Anything, nil).Return(nil)
+	useCase := impl.NewMerchantUseCaseImpl(mockHistoryUseCase, mockMerchantRepository)
+
+	merchant, err := useCase.UpdateMerchant(context.Background(), uuid.New().String(), existing.Id.String(),
+		model.MerchantRequest{Name: "renamed", WebhookUrl: "https://example.com/new"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "https://example.com/new", merchant.WebhookUrl)
+	mockMerchantRepository.AssertExpectations(t)
+}
+
+func TestSuspendMerchant_ShouldSuspendActiveMerchant(t *testing.T) {
+	mockMerchantRepository := new(helper.MockMerchantRepository)
+	mockMerchantRepository.On("FindById", mock.Anything, helper.MerchantId).Return(helper.ExpectedMerchants[0], nil)
+	mockMerchantRepository.On("Update", mock.Anything, mock.MatchedBy(func(merchant entity.Merchant) bool {
+		return merchant.IsSuspended()
+	})).Return(nil)
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, entity.AuditActionMerchantSuspend, mock.Anything, nil).Return(nil)
+	useCase := impl.NewMerchantUseCaseImpl(mockHistoryUseCase, mockMerchantRepository)
+
+	merchant, err := useCase.SuspendMerchant(context.Background(), uuid.New().String(), helper.MerchantId.String())
+
+	assert.Nil(t, err)
+	assert.Equal(t, "SUSPENDED", merchant.Status)
+	mockMerchantRepository.AssertExpectations(t)
+}
+
+func TestReactivateMerchant_ShouldReturnError_WhenMerchantActive(t *testing.T) {
+	mockMerchantRepository := new(helper.MockMerchantRepository)
+	mockMerchantRepository.On("FindById", mock.Anything, helper.MerchantId).Return(helper.ExpectedMerchants[0], nil)
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, entity.AuditActionMerchantReactivate, mock.Anything, mock.Anything).Return(nil)
+	useCase := impl.NewMerchantUseCaseImpl(mockHistoryUseCase, mockMerchantRepository)
+
+	_, err := useCase.ReactivateMerchant(context.Background(), uuid.New().String(), helper.MerchantId.String())
+
+	assert.ErrorContains(t, err, "not suspended")
+	mockMerchantRepository.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestGetMerchants_ShouldSearchFilterAndPage(t *testing.T) {
+	suspended := entity.Merchant{Id: uuid.New(), Name: "Toko Bangunan", Status: entity.MerchantStatusSuspended}
+	merchants := []entity.Merchant{
+		{Id: uuid.New(), Name: "toko harapan"},
+		{Id: uuid.New(), Name: "foodi fud", Status: entity.MerchantStatusActive},
+		suspended,
+		{Id: uuid.New(), Name: "toko abadi"},
+	}
+	mockMerchantRepository := new(helper.MockMerchantRepository)
+	mockMerchantRepository.On("LoadMerchants", mock.Anything).Return(merchants, nil)
+	useCase := impl.NewMerchantUseCaseImpl(new(helper.MockHistoryUseCase), mockMerchantRepository)
+
+	page, err := useCase.GetMerchants(context.Background(), model.MerchantQueryRequest{Search: "TOKO", PageSize: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, page.TotalItems)
+	assert.Equal(t, 2, page.TotalPages)
+	assert.Equal(t, []string{"toko abadi", "Toko Bangunan"}, []string{page.Items[0].Name, page.Items[1].Name})
+
+	page, err = useCase.GetMerchants(context.Background(), model.MerchantQueryRequest{Status: "active"})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, page.TotalItems)
+
+	page, err = useCase.GetMerchants(context.Background(), model.MerchantQueryRequest{Search: suspended.Id.String()})
+	assert.Nil(t, err)
+	assert.Equal(t, "SUSPENDED", page.Items[0].Status)
+
+	_, err = useCase.GetMerchants(context.Background(), model.MerchantQueryRequest{Status: "CLOSED"})
 	assert.NotNil(t, err)
 }
@@ -1,6 +1,8 @@
 package usecase_test
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -8,19 +10,20 @@ import (
 	"merchant_bank_payment_go_api/internal/entity"
 	"merchant_bank_payment_go_api/internal/model"
 	"merchant_bank_payment_go_api/internal/usecase/impl"
+	"merchant_bank_payment_go_api/internal/utils"
 	"merchant_bank_payment_go_api/test/helper"
 	"testing"
 )
 
 func TestAddPayment_ShouldCallRepository(t *testing.T) {
 	mockPaymentRepository := new(helper.MockPaymentTransactionRepository)
-	mockPaymentRepository.On("AddPayment", mock.Anything).Return(nil)
+	mockPaymentRepository.On("StageAddPayment", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	mockCustomerUseCase := new(helper.MockCustomerUseCase)
-	mockCustomerUseCase.On("FindById", helper.CustomerId.String()).Return(helper.ExpectedCustomers[0], nil)
+	mockCustomerUseCase.On("FindById", mock.Anything, helper.CustomerId.String()).Return(helper.ExpectedCustomers[0], nil)
 
 	mockMerchantUseCase := new(helper.MockMerchantUseCase)
-	mockMerchantUseCase.On("FindById", helper.MerchantId.String()).Return(helper.ExpectedMerchants[0], nil)
+	mockMerchantUseCase.On("FindById", mock.Anything, helper.MerchantId.String()).Return(helper.ExpectedMerchants[0], nil)
 
 	paymentRequest := model.PaymentRequest{
 		MerchantId: helper.MerchantId.String(),
@@ -28,11 +31,13 @@ func TestAddPayment_ShouldCallRepository(t *testing.T) {
 	}
 
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
-	paymentUseCase := impl.NewPaymentTransactionUseCaseImpl(mockPaymentRepository, mockCustomerUseCase, mockMerchantUseCase, mockHistoryUseCase)
+	mockExchangeRateUseCase := new(helper.MockExchangeRateUseCase)
 
-	err := paymentUseCase.AddPayment(helper.CustomerId.String(), paymentRequest)
+	paymentUseCase := impl.NewPaymentTransactionUseCaseImpl(mockPaymentRepository, mockCustomerUseCase, mockMerchantUseCase, mockHistoryUseCase, mockExchangeRateUseCase, acceptingEventBus(), noPaymentLimits)
+
+	err := paymentUseCase.AddPayment(context.Background(), helper.CustomerId.String(), paymentRequest)
 
 	assert.Nil(t, err)
 	mockPaymentRepository.AssertExpectations(t)
@@ -45,7 +50,7 @@ func TestAddPayment_ShouldReturnError_WhenInvalidCustomerId(t *testing.T) {
 	mockPaymentRepository := new(helper.MockPaymentTransactionRepository)
 
 	mockCustomerUseCase := new(helper.MockCustomerUseCase)
-	mockCustomerUseCase.On("FindById", customerId.String()).Return(entity.Customer{}, errors.New("invalid customer"))
+	mockCustomerUseCase.On("FindById", mock.Anything, customerId.String()).Return(entity.Customer{}, errors.New("invalid customer"))
 
 	mockMerchantUseCase := new(helper.MockMerchantUseCase)
 
@@ -55,11 +60,13 @@ func TestAddPayment_ShouldReturnError_WhenInvalidCustomerId(t *testing.T) {
 	}
 
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	mockExchangeRateUseCase := new(helper.MockExchangeRateUseCase)
 
-	paymentUseCase := impl.NewPaymentTransactionUseCaseImpl(mockPaymentRepository, mockCustomerUseCase, mockMerchantUseCase, mockHistoryUseCase)
+	paymentUseCase := impl.NewPaymentTransactionUseCaseImpl(mockPaymentRepository, mockCustomerUseCase, mockMerchantUseCase, mockHistoryUseCase, mockExchangeRateUseCase, acceptingEventBus(), noPaymentLimits)
 
-	err := paymentUseCase.AddPayment(customerId.String(), paymentRequest)
+	err := paymentUseCase.AddPayment(context.Background(), customerId.String(), paymentRequest)
 
 	assert.NotNil(t, err)
 	mockCustomerUseCase.AssertExpectations(t)
@@ -70,25 +77,27 @@ func TestAddPayment_ShouldReturnError_WhenErrorLogOnFindMerchant(t *testing.T) {
 	merchantId := uuid.New()
 
 	mockPaymentRepository := new(helper.MockPaymentTransactionRepository)
-	mockPaymentRepository.On("AddPayment", mock.Anything).Return(nil)
+	mockPaymentRepository.On("StageAddPayment", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	mockCustomerUseCase := new(helper.MockCustomerUseCase)
-	mockCustomerUseCase.On("FindById", helper.CustomerId.String()).Return(helper.ExpectedCustomers[0], nil)
+	mockCustomerUseCase.On("FindById", mock.Anything, helper.CustomerId.String()).Return(helper.ExpectedCustomers[0], nil)
 
 	mockMerchantUseCase := new(helper.MockMerchantUseCase)
-	mockMerchantUseCase.On("FindById", merchantId.String()).Return(entity.Merchant{}, errors.New("merchant not found"))
+	mockMerchantUseCase.On("FindById", mock.Anything, merchantId.String()).Return(entity.Merchant{}, errors.New("merchant not found"))
 
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("something wrong"))
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("something wrong"))
 
 	paymentRequest := model.PaymentRequest{
 		MerchantId: merchantId.String(),
 		Amount:     10000,
 	}
 
-	paymentUseCase := impl.NewPaymentTransactionUseCaseImpl(mockPaymentRepository, mockCustomerUseCase, mockMerchantUseCase, mockHistoryUseCase)
+	mockExchangeRateUseCase := new(helper.MockExchangeRateUseCase)
 
-	err := paymentUseCase.AddPayment(helper.CustomerId.String(), paymentRequest)
+	paymentUseCase := impl.NewPaymentTransactionUseCaseImpl(mockPaymentRepository, mockCustomerUseCase, mockMerchantUseCase, mockHistoryUseCase, mockExchangeRateUseCase, acceptingEventBus(), noPaymentLimits)
+
+	err := paymentUseCase.AddPayment(context.Background(), helper.CustomerId.String(), paymentRequest)
 
 	assert.NotNil(t, err)
 	mockCustomerUseCase.AssertExpectations(t)
@@ -97,27 +106,500 @@ func TestAddPayment_ShouldReturnError_WhenErrorLogOnFindMerchant(t *testing.T) {
 
 func TestAddPayment_ShouldReturnError_WhenErrorLogOnAddPayment(t *testing.T) {
 	mockPaymentRepository := new(helper.MockPaymentTransactionRepository)
-	mockPaymentRepository.On("AddPayment", mock.Anything).Return(errors.New("error add"))
+	mockPaymentRepository.On("StageAddPayment", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("error add"))
 
 	mockCustomerUseCase := new(helper.MockCustomerUseCase)
-	mockCustomerUseCase.On("FindById", helper.CustomerId.String()).Return(helper.ExpectedCustomers[0], nil)
+	mockCustomerUseCase.On("FindById", mock.Anything, helper.CustomerId.String()).Return(helper.ExpectedCustomers[0], nil)
 
 	mockMerchantUseCase := new(helper.MockMerchantUseCase)
-	mockMerchantUseCase.On("FindById", helper.MerchantId.String()).Return(helper.ExpectedMerchants[0], nil)
+	mockMerchantUseCase.On("FindById", mock.Anything, helper.MerchantId.String()).Return(helper.ExpectedMerchants[0], nil)
 
 	mockHistoryUseCase := new(helper.MockHistoryUseCase)
-	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("something wrong"))
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("something wrong"))
 
 	paymentRequest := model.PaymentRequest{
 		MerchantId: helper.MerchantId.String(),
 		Amount:     10000,
 	}
 
-	paymentUseCase := impl.NewPaymentTransactionUseCaseImpl(mockPaymentRepository, mockCustomerUseCase, mockMerchantUseCase, mockHistoryUseCase)
+	mockExchangeRateUseCase := new(helper.MockExchangeRateUseCase)
+
+	paymentUseCase := impl.NewPaymentTransactionUseCaseImpl(mockPaymentRepository, mockCustomerUseCase, mockMerchantUseCase, mockHistoryUseCase, mockExchangeRateUseCase, acceptingEventBus(), noPaymentLimits)
 
-	err := paymentUseCase.AddPayment(helper.CustomerId.String(), paymentRequest)
+	err := paymentUseCase.AddPayment(context.Background(), helper.CustomerId.String(), paymentRequest)
 
 	assert.NotNil(t, err)
 	mockCustomerUseCase.AssertExpectations(t)
 	mockMerchantUseCase.AssertExpectations(t)
 }
+
+func TestAddPayment_ShouldReturnError_WhenCurrencyUnsupported(t *testing.T) {
+	mockPaymentRepository := new(helper.MockPaymentTransactionRepository)
+
+	mockCustomerUseCase := new(helper.MockCustomerUseCase)
+	mockCustomerUseCase.On("FindById", mock.Anything, helper.CustomerId.String()).Return(helper.ExpectedCustomers[0], nil)
+
+	mockMerchantUseCase := new(helper.MockMerchantUseCase)
+	mockMerchantUseCase.On("FindById", mock.Anything, helper.MerchantId.String()).Return(helper.ExpectedMerchants[0], nil)
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	paymentRequest := model.PaymentRequest{
+		MerchantId: helper.MerchantId.String(),
+		Amount:     10000,
+		Currency:   "XXX",
+	}
+
+	mockExchangeRateUseCase := new(helper.MockExchangeRateUseCase)
+
+	paymentUseCase := impl.NewPaymentTransactionUseCaseImpl(mockPaymentRepository, mockCustomerUseCase, mockMerchantUseCase, mockHistoryUseCase, mockExchangeRateUseCase, acceptingEventBus(), noPaymentLimits)
+
+	err := paymentUseCase.AddPayment(context.Background(), helper.CustomerId.String(), paymentRequest)
+
+	assert.NotNil(t, err)
+	mockPaymentRepository.AssertNotCalled(t, "StageAddPayment", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAddPayment_ShouldReturnError_WhenCurrencyConversionFails(t *testing.T) {
+	mockPaymentRepository := new(helper.MockPaymentTransactionRepository)
+
+	mockCustomerUseCase := new(helper.MockCustomerUseCase)
+	mockCustomerUseCase.On("FindById", mock.Anything, helper.CustomerId.String()).Return(helper.ExpectedCustomers[0], nil)
+
+	mockMerchantUseCase := new(helper.MockMerchantUseCase)
+	mockMerchantUseCase.On("FindById", mock.Anything, helper.MerchantId.String()).Return(helper.ExpectedMerchants[0], nil)
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	paymentRequest := model.PaymentRequest{
+		MerchantId: helper.MerchantId.String(),
+		Amount:     1250,
+		Currency:   "KWD",
+	}
+
+	mockExchangeRateUseCase := new(helper.MockExchangeRateUseCase)
+	mockExchangeRateUseCase.On("Convert", mock.Anything, entity.Money{Amount: 1250, Currency: "KWD"}, "IDR").
+		Return(entity.Money{}, entity.CurrencyConversion{}, errors.New("exchange rate KWD/IDR not found"))
+
+	paymentUseCase := impl.NewPaymentTransactionUseCaseImpl(mockPaymentRepository, mockCustomerUseCase, mockMerchantUseCase, mockHistoryUseCase, mockExchangeRateUseCase, acceptingEventBus(), noPaymentLimits)
+
+	err := paymentUseCase.AddPayment(context.Background(), helper.CustomerId.String(), paymentRequest)
+
+	assert.NotNil(t, err)
+	mockExchangeRateUseCase.AssertExpectations(t)
+	mockPaymentRepository.AssertNotCalled(t, "StageAddPayment", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAddPayment_ShouldStoreConversion_WhenMerchantDoesNotAcceptCurrency(t *testing.T) {
+	conversion := entity.CurrencyConversion{
+		OriginalAmount:    entity.Money{Amount: 1250, Currency: "KWD"},
+		MidRate:           51540.75,
+		AppliedRate:       51283.04625,
+		SpreadBasisPoints: 50,
+		RateTimestamp:     helper.CreatedAt,
+	}
+
+	mockPaymentRepository := new(helper.MockPaymentTransactionRepository)
+	mockPaymentRepository.On("StageAddPayment", mock.Anything, mock.Anything, mock.MatchedBy(func(payment entity.Payment) bool {
+		return payment.Money == entity.Money{Amount: 64104, Currency: "IDR"} &&
+			payment.Conversion != nil && *payment.Conversion == conversion
+	})).Return(nil)
+
+	mockCustomerUseCase := new(helper.MockCustomerUseCase)
+	mockCustomerUseCase.On("FindById", mock.Anything, helper.CustomerId.String()).Return(helper.ExpectedCustomers[0], nil)
+
+	mockMerchantUseCase := new(helper.MockMerchantUseCase)
+	mockMerchantUseCase.On("FindById", mock.Anything, helper.MerchantId.String()).Return(helper.ExpectedMerchants[0], nil)
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	mockExchangeRateUseCase := new(helper.MockExchangeRateUseCase)
+	mockExchangeRateUseCase.On("Convert", mock.Anything, entity.Money{Amount: 1250, Currency: "KWD"}, "IDR").
+		Return(entity.Money{Amount: 64104, Currency: "IDR"}, conversion, nil)
+
+	paymentRequest := model.PaymentRequest{
+		MerchantId: helper.MerchantId.String(),
+		Amount:     1250,
+		Currency:   "KWD",
+	}
+
+	paymentUseCase := impl.NewPaymentTransactionUseCaseImpl(mockPaymentRepository, mockCustomerUseCase, mockMerchantUseCase, mockHistoryUseCase, mockExchangeRateUseCase, acceptingEventBus(), noPaymentLimits)
+
+	err := paymentUseCase.AddPayment(context.Background(), helper.CustomerId.String(), paymentRequest)
+
+	assert.Nil(t, err)
+	mockPaymentRepository.AssertExpectations(t)
+	mockExchangeRateUseCase.AssertExpectations(t)
+}
+
+func TestAddPayment_ShouldStoreMinorUnits_WhenMerchantAcceptsCurrency(t *testing.T) {
+	merchant := helper.ExpectedMerchants[0]
+	merchant.AcceptedCurrencies = []string{"IDR", "KWD"}
+
+	mockPaymentRepository := new(helper.MockPaymentTransactionRepository)
+	mockPaymentRepository.On("StageAddPayment", mock.Anything, mock.Anything, mock.MatchedBy(func(payment entity.Payment) bool {
+		return payment.Amount == 1250 && payment.Currency == "KWD"
+	})).Return(nil)
+
+	mockCustomerUseCase := new(helper.MockCustomerUseCase)
+	mockCustomerUseCase.On("FindById", mock.Anything, helper.CustomerId.String()).Return(helper.ExpectedCustomers[0], nil)
+
+	mockMerchantUseCase := new(helper.MockMerchantUseCase)
+	mockMerchantUseCase.On("FindById", mock.Anything, helper.MerchantId.String()).Return(merchant, nil)
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	paymentRequest := model.PaymentRequest{
+		MerchantId: helper.MerchantId.String(),
+		Amount:     1250,
+		Currency:   "kwd",
+	}
+
+	mockExchangeRateUseCase := new(helper.MockExchangeRateUseCase)
+
+	paymentUseCase := impl.NewPaymentTransactionUseCaseImpl(mockPaymentRepository, mockCustomerUseCase, mockMerchantUseCase, mockHistoryUseCase, mockExchangeRateUseCase, acceptingEventBus(), noPaymentLimits)
+
+	err := paymentUseCase.AddPayment(context.Background(), helper.CustomerId.String(), paymentRequest)
+
+	assert.Nil(t, err)
+	mockPaymentRepository.AssertExpectations(t)
+}
+
+func TestAddPayment_ShouldStoreMerchantFeeAndNetAmount(t *testing.T) {
+	merchant := helper.ExpectedMerchants[0]
+	merchant.FeePlan = entity.FeePlan{Type: entity.FeeTypePercentage, PercentageBasisPoints: 150, FixedAmount: 500, MaxFee: 1000}
+
+	mockPaymentRepository := new(helper.MockPaymentTransactionRepository)
+	mockPaymentRepository.On("StageAddPayment", mock.Anything, mock.Anything, mock.MatchedBy(func(payment entity.Payment) bool {
+		return payment.Amount == 40000 && payment.FeeAmount == 1000 && payment.NetAmount == 39000
+	})).Return(nil)
+
+	mockCustomerUseCase := new(helper.MockCustomerUseCase)
+	mockCustomerUseCase.On("FindById", mock.Anything, helper.CustomerId.String()).Return(helper.ExpectedCustomers[0], nil)
+
+	mockMerchantUseCase := new(helper.MockMerchantUseCase)
+	mockMerchantUseCase.On("FindById", mock.Anything, helper.MerchantId.String()).Return(merchant, nil)
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	mockExchangeRateUseCase := new(helper.MockExchangeRateUseCase)
+
+	paymentRequest := model.PaymentRequest{
+		MerchantId: helper.MerchantId.String(),
+		Amount:     40000,
+	}
+
+	paymentUseCase := impl.NewPaymentTransactionUseCaseImpl(mockPaymentRepository, mockCustomerUseCase, mockMerchantUseCase, mockHistoryUseCase, mockExchangeRateUseCase, acceptingEventBus(), noPaymentLimits)
+
+	err := paymentUseCase.AddPayment(context.Background(), helper.CustomerId.String(), paymentRequest)
+
+	assert.Nil(t, err)
+	mockPaymentRepository.AssertExpectations(t)
+}
+
+func TestAddPayment_ShouldReturnError_WhenFeeExceedsAmount(t *testing.T) {
+	merchant := helper.ExpectedMerchants[0]
+	merchant.FeePlan = entity.FeePlan{Type: entity.FeeTypeFixed, FixedAmount: 5000}
+
+	mockPaymentRepository := new(helper.MockPaymentTransactionRepository)
+
+	mockCustomerUseCase := new(helper.MockCustomerUseCase)
+	mockCustomerUseCase.On("FindById", mock.Anything, helper.CustomerId.String()).Return(helper.ExpectedCustomers[0], nil)
+
+	mockMerchantUseCase := new(helper.MockMerchantUseCase)
+	mockMerchantUseCase.On("FindById", mock.Anything, helper.MerchantId.String()).Return(merchant, nil)
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	mockExchangeRateUseCase := new(helper.MockExchangeRateUseCase)
+
+	paymentRequest := model.PaymentRequest{
+		MerchantId: helper.MerchantId.String(),
+		Amount:     1000,
+	}
+
+	paymentUseCase := impl.NewPaymentTransactionUseCaseImpl(mockPaymentRepository, mockCustomerUseCase, mockMerchantUseCase, mockHistoryUseCase, mockExchangeRateUseCase, acceptingEventBus(), noPaymentLimits)
+
+	err := paymentUseCase.AddPayment(context.Background(), helper.CustomerId.String(), paymentRequest)
+
+	assert.NotNil(t, err)
+	mockPaymentRepository.AssertNotCalled(t, "StageAddPayment", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAddPayment_ShouldPriceFeeInSettlementCurrency_WhenPaidInOtherAcceptedCurrency(t *testing.T) {
+	merchant := helper.ExpectedMerchants[0]
+	merchant.AcceptedCurrencies = []string{"IDR", "USD"}
+	merchant.FeePlan = entity.FeePlan{Type: entity.FeeTypePercentage, PercentageBasisPoints: 100, MinFee: 1000}
+
+	mockPaymentRepository := new(helper.MockPaymentTransactionRepository)
+	mockPaymentRepository.On("StageAddPayment", mock.Anything, mock.Anything, mock.MatchedBy(func(payment entity.Payment) bool {
+		return payment.Money == entity.Money{Amount: 500, Currency: "USD"} && payment.Conversion == nil &&
+			payment.FeeAmount == 6 && payment.NetAmount == 494
+	})).Return(nil)
+
+	mockCustomerUseCase := new(helper.MockCustomerUseCase)
+	mockCustomerUseCase.On("FindById", mock.Anything, helper.CustomerId.String()).Return(helper.ExpectedCustomers[0], nil)
+
+	mockMerchantUseCase := new(helper.MockMerchantUseCase)
+	mockMerchantUseCase.On("FindById", mock.Anything, helper.MerchantId.String()).Return(merchant, nil)
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	mockExchangeRateUseCase := new(helper.MockExchangeRateUseCase)
+	mockExchangeRateUseCase.On("Convert", mock.Anything, entity.Money{Amount: 500, Currency: "USD"}, "IDR").
+		Return(entity.Money{Amount: 78000, Currency: "IDR"}, entity.CurrencyConversion{}, nil)
+
+	paymentRequest := model.PaymentRequest{
+		MerchantId: helper.MerchantId.String(),
+		Amount:     500,
+		Currency:   "USD",
+	}
+
+	paymentUseCase := impl.NewPaymentTransactionUseCaseImpl(mockPaymentRepository, mockCustomerUseCase, mockMerchantUseCase, mockHistoryUseCase, mockExchangeRateUseCase, acceptingEventBus(), noPaymentLimits)
+
+	err := paymentUseCase.AddPayment(context.Background(), helper.CustomerId.String(), paymentRequest)
+
+	assert.Nil(t, err)
+	mockPaymentRepository.AssertExpectations(t)
+	mockExchangeRateUseCase.AssertExpectations(t)
+}
+
+func TestAddPayment_ShouldReturnError_WhenMerchantSuspended(t *testing.T) {
+	merchant := helper.ExpectedMerchants[0]
+	merchant.Status = entity.MerchantStatusSuspended
+
+	mockPaymentRepository := new(helper.MockPaymentTransactionRepository)
+
+	mockCustomerUseCase := new(helper.MockCustomerUseCase)
+	mockCustomerUseCase.On("FindById", mock.Anything, helper.CustomerId.String()).Return(helper.ExpectedCustomers[0], nil)
+
+	mockMerchantUseCase := new(helper.MockMerchantUseCase)
+	mockMerchantUseCase.On("FindById", mock.Anything, helper.MerchantId.String()).Return(merchant, nil)
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, helper.CustomerId.String(), entity.AuditActionPaymentCreate,
+		mock.MatchedBy(func(details entity.AuditDetails) bool {
+			return details.ErrorCode == entity.AuditErrorMerchantSuspended
+		}), mock.Anything).Return(nil)
+
+	mockExchangeRateUseCase := new(helper.MockExchangeRateUseCase)
+
+	paymentRequest := model.PaymentRequest{
+		MerchantId: helper.MerchantId.String(),
+		Amount:     20000,
+	}
+
+	paymentUseCase := impl.NewPaymentTransactionUseCaseImpl(mockPaymentRepository, mockCustomerUseCase, mockMerchantUseCase, mockHistoryUseCase, mockExchangeRateUseCase, acceptingEventBus(), noPaymentLimits)
+
+	err := paymentUseCase.AddPayment(context.Background(), helper.CustomerId.String(), paymentRequest)
+
+	assert.ErrorContains(t, err, "suspended")
+	mockHistoryUseCase.AssertExpectations(t)
+	mockPaymentRepository.AssertNotCalled(t, "StageAddPayment", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetPayments_ShouldReturnPaymentsWithFees(t *testing.T) {
+	mockPaymentRepository := new(helper.MockPaymentTransactionRepository)
+	mockPaymentRepository.On("FindByCustomerId", mock.Anything, helper.CustomerId).Return(helper.ExpectedPayments, nil)
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	paymentUseCase := impl.NewPaymentTransactionUseCaseImpl(mockPaymentRepository, new(helper.MockCustomerUseCase),
+		new(helper.MockMerchantUseCase), mockHistoryUseCase, new(helper.MockExchangeRateUseCase), acceptingEventBus(), noPaymentLimits)
+
+	paymentsResult, err := paymentUseCase.GetPayments(context.Background(), helper.CustomerId.String())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(paymentsResult))
+	assert.Equal(t, helper.ExpectedPayments[0].Id.String(), paymentsResult[0].Id)
+	assert.Equal(t, int64(50000), paymentsResult[0].Amount)
+	assert.Equal(t, "IDR", paymentsResult[0].Currency)
+	assert.Equal(t, int64(350), paymentsResult[0].FeeAmount)
+	assert.Equal(t, int64(49650), paymentsResult[0].NetAmount)
+	mockPaymentRepository.AssertExpectations(t)
+}
+
+func TestGetPayments_ShouldReturnError_WhenInvalidCustomerId(t *testing.T) {
+	mockPaymentRepository := new(helper.MockPaymentTransactionRepository)
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	paymentUseCase := impl.NewPaymentTransactionUseCaseImpl(mockPaymentRepository, new(helper.MockCustomerUseCase),
+		new(helper.MockMerchantUseCase), mockHistoryUseCase, new(helper.MockExchangeRateUseCase), acceptingEventBus(), noPaymentLimits)
+
+	paymentsResult, err := paymentUseCase.GetPayments(context.Background(), "invalid")
+
+	assert.Nil(t, paymentsResult)
+	assert.NotNil(t, err)
+}
+
+func TestGetPaymentById_ShouldReturnPayment(t *testing.T) {
+	payment := helper.ExpectedPayments[0]
+
+	mockPaymentRepository := new(helper.MockPaymentTransactionRepository)
+	mockPaymentRepository.On("FindById", mock.Anything, payment.Id).Return(payment, nil)
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	paymentUseCase := impl.NewPaymentTransactionUseCaseImpl(mockPaymentRepository, new(helper.MockCustomerUseCase),
+		new(helper.MockMerchantUseCase), mockHistoryUseCase, new(helper.MockExchangeRateUseCase), acceptingEventBus(), noPaymentLimits)
+
+	paymentResult, err := paymentUseCase.GetPaymentById(context.Background(), helper.CustomerId.String(), payment.Id.String())
+
+	assert.Nil(t, err)
+	assert.Equal(t, payment.Id.String(), paymentResult.Id)
+	assert.Equal(t, int64(49650), paymentResult.NetAmount)
+}
+
+func TestGetPaymentById_ShouldReturnError_WhenPaymentBelongsToAnotherCustomer(t *testing.T) {
+	payment := helper.ExpectedPayments[0]
+
+	mockPaymentRepository := new(helper.MockPaymentTransactionRepository)
+	mockPaymentRepository.On("FindById", mock.Anything, payment.Id).Return(payment, nil)
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	paymentUseCase := impl.NewPaymentTransactionUseCaseImpl(mockPaymentRepository, new(helper.MockCustomerUseCase),
+		new(helper.MockMerchantUseCase), mockHistoryUseCase, new(helper.MockExchangeRateUseCase), acceptingEventBus(), noPaymentLimits)
+
+	_, err := paymentUseCase.GetPaymentById(context.Background(), uuid.New().String(), payment.Id.String())
+
+	assert.NotNil(t, err)
+}
+
+func TestAddPayment_ShouldPublishPaymentCreatedInSameTransaction(t *testing.T) {
+	var stagedTx *utils.JsonFileTransaction
+	var stagedPayment entity.Payment
+	mockPaymentRepository := new(helper.MockPaymentTransactionRepository)
+	mockPaymentRepository.On("StageAddPayment", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stagedTx = args.Get(1).(*utils.JsonFileTransaction)
+		stagedPayment = args.Get(2).(entity.Payment)
+	}).Return(nil)
+
+	mockCustomerUseCase := new(helper.MockCustomerUseCase)
+	mockCustomerUseCase.On("FindById", mock.Anything, helper.CustomerId.String()).Return(helper.ExpectedCustomers[0], nil)
+
+	mockMerchantUseCase := new(helper.MockMerchantUseCase)
+	mockMerchantUseCase.On("FindById", mock.Anything, helper.MerchantId.String()).Return(helper.ExpectedMerchants[0], nil)
+
+	mockEventBus := new(helper.MockEventBus)
+	mockEventBus.On("Publish", mock.Anything, mock.MatchedBy(func(tx *utils.JsonFileTransaction) bool {
+		return tx == stagedTx
+	}), mock.MatchedBy(func(events []entity.DomainEvent) bool {
+		var payment entity.Payment
+		return len(events) == 1 && events[0].Type == entity.EventPaymentCreated &&
+			events[0].AggregateId == stagedPayment.Id.String() &&
+			json.Unmarshal(events[0].Payload, &payment) == nil && payment.Amount == 10000
+	})).Return(nil)
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, helper.CustomerId.String(), entity.AuditActionPaymentCreate,
+		mock.MatchedBy(func(details entity.AuditDetails) bool {
+			return details.SubjectType == entity.AuditSubjectPayment && details.SubjectId == stagedPayment.Id.String()
+		}), nil).Return(nil)
+
+	paymentUseCase := impl.NewPaymentTransactionUseCaseImpl(mockPaymentRepository, mockCustomerUseCase, mockMerchantUseCase,
+		mockHistoryUseCase, new(helper.MockExchangeRateUseCase), mockEventBus, noPaymentLimits)
+
+	err := paymentUseCase.AddPayment(context.Background(), helper.CustomerId.String(), model.PaymentRequest{MerchantId: helper.MerchantId.String(), Amount: 10000})
+
+	assert.Nil(t, err)
+	mockEventBus.AssertExpectations(t)
+	mockHistoryUseCase.AssertExpectations(t)
+}
+
+func TestAddPayment_ShouldReturnError_WhenPublishFails(t *testing.T) {
+	mockPaymentRepository := new(helper.MockPaymentTransactionRepository)
+	mockPaymentRepository.On("StageAddPayment", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	mockCustomerUseCase := new(helper.MockCustomerUseCase)
+	mockCustomerUseCase.On("FindById", mock.Anything, helper.CustomerId.String()).Return(helper.ExpectedCustomers[0], nil)
+
+	mockMerchantUseCase := new(helper.MockMerchantUseCase)
+	mockMerchantUseCase.On("FindById", mock.Anything, helper.MerchantId.String()).Return(helper.ExpectedMerchants[0], nil)
+
+	mockEventBus := new(helper.MockEventBus)
+	mockEventBus.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("disk full"))
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, helper.CustomerId.String(), entity.AuditActionPaymentCreate, mock.Anything, mock.Anything).Return(nil)
+
+	paymentUseCase := impl.NewPaymentTransactionUseCaseImpl(mockPaymentRepository, mockCustomerUseCase, mockMerchantUseCase,
+		mockHistoryUseCase, new(helper.MockExchangeRateUseCase), mockEventBus, noPaymentLimits)
+
+	err := paymentUseCase.AddPayment(context.Background(), helper.CustomerId.String(), model.PaymentRequest{MerchantId: helper.MerchantId.String(), Amount: 10000})
+
+	assert.EqualError(t, err, "disk full")
+	mockHistoryUseCase.AssertExpectations(t)
+}
+
+func TestAddPayment_ShouldReturnError_WhenAmountExceedsLimit(t *testing.T) {
+	mockPaymentRepository := new(helper.MockPaymentTransactionRepository)
+
+	mockCustomerUseCase := new(helper.MockCustomerUseCase)
+	mockCustomerUseCase.On("FindById", mock.Anything, helper.CustomerId.String()).Return(helper.ExpectedCustomers[0], nil)
+
+	mockMerchantUseCase := new(helper.MockMerchantUseCase)
+	mockMerchantUseCase.On("FindById", mock.Anything, helper.MerchantId.String()).Return(helper.ExpectedMerchants[0], nil)
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, helper.CustomerId.String(), entity.AuditActionPaymentCreate,
+		mock.MatchedBy(func(details entity.AuditDetails) bool {
+			return details.ErrorCode == entity.AuditErrorLimitExceeded
+		}), mock.Anything).Return(nil)
+
+	limits := entity.PaymentLimits{MaxAmounts: map[string]int64{"IDR": 5000}}
+	paymentUseCase := impl.NewPaymentTransactionUseCaseImpl(mockPaymentRepository, mockCustomerUseCase, mockMerchantUseCase,
+		mockHistoryUseCase, new(helper.MockExchangeRateUseCase), acceptingEventBus(), func() entity.PaymentLimits { return limits })
+
+	err := paymentUseCase.AddPayment(context.Background(), helper.CustomerId.String(), model.PaymentRequest{MerchantId: helper.MerchantId.String(), Amount: 10000})
+
+	assert.ErrorContains(t, err, "amount 10000 IDR exceeds the limit of 5000 IDR per payment")
+	mockHistoryUseCase.AssertExpectations(t)
+	mockPaymentRepository.AssertNotCalled(t, "StageAddPayment", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func noPaymentLimits() entity.PaymentLimits {
+	return entity.PaymentLimits{}
+}
+
+func TestAddPayment_ShouldReturnError_WhenCustomerDisabled(t *testing.T) {
+	customer := helper.ExpectedCustomers[0]
+	customer.Status = entity.CustomerStatusDisabled
+
+	mockCustomerUseCase := new(helper.MockCustomerUseCase)
+	mockCustomerUseCase.On("FindById", mock.Anything, helper.CustomerId.String()).Return(customer, nil)
+
+	mockMerchantUseCase := new(helper.MockMerchantUseCase)
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, helper.CustomerId.String(), entity.AuditActionPaymentCreate,
+		mock.MatchedBy(func(details entity.AuditDetails) bool {
+			return details.ErrorCode == entity.AuditErrorAccountDisabled
+		}), mock.Anything).Return(nil)
+
+	paymentRequest := model.PaymentRequest{
+		MerchantId: helper.MerchantId.String(),
+		Amount:     20000,
+	}
+
+	paymentUseCase := impl.NewPaymentTransactionUseCaseImpl(new(helper.MockPaymentTransactionRepository), mockCustomerUseCase, mockMerchantUseCase,
+		mockHistoryUseCase, new(helper.MockExchangeRateUseCase), acceptingEventBus(), noPaymentLimits)
+
+	err := paymentUseCase.AddPayment(context.Background(), helper.CustomerId.String(), paymentRequest)
+
+	assert.ErrorContains(t, err, "disabled")
+	mockMerchantUseCase.AssertNotCalled(t, "FindById", mock.Anything, mock.Anything)
+	mockHistoryUseCase.AssertExpectations(t)
+}
@@ -0,0 +1,138 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/internal/usecase/impl"
+	"merchant_bank_payment_go_api/test/helper"
+	"testing"
+)
+
+func reconciliationRequest(format string) model.ReconciliationReportRequest {
+	return model.ReconciliationReportRequest{
+		MerchantId: helper.MerchantId.String(),
+		From:       "2024-11-25",
+		To:         "2024-11-25",
+		Format:     format,
+	}
+}
+
+func TestExportReconciliation_ShouldWriteCsvRowsAndTotals(t *testing.T) {
+	mockPaymentRepository := new(helper.MockPaymentTransactionRepository)
+	mockPaymentRepository.On("StreamPayments", mock.Anything).Return(settlementPayments(), nil)
+
+	mockMerchantUseCase := new(helper.MockMerchantUseCase)
+	mockMerchantUseCase.On("FindById", mock.Anything, helper.MerchantId.String()).Return(helper.ExpectedMerchants[0], nil)
+
+	useCase := impl.NewReportUseCaseImpl(mockPaymentRepository, mockMerchantUseCase)
+
+	var output bytes.Buffer
+	err := useCase.ExportReconciliation(context.Background(), reconciliationRequest(""), &output)
+	assert.Nil(t, err)
+
+	records, err := csv.NewReader(&output).ReadAll()
+	assert.Nil(t, err)
+	assert.Len(t, records, 7)
+	assert.Equal(t, "type", records[0][0])
+	assert.Equal(t, []string{"PAYMENT", "PAYMENT", "PAYMENT", "REFUND", "PAYMENT", "TOTAL"},
+		[]string{records[1][0], records[2][0], records[3][0], records[4][0], records[5][0], records[6][0]})
+	assert.Equal(t, "-3000", records[4][5])
+	assert.Equal(t, []string{"TOTAL", "", "", "", "IDR", "34000", "300", "33700", "", ""}, records[6])
+}
+
+func TestExportReconciliation_ShouldWriteJsonDocument(t *testing.T) {
+	mockPaymentRepository := new(helper.MockPaymentTransactionRepository)
+	mockPaymentRepository.On("StreamPayments", mock.Anything).Return(settlementPayments(), nil)
+
+	mockMerchantUseCase := new(helper.MockMerchantUseCase)
+	mockMerchantUseCase.On("FindById", mock.Anything, helper.MerchantId.String()).Return(helper.ExpectedMerchants[0], nil)
+
+	useCase := impl.NewReportUseCaseImpl(mockPaymentRepository, mockMerchantUseCase)
+
+	var output bytes.Buffer
+	err := useCase.ExportReconciliation(context.Background(), reconciliationRequest("json"), &output)
+	assert.Nil(t, err)
+
+	var report struct {
+		MerchantId string                      `json:"merchantId"`
+		Rows       []model.ReconciliationRow   `json:"rows"`
+		Totals     []model.ReconciliationTotal `json:"totals"`
+	}
+	err = json.Unmarshal(output.Bytes(), &report)
+	assert.Nil(t, err)
+	assert.Equal(t, helper.MerchantId.String(), report.MerchantId)
+	assert.Len(t, report.Rows, 5)
+	assert.Equal(t, []model.ReconciliationTotal{{Currency: "IDR", PaymentCount: 4, RefundCount: 1,
+		GrossAmount: 34000, FeeAmount: 300, NetAmount: 33700}}, report.Totals)
+}
+
+func TestExportReconciliation_ShouldWriteEmptyJsonDocument_WhenNoPaymentsInRange(t *testing.T) {
+	mockPaymentRepository := new(helper.MockPaymentTransactionRepository)
+	mockPaymentRepository.On("StreamPayments", mock.Anything).Return(settlementPayments(), nil)
+
+	mockMerchantUseCase := new(helper.MockMerchantUseCase)
+	mockMerchantUseCase.On("FindById", mock.Anything, helper.MerchantId.String()).Return(helper.ExpectedMerchants[0], nil)
+
+	useCase := impl.NewReportUseCaseImpl(mockPaymentRepository, mockMerchantUseCase)
+
+	request := reconciliationRequest("json")
+	request.From, request.To = "2023-01-01", "2023-01-31"
+
+	var output bytes.Buffer
+	err := useCase.ExportReconciliation(context.Background(), request, &output)
+	assert.Nil(t, err)
+	assert.True(t, json.Valid(output.Bytes()))
+	assert.Contains(t, output.String(), `"rows":[],"totals":[]`)
+}
+
+func TestExportReconciliation_ShouldReturnError_WhenInvalidRange(t *testing.T) {
+	mockPaymentRepository := new(helper.MockPaymentTransactionRepository)
+	mockMerchantUseCase := new(helper.MockMerchantUseCase)
+	useCase := impl.NewReportUseCaseImpl(mockPaymentRepository, mockMerchantUseCase)
+
+	request := reconciliationRequest("")
+	request.From = "2024-11-30"
+
+	var output bytes.Buffer
+	err := useCase.ExportReconciliation(context.Background(), request, &output)
+
+	assert.NotNil(t, err)
+	assert.Zero(t, output.Len())
+	mockMerchantUseCase.AssertNotCalled(t, "FindById", mock.Anything, helper.MerchantId.String())
+}
+
+func TestExportReconciliation_ShouldReturnError_WhenUnsupportedFormat(t *testing.T) {
+	mockPaymentRepository := new(helper.MockPaymentTransactionRepository)
+	mockMerchantUseCase := new(helper.MockMerchantUseCase)
+	mockMerchantUseCase.On("FindById", mock.Anything, helper.MerchantId.String()).Return(helper.ExpectedMerchants[0], nil)
+
+	useCase := impl.NewReportUseCaseImpl(mockPaymentRepository, mockMerchantUseCase)
+
+	var output bytes.Buffer
+	err := useCase.ExportReconciliation(context.Background(), reconciliationRequest("xml"), &output)
+
+	assert.NotNil(t, err)
+	assert.Zero(t, output.Len())
+}
+
+func TestExportReconciliation_ShouldReturnError_WhenStreamFails(t *testing.T) {
+	mockPaymentRepository := new(helper.MockPaymentTransactionRepository)
+	mockPaymentRepository.On("StreamPayments", mock.Anything).Return(settlementPayments()[:1], errors.New("decode error"))
+
+	mockMerchantUseCase := new(helper.MockMerchantUseCase)
+	mockMerchantUseCase.On("FindById", mock.Anything, helper.MerchantId.String()).Return(helper.ExpectedMerchants[0], nil)
+
+	useCase := impl.NewReportUseCaseImpl(mockPaymentRepository, mockMerchantUseCase)
+
+	var output bytes.Buffer
+	err := useCase.ExportReconciliation(context.Background(), reconciliationRequest(""), &output)
+
+	assert.NotNil(t, err)
+	assert.Zero(t, output.Len())
+}
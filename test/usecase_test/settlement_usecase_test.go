@@ -0,0 +1,188 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/usecase/impl"
+	"merchant_bank_payment_go_api/test/helper"
+	"testing"
+	"time"
+)
+
+func settlementPayments() []entity.Payment {
+	day := time.Date(2024, 11, 25, 0, 0, 0, 0, time.Local)
+	otherMerchantId := uuid.New()
+	settlementId := uuid.New()
+
+	return []entity.Payment{
+		{Id: uuid.New(), MerchantId: helper.MerchantId, Money: entity.Money{Amount: 10000, Currency: "IDR"},
+			FeeAmount: 100, NetAmount: 9900, Timestamp: day.Add(10 * time.Hour)},
+		{Id: uuid.New(), MerchantId: helper.MerchantId, Money: entity.Money{Amount: 20000, Currency: "IDR"},
+			FeeAmount: 200, NetAmount: 19800, Status: entity.PaymentStatusCaptured, Timestamp: day.Add(15 * time.Hour)},
+		{Id: uuid.New(), MerchantId: otherMerchantId, Money: entity.Money{Amount: 5000, Currency: "IDR"},
+			Timestamp: day.Add(11 * time.Hour)},
+		{Id: uuid.New(), MerchantId: helper.MerchantId, Money: entity.Money{Amount: 7000, Currency: "IDR"},
+			Timestamp: day.Add(34 * time.Hour)},
+		{Id: uuid.New(), MerchantId: helper.MerchantId, Money: entity.Money{Amount: 3000, Currency: "IDR"},
+			Status: entity.PaymentStatusRefunded, Timestamp: day.Add(12 * time.Hour)},
+		{Id: uuid.New(), MerchantId: helper.MerchantId, Money: entity.Money{Amount: 4000, Currency: "IDR"},
+			SettlementId: &settlementId, Timestamp: day.Add(13 * time.Hour)},
+	}
+}
+
+func TestRunSettlement_ShouldGroupSettleablePaymentsPerMerchantAndWindow(t *testing.T) {
+	payments := settlementPayments()
+	cutoff := time.Date(2024, 11, 26, 0, 0, 0, 0, time.Local)
+
+	mockSettlementRepository := new(helper.MockSettlementRepository)
+	mockSettlementRepository.On("LoadSettlements", mock.Anything).Return(helper.ExpectedSettlements, nil)
+	mockSettlementRepository.On("StageSaveSettlements", mock.Anything, mock.Anything, mock.MatchedBy(func(settlements []entity.Settlement) bool {
+		return len(settlements) == 3
+	})).Return()
+
+	mockPaymentRepository := new(helper.MockPaymentTransactionRepository)
+	mockPaymentRepository.On("LoadPayments", mock.Anything).Return(payments, nil)
+	mockPaymentRepository.On("StageSavePayments", mock.Anything, mock.Anything, mock.MatchedBy(func(saved []entity.Payment) bool {
+		return saved[0].SettlementId != nil && saved[1].SettlementId != nil && saved[2].SettlementId != nil &&
+			*saved[0].SettlementId == *saved[1].SettlementId && *saved[0].SettlementId != *saved[2].SettlementId &&
+			saved[3].SettlementId == nil && saved[4].SettlementId == nil
+	})).Return()
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, entity.AuditActionSettlementRun, mock.Anything, nil).Return(nil)
+
+	mockEventBus := new(helper.MockEventBus)
+	mockEventBus.On("Publish", mock.Anything, mock.Anything, mock.MatchedBy(func(events []entity.DomainEvent) bool {
+		return len(events) == 2 && events[0].Type == entity.EventSettlementCreated && events[1].Type == entity.EventSettlementCreated
+	})).Return(nil)
+
+	useCase := impl.NewSettlementUseCaseImpl(mockSettlementRepository, mockPaymentRepository, mockHistoryUseCase, mockEventBus, 0)
+
+	settlementsResult, err := useCase.RunSettlement(context.Background(), helper.CustomerId.String(), cutoff)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(settlementsResult))
+
+	var merchantSettlementFound bool
+	for _, settlement := range settlementsResult {
+		if settlement.MerchantId == helper.MerchantId.String() {
+			merchantSettlementFound = true
+			assert.Equal(t, 2, settlement.PaymentCount)
+			assert.Equal(t, int64(30000), settlement.GrossAmount)
+			assert.Equal(t, int64(300), settlement.FeeAmount)
+			assert.Equal(t, int64(29700), settlement.NetAmount)
+			assert.Equal(t, cutoff, settlement.WindowEnd)
+			assert.Equal(t, string(entity.SettlementStatusPendingPayout), settlement.Status)
+		}
+	}
+	assert.True(t, merchantSettlementFound)
+	mockSettlementRepository.AssertExpectations(t)
+	mockPaymentRepository.AssertExpectations(t)
+}
+
+func TestRunSettlement_ShouldNotSaveAnything_WhenNothingToSettle(t *testing.T) {
+	mockSettlementRepository := new(helper.MockSettlementRepository)
+	mockSettlementRepository.On("LoadSettlements", mock.Anything).Return(helper.ExpectedSettlements, nil)
+
+	mockPaymentRepository := new(helper.MockPaymentTransactionRepository)
+	mockPaymentRepository.On("LoadPayments", mock.Anything).Return(settlementPayments(), nil)
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	useCase := impl.NewSettlementUseCaseImpl(mockSettlementRepository, mockPaymentRepository, mockHistoryUseCase, acceptingEventBus(), 0)
+
+	settlementsResult, err := useCase.RunSettlement(context.Background(), helper.CustomerId.String(), time.Date(2024, 11, 25, 0, 0, 0, 0, time.Local))
+
+	assert.Nil(t, err)
+	assert.Empty(t, settlementsResult)
+	mockSettlementRepository.AssertNotCalled(t, "StageSaveSettlements", mock.Anything, mock.Anything, mock.Anything)
+	mockPaymentRepository.AssertNotCalled(t, "StageSavePayments", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRunSettlement_ShouldReturnError_WhenPublishFails(t *testing.T) {
+	mockSettlementRepository := new(helper.MockSettlementRepository)
+	mockSettlementRepository.On("LoadSettlements", mock.Anything).Return(helper.ExpectedSettlements, nil)
+	mockSettlementRepository.On("StageSaveSettlements", mock.Anything, mock.Anything, mock.Anything).Return()
+
+	mockPaymentRepository := new(helper.MockPaymentTransactionRepository)
+	mockPaymentRepository.On("LoadPayments", mock.Anything).Return(settlementPayments(), nil)
+	mockPaymentRepository.On("StageSavePayments", mock.Anything, mock.Anything, mock.Anything).Return()
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	mockEventBus := new(helper.MockEventBus)
+	mockEventBus.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("disk full"))
+
+	useCase := impl.NewSettlementUseCaseImpl(mockSettlementRepository, mockPaymentRepository, mockHistoryUseCase, mockEventBus, 0)
+
+	settlementsResult, err := useCase.RunSettlement(context.Background(), helper.CustomerId.String(), time.Date(2024, 11, 26, 0, 0, 0, 0, time.Local))
+
+	assert.Nil(t, settlementsResult)
+	assert.NotNil(t, err)
+	mockSettlementRepository.AssertNotCalled(t, "SaveSettlements", mock.Anything, mock.Anything)
+	mockPaymentRepository.AssertNotCalled(t, "SavePayments", mock.Anything, mock.Anything)
+}
+
+func TestRunSettlement_ShouldReturnError_WhenLoadPaymentsFails(t *testing.T) {
+	mockSettlementRepository := new(helper.MockSettlementRepository)
+	mockSettlementRepository.On("LoadSettlements", mock.Anything).Return(helper.ExpectedSettlements, nil)
+
+	mockPaymentRepository := new(helper.MockPaymentTransactionRepository)
+	mockPaymentRepository.On("LoadPayments", mock.Anything).Return([]entity.Payment{}, errors.New("read error"))
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	useCase := impl.NewSettlementUseCaseImpl(mockSettlementRepository, mockPaymentRepository, mockHistoryUseCase, acceptingEventBus(), 0)
+
+	_, err := useCase.RunSettlement(context.Background(), helper.CustomerId.String(), time.Now())
+
+	assert.NotNil(t, err)
+}
+
+func TestGetSettlements_ShouldFilterByMerchant(t *testing.T) {
+	otherSettlement := helper.ExpectedSettlements[0]
+	otherSettlement.Id = uuid.New()
+	otherSettlement.MerchantId = uuid.New()
+
+	mockSettlementRepository := new(helper.MockSettlementRepository)
+	mockSettlementRepository.On("LoadSettlements", mock.Anything).Return(append([]entity.Settlement{otherSettlement}, helper.ExpectedSettlements...), nil)
+
+	useCase := impl.NewSettlementUseCaseImpl(mockSettlementRepository, new(helper.MockPaymentTransactionRepository), new(helper.MockHistoryUseCase), acceptingEventBus(), 0)
+
+	allResult, err := useCase.GetSettlements(context.Background(), "")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(allResult))
+
+	merchantResult, err := useCase.GetSettlements(context.Background(), helper.MerchantId.String())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(merchantResult))
+	assert.Equal(t, helper.ExpectedSettlements[0].Id.String(), merchantResult[0].Id)
+}
+
+func TestGetSettlements_ShouldReturnError_WhenInvalidMerchantId(t *testing.T) {
+	useCase := impl.NewSettlementUseCaseImpl(new(helper.MockSettlementRepository), new(helper.MockPaymentTransactionRepository), new(helper.MockHistoryUseCase), acceptingEventBus(), 0)
+
+	_, err := useCase.GetSettlements(context.Background(), "invalid")
+
+	assert.NotNil(t, err)
+}
+
+func TestGetSettlementById_ShouldReturnSettlement(t *testing.T) {
+	mockSettlementRepository := new(helper.MockSettlementRepository)
+	mockSettlementRepository.On("FindById", mock.Anything, helper.ExpectedSettlements[0].Id).Return(helper.ExpectedSettlements[0], nil)
+
+	useCase := impl.NewSettlementUseCaseImpl(mockSettlementRepository, new(helper.MockPaymentTransactionRepository), new(helper.MockHistoryUseCase), acceptingEventBus(), 0)
+
+	settlementResult, err := useCase.GetSettlementById(context.Background(), helper.ExpectedSettlements[0].Id.String())
+
+	assert.Nil(t, err)
+	assert.Equal(t, helper.ExpectedSettlements[0].NetAmount, settlementResult.NetAmount)
+	assert.Equal(t, 1, len(settlementResult.PaymentIds))
+}
@@ -0,0 +1,423 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"merchant_bank_payment_go_api/internal/entity"
+	"merchant_bank_payment_go_api/internal/model"
+	"merchant_bank_payment_go_api/internal/usecase"
+	"merchant_bank_payment_go_api/internal/usecase/impl"
+	"merchant_bank_payment_go_api/internal/utils"
+	"merchant_bank_payment_go_api/test/helper"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const webhookTestSecret = "merchant-secret"
+
+func webhookMerchant(url string) entity.Merchant {
+	merchant := helper.ExpectedMerchants[0]
+	merchant.WebhookUrl = url
+	merchant.WebhookSecret = webhookTestSecret
+	return merchant
+}
+
+func pendingWebhookDelivery(url string, attempts int) entity.WebhookDelivery {
+	delivery := helper.ExpectedWebhookDeliveries[0]
+	delivery.Url = url
+	delivery.Attempts = attempts
+	return delivery
+}
+
+func newWebhookUseCase(deliveryRepository *helper.MockWebhookDeliveryRepository, merchantRepository *helper.MockMerchantRepository,
+	historyUseCase *helper.MockHistoryUseCase) *impl.WebhookUseCaseImpl {
+	return impl.NewWebhookUseCaseImpl(logrus.New(), deliveryRepository, merchantRepository, historyUseCase,
+		&http.Client{Timeout: time.Second}, 3, time.Minute)
+}
+
+func TestEnqueueWebhook_ShouldQueueSignedEventForMerchantWithUrl(t *testing.T) {
+	eventId := uuid.New()
+	mockMerchantRepository := new(helper.MockMerchantRepository)
+	mockMerchantRepository.On("FindById", mock.Anything, helper.MerchantId).Return(webhookMerchant("http://merchant.test/hook"), nil)
+
+	mockDeliveryRepository := new(helper.MockWebhookDeliveryRepository)
+	mockDeliveryRepository.On("AddDelivery", mock.Anything, mock.MatchedBy(func(delivery entity.WebhookDelivery) bool {
+		var event model.WebhookEvent
+		return json.Unmarshal(delivery.Payload, &event) == nil && delivery.Id == eventId && event.Id == eventId.String() &&
+			event.Type == entity.WebhookEventPaymentCaptured && delivery.Url == "http://merchant.test/hook" &&
+			delivery.Status == entity.WebhookDeliveryStatusPending
+	})).Return(nil)
+
+	useCase := newWebhookUseCase(mockDeliveryRepository, mockMerchantRepository, new(helper.MockHistoryUseCase))
+
+	err := useCase.Enqueue(context.Background(), eventId, helper.MerchantId, entity.WebhookEventPaymentCaptured, model.PaymentResponse{Amount: 10000})
+
+	assert.Nil(t, err)
+	mockDeliveryRepository.AssertExpectations(t)
+}
+
+func TestEnqueueWebhook_ShouldSkipMerchantWithoutUrl(t *testing.T) {
+	mockMerchantRepository := new(helper.MockMerchantRepository)
+	mockMerchantRepository.On("FindById", mock.Anything, helper.MerchantId).Return(helper.ExpectedMerchants[0], nil)
+
+	mockDeliveryRepository := new(helper.MockWebhookDeliveryRepository)
+
+	useCase := newWebhookUseCase(mockDeliveryRepository, mockMerchantRepository, new(helper.MockHistoryUseCase))
+
+	err := useCase.Enqueue(context.Background(), uuid.New(), helper.MerchantId, entity.WebhookEventPaymentCaptured, nil)
+
+	assert.Nil(t, err)
+	mockDeliveryRepository.AssertNotCalled(t, "AddDelivery", mock.Anything, mock.Anything)
+}
+
+func TestHandlePaymentCreated_ShouldQueuePaymentCapturedWebhook(t *testing.T) {
+	event, err := entity.NewDomainEvent(entity.EventPaymentCreated, helper.ExpectedPayments[0].Id.String(), helper.ExpectedPayments[0])
+	assert.Nil(t, err)
+
+	mockMerchantRepository := new(helper.MockMerchantRepository)
+	mockMerchantRepository.On("FindById", mock.Anything, helper.MerchantId).Return(webhookMerchant("http://merchant.test/hook"), nil)
+
+	mockDeliveryRepository := new(helper.MockWebhookDeliveryRepository)
+	mockDeliveryRepository.On("AddDelivery", mock.Anything, mock.MatchedBy(func(delivery entity.WebhookDelivery) bool {
+		var webhookEvent struct {
+			Data model.PaymentResponse `json:"data"`
+		}
+		return delivery.Id == event.Id && delivery.EventType == entity.WebhookEventPaymentCaptured &&
+			json.Unmarshal(delivery.Payload, &webhookEvent) == nil &&
+			webhookEvent.Data.Id == helper.ExpectedPayments[0].Id.String() && webhookEvent.Data.NetAmount == 49650
+	})).Return(nil)
+
+	useCase := newWebhookUseCase(mockDeliveryRepository, mockMerchantRepository, new(helper.MockHistoryUseCase))
+
+	err = useCase.HandlePaymentCreated(context.Background(), event)
+
+	assert.Nil(t, err)
+	mockDeliveryRepository.AssertExpectations(t)
+}
+
+func TestHandleSettlementCreated_ShouldReturnError_WhenPayloadInvalid(t *testing.T) {
+	event := entity.DomainEvent{Id: uuid.New(), Type: entity.EventSettlementCreated, Payload: []byte(`"oops"`)}
+
+	useCase := newWebhookUseCase(new(helper.MockWebhookDeliveryRepository), new(helper.MockMerchantRepository), new(helper.MockHistoryUseCase))
+
+	err := useCase.HandleSettlementCreated(context.Background(), event)
+
+	assert.NotNil(t, err)
+}
+
+func TestDeliverDueWebhooks_ShouldSendSignedPayloadAndMarkDelivered(t *testing.T) {
+	var receivedSignatureErr error
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		receivedSignatureErr = utils.VerifyWebhookSignature(webhookTestSecret, r.Header.Get(utils.WebhookSignatureHeader),
+			body, time.Minute, time.Now())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	delivery := pendingWebhookDelivery(server.URL, 0)
+
+	mockMerchantRepository := new(helper.MockMerchantRepository)
+	mockMerchantRepository.On("FindById", mock.Anything, helper.MerchantId).Return(webhookMerchant(server.URL), nil)
+
+	mockDeliveryRepository := new(helper.MockWebhookDeliveryRepository)
+	mockDeliveryRepository.On("LoadDeliveries", mock.Anything).Return([]entity.WebhookDelivery{delivery}, nil)
+	mockDeliveryRepository.On("FindById", mock.Anything, delivery.Id).Return(delivery, nil)
+	mockDeliveryRepository.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(updated entity.WebhookDelivery) bool {
+		return updated.Status == entity.WebhookDeliveryStatusDelivered && updated.Attempts == 1 &&
+			updated.LastResponseCode == http.StatusNoContent && updated.DeliveredAt != nil
+	})).Return(nil)
+
+	useCase := newWebhookUseCase(mockDeliveryRepository, mockMerchantRepository, new(helper.MockHistoryUseCase))
+
+	attempted, err := useCase.DeliverDue(context.Background(), time.Now())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, attempted)
+	assert.Nil(t, receivedSignatureErr)
+	mockDeliveryRepository.AssertExpectations(t)
+}
+
+func TestDeliverDueWebhooks_ShouldScheduleRetryWithBackoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	delivery := pendingWebhookDelivery(server.URL, 1)
+
+	mockMerchantRepository := new(helper.MockMerchantRepository)
+	mockMerchantRepository.On("FindById", mock.Anything, helper.MerchantId).Return(webhookMerchant(server.URL), nil)
+
+	mockDeliveryRepository := new(helper.MockWebhookDeliveryRepository)
+	mockDeliveryRepository.On("LoadDeliveries", mock.Anything).Return([]entity.WebhookDelivery{delivery}, nil)
+	mockDeliveryRepository.On("FindById", mock.Anything, delivery.Id).Return(delivery, nil)
+	mockDeliveryRepository.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(updated entity.WebhookDelivery) bool {
+		return updated.Status == entity.WebhookDeliveryStatusPending && updated.Attempts == 2 &&
+			updated.NextAttemptAt.Equal(now.Add(2*time.Minute)) && updated.LastResponseCode == http.StatusInternalServerError
+	})).Return(nil)
+
+	useCase := newWebhookUseCase(mockDeliveryRepository, mockMerchantRepository, new(helper.MockHistoryUseCase))
+
+	_, err := useCase.DeliverDue(context.Background(), now)
+
+	assert.Nil(t, err)
+	mockDeliveryRepository.AssertExpectations(t)
+}
+
+func TestDeliverDueWebhooks_ShouldNotSend_WhenMerchantHasNoSecret(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	merchant := webhookMerchant(server.URL)
+	merchant.WebhookSecret = ""
+
+	mockMerchantRepository := new(helper.MockMerchantRepository)
+	mockMerchantRepository.On("FindById", mock.Anything, helper.MerchantId).Return(merchant, nil)
+
+	mockDeliveryRepository := new(helper.MockWebhookDeliveryRepository)
+	delivery := pendingWebhookDelivery(server.URL, 0)
+	mockDeliveryRepository.On("LoadDeliveries", mock.Anything).Return([]entity.WebhookDelivery{delivery}, nil)
+	mockDeliveryRepository.On("FindById", mock.Anything, delivery.Id).Return(delivery, nil)
+	mockDeliveryRepository.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(updated entity.WebhookDelivery) bool {
+		return updated.Status == entity.WebhookDeliveryStatusPending && updated.Attempts == 1 &&
+			updated.LastError == fmt.Sprintf("merchant %s has no webhook secret to sign with", helper.MerchantId)
+	})).Return(nil)
+
+	useCase := newWebhookUseCase(mockDeliveryRepository, mockMerchantRepository, new(helper.MockHistoryUseCase))
+
+	_, err := useCase.DeliverDue(context.Background(), time.Now())
+
+	assert.Nil(t, err)
+	assert.False(t, called)
+	mockDeliveryRepository.AssertExpectations(t)
+}
+
+func TestDeliverDueWebhooks_ShouldDeadLetterAfterMaxAttempts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	delivery := pendingWebhookDelivery(server.URL, 2)
+
+	mockMerchantRepository := new(helper.MockMerchantRepository)
+	mockMerchantRepository.On("FindById", mock.Anything, helper.MerchantId).Return(webhookMerchant(server.URL), nil)
+
+	mockDeliveryRepository := new(helper.MockWebhookDeliveryRepository)
+	mockDeliveryRepository.On("LoadDeliveries", mock.Anything).Return([]entity.WebhookDelivery{delivery}, nil)
+	mockDeliveryRepository.On("FindById", mock.Anything, delivery.Id).Return(delivery, nil)
+	mockDeliveryRepository.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(updated entity.WebhookDelivery) bool {
+		return updated.Status == entity.WebhookDeliveryStatusDeadLetter && updated.Attempts == 3
+	})).Return(nil)
+
+	useCase := newWebhookUseCase(mockDeliveryRepository, mockMerchantRepository, new(helper.MockHistoryUseCase))
+
+	_, err := useCase.DeliverDue(context.Background(), time.Now())
+
+	assert.Nil(t, err)
+	mockDeliveryRepository.AssertExpectations(t)
+}
+
+func TestDeliverDueWebhooks_ShouldSkipDeliveriesNotDue(t *testing.T) {
+	now := time.Now()
+	notDue := pendingWebhookDelivery("http://merchant.test/hook", 1)
+	notDue.NextAttemptAt = now.Add(time.Hour)
+	delivered := pendingWebhookDelivery("http://merchant.test/hook", 1)
+	delivered.Status = entity.WebhookDeliveryStatusDelivered
+
+	mockDeliveryRepository := new(helper.MockWebhookDeliveryRepository)
+	mockDeliveryRepository.On("LoadDeliveries", mock.Anything).Return([]entity.WebhookDelivery{notDue, delivered}, nil)
+
+	useCase := newWebhookUseCase(mockDeliveryRepository, new(helper.MockMerchantRepository), new(helper.MockHistoryUseCase))
+
+	attempted, err := useCase.DeliverDue(context.Background(), now)
+
+	assert.Nil(t, err)
+	assert.Zero(t, attempted)
+	mockDeliveryRepository.AssertNotCalled(t, "UpdateDelivery", mock.Anything, mock.Anything)
+}
+
+func TestDeliverDueWebhooks_ShouldSkipDelivery_WhenRedeliveredSinceLoad(t *testing.T) {
+	now := time.Now()
+	delivery := pendingWebhookDelivery("http://merchant.test/hook", 1)
+	redelivered := delivery
+	redelivered.Status = entity.WebhookDeliveryStatusDelivered
+
+	mockDeliveryRepository := new(helper.MockWebhookDeliveryRepository)
+	mockDeliveryRepository.On("LoadDeliveries", mock.Anything).Return([]entity.WebhookDelivery{delivery}, nil)
+	mockDeliveryRepository.On("FindById", mock.Anything, delivery.Id).Return(redelivered, nil)
+
+	useCase := newWebhookUseCase(mockDeliveryRepository, new(helper.MockMerchantRepository), new(helper.MockHistoryUseCase))
+
+	attempted, err := useCase.DeliverDue(context.Background(), now)
+
+	assert.Nil(t, err)
+	assert.Zero(t, attempted)
+	mockDeliveryRepository.AssertNotCalled(t, "UpdateDelivery", mock.Anything, mock.Anything)
+}
+
+func TestRedeliverWebhook_ShouldNotWaitForSlowDeliveries(t *testing.T) {
+	release := make(chan struct{})
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer slowServer.Close()
+	defer close(release)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	slow := pendingWebhookDelivery(slowServer.URL, 0)
+	deadLetter := pendingWebhookDelivery(server.URL, 3)
+	deadLetter.Id = uuid.New()
+	deadLetter.Status = entity.WebhookDeliveryStatusDeadLetter
+
+	mockMerchantRepository := new(helper.MockMerchantRepository)
+	mockMerchantRepository.On("FindById", mock.Anything, helper.MerchantId).Return(webhookMerchant(server.URL), nil)
+
+	sending := make(chan struct{})
+	mockDeliveryRepository := new(helper.MockWebhookDeliveryRepository)
+	mockDeliveryRepository.On("LoadDeliveries", mock.Anything).Return([]entity.WebhookDelivery{slow}, nil)
+	mockDeliveryRepository.On("FindById", mock.Anything, slow.Id).Return(slow, nil).Run(func(args mock.Arguments) {
+		close(sending)
+	})
+	mockDeliveryRepository.On("FindById", mock.Anything, deadLetter.Id).Return(deadLetter, nil)
+	mockDeliveryRepository.On("UpdateDelivery", mock.Anything, mock.Anything).Return(nil)
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	useCase := impl.NewWebhookUseCaseImpl(logrus.New(), mockDeliveryRepository, mockMerchantRepository, mockHistoryUseCase,
+		&http.Client{Timeout: 10 * time.Second}, 3, time.Minute)
+
+	go func() {
+		_, _ = useCase.DeliverDue(context.Background(), time.Now())
+	}()
+	<-sending
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := useCase.Redeliver(context.Background(), helper.CustomerId.String(), deadLetter.Id.String())
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		assert.Nil(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("redelivery waited for the worker's delivery to a slow endpoint")
+	}
+}
+
+func TestRedeliverWebhook_ShouldReturnError_WhenDeliveryBeingSent(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	delivery := pendingWebhookDelivery(server.URL, 0)
+
+	mockMerchantRepository := new(helper.MockMerchantRepository)
+	mockMerchantRepository.On("FindById", mock.Anything, helper.MerchantId).Return(webhookMerchant(server.URL), nil)
+
+	sending := make(chan struct{})
+	mockDeliveryRepository := new(helper.MockWebhookDeliveryRepository)
+	mockDeliveryRepository.On("LoadDeliveries", mock.Anything).Return([]entity.WebhookDelivery{delivery}, nil)
+	mockDeliveryRepository.On("FindById", mock.Anything, delivery.Id).Return(delivery, nil).Run(func(args mock.Arguments) {
+		close(sending)
+	}).Once()
+	mockDeliveryRepository.On("UpdateDelivery", mock.Anything, mock.Anything).Return(nil)
+
+	useCase := impl.NewWebhookUseCaseImpl(logrus.New(), mockDeliveryRepository, mockMerchantRepository, new(helper.MockHistoryUseCase),
+		&http.Client{Timeout: 10 * time.Second}, 3, time.Minute)
+
+	go func() {
+		_, _ = useCase.DeliverDue(context.Background(), time.Now())
+	}()
+	<-sending
+
+	_, err := useCase.Redeliver(context.Background(), helper.CustomerId.String(), delivery.Id.String())
+
+	assert.ErrorIs(t, err, usecase.ErrWebhookDeliveryInFlight)
+}
+
+func TestRedeliverWebhook_ShouldResetDeadLetterAndSend(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	delivery := pendingWebhookDelivery(server.URL, 3)
+	delivery.Status = entity.WebhookDeliveryStatusDeadLetter
+
+	mockMerchantRepository := new(helper.MockMerchantRepository)
+	mockMerchantRepository.On("FindById", mock.Anything, helper.MerchantId).Return(webhookMerchant(server.URL), nil)
+
+	mockDeliveryRepository := new(helper.MockWebhookDeliveryRepository)
+	mockDeliveryRepository.On("FindById", mock.Anything, delivery.Id).Return(delivery, nil)
+	mockDeliveryRepository.On("UpdateDelivery", mock.Anything, mock.Anything).Return(nil)
+
+	mockHistoryUseCase := new(helper.MockHistoryUseCase)
+	mockHistoryUseCase.On("LogAndAddHistory", mock.Anything, helper.CustomerId.String(), entity.AuditActionWebhookRedeliver, mock.Anything, nil).Return(nil)
+
+	useCase := newWebhookUseCase(mockDeliveryRepository, mockMerchantRepository, mockHistoryUseCase)
+
+	deliveryResult, err := useCase.Redeliver(context.Background(), helper.CustomerId.String(), delivery.Id.String())
+
+	assert.Nil(t, err)
+	assert.Equal(t, string(entity.WebhookDeliveryStatusDelivered), deliveryResult.Status)
+	assert.Equal(t, 1, deliveryResult.Attempts)
+	mockHistoryUseCase.AssertExpectations(t)
+}
+
+func TestRedeliverWebhook_ShouldReturnError_WhenNotFound(t *testing.T) {
+	id := uuid.New()
+	mockDeliveryRepository := new(helper.MockWebhookDeliveryRepository)
+	mockDeliveryRepository.On("FindById", mock.Anything, id).Return(entity.WebhookDelivery{}, errors.New("not found"))
+
+	useCase := newWebhookUseCase(mockDeliveryRepository, new(helper.MockMerchantRepository), new(helper.MockHistoryUseCase))
+
+	_, err := useCase.Redeliver(context.Background(), helper.CustomerId.String(), id.String())
+
+	assert.NotNil(t, err)
+}
+
+func TestGetWebhookDeliveries_ShouldFilterByStatus(t *testing.T) {
+	deadLetter := pendingWebhookDelivery("http://merchant.test/hook", 3)
+	deadLetter.Id = uuid.New()
+	deadLetter.Status = entity.WebhookDeliveryStatusDeadLetter
+
+	mockDeliveryRepository := new(helper.MockWebhookDeliveryRepository)
+	mockDeliveryRepository.On("LoadDeliveries", mock.Anything).Return(append([]entity.WebhookDelivery{deadLetter}, helper.ExpectedWebhookDeliveries...), nil)
+
+	useCase := newWebhookUseCase(mockDeliveryRepository, new(helper.MockMerchantRepository), new(helper.MockHistoryUseCase))
+
+	deliveriesResult, err := useCase.GetDeliveries(context.Background(), "dead_letter", helper.MerchantId.String())
+
+	assert.Nil(t, err)
+	assert.Len(t, deliveriesResult, 1)
+	assert.Equal(t, deadLetter.Id.String(), deliveriesResult[0].Id)
+
+	_, err = useCase.GetDeliveries(context.Background(), "", "not-a-uuid")
+	assert.NotNil(t, err)
+}
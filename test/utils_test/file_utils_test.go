@@ -1,6 +1,7 @@
 package utils_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/sirupsen/logrus"
@@ -9,6 +10,7 @@ import (
 	"merchant_bank_payment_go_api/internal/utils"
 	"merchant_bank_payment_go_api/test/helper"
 	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -29,7 +31,7 @@ func TestReadJsonFile_Success(t *testing.T) {
 		}
 	}(helper.FileUtilsFileName)
 
-	contentResult, err := utils.ReadJsonFile(helper.FileUtilsFileName, log)
+	contentResult, err := utils.ReadJsonFile(context.Background(), helper.FileUtilsFileName, log)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
@@ -39,7 +41,7 @@ func TestReadJsonFile_Success(t *testing.T) {
 
 func TestReadJsonFile_FileNotFound(t *testing.T) {
 	log := logrus.New()
-	contentResult, err := utils.ReadJsonFile(helper.FileUtilsFileName, log)
+	contentResult, err := utils.ReadJsonFile(context.Background(), helper.FileUtilsFileName, log)
 
 	assert.Nil(t, contentResult)
 	assert.NotNil(t, err)
@@ -58,7 +60,7 @@ func TestReadJsonFile_ReadError(t *testing.T) {
 		}
 	}(helper.FileUtilsFileName)
 
-	content, err := utils.ReadJsonFile(helper.FileUtilsFileName, log)
+	content, err := utils.ReadJsonFile(context.Background(), helper.FileUtilsFileName, log)
 
 	assert.Nil(t, content)
 	assert.NotNil(t, err)
@@ -66,7 +68,7 @@ func TestReadJsonFile_ReadError(t *testing.T) {
 
 func TestWriteJsonFile_Success(t *testing.T) {
 	log := logrus.New()
-	err := utils.WriteJsonFile(helper.FileUtilsFileName, helper.ExpectedCustomers, log)
+	err := utils.WriteJsonFile(context.Background(), helper.FileUtilsFileName, helper.ExpectedCustomers, log)
 	if err != nil {
 		t.Errorf("Error write file %s: %v", helper.FileUtilsFileName, err)
 	}
@@ -95,7 +97,7 @@ func TestWriteJsonFile_EncodingError(t *testing.T) {
 	log := logrus.New()
 	testData := func() {}
 
-	err := utils.WriteJsonFile(helper.FileUtilsFileName, testData, log)
+	err := utils.WriteJsonFile(context.Background(), helper.FileUtilsFileName, testData, log)
 
 	assert.NotNil(t, err)
 
@@ -106,3 +108,114 @@ func TestWriteJsonFile_EncodingError(t *testing.T) {
 		}
 	}(helper.FileUtilsFileName)
 }
+
+func TestStreamJsonArray_ShouldDecodeEachItem(t *testing.T) {
+	log := logrus.New()
+	err := os.WriteFile(helper.FileUtilsFileName, []byte(`[{"value":1},{"value":2},{"value":3}]`), 0644)
+	if err != nil {
+		t.Errorf("Error creating file %s: %v", helper.FileUtilsFileName, err)
+	}
+	defer os.Remove(helper.FileUtilsFileName)
+
+	var values []int
+	err = utils.StreamJsonArray(context.Background(), helper.FileUtilsFileName, log, func(item struct{ Value int }) error {
+		values = append(values, item.Value)
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 2, 3}, values)
+}
+
+func TestStreamJsonArray_ShouldStopOnHandlerError(t *testing.T) {
+	log := logrus.New()
+	err := os.WriteFile(helper.FileUtilsFileName, []byte(`[1,2,3]`), 0644)
+	if err != nil {
+		t.Errorf("Error creating file %s: %v", helper.FileUtilsFileName, err)
+	}
+	defer os.Remove(helper.FileUtilsFileName)
+
+	visited := 0
+	err = utils.StreamJsonArray(context.Background(), helper.FileUtilsFileName, log, func(item int) error {
+		visited++
+		return fmt.Errorf("stop")
+	})
+
+	assert.EqualError(t, err, "stop")
+	assert.Equal(t, 1, visited)
+}
+
+func TestStreamJsonArray_ShouldReturnError_WhenNotArray(t *testing.T) {
+	log := logrus.New()
+	err := os.WriteFile(helper.FileUtilsFileName, []byte(`{"value":1}`), 0644)
+	if err != nil {
+		t.Errorf("Error creating file %s: %v", helper.FileUtilsFileName, err)
+	}
+	defer os.Remove(helper.FileUtilsFileName)
+
+	err = utils.StreamJsonArray(context.Background(), helper.FileUtilsFileName, log, func(item int) error {
+		return nil
+	})
+
+	assert.NotNil(t, err)
+}
+
+func TestReadJsonFile_ShouldReturnError_WhenContextCancelled(t *testing.T) {
+	log := logrus.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	contentResult, err := utils.ReadJsonFile(ctx, helper.FileUtilsFileName, log)
+
+	assert.Nil(t, contentResult)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestStreamJsonArray_ShouldStop_WhenContextCancelled(t *testing.T) {
+	log := logrus.New()
+	err := os.WriteFile(helper.FileUtilsFileName, []byte(`[1,2,3]`), 0644)
+	if err != nil {
+		t.Errorf("Error creating file %s: %v", helper.FileUtilsFileName, err)
+	}
+	defer os.Remove(helper.FileUtilsFileName)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	visited := 0
+	err = utils.StreamJsonArray(ctx, helper.FileUtilsFileName, log, func(item int) error {
+		visited++
+		cancel()
+		return nil
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, visited)
+}
+
+func TestCheckFileAccess_ShouldLeaveDirectoryUnchanged(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "data.json")
+	assert.Nil(t, os.WriteFile(filename, []byte(`[]`), 0o644))
+
+	err := utils.CheckFileAccess(context.Background(), filename)
+
+	assert.Nil(t, err)
+	entries, _ := os.ReadDir(dir)
+	assert.Len(t, entries, 1)
+	content, _ := os.ReadFile(filename)
+	assert.Equal(t, `[]`, string(content))
+}
+
+func TestCheckFileAccess_ShouldReturnError_WhenPathIsDirectory(t *testing.T) {
+	err := utils.CheckFileAccess(context.Background(), t.TempDir())
+
+	assert.NotNil(t, err)
+}
+
+func TestCheckDirAccess_ShouldReturnError_WhenContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := utils.CheckDirAccess(ctx, t.TempDir())
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
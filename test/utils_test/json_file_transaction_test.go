@@ -0,0 +1,75 @@
+package utils_test
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"merchant_bank_payment_go_api/internal/utils"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJsonFileTransaction_ShouldReplaceAllStagedFiles(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.json")
+	second := filepath.Join(dir, "second.json")
+	journal := filepath.Join(dir, "tx.journal")
+	assert.Nil(t, os.WriteFile(first, []byte(`[1]`), 0644))
+
+	tx := utils.NewJsonFileTransaction(logrus.New(), journal)
+	tx.Stage(first, []int{1, 2})
+	tx.Stage(second, []string{"a"})
+	tx.Stage(first, []int{1, 2, 3})
+
+	err := tx.Commit(context.Background())
+	assert.Nil(t, err)
+
+	firstContent, _ := os.ReadFile(first)
+	secondContent, _ := os.ReadFile(second)
+	assert.JSONEq(t, `[1,2,3]`, string(firstContent))
+	assert.JSONEq(t, `["a"]`, string(secondContent))
+	assert.NoFileExists(t, journal)
+	assert.NoFileExists(t, first+".tmp")
+}
+
+func TestJsonFileTransaction_ShouldLeaveFilesUntouched_WhenStagingFails(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.json")
+	assert.Nil(t, os.WriteFile(first, []byte(`[1]`), 0644))
+
+	tx := utils.NewJsonFileTransaction(logrus.New(), filepath.Join(dir, "tx.journal"))
+	tx.Stage(first, []int{1, 2})
+	tx.Stage(filepath.Join(dir, "missing", "second.json"), []int{3})
+
+	err := tx.Commit(context.Background())
+	assert.NotNil(t, err)
+
+	firstContent, _ := os.ReadFile(first)
+	assert.Equal(t, `[1]`, string(firstContent))
+	assert.NoFileExists(t, first+".tmp")
+}
+
+func TestRecoverJsonFileTransaction_ShouldRollInterruptedCommitForward(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.json")
+	second := filepath.Join(dir, "second.json")
+	journal := filepath.Join(dir, "tx.journal")
+	assert.Nil(t, os.WriteFile(first, []byte(`[1,2]`), 0644))
+	assert.Nil(t, os.WriteFile(second, []byte(`["old"]`), 0644))
+	assert.Nil(t, os.WriteFile(second+".tmp", []byte(`["new"]`), 0644))
+	assert.Nil(t, os.WriteFile(journal, []byte(`["`+filepath.ToSlash(first)+`","`+filepath.ToSlash(second)+`"]`), 0644))
+
+	err := utils.RecoverJsonFileTransaction(journal, logrus.New())
+	assert.Nil(t, err)
+
+	secondContent, _ := os.ReadFile(second)
+	assert.Equal(t, `["new"]`, string(secondContent))
+	assert.NoFileExists(t, journal)
+}
+
+func TestRecoverJsonFileTransaction_ShouldDoNothing_WhenNoJournal(t *testing.T) {
+	err := utils.RecoverJsonFileTransaction(filepath.Join(t.TempDir(), "tx.journal"), logrus.New())
+
+	assert.Nil(t, err)
+}
@@ -1,10 +1,12 @@
 package utils_test
 
 import (
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"merchant_bank_payment_go_api/internal/utils"
 	"merchant_bank_payment_go_api/test/helper"
 	"testing"
+	"time"
 )
 
 func TestGenerateAccessToken_ShouldReturnAccessToken(t *testing.T) {
@@ -44,3 +46,30 @@ func TestExtractIDFromToken_InvalidToken(t *testing.T) {
 	_, err := utils.ExtractIDFromToken(tokenString)
 	assert.Error(t, err)
 }
+
+func TestExtractIssuedAtFromToken_ShouldReturnIssueTime(t *testing.T) {
+	utils.InitJwtConfig([]byte("abc"), 10)
+
+	before := time.Now().Truncate(time.Second)
+	token, err := utils.GenerateAccessToken(helper.CustomerId.String())
+	assert.Nil(t, err)
+
+	issuedAt, err := utils.ExtractIssuedAtFromToken(token)
+	assert.Nil(t, err)
+	assert.False(t, issuedAt.Before(before))
+	assert.False(t, issuedAt.After(time.Now()))
+}
+
+func TestExtractIssuedAtFromToken_ShouldReturnZero_WhenTokenHasNoIssueTime(t *testing.T) {
+	utils.InitJwtConfig([]byte("abc"), 10)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user": helper.CustomerId.String(),
+		"exp":  time.Now().Add(time.Minute).Unix(),
+	}).SignedString([]byte("abc"))
+	assert.Nil(t, err)
+
+	issuedAt, err := utils.ExtractIssuedAtFromToken(token)
+	assert.Nil(t, err)
+	assert.True(t, issuedAt.IsZero())
+}
@@ -0,0 +1,86 @@
+package utils_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"merchant_bank_payment_go_api/internal/utils"
+	"testing"
+	"time"
+)
+
+func newTestRateLimiter(now *time.Time) *utils.RateLimiter {
+	limiter := utils.NewRateLimiter()
+	limiter.Now = func() time.Time { return *now }
+	return limiter
+}
+
+func TestParseRateLimitPolicy_ShouldParseRequestsPerWindow(t *testing.T) {
+	policy, err := utils.ParseRateLimitPolicy("10/1m")
+
+	assert.Nil(t, err)
+	assert.Equal(t, utils.RateLimitPolicy{Requests: 10, Window: time.Minute}, policy)
+	assert.Equal(t, "10/1m0s", policy.String())
+
+	policy, err = utils.ParseRateLimitPolicy("OFF")
+
+	assert.Nil(t, err)
+	assert.False(t, policy.Enabled())
+}
+
+func TestParseRateLimitPolicy_ShouldRejectInvalidPolicies(t *testing.T) {
+	for _, value := range []string{"10", "0/1m", "ten/1m", "10/soon", "10/-1s", ""} {
+		_, err := utils.ParseRateLimitPolicy(value)
+		assert.NotNil(t, err, value)
+	}
+}
+
+func TestRateLimiterAllow_ShouldAllowBurstThenRefill(t *testing.T) {
+	now := time.Date(2024, 11, 22, 10, 0, 0, 0, time.UTC)
+	limiter := newTestRateLimiter(&now)
+	policy := utils.RateLimitPolicy{Requests: 3, Window: 30 * time.Second}
+
+	for remaining := 2; remaining >= 0; remaining-- {
+		status := limiter.Allow("ip:1.2.3.4", policy)
+		assert.True(t, status.Allowed)
+		assert.Equal(t, 3, status.Limit)
+		assert.Equal(t, remaining, status.Remaining)
+	}
+
+	status := limiter.Allow("ip:1.2.3.4", policy)
+	assert.False(t, status.Allowed)
+	assert.Equal(t, 10*time.Second, status.RetryAfter)
+	assert.Equal(t, 30*time.Second, status.Reset)
+
+	assert.True(t, limiter.Allow("ip:5.6.7.8", policy).Allowed)
+
+	now = now.Add(10 * time.Second)
+	status = limiter.Allow("ip:1.2.3.4", policy)
+	assert.True(t, status.Allowed)
+	assert.Equal(t, 0, status.Remaining)
+}
+
+func TestRateLimiterAllow_ShouldIgnoreDisabledPolicy(t *testing.T) {
+	limiter := utils.NewRateLimiter()
+
+	for i := 0; i < 100; i++ {
+		assert.True(t, limiter.Allow("ip:1.2.3.4", utils.RateLimitPolicy{}).Allowed)
+	}
+	assert.Equal(t, 0, limiter.Size())
+}
+
+func TestRateLimiterAllow_ShouldDropFullBuckets(t *testing.T) {
+	now := time.Date(2024, 11, 22, 10, 0, 0, 0, time.UTC)
+	limiter := newTestRateLimiter(&now)
+	policy := utils.RateLimitPolicy{Requests: 2, Window: time.Minute}
+
+	limiter.Allow("ip:1.2.3.4", policy)
+	limiter.Allow("ip:5.6.7.8", policy)
+	limiter.Allow("ip:5.6.7.8", policy)
+	assert.Equal(t, 2, limiter.Size())
+
+	now = now.Add(45 * time.Second)
+	limiter.Allow("ip:9.9.9.9", policy)
+	now = now.Add(30 * time.Second)
+	limiter.Allow("ip:9.9.9.9", policy)
+
+	assert.Equal(t, 1, limiter.Size())
+}
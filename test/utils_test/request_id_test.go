@@ -0,0 +1,36 @@
+package utils_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"merchant_bank_payment_go_api/internal/utils"
+	"testing"
+)
+
+func TestRequestIdHook_ShouldAddRequestIdField(t *testing.T) {
+	var output bytes.Buffer
+	log := logrus.New()
+	log.SetOutput(&output)
+	log.SetFormatter(&logrus.JSONFormatter{})
+	log.AddHook(utils.RequestIdHook{})
+
+	ctx := utils.WithRequestId(context.Background(), "req-1")
+	log.WithContext(ctx).Info("with request")
+	log.Info("without request")
+
+	lines := bytes.Split(bytes.TrimSpace(output.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2)
+
+	var first, second map[string]interface{}
+	assert.Nil(t, json.Unmarshal(lines[0], &first))
+	assert.Nil(t, json.Unmarshal(lines[1], &second))
+	assert.Equal(t, "req-1", first["request_id"])
+	assert.NotContains(t, second, "request_id")
+}
+
+func TestRequestIdFromContext_ShouldReturnEmpty_WhenNotSet(t *testing.T) {
+	assert.Empty(t, utils.RequestIdFromContext(context.Background()))
+}
@@ -0,0 +1,147 @@
+package utils_test
+
+import (
+	"compress/gzip"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"merchant_bank_payment_go_api/internal/utils"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func listLogDir(t *testing.T, dir string) []string {
+	entries, err := os.ReadDir(dir)
+	assert.Nil(t, err)
+
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func readGzip(t *testing.T, filename string) string {
+	file, err := os.Open(filename)
+	assert.Nil(t, err)
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	assert.Nil(t, err)
+	content, err := io.ReadAll(reader)
+	assert.Nil(t, err)
+	return string(content)
+}
+
+func TestRotatingFile_ShouldRotate_WhenMaxSizeReached(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 8, 23, 10, 0, 0, 0, time.UTC)
+	file := &utils.RotatingFile{
+		Filename: filepath.Join(dir, "app.log"),
+		MaxSize:  10,
+		Now:      func() time.Time { return now },
+	}
+
+	_, err := file.Write([]byte("first\n"))
+	assert.Nil(t, err)
+	_, err = file.Write([]byte("second\n"))
+	assert.Nil(t, err)
+	assert.Nil(t, file.Close())
+
+	assert.Equal(t, []string{"app-2024-08-23T10-00-00.000.log", "app.log"}, listLogDir(t, dir))
+	rotated, _ := os.ReadFile(filepath.Join(dir, "app-2024-08-23T10-00-00.000.log"))
+	current, _ := os.ReadFile(filepath.Join(dir, "app.log"))
+	assert.Equal(t, "first\n", string(rotated))
+	assert.Equal(t, "second\n", string(current))
+}
+
+func TestRotatingFile_ShouldRotate_WhenPeriodChanges(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 8, 23, 23, 59, 0, 0, time.UTC)
+	file := &utils.RotatingFile{
+		Filename:    filepath.Join(dir, "app.log"),
+		RotateEvery: 24 * time.Hour,
+		Now:         func() time.Time { return now },
+	}
+
+	_, err := file.Write([]byte("before midnight\n"))
+	assert.Nil(t, err)
+	now = now.Add(30 * time.Second)
+	_, err = file.Write([]byte("same day\n"))
+	assert.Nil(t, err)
+	now = now.Add(time.Minute)
+	_, err = file.Write([]byte("next day\n"))
+	assert.Nil(t, err)
+	assert.Nil(t, file.Close())
+
+	assert.Equal(t, []string{"app-2024-08-24T00-00-30.000.log", "app.log"}, listLogDir(t, dir))
+	rotated, _ := os.ReadFile(filepath.Join(dir, "app-2024-08-24T00-00-30.000.log"))
+	assert.Equal(t, "before midnight\nsame day\n", string(rotated))
+}
+
+func TestRotatingFile_ShouldCompressAndPruneBackups(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 8, 23, 10, 0, 0, 0, time.UTC)
+	file := &utils.RotatingFile{
+		Filename:   filepath.Join(dir, "app.log"),
+		MaxSize:    1,
+		MaxBackups: 2,
+		Compress:   true,
+		Now:        func() time.Time { return now },
+	}
+
+	for _, line := range []string{"one\n", "two\n", "three\n", "four\n"} {
+		_, err := file.Write([]byte(line))
+		assert.Nil(t, err)
+		now = now.Add(time.Minute)
+	}
+	assert.Nil(t, file.Close())
+
+	names := listLogDir(t, dir)
+	assert.Equal(t, []string{"app-2024-08-23T10-02-00.000.log.gz", "app-2024-08-23T10-03-00.000.log.gz", "app.log"}, names)
+	assert.Equal(t, "two\n", readGzip(t, filepath.Join(dir, names[0])))
+	assert.Equal(t, "three\n", readGzip(t, filepath.Join(dir, names[1])))
+}
+
+func TestRotatingFile_ShouldRemoveBackupsOlderThanMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	stale := filepath.Join(dir, "app-2024-07-01T00-00-00.000.log.gz")
+	unrelated := filepath.Join(dir, "app-notes.log")
+	assert.Nil(t, os.WriteFile(stale, []byte("old"), 0o644))
+	assert.Nil(t, os.WriteFile(unrelated, []byte("keep"), 0o644))
+
+	now := time.Date(2024, 8, 23, 10, 0, 0, 0, time.UTC)
+	file := &utils.RotatingFile{
+		Filename: filepath.Join(dir, "app.log"),
+		MaxSize:  1,
+		MaxAge:   7 * 24 * time.Hour,
+		Now:      func() time.Time { return now },
+	}
+
+	_, _ = file.Write([]byte("one\n"))
+	_, _ = file.Write([]byte("two\n"))
+	assert.Nil(t, file.Close())
+
+	names := strings.Join(listLogDir(t, dir), ",")
+	assert.NotContains(t, names, "2024-07-01")
+	assert.Contains(t, names, "app-notes.log")
+	assert.Contains(t, names, "app-2024-08-23T10-00-00.000.log")
+}
+
+func TestRotatingFile_ShouldContinueExistingFile(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "app.log")
+	assert.Nil(t, os.WriteFile(filename, []byte("existing\n"), 0o644))
+
+	file := &utils.RotatingFile{Filename: filename, MaxSize: 1024}
+	_, err := file.Write([]byte("appended\n"))
+	assert.Nil(t, err)
+	assert.Nil(t, file.Close())
+
+	content, _ := os.ReadFile(filename)
+	assert.Equal(t, "existing\nappended\n", string(content))
+}
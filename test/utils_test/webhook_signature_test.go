@@ -0,0 +1,53 @@
+package utils_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"merchant_bank_payment_go_api/internal/utils"
+	"testing"
+	"time"
+)
+
+func TestSignWebhookPayload_ShouldBeVerifiable(t *testing.T) {
+	now := time.Unix(1732600000, 0)
+	payload := []byte(`{"type":"payment.captured"}`)
+
+	header := utils.SignWebhookPayload("merchant-secret", now, payload)
+
+	assert.Contains(t, header, "t=1732600000,v1=")
+	assert.Nil(t, utils.VerifyWebhookSignature("merchant-secret", header, payload, 5*time.Minute, now.Add(time.Minute)))
+}
+
+func TestVerifyWebhookSignature_ShouldRejectTamperedPayload(t *testing.T) {
+	now := time.Unix(1732600000, 0)
+	header := utils.SignWebhookPayload("merchant-secret", now, []byte(`{"amount":100}`))
+
+	err := utils.VerifyWebhookSignature("merchant-secret", header, []byte(`{"amount":999}`), 0, now)
+
+	assert.EqualError(t, err, "webhook signature mismatch")
+}
+
+func TestVerifyWebhookSignature_ShouldRejectWrongSecret(t *testing.T) {
+	now := time.Unix(1732600000, 0)
+	payload := []byte(`{"amount":100}`)
+	header := utils.SignWebhookPayload("merchant-secret", now, payload)
+
+	err := utils.VerifyWebhookSignature("other-secret", header, payload, 0, now)
+
+	assert.NotNil(t, err)
+}
+
+func TestVerifyWebhookSignature_ShouldRejectStaleTimestamp(t *testing.T) {
+	now := time.Unix(1732600000, 0)
+	payload := []byte(`{"amount":100}`)
+	header := utils.SignWebhookPayload("merchant-secret", now, payload)
+
+	err := utils.VerifyWebhookSignature("merchant-secret", header, payload, 5*time.Minute, now.Add(time.Hour))
+
+	assert.EqualError(t, err, "webhook signature timestamp outside tolerance")
+}
+
+func TestVerifyWebhookSignature_ShouldRejectMalformedHeader(t *testing.T) {
+	err := utils.VerifyWebhookSignature("merchant-secret", "v1=abc", []byte(`{}`), 0, time.Now())
+
+	assert.EqualError(t, err, "malformed webhook signature header")
+}